@@ -61,6 +61,102 @@ const docTemplate = `{
                 }
             }
         },
+        "/auth/oidc/authorize": {
+            "get": {
+                "description": "Returns the identity provider URL to send the user to and a login binding the browser\nkeeps. The provider redirects back to the configured redirect URI with code and state,\nto be posted to /auth/oidc/callback with the login binding.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "Start OIDC login",
+                "responses": {
+                    "200": {
+                        "description": "Provider authorization URL",
+                        "schema": {
+                            "$ref": "#/definitions/accounts.OIDCAuthorizationResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "OIDC login is not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/callback": {
+            "post": {
+                "description": "Exchanges the authorization code returned by the identity provider and logs the user in.\nThe identity logs into the account it is linked to, else the account with the same\nverified email (which gets linked), else a new account when auto-provisioning is enabled.\nA pending account linked this way is activated and its password replaced by a random one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "Complete OIDC login",
+                "parameters": [
+                    {
+                        "description": "Code and state from the provider redirect",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/accounts.OIDCCallbackInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login successful with access and refresh tokens",
+                        "schema": {
+                            "$ref": "#/definitions/security.TokenPairResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or invalid state",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Identity verification failed or account disabled",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "No account linked to this identity",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "OIDC login is not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/auth/refresh": {
             "post": {
                 "description": "Exchange a valid refresh token for a new access token",
@@ -115,7 +211,7 @@ const docTemplate = `{
         },
         "/confirmemail": {
             "get": {
-                "description": "Confirm email address by providing username and email",
+                "description": "Confirm email address by providing username and email\nThe link of an email change swaps the new address in instead",
                 "produces": [
                     "application/json"
                 ],
@@ -136,6 +232,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "New email already in use",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -234,6 +336,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
+                    "423": {
+                        "description": "Account temporarily locked after repeated failed logins",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed login attempts, retry later",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -297,7 +411,7 @@ const docTemplate = `{
         },
         "/register": {
             "post": {
-                "description": "Register a new user with username, password, email, firstname, and lastname",
+                "description": "Register a new user with username, password, email, firstname, and lastname\nEmails are sent in preferred_language (en, fr), the Accept-Language header by default",
                 "consumes": [
                     "application/json"
                 ],
@@ -387,6 +501,76 @@ const docTemplate = `{
                 }
             }
         },
+        "/restoreaccount": {
+            "post": {
+                "description": "Cancel the pending deletion of an account during its grace period and log in.\nThe username field accepts either a username or an email address. Accounts logging in\nthrough OIDC send a fresh OIDC login started with /auth/oidc/authorize as oidc instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "Restore account",
+                "parameters": [
+                    {
+                        "description": "Credentials of the account",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/accounts.RestoreAccountInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Account restored, with access and refresh tokens",
+                        "schema": {
+                            "$ref": "#/definitions/security.TokenPairResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or account not scheduled for deletion",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid credentials or re-authentication failed",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "OIDC login is not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Account temporarily locked after repeated failed logins",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed login attempts, retry later",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/sharedlist/{sharing_code}": {
             "get": {
                 "description": "Retrieves pack metadata and contents using a sharing code",
@@ -407,6 +591,17 @@ const docTemplate = `{
                         "name": "sharing_code",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "preferred",
+                            "metric",
+                            "imperial"
+                        ],
+                        "type": "string",
+                        "description": "Add weight displays in this unit system",
+                        "name": "units",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -416,6 +611,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/packs.SharedPackResponse"
                         }
                     },
+                    "400": {
+                        "description": "Invalid units",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Pack not found or not shared",
                         "schema": {
@@ -431,6 +632,54 @@ const docTemplate = `{
                 }
             }
         },
+        "/unlockaccount": {
+            "get": {
+                "description": "Lift an account lock early using the link sent by email when the account was locked",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "Unlock account",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unlock code",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.OkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or expired unlock link",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/user/{username}": {
             "get": {
                 "description": "Returns public profile information and shared packs for a user.\nReturns 404 for non-existent users or users without a public profile (anti-enumeration).",
@@ -829,36 +1078,50 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/myaccount": {
-            "get": {
+        "/v1/importpack/commit": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get information of the currently logged-in user",
+                "description": "Create a pack from a reconciled import plan. Each item must either reuse an inventory item of the\nuser (action reuse with inventory_id) or create a new one (action create). An inventory item\ncan only be reused, or identical items created, once per plan.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Accounts"
+                    "Packs"
+                ],
+                "summary": "Commit a reconciled pack import",
+                "parameters": [
+                    {
+                        "description": "Pack name, description and reconciled items",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/packs.ImportPlanRequest"
+                        }
+                    }
                 ],
-                "summary": "Get account info",
                 "responses": {
                     "200": {
-                        "description": "Account Information",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/accounts.Account"
+                            "$ref": "#/definitions/packs.ImportExternalPackResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Invalid request, unresolved or duplicate item",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Account not found",
+                    "422": {
+                        "description": "Empty payload",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
@@ -870,14 +1133,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/v1/importpack/preview": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Update information of the currently logged-in user (email, name, preferences only)",
+                "description": "Propose an action for each item without persisting anything: reuse (an identical inventory item\nexists), create, or review (similar inventory items are listed as candidates). Submit the reconciled\nitems to /v1/importpack/commit.",
                 "consumes": [
                     "application/json"
                 ],
@@ -885,17 +1150,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Accounts"
+                    "Packs"
                 ],
-                "summary": "Update account info",
+                "summary": "Preview a pack import",
                 "parameters": [
                     {
-                        "description": "Account update data (excludes role, status, username)",
+                        "description": "Pack name, description and items",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/accounts.AccountUpdateInput"
+                            "$ref": "#/definitions/packs.ImportPackRequest"
                         }
                     }
                 ],
@@ -903,17 +1168,17 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/accounts.Account"
+                            "$ref": "#/definitions/packs.ImportPreviewResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid request",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "422": {
+                        "description": "Empty payload",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
@@ -927,14 +1192,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/myaccount/banner": {
+        "/v1/importspreadsheet": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Upload or update the banner/hero image for the currently logged-in user",
+                "description": "Import a gear list exported by LighterPack, Packstack, GearGrams, Hikerhub or written by hand.\nCSV files may use comma, semicolon, tab or pipe separators and UTF-8, UTF-16 or Windows-1252.\nColumns are detected from the header row; mapping overrides them with a JSON object from field\n(item_name, category, desc, qty, weight, unit, url, price, currency, worn, consumable) to header.\nRows that cannot be read are skipped and reported in errors.",
                 "consumes": [
                     "multipart/form-data"
                 ],
@@ -942,115 +1207,261 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Account Images"
+                    "Packs"
                 ],
-                "summary": "Upload or update banner image",
+                "summary": "Import a pack from a CSV or XLSX spreadsheet",
                 "parameters": [
                     {
                         "type": "file",
-                        "description": "Image file (JPEG, PNG, or WebP, max 5MB)",
-                        "name": "image",
+                        "description": "CSV or XLSX file",
+                        "name": "file",
                         "in": "formData",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pack name, the file name by default",
+                        "name": "pack_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pack description",
+                        "name": "pack_description",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column mapping, e.g. {\\",
+                        "name": "mapping",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit of weights without a unit column (g, kg, oz, lb), g by default",
+                        "name": "weight_unit",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of prices without one, the preferred currency by default",
+                        "name": "currency",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return the import preview instead of creating the pack",
+                        "name": "dry_run",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Banner image uploaded successfully",
+                        "description": "Spreadsheet imported with pack ID and skipped rows",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/packs.ImportSpreadsheetResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request or image",
+                        "description": "Unreadable file, invalid options or no valid row",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/packs.ImportSpreadsheetErrorResponse"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
-                    "413": {
-                        "description": "Payload too large",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/myaccount": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get information of the currently logged-in user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Accounts"
+                ],
+                "summary": "Get account info",
+                "responses": {
+                    "200": {
+                        "description": "Account Information",
+                        "schema": {
+                            "$ref": "#/definitions/accounts.Account"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Account not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     }
                 }
             },
-            "delete": {
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Delete the banner/hero image for the currently logged-in user",
+                "description": "Update information of the currently logged-in user (email, name, preferences only)\nA new email is returned as pending_email and only replaces the current one once the\nlink sent to it is confirmed (/confirmemail); the current address is notified",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Account Images"
+                    "Accounts"
+                ],
+                "summary": "Update account info",
+                "parameters": [
+                    {
+                        "description": "Account update data (excludes role, status, username)",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/accounts.AccountUpdateInput"
+                        }
+                    }
                 ],
-                "summary": "Delete banner image",
                 "responses": {
                     "200": {
-                        "description": "Banner image deleted successfully",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/accounts.Account"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Email already in use",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Schedule the deletion of the currently logged-in user's account, confirmed with the password\nor, for accounts logging in through OIDC, with the code, state and login binding of a fresh\nOIDC login started with /auth/oidc/authorize.\nThe account is deactivated at once and permanently deleted with all its data after the\ngrace period (ACCOUNT_DELETION_GRACE_DAYS); until then it can be restored with /restoreaccount.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Accounts"
+                ],
+                "summary": "Delete my account",
+                "parameters": [
+                    {
+                        "description": "Password or OIDC re-authentication",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/accounts.DeleteMyAccountInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Deletion scheduled",
+                        "schema": {
+                            "$ref": "#/definitions/accounts.AccountDeletionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request, incorrect password or failed re-authentication",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "OIDC login is not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Deletion already requested",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/v1/myaccount/image": {
+        "/v1/myaccount/banner": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Upload or update the profile image for the currently logged-in user",
+                "description": "Upload or update the banner/hero image for the currently logged-in user",
                 "consumes": [
                     "multipart/form-data"
                 ],
@@ -1060,7 +1471,7 @@ const docTemplate = `{
                 "tags": [
                     "Account Images"
                 ],
-                "summary": "Upload or update profile image",
+                "summary": "Upload or update banner image",
                 "parameters": [
                     {
                         "type": "file",
@@ -1072,7 +1483,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Image uploaded successfully",
+                        "description": "Banner image uploaded successfully",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -1122,17 +1533,17 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Delete the profile image for the currently logged-in user",
+                "description": "Delete the banner/hero image for the currently logged-in user",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Account Images"
                 ],
-                "summary": "Delete profile image",
+                "summary": "Delete banner image",
                 "responses": {
                     "200": {
-                        "description": "Image deleted successfully",
+                        "description": "Banner image deleted successfully",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -1159,29 +1570,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/myinventory": {
+        "/v1/myaccount/export": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieves a list of all inventories of the user",
+                "description": "Download a ZIP archive of all the data of the currently logged-in user:\naccount.json, inventory.json, custom_fields.json, packs.json, pack_contents.json and every image\n(images/profile, images/banner, images/packs/\u003cpack id\u003e, images/inventory/\u003citem id\u003e)",
                 "produces": [
-                    "application/json"
+                    "application/zip"
                 ],
                 "tags": [
-                    "Inventories"
+                    "Accounts"
                 ],
-                "summary": "Get all inventories of the user",
+                "summary": "Export my data",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "ZIP archive",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/inventories.Inventory"
-                            }
+                            "type": "file"
                         }
                     },
                     "401": {
@@ -1190,12 +1598,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "No Inventory Found",
-                        "schema": {
-                            "$ref": "#/definitions/apitypes.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1203,101 +1605,183 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/v1/myaccount/image": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Creates an inventory",
+                "description": "Upload or update the profile image for the currently logged-in user",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Inventories"
+                    "Account Images"
                 ],
-                "summary": "Create an inventory",
+                "summary": "Upload or update profile image",
                 "parameters": [
                     {
-                        "description": "Inventory",
-                        "name": "inventory",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/inventories.InventoryCreateRequest"
-                        }
+                        "type": "file",
+                        "description": "Image file (JPEG, PNG, or WebP, max 5MB)",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Inventory Updated",
+                    "200": {
+                        "description": "Image uploaded successfully",
                         "schema": {
-                            "$ref": "#/definitions/inventories.Inventory"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Invalid payload",
+                        "description": "Invalid request or image",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.ErrorResponse"
-                        }
-                    },
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Payload too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/v1/myinventory/merge": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Merges the source inventory item into the target item. Pack references are consolidated.",
-                "consumes": [
+                "description": "Delete the profile image for the currently logged-in user",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "Account Images"
+                ],
+                "summary": "Delete profile image",
+                "responses": {
+                    "200": {
+                        "description": "Image deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/myaccount/security-events": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "List the security events (logins, failed logins, logouts, refreshes) of the\nauthenticated user, most recent first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Inventories"
+                    "Accounts"
                 ],
-                "summary": "Merge two inventory items",
+                "summary": "Get my security events",
                 "parameters": [
                     {
-                        "description": "Merge request",
-                        "name": "merge",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/inventories.MergeInventoryRequest"
-                        }
+                        "type": "string",
+                        "description": "Event type (e.g. login_failed)",
+                        "name": "event_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the time range (RFC 3339, inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the time range (RFC 3339, exclusive)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of events (default 100, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of events to skip",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Merged inventory item",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/inventories.Inventory"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/security.AuditEvent"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid payload or source equals target",
+                        "description": "Invalid filter",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
@@ -1308,14 +1792,42 @@ const docTemplate = `{
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Item does not belong to you",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/v1/mycategories": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Lists the inventory categories of the user in display order, with their color, icon and\nitem count. Categories of new items are added after the existing ones.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Get my categories",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/categories.Category"
+                            }
+                        }
                     },
-                    "404": {
-                        "description": "Item not found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
@@ -1329,51 +1841,56 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/myinventory/{id}": {
-            "get": {
+        "/v1/mycategories/merge": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieves an inventory by ID",
+                "description": "Moves every item of the source categories to the target category and deletes the sources.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Inventories"
+                    "Categories"
                 ],
-                "summary": "Get an inventory by ID",
+                "summary": "Merge categories",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Inventory ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Source and target categories",
+                        "name": "merge",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/categories.CategoryMergeRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Inventory",
+                        "description": "Merged category",
                         "schema": {
-                            "$ref": "#/definitions/inventories.Inventory"
+                            "$ref": "#/definitions/categories.Category"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Invalid payload or target among sources",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "This item does not belong to you",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Inventory not found",
+                        "description": "Category not found",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
@@ -1385,14 +1902,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/v1/mycategories/order": {
             "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Updates an inventory by ID",
+                "description": "Puts the listed categories first, in order; the others keep their relative order after them.\nPack contents are listed in this order.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1400,96 +1919,102 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Inventories"
+                    "Categories"
                 ],
-                "summary": "Update an inventory by ID",
+                "summary": "Order categories",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Inventory ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Inventory",
-                        "name": "inventory",
+                        "description": "Category IDs in display order",
+                        "name": "order",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/inventories.InventoryUpdateRequest"
+                            "$ref": "#/definitions/categories.CategoryOrderRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Inventory Updated",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/inventories.Inventory"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/categories.Category"
+                            }
                         }
                     },
                     "400": {
                         "description": "Invalid payload",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "This item does not belong to you",
+                    "404": {
+                        "description": "Category not found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/v1/mycategories/{id}": {
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Deletes an inventory by ID",
+                "description": "Updates the name, color and icon of a category. Renaming moves every item of the category.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Inventories"
+                    "Categories"
                 ],
-                "summary": "Delete an inventory by ID",
+                "summary": "Update a category",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Inventory ID",
+                        "description": "Category ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Category settings",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/categories.CategoryUpdateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Inventory deleted",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.OkResponse"
+                            "$ref": "#/definitions/categories.Category"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID format",
+                        "description": "Invalid payload",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
@@ -1500,8 +2025,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "This item does not belong to you",
+                    "404": {
+                        "description": "Category not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Category name already exists",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
@@ -1513,27 +2044,25 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/myinventory/{id}/image": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get the image for an inventory item. Only the item owner can view the image.",
+                "description": "Deletes a category without items",
                 "produces": [
-                    "image/jpeg"
+                    "application/json"
                 ],
                 "tags": [
-                    "Inventory Images"
+                    "Categories"
                 ],
-                "summary": "Get inventory item image",
+                "summary": "Delete a category",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Inventory Item ID",
+                        "description": "Category ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1541,236 +2070,153 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Category deleted",
                         "schema": {
-                            "type": "file"
+                            "$ref": "#/definitions/apitypes.OkResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid item ID",
+                        "description": "Invalid ID format",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "404": {
+                        "description": "Category not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Item not found or has no image",
+                    "409": {
+                        "description": "Category still has items",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/v1/mycustomfields": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Upload or update an image for an inventory item. Only the item owner can upload images.",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                "description": "Lists the custom field definitions of the user, with the number of items that have a value",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Inventory Images"
-                ],
-                "summary": "Upload or update inventory item image",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Inventory Item ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "Image file (JPEG, PNG, or WebP, max 5MB)",
-                        "name": "image",
-                        "in": "formData",
-                        "required": true
-                    }
+                    "Custom fields"
                 ],
+                "summary": "Get my custom fields",
                 "responses": {
                     "200": {
-                        "description": "Image uploaded successfully",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid request or image",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/customfields.Field"
                             }
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "403": {
-                        "description": "Item does not belong to user",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "404": {
-                        "description": "Item not found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "413": {
-                        "description": "Payload too large",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "503": {
-                        "description": "Feature disabled",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Delete the image for an inventory item. Only the item owner can delete images.",
+                "description": "Defines a custom field for the inventory items of the user: text, number with an optional\nunit, boolean, or enum with its options. Items hold their values under the field key.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Inventory Images"
+                    "Custom fields"
                 ],
-                "summary": "Delete inventory item image",
+                "summary": "Create a custom field",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Inventory Item ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Custom field",
+                        "name": "field",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/customfields.FieldCreateRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Image deleted successfully",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/customfields.Field"
                         }
                     },
                     "400": {
-                        "description": "Invalid item ID",
+                        "description": "Invalid payload",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "403": {
-                        "description": "Item does not belong to user",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Item not found",
+                    "409": {
+                        "description": "Custom field key already exists",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/v1/mypack": {
-            "post": {
+        "/v1/mycustomfields/{id}": {
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Create a new pack",
+                "description": "Updates the name, unit and options of a custom field; its key and type cannot change.\nItem values no longer among the options of an enum field are removed.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1778,29 +2224,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Packs"
+                    "Custom fields"
                 ],
-                "summary": "Create a new pack",
+                "summary": "Update a custom field",
                 "parameters": [
                     {
-                        "description": "Pack",
-                        "name": "pack",
+                        "type": "integer",
+                        "description": "Custom field ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Custom field settings",
+                        "name": "field",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/packs.PackCreateRequest"
+                            "$ref": "#/definitions/customfields.FieldUpdateRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Pack created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/packs.Pack"
+                            "$ref": "#/definitions/customfields.Field"
                         }
                     },
                     "400": {
-                        "description": "Invalid Body format",
+                        "description": "Invalid payload",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
@@ -1811,6 +2264,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Custom field not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1818,46 +2277,39 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/mypack/:id/packcontent": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Create a new pack content",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Deletes a custom field and its values on every item",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Packs"
+                    "Custom fields"
                 ],
-                "summary": "Create a new pack content",
+                "summary": "Delete a custom field",
                 "parameters": [
                     {
-                        "description": "Pack Content",
-                        "name": "packcontent",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/packs.PackContent"
-                        }
+                        "type": "integer",
+                        "description": "Custom field ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "Custom field deleted",
                         "schema": {
-                            "$ref": "#/definitions/packs.PackContent"
+                            "$ref": "#/definitions/apitypes.OkResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid ID format",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
@@ -1868,8 +2320,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "404": {
+                        "description": "Custom field not found",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
@@ -1883,39 +2335,64 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/mypack/{id}": {
+        "/v1/myinventory": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get pack by ID",
+                "description": "Retrieves a list of all inventories of the user. Wishlist items are listed apart, with wishlist=true.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Packs"
+                    "Inventories"
                 ],
-                "summary": "Get My pack by ID",
+                "summary": "Get all inventories of the user",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Pack ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "enum": [
+                            "preferred",
+                            "metric",
+                            "imperial"
+                        ],
+                        "type": "string",
+                        "description": "Add weight displays in this unit system",
+                        "name": "units",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include retired items, hidden by default",
+                        "name": "include_retired",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "List the wishlist items instead of the owned ones",
+                        "name": "wishlist",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter on a custom field by key: text contained, exact value, or min..max",
+                        "name": "field.key",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/packs.Pack"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/inventories.Inventory"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid ID format",
+                        "description": "Invalid units or custom field filter",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
@@ -1926,14 +2403,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "This pack does not belong to you",
-                        "schema": {
-                            "$ref": "#/definitions/apitypes.ErrorResponse"
-                        }
-                    },
                     "404": {
-                        "description": "Pack not found",
+                        "description": "No Inventory Found",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
@@ -1946,13 +2417,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Update a pack by ID",
+                "description": "Creates an inventory. A product_id found with GET /v1/products links the item to the\nproduct, which fills a zero weight and an empty URL.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1960,36 +2431,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Packs"
+                    "Inventories"
                 ],
-                "summary": "Update a pack by ID",
+                "summary": "Create an inventory",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Pack ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Pack",
-                        "name": "pack",
+                        "description": "Inventory",
+                        "name": "inventory",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/packs.PackUpdateRequest"
+                            "$ref": "#/definitions/inventories.InventoryCreateRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Pack updated",
+                    "201": {
+                        "description": "Inventory Updated",
                         "schema": {
-                            "$ref": "#/definitions/packs.Pack"
+                            "$ref": "#/definitions/inventories.Inventory"
                         }
                     },
                     "400": {
-                        "description": "Invalid Payload",
+                        "description": "Invalid payload",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
@@ -2000,8 +2464,42 @@ const docTemplate = `{
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "This pack does not belong to you",
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/myinventory/duplicates": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Clusters inventory items that are likely the same piece of gear, based on name similarity,\ncategory, weight and URL. Each group has a confidence from 0 to 1, the shared signals and\nthe suggested merge target (the item used in the most packs). Wishlist items are left out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventories"
+                ],
+                "summary": "Find duplicate inventory items",
+                "responses": {
+                    "200": {
+                        "description": "Duplicate groups, most confident first",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/inventories.DuplicateGroup"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
@@ -2013,39 +2511,48 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/v1/myinventory/export": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Delete a pack by ID",
+                "description": "Download the inventory as CSV or JSON, with the number of packs using each item.\nCustom field values are exported as custom_fields in JSON and field.\u003ckey\u003e columns in CSV.\nThe file can be edited and imported back with /v1/myinventory/import.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv"
                 ],
                 "tags": [
-                    "Packs"
+                    "Inventories"
                 ],
-                "summary": "Delete a pack by ID",
+                "summary": "Export inventory items",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Pack ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "enum": [
+                            "csv",
+                            "json"
+                        ],
+                        "type": "string",
+                        "description": "Export format, json by default",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Pack deleted",
+                        "description": "Inventory items",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.OkResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/inventories.InventoryTransferItem"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid ID format",
+                        "description": "Invalid format",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
@@ -2056,12 +2563,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "This pack does not belong to you",
-                        "schema": {
-                            "$ref": "#/definitions/apitypes.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2071,41 +2572,44 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/mypack/{id}/duplicate": {
+        "/v1/myinventory/import": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Clone an existing pack (metadata + all pack contents) into a new pack\nowned by the same user. The new pack's name is prefixed with \"COPY - \",\nis_favorite is reset to false and sharing_code is not copied. The pack\nimage is intentionally not copied.",
+                "description": "Create or update inventory items from a CSV file (header row with external_key, item_name,\ncategory, description, weight, url, price, currency), a JSON file, or a JSON body\n{\"items\": [...]}. Weights are in grams, prices in the minor unit of their currency.\nItems are matched by external_key, then by item name, category and description; unmatched\nitems are created. Description, weight, url and price missing from a row keep the value of\nthe matched item. Rows that cannot be read are skipped and reported in errors.",
+                "consumes": [
+                    "multipart/form-data",
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Packs"
+                    "Inventories"
                 ],
-                "summary": "Duplicate a pack by ID",
+                "summary": "Import inventory items",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Pack ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "file",
+                        "description": "CSV or JSON file, unless the items are sent as a JSON body",
+                        "name": "file",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Pack duplicated successfully",
+                    "200": {
+                        "description": "Created and updated counts with skipped rows",
                         "schema": {
-                            "$ref": "#/definitions/packs.Pack"
+                            "$ref": "#/definitions/inventories.InventoryImportResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID format",
+                        "description": "Unreadable file or no valid row",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                            "$ref": "#/definitions/inventories.InventoryImportErrorResponse"
                         }
                     },
                     "401": {
@@ -2114,14 +2618,56 @@ const docTemplate = `{
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "This pack does not belong to you",
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/myinventory/maintenance/due": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Lists the items in service of the user whose maintenance interval has elapsed,\nor elapses within within_days days, counted from the last maintenance,\nelse the purchase date, else the creation of the item. Most overdue first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventories"
+                ],
+                "summary": "Get the items due for maintenance",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Also list the items due within this number of days",
+                        "name": "within_days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/inventories.MaintenanceDue"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid within_days",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Pack not found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
@@ -2135,39 +2681,44 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/mypack/{id}/favorite": {
+        "/v1/myinventory/merge": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Mark a pack as favorite. Only one pack per user can be favorite at a time (idempotent)",
+                "description": "Merges the source inventory item into the target item. Pack references are consolidated.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Packs"
+                    "Inventories"
                 ],
-                "summary": "Favorite a pack by ID",
+                "summary": "Merge two inventory items",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Pack ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Merge request",
+                        "name": "merge",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/inventories.MergeInventoryRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Pack favorited successfully",
+                        "description": "Merged inventory item",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.OkResponse"
+                            "$ref": "#/definitions/inventories.Inventory"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID format",
+                        "description": "Invalid payload or source equals target",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
@@ -2179,13 +2730,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "This pack does not belong to you",
+                        "description": "Item does not belong to you",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Pack not found",
+                        "description": "Item not found",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
@@ -2197,39 +2748,46 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/v1/myinventory/merge/batch": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Remove favorite status from a pack (idempotent)",
+                "description": "Merges every source inventory item into the target item. Pack references are consolidated.\nWith image_source \"source\", the image of image_source_item_id is kept.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Packs"
+                    "Inventories"
                 ],
-                "summary": "Unfavorite a pack by ID",
+                "summary": "Merge several inventory items",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Pack ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Batch merge request",
+                        "name": "merge",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/inventories.BatchMergeInventoryRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Pack unfavorited successfully",
+                        "description": "Merged inventory item",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.OkResponse"
+                            "$ref": "#/definitions/inventories.Inventory"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID format",
+                        "description": "Invalid payload or target among sources",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
@@ -2241,13 +2799,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "This pack does not belong to you",
+                        "description": "Item does not belong to you",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Pack not found",
+                        "description": "Item not found",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
@@ -2261,191 +2819,87 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/mypack/{id}/image": {
-            "post": {
+        "/v1/myinventory/{id}": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Upload or update an image for a pack. Only the pack owner can upload images.",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                "description": "Retrieves an inventory by ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Pack Images"
+                    "Inventories"
                 ],
-                "summary": "Upload or update pack image",
+                "summary": "Get an inventory by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Pack ID",
+                        "description": "Inventory ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "file",
-                        "description": "Image file (JPEG, PNG, or WebP, max 5MB)",
-                        "name": "image",
-                        "in": "formData",
-                        "required": true
+                        "enum": [
+                            "preferred",
+                            "metric",
+                            "imperial"
+                        ],
+                        "type": "string",
+                        "description": "Add weight displays in this unit system",
+                        "name": "units",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Image uploaded successfully",
+                        "description": "Inventory",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/inventories.Inventory"
                         }
                     },
                     "400": {
-                        "description": "Invalid request or image",
+                        "description": "Invalid ID format or units",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "Pack does not belong to user",
+                        "description": "This item does not belong to you",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Pack not found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "413": {
-                        "description": "Payload too large",
+                        "description": "Inventory not found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     }
                 }
             },
-            "delete": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Delete the image for a pack. Only the pack owner can delete images.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Pack Images"
-                ],
-                "summary": "Delete pack image",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Pack ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Image deleted successfully",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid pack ID",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "403": {
-                        "description": "Pack does not belong to user",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "404": {
-                        "description": "Pack not found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    }
-                }
-            }
-        },
-        "/v1/mypack/{id}/packcontent/{item_id}": {
             "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Update My pack content ID by Pack ID",
+                "description": "Updates an inventory by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -2453,63 +2907,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Packs"
+                    "Inventories"
                 ],
-                "summary": "Update My pack content ID by Pack ID",
+                "summary": "Update an inventory by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Pack ID",
+                        "description": "Inventory ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Item ID",
-                        "name": "item_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Pack Content",
-                        "name": "packcontent",
+                        "description": "Inventory",
+                        "name": "inventory",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/packs.PackContentRequest"
+                            "$ref": "#/definitions/inventories.InventoryUpdateRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Pack Content updated",
+                        "description": "Inventory Updated",
                         "schema": {
-                            "$ref": "#/definitions/packs.PackContent"
+                            "$ref": "#/definitions/inventories.Inventory"
                         }
                     },
                     "400": {
-                        "description": "Invalid Body format",
+                        "description": "Invalid payload",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "403": {
-                        "description": "This pack does not belong to you",
+                        "description": "This item does not belong to you",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
@@ -2520,33 +2971,26 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Delete a pack content by ID",
+                "description": "Deletes an inventory by ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Packs"
+                    "Inventories"
                 ],
-                "summary": "Delete a pack content by ID",
+                "summary": "Delete an inventory by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Pack ID",
+                        "description": "Inventory ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Item ID",
-                        "name": "item_id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Pack Item deleted",
+                        "description": "Inventory deleted",
                         "schema": {
                             "$ref": "#/definitions/apitypes.OkResponse"
                         }
@@ -2564,7 +3008,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "This pack does not belong to you",
+                        "description": "This item does not belong to you",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
@@ -2578,25 +3022,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/mypack/{id}/packcontents": {
+        "/v1/myinventory/{id}/image": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get pack content by ID",
+                "description": "Get the image for an inventory item. Only the item owner can view the image.",
                 "produces": [
-                    "application/json"
+                    "image/jpeg"
                 ],
                 "tags": [
-                    "Packs"
+                    "Inventory Images"
                 ],
-                "summary": "Get pack content by ID",
+                "summary": "Get inventory item image",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Pack Content ID",
+                        "description": "Inventory Item ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2604,106 +3048,145 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Pack Item",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/packs.PackContent"
+                            "type": "file"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID format",
+                        "description": "Invalid item ID",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                            "type": "string"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                            "type": "string"
                         }
                     },
                     "403": {
-                        "description": "This pack does not belong to you",
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                            "type": "string"
                         }
                     },
                     "404": {
-                        "description": "Pack not found",
+                        "description": "Item not found or has no image",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                            "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                            "type": "string"
                         }
                     }
                 }
-            }
-        },
-        "/v1/mypack/{id}/share": {
+            },
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Generate a sharing code for a pack to make it publicly accessible (idempotent)",
+                "description": "Upload or update an image for an inventory item. Only the item owner can upload images.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Packs"
+                    "Inventory Images"
                 ],
-                "summary": "Share a pack by ID",
+                "summary": "Upload or update inventory item image",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Pack ID",
+                        "description": "Inventory Item ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Image file (JPEG, PNG, or WebP, max 5MB)",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Pack shared successfully",
+                        "description": "Image uploaded successfully",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Invalid ID format",
+                        "description": "Invalid request or image",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "403": {
-                        "description": "This pack does not belong to you",
+                        "description": "Item does not belong to user",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "404": {
-                        "description": "Pack not found",
+                        "description": "Item not found",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "413": {
+                        "description": "Payload too large",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Feature disabled",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
@@ -2714,18 +3197,18 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Remove the sharing code from a pack to make it private (idempotent)",
+                "description": "Delete the image for an inventory item. Only the item owner can delete images.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Packs"
+                    "Inventory Images"
                 ],
-                "summary": "Unshare a pack by ID",
+                "summary": "Delete inventory item image",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Pack ID",
+                        "description": "Inventory Item ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2733,77 +3216,114 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Pack unshared successfully",
+                        "description": "Image deleted successfully",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.OkResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Invalid ID format",
+                        "description": "Invalid item ID",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "403": {
-                        "description": "This pack does not belong to you",
+                        "description": "Item does not belong to user",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "404": {
-                        "description": "Pack not found",
+                        "description": "Item not found",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/v1/mypacks": {
+        "/v1/myinventory/{id}/maintenance": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get my packs",
+                "description": "Retrieves the maintenance entries of an item of the user, most recent first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Packs"
+                    "Inventories"
+                ],
+                "summary": "Get the maintenance log of an item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Inventory ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get My Packs",
                 "responses": {
                     "200": {
-                        "description": "Packs",
+                        "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/packs.Pack"
+                                "$ref": "#/definitions/inventories.MaintenanceEntry"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "This item does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
                     "404": {
-                        "description": "No pack found",
+                        "description": "Inventory not found",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
@@ -2815,16 +3335,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/mypassword": {
-            "put": {
+            },
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Update the password of the current logged-in user",
+                "description": "Adds an entry to the maintenance log of an item of the user.\nThe cost is in the minor unit of the currency, the item currency by default.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2832,29 +3350,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Accounts"
+                    "Inventories"
                 ],
-                "summary": "Update password",
+                "summary": "Log a maintenance of an item",
                 "parameters": [
                     {
-                        "description": "Current and New Password",
-                        "name": "password",
+                        "type": "integer",
+                        "description": "Inventory ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Maintenance entry",
+                        "name": "entry",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/accounts.PasswordUpdateInput"
+                            "$ref": "#/definitions/inventories.MaintenanceEntryRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Password updated",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/apitypes.OkResponse"
+                            "$ref": "#/definitions/inventories.MaintenanceEntry"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid payload",
                         "schema": {
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
@@ -2865,6 +3390,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "This item does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Inventory not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2874,587 +3411,4610 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/pack-options": {
-            "get": {
+        "/v1/myinventory/{id}/maintenance/{entry_id}": {
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Returns the allowed values for season, trail, and adventure fields",
+                "description": "Removes an entry from the maintenance log of an item of the user",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Packs"
-                ],
-                "summary": "Get allowed values for pack metadata",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/packs.PackOptionsResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/v1/packs/{id}/image": {
-            "get": {
-                "description": "Get the image for a pack. Public packs are accessible without authentication.",
-                "produces": [
-                    "image/jpeg"
-                ],
-                "tags": [
-                    "Pack Images"
+                    "Inventories"
                 ],
-                "summary": "Get pack image",
+                "summary": "Delete a maintenance entry",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Pack ID",
+                        "description": "Inventory ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maintenance entry ID",
+                        "name": "entry_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Maintenance entry deleted",
                         "schema": {
-                            "type": "file"
+                            "$ref": "#/definitions/apitypes.OkResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid pack ID",
+                        "description": "Invalid ID format",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized (for private packs)",
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "Forbidden (for private packs not owned by user)",
+                        "description": "This item does not belong to you",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Pack not found or has no image",
+                        "description": "Inventory or maintenance entry not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/v1/packs/{id}/items/{itemId}/image": {
+        "/v1/myinventory/{id}/usage": {
             "get": {
-                "description": "Get the image for an inventory item within a pack. Public packs are accessible without authentication.",
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Sums the trips, nights and kilometres of the trips logged on packs containing the item,\nwith the item price divided by each of them",
                 "produces": [
-                    "image/jpeg"
+                    "application/json"
                 ],
                 "tags": [
-                    "Pack Images"
+                    "Inventories"
                 ],
-                "summary": "Get pack item image",
+                "summary": "Get the usage of an item",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Pack ID",
+                        "description": "Inventory ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Item (Inventory) ID",
-                        "name": "itemId",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "file"
+                            "$ref": "#/definitions/inventories.ItemUsage"
                         }
                     },
                     "400": {
-                        "description": "Invalid pack ID or item ID",
+                        "description": "Invalid ID format",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "This item does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Pack not found, item not in pack, or has no image",
+                        "description": "Inventory not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/v2/pack-options": {
-            "get": {
+        "/v1/mypack": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Returns the allowed values for season, trail (grouped by continent/country), and adventure fields",
+                "description": "Create a new pack",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Packs"
                 ],
-                "summary": "Get allowed values for pack metadata (V2)",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                "summary": "Create a new pack",
+                "parameters": [
+                    {
+                        "description": "Pack",
+                        "name": "pack",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/packs.PackOptionsV2Response"
+                            "$ref": "#/definitions/packs.PackCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Pack created",
+                        "schema": {
+                            "$ref": "#/definitions/packs.Pack"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Body format",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/mypack/:id/packcontent": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create a new pack content",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Create a new pack content",
+                "parameters": [
+                    {
+                        "description": "Pack Content",
+                        "name": "packcontent",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/packs.PackContent"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/packs.PackContent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/mypack/from-template/{id}": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Creates a pack of the user from a template. Each template item is mapped onto the\ninventory of the user by name and category, ignoring case; missing gear is added to the\nwishlist as a placeholder. The season, adventure and trail of the template are copied.\nThe pack name defaults to the template name.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Create a pack from a template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Pack name and description",
+                        "name": "pack",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/packs.FromTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/packs.FromTemplateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format, payload or empty template",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Template not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/mypack/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get pack by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Get My pack by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "preferred",
+                            "metric",
+                            "imperial"
+                        ],
+                        "type": "string",
+                        "description": "Add weight displays in this unit system",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/packs.Pack"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format or units",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "This pack does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
                         }
                     }
                 }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update a pack by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Update a pack by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Pack",
+                        "name": "pack",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/packs.PackUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pack updated",
+                        "schema": {
+                            "$ref": "#/definitions/packs.Pack"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Payload",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "This pack does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a pack by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Delete a pack by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pack deleted",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.OkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "This pack does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/mypack/{id}/cost": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Sum the price x quantity of the items of a pack in the preferred currency of the user,\nwith a breakdown by original currency. Currencies without an exchange rate are listed\nin missing_rates and left out of the total.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Get the cost of a pack",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/packs.PackCost"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "This pack does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/mypack/{id}/duplicate": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Clone an existing pack (metadata + all pack contents) into a new pack\nowned by the same user. The new pack's name is prefixed with \"COPY - \",\nis_favorite is reset to false and sharing_code is not copied. The pack\nimage is intentionally not copied.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Duplicate a pack by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Pack duplicated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/packs.Pack"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "This pack does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/mypack/{id}/favorite": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Mark a pack as favorite. Only one pack per user can be favorite at a time (idempotent)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Favorite a pack by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pack favorited successfully",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.OkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "This pack does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Remove favorite status from a pack (idempotent)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Unfavorite a pack by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pack unfavorited successfully",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.OkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "This pack does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/mypack/{id}/image": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Upload or update an image for a pack. Only the pack owner can upload images.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pack Images"
+                ],
+                "summary": "Upload or update pack image",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Image file (JPEG, PNG, or WebP, max 5MB)",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Image uploaded successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or image",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Pack does not belong to user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Payload too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete the image for a pack. Only the pack owner can delete images.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pack Images"
+                ],
+                "summary": "Delete pack image",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Image deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pack ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Pack does not belong to user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/mypack/{id}/packcontent/{item_id}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update My pack content ID by Pack ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Update My pack content ID by Pack ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Pack Content",
+                        "name": "packcontent",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/packs.PackContentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pack Content updated",
+                        "schema": {
+                            "$ref": "#/definitions/packs.PackContent"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Body format",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "This pack does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a pack content by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Delete a pack content by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pack Item deleted",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.OkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "This pack does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/mypack/{id}/packcontents": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get pack content by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Get pack content by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack Content ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "preferred",
+                            "metric",
+                            "imperial"
+                        ],
+                        "type": "string",
+                        "description": "Add weight displays in this unit system",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pack Item",
+                        "schema": {
+                            "$ref": "#/definitions/packs.PackContent"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format or units",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "This pack does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/mypack/{id}/packcontents/order": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Sets the position of the listed pack contents to their index in the list. Items are\nordered by position within their category; the contents left out keep their position.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Reorder the items of a pack",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Pack content IDs in their new order",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/packs.PackContentOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/packs.PackContentWithItem"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload, duplicate or unknown pack content",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "This pack does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/mypack/{id}/planner": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Matches each wishlist item of the user with the heaviest owned item of the same category in\nthe pack that it would replace, and ranks them by the grams saved per unit of the preferred\ncurrency of the user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Plan wishlist purchases for a pack",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/packs.PurchasePlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "This pack does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/mypack/{id}/share": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Generate a sharing code for a pack to make it publicly accessible (idempotent)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Share a pack by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pack shared successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "This pack does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Remove the sharing code from a pack to make it private (idempotent)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Unshare a pack by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pack unshared successfully",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.OkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "This pack does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/mypack/{id}/suggestions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Suggests lighter items of the same category for each item of a pack of the user: unused\nitems of the user's inventory (in no pack and not retired) first, then items of other\nusers' shared packs, lightest first. Each suggestion gives the weight saved and the price\ndifference in the preferred currency of the user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Suggest lighter items for a pack",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Suggestions per item, 3 by default and 10 at most",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/packs.PackSuggestions"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format or limit",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "This pack does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/mypack/{id}/template": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Lists a pack of the user as a template other users can start from; its season,\nadventure and trail describe the template. Official templates stay official.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Mark a pack as a template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pack marked as template",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.OkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "This pack does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Removes a pack of the user from the templates (idempotent)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Unmark a pack as a template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pack removed from templates",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.OkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "This pack does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/mypack/{id}/trips": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Lists the trips logged on a pack of the user, most recent first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Get the trips of a pack",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/packs.Trip"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "This pack does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Logs a trip made with a pack of the user. The current pack contents are recorded as used,\nwhich adds up in the usage of each inventory item. end_date defaults to start_date,\nnights to the days between them and distance_km to the distance of the pack trail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Log a trip on a pack",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Trip",
+                        "name": "trip",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/packs.TripRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/packs.Trip"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "This pack does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/mypack/{id}/trips/{trip_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Deletes a trip logged on a pack of the user, and the item usage it recorded",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Delete a trip of a pack",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Trip ID",
+                        "name": "trip_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Trip deleted",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.OkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "This pack does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pack or trip not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/mypack/{id}/variants": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Lists the named variants of a pack of the user, with the totals of the pack contents they\nchange and their weight delta from the pack.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "List the variants of a pack",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/packs.PackVariants"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "This pack does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Creates a named variant of a pack of the user, such as \"rain forecast\", from changes to\nthe pack contents: add an item, remove an item, or update its quantity, worn or consumable\nfields. The changes apply to the current pack contents whenever the variant is read.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Create a variant of a pack",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Variant",
+                        "name": "variant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/packs.PackVariantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/packs.PackVariant"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload or changes",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "This pack does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A variant with this name already exists",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/mypack/{id}/variants/{variant_id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns a variant of a pack of the user with the pack contents it changes and their totals",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Get a variant of a pack",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Variant ID",
+                        "name": "variant_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/packs.PackVariant"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "This pack does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pack or variant not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Replaces the name and the changes of a variant of a pack of the user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Replace a variant of a pack",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Variant ID",
+                        "name": "variant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Variant",
+                        "name": "variant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/packs.PackVariantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/packs.PackVariant"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload or changes",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "This pack does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pack or variant not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A variant with this name already exists",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Deletes a variant of a pack of the user; the pack contents are left unchanged",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Delete a variant of a pack",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Variant ID",
+                        "name": "variant_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Variant deleted",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.OkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "This pack does not belong to you",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Pack or variant not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/mypacks": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get my packs",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Get My Packs",
+                "parameters": [
+                    {
+                        "enum": [
+                            "preferred",
+                            "metric",
+                            "imperial"
+                        ],
+                        "type": "string",
+                        "description": "Add weight displays in this unit system",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Packs",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/packs.Pack"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid units",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No pack found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/mypassword": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update the password of the current logged-in user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Accounts"
+                ],
+                "summary": "Update password",
+                "parameters": [
+                    {
+                        "description": "Current and New Password",
+                        "name": "password",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/accounts.PasswordUpdateInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Password updated",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.OkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/pack-options": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns the allowed values for season, trail, and adventure fields",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Get allowed values for pack metadata",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/packs.PackOptionsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/packs/{id}/image": {
+            "get": {
+                "description": "Get the image for a pack. Public packs are accessible without authentication.",
+                "produces": [
+                    "image/jpeg"
+                ],
+                "tags": [
+                    "Pack Images"
+                ],
+                "summary": "Get pack image",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pack ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized (for private packs)",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden (for private packs not owned by user)",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found or has no image",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/packs/{id}/items/{itemId}/image": {
+            "get": {
+                "description": "Get the image for an inventory item within a pack. Public packs are accessible without authentication.",
+                "produces": [
+                    "image/jpeg"
+                ],
+                "tags": [
+                    "Pack Images"
+                ],
+                "summary": "Get pack item image",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Item (Inventory) ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pack ID or item ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Pack not found, item not in pack, or has no image",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/products": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Autocompletes products of the community gear catalog for item creation: products whose\nbrand, model and variant contain every word of q, the most used first. Pass the ID of\nthe chosen product as product_id to POST /v1/myinventory.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Search products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search words, at least 2 characters",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of products, 10 by default and 50 at most",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/products.Product"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Query too short or invalid limit",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/products/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns a product with the number of inventory items linked to it and their median weight",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Get a product",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/products.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/templates": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Lists the pack templates, official ones first, optionally filtered by season,\nadventure and trail, ignoring case",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Get the pack templates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Season",
+                        "name": "season",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Adventure",
+                        "name": "adventure",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Trail",
+                        "name": "trail",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/packs.PackTemplate"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apitypes.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/pack-options": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns the allowed values for season, trail (grouped by continent/country), and adventure fields",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packs"
+                ],
+                "summary": "Get allowed values for pack metadata (V2)",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/packs.PackOptionsV2Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "accounts.Account": {
+            "type": "object",
+            "properties": {
+                "banner_position_y": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deletion_scheduled_at": {
+                    "description": "set while a deletion is pending",
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "firstname": {
+                    "type": "string"
+                },
+                "has_banner_image": {
+                    "type": "boolean"
+                },
+                "has_profile_image": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_position_x": {
+                    "type": "integer"
+                },
+                "image_position_y": {
+                    "type": "integer"
+                },
+                "instagram_url": {
+                    "type": "string"
+                },
+                "is_profile_public": {
+                    "type": "boolean"
+                },
+                "lastname": {
+                    "type": "string"
+                },
+                "pending_email": {
+                    "description": "new email awaiting confirmation",
+                    "type": "string"
+                },
+                "preferred_currency": {
+                    "type": "string"
+                },
+                "preferred_language": {
+                    "type": "string"
+                },
+                "preferred_unit_system": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                },
+                "youtube_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "accounts.AccountDeletionResponse": {
+            "type": "object",
+            "properties": {
+                "deletion_scheduled_at": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "accounts.AccountUpdateInput": {
+            "type": "object",
+            "required": [
+                "email",
+                "firstname",
+                "lastname"
+            ],
+            "properties": {
+                "banner_position_y": {
+                    "type": "integer"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "firstname": {
+                    "type": "string"
+                },
+                "image_position_x": {
+                    "type": "integer"
+                },
+                "image_position_y": {
+                    "type": "integer"
+                },
+                "instagram_url": {
+                    "type": "string"
+                },
+                "is_profile_public": {
+                    "type": "boolean"
+                },
+                "lastname": {
+                    "type": "string"
+                },
+                "preferred_currency": {
+                    "type": "string"
+                },
+                "preferred_language": {
+                    "type": "string"
+                },
+                "preferred_unit_system": {
+                    "type": "string"
+                },
+                "youtube_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "accounts.DeleteMyAccountInput": {
+            "type": "object",
+            "properties": {
+                "oidc": {
+                    "$ref": "#/definitions/accounts.OIDCReauthInput"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "accounts.ForgotPasswordInput": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "accounts.LoginInput": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "remember_me": {
+                    "description": "optional, defaults to false",
+                    "type": "boolean"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "accounts.OIDCAuthorizationResponse": {
+            "type": "object",
+            "properties": {
+                "authorization_url": {
+                    "type": "string"
+                },
+                "login_binding": {
+                    "description": "LoginBinding is kept by the browser, for example in session storage,\nand posted with the callback: only this browser can complete the login",
+                    "type": "string"
+                }
+            }
+        },
+        "accounts.OIDCCallbackInput": {
+            "type": "object",
+            "required": [
+                "code",
+                "login_binding",
+                "state"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "login_binding": {
+                    "description": "LoginBinding is the login_binding returned by /auth/oidc/authorize",
+                    "type": "string"
+                },
+                "remember_me": {
+                    "description": "optional, defaults to false",
+                    "type": "boolean"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "accounts.OIDCReauthInput": {
+            "type": "object",
+            "required": [
+                "code",
+                "login_binding",
+                "state"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "login_binding": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "accounts.PasswordUpdateInput": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "new_password": {
+                    "type": "string"
+                }
+            }
+        },
+        "accounts.RegisterInput": {
+            "type": "object",
+            "required": [
+                "email",
+                "firstname",
+                "lastname",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "firstname": {
+                    "type": "string"
+                },
+                "lastname": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "preferred_language": {
+                    "description": "PreferredLanguage is optional, the Accept-Language of the request is used by default",
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "accounts.ResendConfirmEmailInput": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "accounts.RestoreAccountInput": {
+            "type": "object",
+            "properties": {
+                "oidc": {
+                    "$ref": "#/definitions/accounts.OIDCReauthInput"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "apitypes.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "apitypes.OkResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "categories.Category": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "item_count": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "categories.CategoryMergeRequest": {
+            "type": "object",
+            "required": [
+                "source_ids",
+                "target_id"
+            ],
+            "properties": {
+                "source_ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "target_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "categories.CategoryOrderRequest": {
+            "type": "object",
+            "required": [
+                "category_ids"
+            ],
+            "properties": {
+                "category_ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "categories.CategoryUpdateRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "customfields.Field": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "item_count": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "text",
+                        "number",
+                        "boolean",
+                        "enum"
+                    ]
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "customfields.FieldCreateRequest": {
+            "type": "object",
+            "required": [
+                "key",
+                "name",
+                "type"
+            ],
+            "properties": {
+                "key": {
+                    "type": "string",
+                    "example": "temp_rating"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Temperature rating"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "text",
+                        "number",
+                        "boolean",
+                        "enum"
+                    ]
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "°C"
+                }
+            }
+        },
+        "customfields.FieldUpdateRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "customfields.Values": {
+            "type": "object",
+            "additionalProperties": {}
+        },
+        "inventories.BatchMergeInventoryRequest": {
+            "type": "object",
+            "required": [
+                "category",
+                "image_source",
+                "item_name",
+                "source_item_ids",
+                "target_item_id"
+            ],
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "image_source": {
+                    "type": "string",
+                    "enum": [
+                        "source",
+                        "target",
+                        "none"
+                    ]
+                },
+                "image_source_item_id": {
+                    "type": "integer"
+                },
+                "item_name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "source_item_ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "target_item_id": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "weight": {
+                    "type": "integer"
+                }
+            }
+        },
+        "inventories.DuplicateGroup": {
+            "type": "object",
+            "properties": {
+                "confidence": {
+                    "type": "number"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/inventories.Inventory"
+                    }
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "suggested_target_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "inventories.Inventory": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "condition": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "custom_fields": {
+                    "description": "CustomFields holds the values of the custom fields of the owner by key",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/customfields.Values"
+                        }
+                    ]
+                },
+                "description": {
+                    "type": "string"
+                },
+                "has_image": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "item_name": {
+                    "type": "string"
+                },
+                "maintenance_interval_days": {
+                    "type": "integer"
+                },
+                "pack_count": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "price_in_preferred_currency": {
+                    "description": "PriceInPreferredCurrency is Price converted to the preferred currency of\nthe owner; omitted when no exchange rate is known",
+                    "type": "integer"
+                },
+                "product_id": {
+                    "description": "ProductID links the item to a product of the community catalog, whose\nmedian measured weight is ProductMedianWeight",
+                    "type": "integer"
+                },
+                "product_median_weight": {
+                    "type": "integer"
+                },
+                "purchase_date": {
+                    "description": "PurchaseDate is formatted as YYYY-MM-DD",
+                    "type": "string"
+                },
+                "retired": {
+                    "description": "Retired items stay in their packs but are hidden from GET /myinventory by default",
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "weight": {
+                    "type": "integer"
+                },
+                "weight_display": {
+                    "description": "WeightDisplay is Weight in the unit system asked with ?units=",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/units.Weight"
+                        }
+                    ]
+                },
+                "wishlist": {
+                    "description": "Wishlist items are planned purchases: they can be put in packs but are\nlisted by GET /myinventory only with ?wishlist=true",
+                    "type": "boolean"
+                }
+            }
+        },
+        "inventories.InventoryCreateRequest": {
+            "type": "object",
+            "required": [
+                "category",
+                "item_name"
+            ],
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "condition": {
+                    "type": "string",
+                    "enum": [
+                        "new",
+                        "good",
+                        "fair",
+                        "poor",
+                        "broken"
+                    ]
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "custom_fields": {
+                    "description": "CustomFields replaces the custom field values of the item when set",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/customfields.Values"
+                        }
+                    ]
+                },
+                "description": {
+                    "type": "string"
+                },
+                "item_name": {
+                    "type": "string"
+                },
+                "maintenance_interval_days": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "description": "ProductID links the item to a catalog product; a zero weight and an\nempty URL are taken from the product",
+                    "type": "integer"
+                },
+                "purchase_date": {
+                    "type": "string",
+                    "example": "2024-05-01"
+                },
+                "retired": {
+                    "type": "boolean"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "weight": {
+                    "type": "integer"
+                },
+                "wishlist": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "inventories.InventoryImportErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/inventories.InventoryImportRowError"
+                    }
+                }
+            }
+        },
+        "inventories.InventoryImportResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/inventories.InventoryImportRowError"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "inventories.InventoryImportRowError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "integer"
+                }
+            }
+        },
+        "inventories.InventoryTransferItem": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "custom_fields": {
+                    "description": "CustomFields are exported as field.\u003ckey\u003e columns in CSV files",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/customfields.Values"
+                        }
+                    ]
+                },
+                "description": {
+                    "type": "string"
+                },
+                "external_key": {
+                    "type": "string"
+                },
+                "item_name": {
+                    "type": "string"
+                },
+                "pack_count": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "weight": {
+                    "type": "integer"
+                }
+            }
+        },
+        "inventories.InventoryUpdateRequest": {
+            "type": "object",
+            "required": [
+                "category",
+                "item_name"
+            ],
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "condition": {
+                    "type": "string",
+                    "enum": [
+                        "new",
+                        "good",
+                        "fair",
+                        "poor",
+                        "broken"
+                    ]
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "custom_fields": {
+                    "description": "CustomFields replaces the custom field values of the item when set",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/customfields.Values"
+                        }
+                    ]
+                },
+                "description": {
+                    "type": "string"
+                },
+                "item_name": {
+                    "type": "string"
+                },
+                "maintenance_interval_days": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "description": "ProductID links the item to a catalog product when set; 0 unlinks it",
+                    "type": "integer"
+                },
+                "purchase_date": {
+                    "type": "string",
+                    "example": "2024-05-01"
+                },
+                "retired": {
+                    "type": "boolean"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "weight": {
+                    "type": "integer"
+                },
+                "wishlist": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "inventories.ItemUsage": {
+            "type": "object",
+            "properties": {
+                "cost_per_km": {
+                    "type": "integer"
+                },
+                "cost_per_night": {
+                    "type": "integer"
+                },
+                "cost_per_use": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "distance_km": {
+                    "type": "integer"
+                },
+                "first_used": {
+                    "type": "string"
+                },
+                "item_id": {
+                    "type": "integer"
+                },
+                "last_used": {
+                    "type": "string"
+                },
+                "nights": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "trips": {
+                    "type": "integer"
+                }
+            }
+        },
+        "inventories.MaintenanceDue": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "days_overdue": {
+                    "type": "integer"
+                },
+                "due_on": {
+                    "type": "string"
+                },
+                "item_id": {
+                    "type": "integer"
+                },
+                "item_name": {
+                    "type": "string"
+                },
+                "last_maintenance": {
+                    "type": "string"
+                },
+                "maintenance_interval_days": {
+                    "type": "integer"
+                }
+            }
+        },
+        "inventories.MaintenanceEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "cost": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "item_id": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "performed_on": {
+                    "type": "string"
+                }
+            }
+        },
+        "inventories.MaintenanceEntryRequest": {
+            "type": "object",
+            "required": [
+                "action",
+                "performed_on"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "cost": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "performed_on": {
+                    "type": "string",
+                    "example": "2024-05-01"
+                }
+            }
+        },
+        "inventories.MergeInventoryRequest": {
+            "type": "object",
+            "required": [
+                "category",
+                "image_source",
+                "item_name",
+                "source_item_id",
+                "target_item_id"
+            ],
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "image_source": {
+                    "type": "string",
+                    "enum": [
+                        "source",
+                        "target",
+                        "none"
+                    ]
+                },
+                "item_name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "source_item_id": {
+                    "type": "integer"
+                },
+                "target_item_id": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "weight": {
+                    "type": "integer"
+                }
+            }
+        },
+        "packs.CurrencyAmount": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                }
+            }
+        },
+        "packs.ExternalPackItem": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "consumable": {
+                    "type": "boolean"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "desc": {
+                    "type": "string"
+                },
+                "item_name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "optional": {
+                    "type": "boolean"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "qty": {
+                    "type": "integer"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "weight": {
+                    "type": "integer"
+                },
+                "worn": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "packs.FromTemplateRequest": {
+            "type": "object",
+            "properties": {
+                "pack_description": {
+                    "type": "string"
+                },
+                "pack_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "packs.FromTemplateResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/packs.TemplateItemMatch"
+                    }
+                },
+                "pack_id": {
+                    "type": "integer"
+                },
+                "placeholders": {
+                    "type": "integer"
+                },
+                "reused": {
+                    "type": "integer"
+                }
+            }
+        },
+        "packs.ImportCandidate": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "inventory_id": {
+                    "type": "integer"
+                },
+                "item_name": {
+                    "type": "string"
+                },
+                "similarity": {
+                    "type": "number"
+                },
+                "weight": {
+                    "type": "integer"
+                }
+            }
+        },
+        "packs.ImportExternalPackResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "pack_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "packs.ImportFromURLRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "packs.ImportPackRequest": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/packs.ExternalPackItem"
+                    }
+                },
+                "pack_description": {
+                    "type": "string"
+                },
+                "pack_name": {
+                    "type": "string"
+                }
             }
-        }
-    },
-    "definitions": {
-        "accounts.Account": {
+        },
+        "packs.ImportPlanItem": {
             "type": "object",
             "properties": {
-                "banner_position_y": {
+                "action": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "consumable": {
+                    "type": "boolean"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "desc": {
+                    "type": "string"
+                },
+                "inventory_id": {
                     "type": "integer"
                 },
-                "created_at": {
+                "item_name": {
                     "type": "string"
                 },
-                "email": {
+                "notes": {
                     "type": "string"
                 },
-                "firstname": {
+                "optional": {
+                    "type": "boolean"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "qty": {
+                    "type": "integer"
+                },
+                "unit": {
                     "type": "string"
                 },
-                "has_banner_image": {
+                "url": {
+                    "type": "string"
+                },
+                "weight": {
+                    "type": "integer"
+                },
+                "worn": {
                     "type": "boolean"
+                }
+            }
+        },
+        "packs.ImportPlanRequest": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/packs.ImportPlanItem"
+                    }
                 },
-                "has_profile_image": {
+                "pack_description": {
+                    "type": "string"
+                },
+                "pack_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "packs.ImportPreviewItem": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "candidates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/packs.ImportCandidate"
+                    }
+                },
+                "category": {
+                    "type": "string"
+                },
+                "consumable": {
                     "type": "boolean"
                 },
-                "id": {
+                "currency": {
+                    "type": "string"
+                },
+                "desc": {
+                    "type": "string"
+                },
+                "inventory_id": {
                     "type": "integer"
                 },
-                "image_position_x": {
+                "item_name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "optional": {
+                    "type": "boolean"
+                },
+                "price": {
                     "type": "integer"
                 },
-                "image_position_y": {
+                "qty": {
                     "type": "integer"
                 },
-                "instagram_url": {
+                "unit": {
                     "type": "string"
                 },
-                "is_profile_public": {
+                "url": {
+                    "type": "string"
+                },
+                "weight": {
+                    "type": "integer"
+                },
+                "worn": {
                     "type": "boolean"
+                }
+            }
+        },
+        "packs.ImportPreviewResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/packs.ImportPreviewItem"
+                    }
                 },
-                "lastname": {
+                "pack_description": {
                     "type": "string"
                 },
-                "preferred_currency": {
+                "pack_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "packs.ImportSpreadsheetErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
                     "type": "string"
                 },
-                "preferred_unit_system": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/packs.SpreadsheetRowError"
+                    }
+                }
+            }
+        },
+        "packs.ImportSpreadsheetResponse": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/packs.SpreadsheetRowError"
+                    }
+                },
+                "imported": {
+                    "type": "integer"
+                },
+                "message": {
                     "type": "string"
                 },
-                "role": {
+                "pack_id": {
+                    "type": "integer"
+                },
+                "preview": {
+                    "description": "Preview is set instead of PackID on dry runs",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/packs.ImportPreviewResponse"
+                        }
+                    ]
+                }
+            }
+        },
+        "packs.ItemSuggestions": {
+            "type": "object",
+            "properties": {
+                "category": {
                     "type": "string"
                 },
-                "status": {
+                "inventory_id": {
+                    "type": "integer"
+                },
+                "item_name": {
                     "type": "string"
                 },
-                "updated_at": {
+                "pack_content_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "suggestions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/packs.Suggestion"
+                    }
+                },
+                "weight": {
+                    "type": "integer"
+                }
+            }
+        },
+        "packs.Pack": {
+            "type": "object",
+            "properties": {
+                "adventure": {
                     "type": "string"
                 },
-                "username": {
+                "created_at": {
+                    "type": "string"
+                },
+                "has_image": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_favorite": {
+                    "type": "boolean"
+                },
+                "pack_description": {
+                    "type": "string"
+                },
+                "pack_items_count": {
+                    "type": "integer"
+                },
+                "pack_name": {
+                    "type": "string"
+                },
+                "pack_weight": {
+                    "type": "integer"
+                },
+                "pack_weight_display": {
+                    "description": "PackWeightDisplay is PackWeight in the unit system asked with ?units=",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/units.Weight"
+                        }
+                    ]
+                },
+                "season": {
+                    "type": "string"
+                },
+                "sharing_code": {
                     "type": "string"
                 },
-                "youtube_url": {
-                    "type": "string"
+                "template": {
+                    "description": "Template is set for the packs listed as templates, official ones being\nmarked by admins",
+                    "type": "string",
+                    "enum": [
+                        "user",
+                        "official"
+                    ]
+                },
+                "trail": {
+                    "type": "string"
+                },
+                "trail_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "wishlist_weight": {
+                    "description": "WishlistWeight is the weight of the planned purchases put in the pack,\nleft out of PackWeight and PackItemsCount",
+                    "type": "integer"
                 }
             }
         },
-        "accounts.AccountUpdateInput": {
+        "packs.PackContent": {
             "type": "object",
-            "required": [
-                "email",
-                "firstname",
-                "lastname"
-            ],
             "properties": {
-                "banner_position_y": {
-                    "type": "integer"
-                },
-                "email": {
-                    "type": "string"
+                "consumable": {
+                    "type": "boolean"
                 },
-                "firstname": {
+                "created_at": {
                     "type": "string"
                 },
-                "image_position_x": {
+                "id": {
                     "type": "integer"
                 },
-                "image_position_y": {
+                "item_id": {
                     "type": "integer"
                 },
-                "instagram_url": {
+                "notes": {
                     "type": "string"
                 },
-                "is_profile_public": {
+                "optional": {
+                    "description": "Optional items are listed but left out of the totals",
                     "type": "boolean"
                 },
-                "lastname": {
-                    "type": "string"
+                "pack_id": {
+                    "type": "integer"
                 },
-                "preferred_currency": {
-                    "type": "string"
+                "position": {
+                    "description": "Position orders the item within its category; items without one come\nafter the others, by name",
+                    "type": "integer"
                 },
-                "preferred_unit_system": {
-                    "type": "string"
+                "quantity": {
+                    "type": "integer"
                 },
-                "youtube_url": {
+                "updated_at": {
                     "type": "string"
+                },
+                "worn": {
+                    "type": "boolean"
                 }
             }
         },
-        "accounts.ForgotPasswordInput": {
+        "packs.PackContentOrderRequest": {
             "type": "object",
             "required": [
-                "email"
+                "pack_content_ids"
             ],
             "properties": {
-                "email": {
-                    "type": "string"
+                "pack_content_ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
                 }
             }
         },
-        "accounts.LoginInput": {
+        "packs.PackContentRequest": {
             "type": "object",
             "required": [
-                "password",
-                "username"
+                "inventory_id",
+                "quantity"
             ],
             "properties": {
-                "password": {
-                    "type": "string"
+                "consumable": {
+                    "type": "boolean"
                 },
-                "remember_me": {
-                    "description": "optional, defaults to false",
+                "inventory_id": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "description": "Notes, Position and Optional are kept on update when omitted",
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "rent on site"
+                },
+                "optional": {
                     "type": "boolean"
                 },
-                "username": {
-                    "type": "string"
+                "position": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "quantity": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "worn": {
+                    "type": "boolean"
                 }
             }
         },
-        "accounts.PasswordUpdateInput": {
+        "packs.PackContentWithItem": {
             "type": "object",
-            "required": [
-                "current_password",
-                "new_password"
-            ],
             "properties": {
-                "current_password": {
+                "category": {
                     "type": "string"
                 },
-                "new_password": {
+                "consumable": {
+                    "type": "boolean"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "has_image": {
+                    "type": "boolean"
+                },
+                "inventory_id": {
+                    "type": "integer"
+                },
+                "item_description": {
+                    "type": "string"
+                },
+                "item_name": {
+                    "type": "string"
+                },
+                "item_url": {
+                    "type": "string"
+                },
+                "notes": {
                     "type": "string"
+                },
+                "optional": {
+                    "description": "Optional items are listed but left out of the totals",
+                    "type": "boolean"
+                },
+                "pack_content_id": {
+                    "type": "integer"
+                },
+                "pack_id": {
+                    "type": "integer"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "price_in_preferred_currency": {
+                    "description": "PriceInPreferredCurrency is Price converted to the preferred currency of\nthe requester; omitted when no exchange rate is known",
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "weight": {
+                    "type": "integer"
+                },
+                "weight_display": {
+                    "description": "WeightDisplay is Weight in the unit system asked with ?units=",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/units.Weight"
+                        }
+                    ]
+                },
+                "wishlist": {
+                    "description": "Wishlist is set for the planned purchases put in the pack",
+                    "type": "boolean"
+                },
+                "worn": {
+                    "type": "boolean"
                 }
             }
         },
-        "accounts.RegisterInput": {
+        "packs.PackCost": {
             "type": "object",
-            "required": [
-                "email",
-                "firstname",
-                "lastname",
-                "password",
-                "username"
-            ],
             "properties": {
-                "email": {
-                    "type": "string"
+                "by_currency": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/packs.CurrencyAmount"
+                    }
                 },
-                "firstname": {
+                "currency": {
                     "type": "string"
                 },
-                "lastname": {
-                    "type": "string"
+                "missing_rates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "password": {
-                    "type": "string"
+                "pack_id": {
+                    "type": "integer"
                 },
-                "username": {
-                    "type": "string"
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "accounts.ResendConfirmEmailInput": {
+        "packs.PackCreateRequest": {
             "type": "object",
             "required": [
-                "email"
+                "pack_name"
             ],
             "properties": {
-                "email": {
+                "adventure": {
+                    "type": "string"
+                },
+                "pack_description": {
+                    "type": "string"
+                },
+                "pack_name": {
+                    "type": "string"
+                },
+                "season": {
+                    "type": "string"
+                },
+                "trail": {
                     "type": "string"
                 }
             }
         },
-        "apitypes.ErrorResponse": {
+        "packs.PackOptionsResponse": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string"
+                "adventures": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "seasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "trails": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "apitypes.OkResponse": {
+        "packs.PackOptionsV2Response": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string"
+                "adventures": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "seasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "trails": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "object",
+                        "additionalProperties": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/trails.TrailSummary"
+                            }
+                        }
+                    }
                 }
             }
         },
-        "inventories.Inventory": {
+        "packs.PackSuggestions": {
             "type": "object",
             "properties": {
-                "category": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
                 "currency": {
                     "type": "string"
                 },
-                "description": {
-                    "type": "string"
-                },
-                "has_image": {
-                    "type": "boolean"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "item_name": {
-                    "type": "string"
-                },
-                "pack_count": {
-                    "type": "integer"
-                },
-                "price": {
-                    "type": "integer"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "url": {
-                    "type": "string"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/packs.ItemSuggestions"
+                    }
                 },
-                "user_id": {
+                "pack_id": {
                     "type": "integer"
                 },
-                "weight": {
+                "weight_saved": {
                     "type": "integer"
                 }
             }
         },
-        "inventories.InventoryCreateRequest": {
+        "packs.PackTemplate": {
             "type": "object",
-            "required": [
-                "category",
-                "item_name"
-            ],
             "properties": {
-                "category": {
+                "adventure": {
                     "type": "string"
                 },
-                "currency": {
-                    "type": "string"
+                "id": {
+                    "type": "integer"
                 },
-                "description": {
+                "pack_description": {
                     "type": "string"
                 },
-                "item_name": {
+                "pack_items_count": {
+                    "type": "integer"
+                },
+                "pack_name": {
                     "type": "string"
                 },
-                "price": {
+                "pack_weight": {
                     "type": "integer"
                 },
-                "url": {
+                "season": {
                     "type": "string"
                 },
-                "weight": {
-                    "type": "integer"
+                "template": {
+                    "type": "string",
+                    "enum": [
+                        "user",
+                        "official"
+                    ]
+                },
+                "trail": {
+                    "type": "string"
                 }
             }
         },
-        "inventories.InventoryUpdateRequest": {
+        "packs.PackTotals": {
             "type": "object",
-            "required": [
-                "category",
-                "item_name"
-            ],
             "properties": {
-                "category": {
-                    "type": "string"
-                },
-                "currency": {
-                    "type": "string"
+                "base_weight": {
+                    "type": "integer"
                 },
-                "description": {
-                    "type": "string"
+                "consumable_weight": {
+                    "type": "integer"
                 },
-                "item_name": {
-                    "type": "string"
+                "items_count": {
+                    "type": "integer"
                 },
-                "price": {
+                "total_weight": {
                     "type": "integer"
                 },
-                "url": {
-                    "type": "string"
+                "wishlist_weight": {
+                    "description": "WishlistWeight is the weight of the wishlist items, which are not owned\ngear and are left out of the other totals",
+                    "type": "integer"
                 },
-                "weight": {
+                "worn_weight": {
                     "type": "integer"
                 }
             }
         },
-        "inventories.MergeInventoryRequest": {
+        "packs.PackUpdateRequest": {
             "type": "object",
             "required": [
-                "category",
-                "image_source",
-                "item_name",
-                "source_item_id",
-                "target_item_id"
+                "pack_name"
             ],
             "properties": {
-                "category": {
+                "adventure": {
                     "type": "string"
                 },
-                "currency": {
+                "pack_description": {
                     "type": "string"
                 },
-                "description": {
+                "pack_name": {
                     "type": "string"
                 },
-                "image_source": {
-                    "type": "string",
-                    "enum": [
-                        "source",
-                        "target",
-                        "none"
-                    ]
-                },
-                "item_name": {
+                "season": {
                     "type": "string"
                 },
-                "price": {
-                    "type": "integer"
-                },
-                "source_item_id": {
-                    "type": "integer"
-                },
-                "target_item_id": {
-                    "type": "integer"
-                },
-                "url": {
+                "trail": {
                     "type": "string"
-                },
-                "weight": {
-                    "type": "integer"
                 }
             }
         },
-        "packs.ExternalPackItem": {
+        "packs.PackVariant": {
             "type": "object",
             "properties": {
-                "category": {
-                    "type": "string"
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/packs.VariantChange"
+                    }
                 },
-                "consumable": {
-                    "type": "boolean"
+                "contents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/packs.PackContentWithItem"
+                    }
                 },
-                "currency": {
+                "created_at": {
                     "type": "string"
                 },
-                "desc": {
-                    "type": "string"
+                "id": {
+                    "type": "integer"
                 },
-                "item_name": {
+                "name": {
                     "type": "string"
                 },
-                "price": {
-                    "type": "integer"
-                },
-                "qty": {
+                "pack_id": {
                     "type": "integer"
                 },
-                "unit": {
-                    "type": "string"
+                "totals": {
+                    "$ref": "#/definitions/packs.PackTotals"
                 },
-                "url": {
+                "updated_at": {
                     "type": "string"
                 },
-                "weight": {
+                "weight_delta": {
                     "type": "integer"
-                },
-                "worn": {
-                    "type": "boolean"
                 }
             }
         },
-        "packs.ImportExternalPackResponse": {
+        "packs.PackVariantRequest": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
-                "message": {
-                    "type": "string"
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/packs.VariantChange"
+                    }
                 },
-                "pack_id": {
-                    "type": "integer"
+                "name": {
+                    "type": "string",
+                    "example": "rain forecast"
                 }
             }
         },
-        "packs.ImportFromURLRequest": {
+        "packs.PackVariants": {
             "type": "object",
-            "required": [
-                "url"
-            ],
             "properties": {
-                "url": {
-                    "type": "string"
+                "pack_id": {
+                    "type": "integer"
+                },
+                "totals": {
+                    "$ref": "#/definitions/packs.PackTotals"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/packs.PackVariant"
+                    }
                 }
             }
         },
-        "packs.ImportPackRequest": {
+        "packs.ParseExternalPackResponse": {
             "type": "object",
             "properties": {
                 "items": {
@@ -3471,163 +8031,93 @@ const docTemplate = `{
                 }
             }
         },
-        "packs.Pack": {
+        "packs.PlannedPurchase": {
             "type": "object",
             "properties": {
-                "adventure": {
+                "category": {
                     "type": "string"
                 },
-                "created_at": {
+                "currency": {
                     "type": "string"
                 },
-                "has_image": {
-                    "type": "boolean"
+                "grams_per_unit": {
+                    "type": "number"
                 },
-                "id": {
+                "inventory_id": {
                     "type": "integer"
                 },
-                "is_favorite": {
-                    "type": "boolean"
-                },
-                "pack_description": {
+                "item_name": {
                     "type": "string"
                 },
-                "pack_items_count": {
+                "price": {
                     "type": "integer"
                 },
-                "pack_name": {
-                    "type": "string"
-                },
-                "pack_weight": {
+                "price_in_preferred_currency": {
                     "type": "integer"
                 },
-                "season": {
-                    "type": "string"
-                },
-                "sharing_code": {
-                    "type": "string"
-                },
-                "trail": {
-                    "type": "string"
+                "replaces": {
+                    "$ref": "#/definitions/packs.ReplacedItem"
                 },
-                "trail_id": {
+                "weight": {
                     "type": "integer"
                 },
-                "updated_at": {
-                    "type": "string"
-                },
-                "user_id": {
+                "weight_saved": {
                     "type": "integer"
                 }
             }
         },
-        "packs.PackContent": {
+        "packs.PurchasePlan": {
             "type": "object",
             "properties": {
-                "consumable": {
-                    "type": "boolean"
-                },
-                "created_at": {
+                "currency": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
-                },
-                "item_id": {
-                    "type": "integer"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/packs.PlannedPurchase"
+                    }
                 },
                 "pack_id": {
                     "type": "integer"
-                },
-                "quantity": {
-                    "type": "integer"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "worn": {
-                    "type": "boolean"
-                }
-            }
-        },
-        "packs.PackContentRequest": {
-            "type": "object",
-            "required": [
-                "inventory_id",
-                "quantity"
-            ],
-            "properties": {
-                "consumable": {
-                    "type": "boolean"
-                },
-                "inventory_id": {
-                    "type": "integer"
-                },
-                "quantity": {
-                    "type": "integer",
-                    "minimum": 1
-                },
-                "worn": {
-                    "type": "boolean"
                 }
             }
         },
-        "packs.PackContentWithItem": {
-            "type": "object",
-            "properties": {
-                "category": {
-                    "type": "string"
-                },
-                "consumable": {
-                    "type": "boolean"
-                },
-                "currency": {
-                    "type": "string"
-                },
-                "has_image": {
-                    "type": "boolean"
-                },
+        "packs.ReplacedItem": {
+            "type": "object",
+            "properties": {
                 "inventory_id": {
                     "type": "integer"
                 },
-                "item_description": {
-                    "type": "string"
-                },
                 "item_name": {
                     "type": "string"
                 },
-                "item_url": {
-                    "type": "string"
-                },
                 "pack_content_id": {
                     "type": "integer"
                 },
-                "pack_id": {
-                    "type": "integer"
-                },
-                "price": {
-                    "type": "integer"
-                },
                 "quantity": {
                     "type": "integer"
                 },
                 "weight": {
                     "type": "integer"
-                },
-                "worn": {
-                    "type": "boolean"
                 }
             }
         },
-        "packs.PackCreateRequest": {
+        "packs.SharedPackInfo": {
             "type": "object",
-            "required": [
-                "pack_name"
-            ],
             "properties": {
                 "adventure": {
                     "type": "string"
                 },
+                "created_at": {
+                    "type": "string"
+                },
+                "has_image": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
                 "pack_description": {
                     "type": "string"
                 },
@@ -3642,138 +8132,215 @@ const docTemplate = `{
                 }
             }
         },
-        "packs.PackOptionsResponse": {
+        "packs.SharedPackResponse": {
             "type": "object",
             "properties": {
-                "adventures": {
+                "contents": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/packs.PackContentWithItem"
                     }
                 },
-                "seasons": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "pack": {
+                    "$ref": "#/definitions/packs.SharedPackInfo"
+                }
+            }
+        },
+        "packs.SpreadsheetRowError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
                 },
-                "trails": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "row": {
+                    "type": "integer"
                 }
             }
         },
-        "packs.PackOptionsV2Response": {
+        "packs.Suggestion": {
             "type": "object",
             "properties": {
-                "adventures": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "currency": {
+                    "type": "string"
                 },
-                "seasons": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "inventory_id": {
+                    "type": "integer"
                 },
-                "trails": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "object",
-                        "additionalProperties": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/trails.TrailSummary"
-                            }
-                        }
-                    }
+                "item_name": {
+                    "type": "string"
+                },
+                "pack_count": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "price_difference": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string",
+                    "enum": [
+                        "inventory",
+                        "community"
+                    ]
+                },
+                "url": {
+                    "type": "string"
+                },
+                "weight": {
+                    "type": "integer"
+                },
+                "weight_saved": {
+                    "type": "integer"
                 }
             }
         },
-        "packs.PackUpdateRequest": {
+        "packs.TemplateItemMatch": {
             "type": "object",
-            "required": [
-                "pack_name"
-            ],
             "properties": {
-                "adventure": {
+                "category": {
                     "type": "string"
                 },
-                "pack_description": {
+                "inventory_id": {
+                    "type": "integer"
+                },
+                "item_name": {
                     "type": "string"
                 },
-                "pack_name": {
+                "placeholder": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "packs.Trip": {
+            "type": "object",
+            "properties": {
+                "created_at": {
                     "type": "string"
                 },
-                "season": {
+                "distance_km": {
+                    "type": "integer"
+                },
+                "end_date": {
                     "type": "string"
                 },
-                "trail": {
+                "id": {
+                    "type": "integer"
+                },
+                "items_count": {
+                    "type": "integer"
+                },
+                "nights": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "pack_id": {
+                    "type": "integer"
+                },
+                "start_date": {
                     "type": "string"
                 }
             }
         },
-        "packs.ParseExternalPackResponse": {
+        "packs.TripRequest": {
             "type": "object",
+            "required": [
+                "start_date"
+            ],
             "properties": {
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/packs.ExternalPackItem"
-                    }
+                "distance_km": {
+                    "type": "integer"
                 },
-                "pack_description": {
-                    "type": "string"
+                "end_date": {
+                    "type": "string",
+                    "example": "2024-07-04"
                 },
-                "pack_name": {
+                "nights": {
+                    "type": "integer"
+                },
+                "notes": {
                     "type": "string"
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2024-07-01"
                 }
             }
         },
-        "packs.SharedPackInfo": {
+        "packs.VariantChange": {
             "type": "object",
+            "required": [
+                "action",
+                "item_id"
+            ],
             "properties": {
-                "adventure": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "add",
+                        "remove",
+                        "update"
+                    ]
+                },
+                "consumable": {
+                    "type": "boolean"
+                },
+                "item_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "worn": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "products.Product": {
+            "type": "object",
+            "properties": {
+                "brand": {
                     "type": "string"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "has_image": {
-                    "type": "boolean"
-                },
                 "id": {
                     "type": "integer"
                 },
-                "pack_description": {
+                "item_count": {
+                    "type": "integer"
+                },
+                "median_weight": {
+                    "type": "integer"
+                },
+                "model": {
                     "type": "string"
                 },
-                "pack_name": {
+                "name": {
                     "type": "string"
                 },
-                "season": {
+                "source": {
+                    "type": "string",
+                    "enum": [
+                        "admin",
+                        "community"
+                    ]
+                },
+                "updated_at": {
                     "type": "string"
                 },
-                "trail": {
+                "url": {
                     "type": "string"
-                }
-            }
-        },
-        "packs.SharedPackResponse": {
-            "type": "object",
-            "properties": {
-                "contents": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/packs.PackContentWithItem"
-                    }
                 },
-                "pack": {
-                    "$ref": "#/definitions/packs.SharedPackInfo"
+                "variant": {
+                    "type": "string"
+                },
+                "weight": {
+                    "type": "integer"
                 }
             }
         },
@@ -3859,6 +8426,75 @@ const docTemplate = `{
                 }
             }
         },
+        "security.AuditEvent": {
+            "type": "object",
+            "properties": {
+                "event_type": {
+                    "$ref": "#/definitions/security.AuditEventType"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "remember_me": {
+                    "type": "boolean"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "security.AuditEventType": {
+            "type": "string",
+            "enum": [
+                "login_success",
+                "login_failed",
+                "refresh_success",
+                "refresh_failed",
+                "logout_success",
+                "logout_all_success",
+                "refresh_token_reuse_detected",
+                "rate_limit_exceeded",
+                "login_throttled",
+                "account_locked",
+                "account_unlocked",
+                "oidc_account_linked",
+                "account_deletion_requested",
+                "account_restored",
+                "email_changed"
+            ],
+            "x-enum-varnames": [
+                "EventLoginSuccess",
+                "EventLoginFailed",
+                "EventRefreshSuccess",
+                "EventRefreshFailed",
+                "EventLogout",
+                "EventLogoutAll",
+                "EventRefreshReuse",
+                "EventRateLimitExceeded",
+                "EventLoginThrottled",
+                "EventAccountLocked",
+                "EventAccountUnlocked",
+                "EventOIDCAccountLinked",
+                "EventAccountDeletion",
+                "EventAccountRestored",
+                "EventEmailChanged"
+            ]
+        },
         "security.LogoutAllResponse": {
             "type": "object",
             "properties": {
@@ -3935,6 +8571,20 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "units.Weight": {
+            "type": "object",
+            "properties": {
+                "formatted": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
         }
     }
 }`
@@ -9,12 +9,19 @@ import (
 
 	_ "github.com/Angak0k/pimpmypack/api-doc"
 	"github.com/Angak0k/pimpmypack/pkg/accounts"
+	"github.com/Angak0k/pimpmypack/pkg/categories"
 	"github.com/Angak0k/pimpmypack/pkg/config"
+	"github.com/Angak0k/pimpmypack/pkg/currencies"
+	"github.com/Angak0k/pimpmypack/pkg/customfields"
 	"github.com/Angak0k/pimpmypack/pkg/database"
 	"github.com/Angak0k/pimpmypack/pkg/database/seed"
+	"github.com/Angak0k/pimpmypack/pkg/dataexport"
+	"github.com/Angak0k/pimpmypack/pkg/i18n"
 	"github.com/Angak0k/pimpmypack/pkg/images"
 	"github.com/Angak0k/pimpmypack/pkg/inventories"
+	"github.com/Angak0k/pimpmypack/pkg/mailer"
 	"github.com/Angak0k/pimpmypack/pkg/packs"
+	"github.com/Angak0k/pimpmypack/pkg/products"
 	"github.com/Angak0k/pimpmypack/pkg/profiles"
 	"github.com/Angak0k/pimpmypack/pkg/security"
 	"github.com/Angak0k/pimpmypack/pkg/trails"
@@ -29,6 +36,8 @@ const (
 
 	// maxImportBodyBytes bounds request bodies on pack import/parse endpoints (1 MiB)
 	maxImportBodyBytes = 1 << 20
+	// maxSpreadsheetBodyBytes bounds spreadsheet imports, XLSX workbooks being larger (5 MiB)
+	maxSpreadsheetBodyBytes = 5 << 20
 	// defaultMaxBodyBytes bounds every request body unless a stricter per-route limit
 	// applies (6 MiB: the largest legitimate payload is a 5 MB image upload plus
 	// multipart overhead)
@@ -42,6 +51,12 @@ func initApp() error {
 		return fmt.Errorf("error loading .env file or environment variable : %w", err)
 	}
 
+	// init JWT signing and verification keys
+	err = security.LoadJWTKeys()
+	if err != nil {
+		return fmt.Errorf("error loading JWT keys : %w", err)
+	}
+
 	// init DB
 	err = database.Initialization()
 	if err != nil {
@@ -89,36 +104,61 @@ func main() {
 	}
 
 	router.Use(security.Headers())
+	router.Use(i18n.Middleware())
 	router.Use(security.MaxBodyBytes(defaultMaxBodyBytes))
 
 	setupRoutes(router)
 
-	// Start refresh token cleanup job
-	go func() {
-		cleanupInterval := time.Hour * time.Duration(config.RefreshTokenCleanupIntervalHours)
-		ticker := time.NewTicker(cleanupInterval)
-		defer ticker.Stop()
+	go runCleanupJobs()
+	go mailer.RunWorker(context.Background())
 
-		for range ticker.C {
+	startServer(router)
+}
+
+// cleanupJob is a periodic deletion of stale rows; name is used in log lines
+type cleanupJob struct {
+	name string
+	run  func(ctx context.Context) (int64, error)
+}
+
+// runCleanupJobs runs the cleanup jobs every RefreshTokenCleanupIntervalHours
+func runCleanupJobs() {
+	jobs := []cleanupJob{
+		{"expired refresh tokens", security.CleanupExpiredTokens},
+		{"audit events past retention", security.CleanupExpiredAuditEvents},
+		{"stale login failures", security.CleanupStaleLoginFailures},
+		{"expired rate limit counters", security.CleanupExpiredRateLimitCounters},
+		{"expired OIDC login states", accounts.CleanupExpiredOIDCStates},
+		{"accounts past deletion grace period", accounts.PurgeDeletedAccounts},
+		{"expired email changes", accounts.CleanupExpiredEmailChanges},
+		{"sent emails past retention", mailer.CleanupSentEmails},
+		{"dead emails past retention", mailer.CleanupDeadEmails},
+	}
+
+	cleanupInterval := time.Hour * time.Duration(config.RefreshTokenCleanupIntervalHours)
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		for _, job := range jobs {
 			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-			deleted, err := security.CleanupExpiredTokens(ctx)
+			deleted, err := job.run(ctx)
 			cancel()
 
 			if err != nil {
-				log.Printf("Error cleaning up expired tokens: %v", err)
+				log.Printf("Error cleaning up %s: %v", job.name, err)
 			} else if deleted > 0 {
-				log.Printf("Cleaned up %d expired refresh tokens", deleted)
+				log.Printf("Cleaned up %d %s", deleted, job.name)
 			}
 		}
-	}()
-
-	startServer(router)
+	}
 }
 
 func setupRoutes(router *gin.Engine) {
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
+	router.GET("/.well-known/jwks.json", security.JWKSHandler)
 	setupPublicRoutes(router)
 	setupProtectedRoutes(router)
 	setupV2Routes(router)
@@ -130,6 +170,8 @@ func setupPublicRoutes(router *gin.Engine) {
 	public := router.Group("/api")
 	public.POST("/register", accounts.Register)
 	public.POST("/login", accounts.Login)
+	public.GET("/auth/oidc/authorize", accounts.OIDCAuthorize)
+	public.POST("/auth/oidc/callback", accounts.OIDCCallback)
 	public.POST("/auth/refresh",
 		security.NewEndpointRateLimiter(
 			"/auth/refresh",
@@ -140,6 +182,8 @@ func setupPublicRoutes(router *gin.Engine) {
 		security.RefreshTokenHandler)
 	public.POST("/auth/logout", security.LogoutHandler)
 	public.GET("/confirmemail", accounts.ConfirmEmail)
+	public.GET("/unlockaccount", accounts.UnlockAccount)
+	public.POST("/restoreaccount", accounts.RestoreAccount)
 	public.POST("/forgotpassword", accounts.ForgotPassword)
 	public.POST("/resend-confirmemail",
 		security.NewEndpointRateLimiter(
@@ -166,7 +210,10 @@ func setupProtectedRoutes(router *gin.Engine) {
 	protected.POST("/auth/logout-all", security.LogoutAllHandler)
 	protected.GET("/myaccount", accounts.GetMyAccount)
 	protected.PUT("/myaccount", accounts.PutMyAccount)
+	protected.DELETE("/myaccount", accounts.DeleteMyAccount)
+	protected.GET("/myaccount/export", dataexport.GetMyAccountExport)
 	protected.PUT("/mypassword", accounts.PutMyPassword)
+	protected.GET("/myaccount/security-events", security.GetMySecurityEvents)
 	protected.GET("/myinventory", inventories.GetMyInventory)
 	protected.GET("/mypacks", packs.GetMyPacks)
 	protected.GET("/mypack/:id", packs.GetMyPackByID)
@@ -179,34 +226,136 @@ func setupProtectedRoutes(router *gin.Engine) {
 	protected.DELETE("/mypack/:id/favorite", packs.UnfavoriteMyPack)
 	protected.POST("/mypack/:id/duplicate", packs.DuplicateMyPack)
 	protected.GET("/mypack/:id/packcontents", packs.GetMyPackContentsByPackID)
+	protected.PUT("/mypack/:id/packcontents/order", packs.PutMyPackContentsOrder)
+	protected.GET("/mypack/:id/cost", packs.GetMyPackCost)
+	protected.GET("/mypack/:id/suggestions", packs.GetMyPackSuggestions)
+	protected.GET("/mypack/:id/planner", packs.GetMyPackPlanner)
 	protected.POST("/mypack/:id/packcontent", packs.PostMyPackContent)
 	protected.PUT("/mypack/:id/packcontent/:item_id", packs.PutMyPackContentByID)
 	protected.DELETE("/mypack/:id/packcontent/:item_id", packs.DeleteMyPackContentByID)
 	protected.POST("/myinventory/merge", inventories.PostMyInventoryMerge)
+	protected.POST("/myinventory/merge/batch", inventories.PostMyInventoryBatchMerge)
+	protected.GET("/myinventory/duplicates", inventories.GetMyInventoryDuplicates)
+	protected.GET("/myinventory/export", inventories.ExportMyInventory)
 	protected.GET("/myinventory/:id", inventories.GetMyInventoryByID)
 	protected.POST("/myinventory", inventories.PostMyInventory)
 	protected.PUT("/myinventory/:id", inventories.PutMyInventoryByID)
 	protected.DELETE("/myinventory/:id", inventories.DeleteMyInventoryByID)
 	protected.GET("/pack-options", packs.GetPackOptions)
+	protected.GET("/products", products.SearchProducts)
+	protected.GET("/products/:id", products.GetProductByID)
+	setupCategoryRoutes(protected)
+	setupCustomFieldRoutes(protected)
+	setupMaintenanceRoutes(protected)
+	setupTripRoutes(protected)
+	setupVariantRoutes(protected)
+	setupTemplateRoutes(protected)
+	setupImportRoutes(protected)
+	setupUploadRoutes(protected)
+}
+
+func setupCategoryRoutes(protected *gin.RouterGroup) {
+	protected.GET("/mycategories", categories.GetMyCategories)
+	protected.PUT("/mycategories/order", categories.PutMyCategoriesOrder)
+	protected.POST("/mycategories/merge", categories.PostMyCategoriesMerge)
+	protected.PUT("/mycategories/:id", categories.PutMyCategoryByID)
+	protected.DELETE("/mycategories/:id", categories.DeleteMyCategoryByID)
+}
+
+func setupCustomFieldRoutes(protected *gin.RouterGroup) {
+	protected.GET("/mycustomfields", customfields.GetMyCustomFields)
+	protected.POST("/mycustomfields", customfields.PostMyCustomField)
+	protected.PUT("/mycustomfields/:id", customfields.PutMyCustomFieldByID)
+	protected.DELETE("/mycustomfields/:id", customfields.DeleteMyCustomFieldByID)
+}
+
+func setupMaintenanceRoutes(protected *gin.RouterGroup) {
+	protected.GET("/myinventory/maintenance/due", inventories.GetMyMaintenanceDue)
+	protected.GET("/myinventory/:id/maintenance", inventories.GetMyInventoryMaintenance)
+	protected.POST("/myinventory/:id/maintenance", inventories.PostMyInventoryMaintenance)
+	protected.DELETE("/myinventory/:id/maintenance/:entry_id", inventories.DeleteMyInventoryMaintenance)
+}
+
+func setupTripRoutes(protected *gin.RouterGroup) {
+	protected.GET("/mypack/:id/trips", packs.GetMyPackTrips)
+	protected.POST("/mypack/:id/trips", packs.PostMyPackTrip)
+	protected.DELETE("/mypack/:id/trips/:trip_id", packs.DeleteMyPackTrip)
+	protected.GET("/myinventory/:id/usage", inventories.GetMyInventoryUsage)
+}
+
+func setupVariantRoutes(protected *gin.RouterGroup) {
+	protected.GET("/mypack/:id/variants", packs.GetMyPackVariants)
+	protected.POST("/mypack/:id/variants", packs.PostMyPackVariant)
+	protected.GET("/mypack/:id/variants/:variant_id", packs.GetMyPackVariant)
+	protected.PUT("/mypack/:id/variants/:variant_id", packs.PutMyPackVariant)
+	protected.DELETE("/mypack/:id/variants/:variant_id", packs.DeleteMyPackVariant)
+}
+
+func setupTemplateRoutes(protected *gin.RouterGroup) {
+	protected.GET("/templates", packs.GetTemplates)
+	protected.POST("/mypack/:id/template", packs.TemplateMyPack)
+	protected.DELETE("/mypack/:id/template", packs.UntemplateMyPack)
+}
+
+func setupImportRoutes(protected *gin.RouterGroup) {
+	importLimiter := security.NewUserRateLimiter(
+		"import",
+		config.ImportRateLimitRequests,
+		config.ImportRateLimitRequests,
+		config.ImportRateLimitWindowMinutes,
+	)
 	protected.POST("/importfromlighterpack",
+		importLimiter,
 		security.MaxBodyBytes(maxImportBodyBytes),
 		packs.ImportFromLighterPack)
 	protected.POST("/importfromlighterpackurl",
+		importLimiter,
 		security.MaxBodyBytes(maxImportBodyBytes),
 		packs.ImportFromLighterPackURL)
 	protected.POST("/importfrompimpmypackurl",
+		importLimiter,
 		security.MaxBodyBytes(maxImportBodyBytes),
 		packs.ImportFromPimpMyPackURL)
 	protected.POST("/importpack",
+		importLimiter,
 		security.MaxBodyBytes(maxImportBodyBytes),
 		packs.ImportPack)
-	protected.POST("/mypack/:id/image", images.UploadPackImage)
+	protected.POST("/importpack/preview",
+		importLimiter,
+		security.MaxBodyBytes(maxImportBodyBytes),
+		packs.PreviewImportPack)
+	protected.POST("/importpack/commit",
+		importLimiter,
+		security.MaxBodyBytes(maxImportBodyBytes),
+		packs.CommitImportPack)
+	protected.POST("/importspreadsheet",
+		importLimiter,
+		security.MaxBodyBytes(maxSpreadsheetBodyBytes),
+		packs.ImportSpreadsheet)
+	protected.POST("/myinventory/import",
+		importLimiter,
+		security.MaxBodyBytes(maxSpreadsheetBodyBytes),
+		inventories.ImportMyInventory)
+	protected.POST("/mypack/from-template/:id",
+		importLimiter,
+		security.MaxBodyBytes(maxImportBodyBytes),
+		packs.PostMyPackFromTemplate)
+}
+
+func setupUploadRoutes(protected *gin.RouterGroup) {
+	uploadLimiter := security.NewUserRateLimiter(
+		"image-upload",
+		config.UploadRateLimitRequests,
+		config.UploadRateLimitRequests,
+		config.UploadRateLimitWindowMinutes,
+	)
+	protected.POST("/mypack/:id/image", uploadLimiter, images.UploadPackImage)
 	protected.DELETE("/mypack/:id/image", images.DeletePackImage)
-	protected.POST("/myaccount/image", images.UploadMyProfileImage)
+	protected.POST("/myaccount/image", uploadLimiter, images.UploadMyProfileImage)
 	protected.DELETE("/myaccount/image", images.DeleteMyProfileImage)
-	protected.POST("/myaccount/banner", images.UploadMyBannerImage)
+	protected.POST("/myaccount/banner", uploadLimiter, images.UploadMyBannerImage)
 	protected.DELETE("/myaccount/banner", images.DeleteMyBannerImage)
-	protected.POST("/myinventory/:id/image", images.UploadInventoryItemImage)
+	protected.POST("/myinventory/:id/image", uploadLimiter, images.UploadInventoryItemImage)
 	protected.GET("/myinventory/:id/image", images.GetInventoryItemImage)
 	protected.DELETE("/myinventory/:id/image", images.DeleteInventoryItemImage)
 }
@@ -225,16 +374,28 @@ func setupPrivateRoutes(router *gin.Engine) {
 	private.POST("/accounts", accounts.PostAccount)
 	private.PUT("/accounts/:id", accounts.PutAccountByID)
 	private.DELETE("/accounts/:id", accounts.DeleteAccountByID)
+	private.POST("/accounts/:id/unlock", accounts.UnlockAccountByID)
+	private.GET("/audit", security.GetAuditEvents)
+	private.GET("/emails", mailer.GetOutboxEmails)
+	private.GET("/exchangerates", currencies.GetExchangeRates)
+	private.PUT("/exchangerates", currencies.PutExchangeRates)
+	private.DELETE("/exchangerates/:currency", currencies.DeleteExchangeRate)
+	private.POST("/emails/:id/retry", mailer.RetryOutboxEmail)
 	private.GET("/inventories", inventories.GetInventories)
 	private.GET("/inventories/:id", inventories.GetInventoryByID)
 	private.POST("/inventories", inventories.PostInventory)
 	private.PUT("/inventories/:id", inventories.PutInventoryByID)
 	private.DELETE("/inventories/:id", inventories.DeleteInventoryByID)
+	private.POST("/products/import", products.ImportProducts)
+	private.POST("/products/community", products.PostCommunityProducts)
+	private.DELETE("/products/:id", products.DeleteProductByID)
 	private.GET("/packs", packs.GetPacks)
 	private.GET("/packs/:id", packs.GetPackByID)
 	private.POST("/packs", packs.PostPack)
 	private.PUT("/packs/:id", packs.PutPackByID)
 	private.DELETE("/packs/:id", packs.DeletePackByID)
+	private.POST("/packs/:id/template", packs.PostOfficialTemplate)
+	private.DELETE("/packs/:id/template", packs.DeleteOfficialTemplate)
 	private.GET("/packcontents", packs.GetPackContents)
 	private.GET("/packcontents/:id", packs.GetPackContentByID)
 	private.POST("/packcontents", packs.PostPackContent)
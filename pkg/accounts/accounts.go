@@ -10,8 +10,11 @@ import (
 	"time"
 
 	"github.com/Angak0k/pimpmypack/pkg/config"
+	"github.com/Angak0k/pimpmypack/pkg/currencies"
 	"github.com/Angak0k/pimpmypack/pkg/database"
 	"github.com/Angak0k/pimpmypack/pkg/helper"
+	"github.com/Angak0k/pimpmypack/pkg/i18n"
+	"github.com/Angak0k/pimpmypack/pkg/mailer"
 	"github.com/Angak0k/pimpmypack/pkg/security"
 	"github.com/lib/pq"
 )
@@ -22,12 +25,24 @@ var ErrNoAccountFound = errors.New("no account found")
 // stageLocal represents the local development stage
 const stageLocal = "LOCAL"
 
+// statusActive is the status of a confirmed account allowed to log in
+const statusActive = "active"
+
 // ErrInvalidCredentials is returned when login credentials are invalid.
 var ErrInvalidCredentials = errors.New("invalid credentials")
 
+// ErrInvalidUnlockLink is returned when an account unlock link is unknown, already used or expired.
+var ErrInvalidUnlockLink = errors.New("invalid or expired unlock link")
+
 // ErrEmailAlreadyExists is returned when a registration is attempted with an already-used email.
 var ErrEmailAlreadyExists = errors.New("email already exists")
 
+// ErrUnsupportedLanguage is returned when an account asks for a language without a catalog
+var ErrUnsupportedLanguage = errors.New("unsupported preferred_language")
+
+// ErrInvalidPreferredCurrency is returned when an account asks for a code that is not ISO 4217
+var ErrInvalidPreferredCurrency = errors.New("preferred_currency must be an ISO 4217 currency code")
+
 // emailSender is the package-level email sender, replaceable for testing.
 var emailSender helper.EmailSender
 
@@ -36,12 +51,13 @@ func setEmailSender(s helper.EmailSender) {
 	emailSender = s
 }
 
-// getEmailSender returns the configured email sender, defaulting to SMTP.
+// getEmailSender returns the configured email sender, defaulting to the
+// outbox: emails are delivered by the mailer worker, with retries.
 func getEmailSender() helper.EmailSender {
 	if emailSender != nil {
 		return emailSender
 	}
-	return &helper.SMTPClient{Server: config.MailServerConfig}
+	return mailer.NewOutbox()
 }
 
 func registerUser(ctx context.Context, u User) (bool, error) {
@@ -54,8 +70,9 @@ func registerUser(ctx context.Context, u User) (bool, error) {
 
 	err = database.DB().QueryRowContext(ctx,
 		`INSERT INTO account 
-		(username, email, firstname, lastname, role, status, confirmation_code, created_at, updated_at) 
-		VALUES ($1,$2,$3,$4,$5,$6,$7,$8, $9) 
+		(username, email, firstname, lastname, role, status, confirmation_code, created_at, updated_at,
+		preferred_language) 
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8, $9, $10) 
 		RETURNING id;`,
 		u.Username,
 		u.Email,
@@ -65,7 +82,8 @@ func registerUser(ctx context.Context, u User) (bool, error) {
 		u.Status,
 		confirmationCode,
 		u.CreatedAt,
-		u.UpdatedAt).Scan(&id)
+		u.UpdatedAt,
+		i18n.Normalize(u.PreferredLanguage)).Scan(&id)
 	if err != nil {
 		var pqErr *pq.Error
 		if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "account_email_unique" {
@@ -116,9 +134,10 @@ func sendConfirmationEmail(u User, code string) error {
 		strconv.FormatUint(uint64(u.ID), 10) + "&code=" + code
 
 	mailRcpt := u.Email
-	mailSubject := "PimpMyPack - Confirm your email address"
-	mailTextBody := helper.BuildConfirmationEmailText(u.Username, confirmURL)
-	mailHTMLBody := helper.BuildConfirmationEmailHTML(u.Username, confirmURL)
+	lang := u.PreferredLanguage
+	mailSubject := i18n.T(lang, "email.confirmation.subject")
+	mailTextBody := helper.BuildConfirmationEmailText(lang, u.Username, confirmURL)
+	mailHTMLBody := helper.BuildConfirmationEmailHTML(lang, u.Username, confirmURL)
 
 	err := getEmailSender().SendEmail(mailRcpt, mailSubject, mailTextBody, mailHTMLBody)
 	if err != nil {
@@ -131,9 +150,9 @@ func sendConfirmationEmail(u User, code string) error {
 func resendConfirmEmail(ctx context.Context, email string) error {
 	var user User
 	err := database.DB().QueryRowContext(ctx,
-		`SELECT id, username, email FROM account WHERE email = $1 AND status = 'pending'`,
+		`SELECT id, username, email, preferred_language FROM account WHERE email = $1 AND status = 'pending'`,
 		email,
-	).Scan(&user.ID, &user.Username, &user.Email)
+	).Scan(&user.ID, &user.Username, &user.Email, &user.PreferredLanguage)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			// Email not found or not pending — return nil to prevent user enumeration
@@ -194,8 +213,10 @@ func confirmEmail(ctx context.Context, id string, code string) error {
 		}
 		return err
 	}
-	// Update the DB
-	statement, err := database.DB().PrepareContext(ctx, "UPDATE account SET status = 'active' WHERE id = $1;")
+	// Update the DB; only a pending registration is activated, the code of an
+	// expired email change must not reactivate a deactivated account
+	statement, err := database.DB().PrepareContext(ctx,
+		"UPDATE account SET status = 'active' WHERE id = $1 AND status = 'pending';")
 	if err != nil {
 		return fmt.Errorf("failed to prepare update statement: %w", err)
 	}
@@ -238,7 +259,7 @@ func forgotPassword(ctx context.Context, email string) error {
 		return fmt.Errorf("failed to generate new password: %w", err)
 	}
 
-	userID, err := getUserIDByEmail(ctx, email)
+	userID, lang, err := getUserIDByEmail(ctx, email)
 	if err != nil {
 		// email not found but we don't want to leak this information
 		return fmt.Errorf("failed to process the request %w", err)
@@ -250,9 +271,9 @@ func forgotPassword(ctx context.Context, email string) error {
 	}
 
 	mailRcpt := email
-	mailSubject := "PimpMyPack - Your password has been reset"
-	mailTextBody := helper.BuildPasswordResetEmailText(newPassword)
-	mailHTMLBody := helper.BuildPasswordResetEmailHTML(newPassword)
+	mailSubject := i18n.T(lang, "email.password_reset.subject")
+	mailTextBody := helper.BuildPasswordResetEmailText(lang, newPassword)
+	mailHTMLBody := helper.BuildPasswordResetEmailHTML(lang, newPassword)
 
 	err = getEmailSender().SendEmail(mailRcpt, mailSubject, mailTextBody, mailHTMLBody)
 	if err != nil {
@@ -262,46 +283,59 @@ func forgotPassword(ctx context.Context, email string) error {
 }
 
 func loginCheck(
-	ctx context.Context, username string, password string, rememberMe bool,
+	ctx context.Context, username string, password string, rememberMe bool, ip string,
 ) (*security.TokenPairResponse, uint, bool, error) {
 	var err error
 	var status string
 	var storedPassword string
-	var id uint
+	var user User
 
 	row := database.DB().QueryRowContext(ctx,
-		`SELECT p.password, p.user_id, a.status
+		`SELECT p.password, p.user_id, a.status, a.username, a.email, a.preferred_language
 		FROM password AS p JOIN account AS a ON p.user_id = a.id
 		WHERE a.username = $1 OR a.email = $1;`,
 		username)
-	err = row.Scan(&storedPassword, &id, &status)
+	err = row.Scan(&storedPassword, &user.ID, &status, &user.Username, &user.Email, &user.PreferredLanguage)
 
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, 0, false, ErrInvalidCredentials
-		}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return nil, 0, false, fmt.Errorf("failed to query user: %w", err)
 	}
 
+	// Throttling is checked before the password so that a locked account or
+	// a backed-off IP gets no feedback on the guessed password.
+	// An unknown username (ID 0) is only throttled by IP.
+	err = security.CheckLoginThrottle(ctx, user.ID, ip)
+	if err != nil {
+		return nil, user.ID, false, err
+	}
+
+	if user.ID == 0 {
+		recordFailedLogin(ctx, user, ip)
+		return nil, 0, false, ErrInvalidCredentials
+	}
+
 	err = security.VerifyPassword(password, storedPassword)
 
 	if err != nil {
-		return nil, id, false, ErrInvalidCredentials
+		recordFailedLogin(ctx, user, ip)
+		return nil, user.ID, false, ErrInvalidCredentials
 	}
 
+	resetFailedLogins(ctx, user.ID)
+
 	// Check account status before generating tokens
-	if status != "active" {
-		return nil, id, true, nil
+	if status != statusActive {
+		return nil, user.ID, true, nil
 	}
 
 	// Only generate tokens for active accounts
-	tokenPair, err := security.GenerateTokenPair(ctx, id, rememberMe)
+	tokenPair, err := security.GenerateTokenPair(ctx, user.ID, rememberMe)
 
 	if err != nil {
-		return nil, id, false, err
+		return nil, user.ID, false, err
 	}
 
-	return tokenPair, id, false, nil
+	return tokenPair, user.ID, false, nil
 }
 
 func updatePassword(ctx context.Context, userID uint, updatedPassword string) error {
@@ -348,11 +382,11 @@ func returnAccounts(ctx context.Context) (*Accounts, error) {
 
 	rows, err := database.DB().QueryContext(ctx,
 		`SELECT a.id, a.username, a.email, a.firstname, a.lastname, a.role, a.status,
-		    a.preferred_currency, a.preferred_unit_system, a.youtube_url, a.instagram_url,
+		    a.preferred_currency, a.preferred_unit_system, a.preferred_language, a.youtube_url, a.instagram_url,
 		    CASE WHEN ai.account_id IS NOT NULL THEN true ELSE false END AS has_profile_image,
 		    a.image_position_x, a.image_position_y, a.is_profile_public,
 		    CASE WHEN abi.account_id IS NOT NULL THEN true ELSE false END AS has_banner_image,
-		    a.banner_position_y,
+		    a.banner_position_y, a.deletion_scheduled_at,
 		    a.created_at, a.updated_at
 		FROM account a
 		LEFT JOIN account_images ai ON a.id = ai.account_id
@@ -374,6 +408,7 @@ func returnAccounts(ctx context.Context) (*Accounts, error) {
 			&account.Status,
 			&account.PreferredCurrency,
 			&account.PreferredUnitSystem,
+			&account.PreferredLanguage,
 			&account.YoutubeURL,
 			&account.InstagramURL,
 			&account.HasProfileImage,
@@ -382,6 +417,7 @@ func returnAccounts(ctx context.Context) (*Accounts, error) {
 			&account.IsProfilePublic,
 			&account.HasBannerImage,
 			&account.BannerPositionY,
+			&account.DeletionScheduledAt,
 			&account.CreatedAt,
 			&account.UpdatedAt)
 		if err != nil {
@@ -402,11 +438,11 @@ func findAccountByID(ctx context.Context, id uint) (*Account, error) {
 
 	row := database.DB().QueryRowContext(ctx,
 		`SELECT a.id, a.username, a.email, a.firstname, a.lastname, a.role, a.status,
-		    a.preferred_currency, a.preferred_unit_system, a.youtube_url, a.instagram_url,
+		    a.preferred_currency, a.preferred_unit_system, a.preferred_language, a.youtube_url, a.instagram_url,
 		    CASE WHEN ai.account_id IS NOT NULL THEN true ELSE false END AS has_profile_image,
 		    a.image_position_x, a.image_position_y, a.is_profile_public,
 		    CASE WHEN abi.account_id IS NOT NULL THEN true ELSE false END AS has_banner_image,
-		    a.banner_position_y,
+		    a.banner_position_y, a.deletion_scheduled_at, a.pending_email,
 		    a.created_at, a.updated_at
 		FROM account a
 		LEFT JOIN account_images ai ON a.id = ai.account_id
@@ -423,6 +459,7 @@ func findAccountByID(ctx context.Context, id uint) (*Account, error) {
 		&account.Status,
 		&account.PreferredCurrency,
 		&account.PreferredUnitSystem,
+		&account.PreferredLanguage,
 		&account.YoutubeURL,
 		&account.InstagramURL,
 		&account.HasProfileImage,
@@ -431,6 +468,8 @@ func findAccountByID(ctx context.Context, id uint) (*Account, error) {
 		&account.IsProfilePublic,
 		&account.HasBannerImage,
 		&account.BannerPositionY,
+		&account.DeletionScheduledAt,
+		&account.PendingEmail,
 		&account.CreatedAt,
 		&account.UpdatedAt)
 
@@ -489,7 +528,9 @@ func updateAccountByID(ctx context.Context, id uint, a *Account) error {
 	statement, err := database.DB().PrepareContext(ctx,
 		`UPDATE account SET email=$1, firstname=$2, lastname=$3, status=$4, role=$5, preferred_currency=$6,
 		    preferred_unit_system=$7, youtube_url=$8, instagram_url=$9, image_position_x=$10,
-		    image_position_y=$11, is_profile_public=$12, banner_position_y=$13, updated_at=$14
+		    image_position_y=$11, is_profile_public=$12, banner_position_y=$13, updated_at=$14,
+		    deletion_requested_at = CASE WHEN $4 = 'active' THEN NULL ELSE deletion_requested_at END,
+		    deletion_scheduled_at = CASE WHEN $4 = 'active' THEN NULL ELSE deletion_scheduled_at END
 		WHERE id=$15 RETURNING username;`)
 	if err != nil {
 		return err
@@ -522,7 +563,9 @@ func isValidationError(err error) bool {
 	return msg == "invalid email format" ||
 		msg == "image_position_x must be between 0 and 100" ||
 		msg == "image_position_y must be between 0 and 100" ||
-		msg == "banner_position_y must be between 0 and 100"
+		msg == "banner_position_y must be between 0 and 100" ||
+		errors.Is(err, ErrUnsupportedLanguage) ||
+		errors.Is(err, ErrInvalidPreferredCurrency)
 }
 
 // normalizeEmptyStringPtr converts empty string pointers to nil for clean DB storage
@@ -533,52 +576,78 @@ func normalizeEmptyStringPtr(s *string) *string {
 	return s
 }
 
+// validateAccountUpdateInput checks the email format, the image position ranges, the language
+// and the currency, which is upper-cased
+func validateAccountUpdateInput(input *AccountUpdateInput) error {
+	if !helper.IsValidEmail(input.Email) {
+		return errors.New("invalid email format")
+	}
+
+	// Validate image position range when provided
+	if input.ImagePositionX != nil && (*input.ImagePositionX < 0 || *input.ImagePositionX > 100) {
+		return errors.New("image_position_x must be between 0 and 100")
+	}
+	if input.ImagePositionY != nil && (*input.ImagePositionY < 0 || *input.ImagePositionY > 100) {
+		return errors.New("image_position_y must be between 0 and 100")
+	}
+	if input.BannerPositionY != nil && (*input.BannerPositionY < 0 || *input.BannerPositionY > 100) {
+		return errors.New("banner_position_y must be between 0 and 100")
+	}
+	if input.PreferredLanguage != nil && !i18n.IsSupported(*input.PreferredLanguage) {
+		return ErrUnsupportedLanguage
+	}
+	if input.PreferredCurrency != "" {
+		currency, err := currencies.Normalize(input.PreferredCurrency)
+		if err != nil {
+			return ErrInvalidPreferredCurrency
+		}
+		input.PreferredCurrency = currency
+	}
+
+	return nil
+}
+
 // updateMyAccount updates only user-controllable fields of an account
 // SECURITY: This function explicitly excludes role, status, username to prevent privilege escalation
-// Only safe fields can be updated: email, firstname, lastname, preferences, social URLs
+// Only safe fields can be updated: firstname, lastname, preferences, social URLs; a new email
+// is only requested, see requestEmailChange
 func updateMyAccount(ctx context.Context, userID uint, input *AccountUpdateInput) (*Account, error) {
 	if input == nil {
 		return nil, errors.New("input is empty")
 	}
 
-	// Validate email format
-	if !helper.IsValidEmail(input.Email) {
-		return nil, errors.New("invalid email format")
+	if err := validateAccountUpdateInput(input); err != nil {
+		return nil, err
 	}
 
 	// Normalize empty strings to nil for optional URL fields
 	input.YoutubeURL = normalizeEmptyStringPtr(input.YoutubeURL)
 	input.InstagramURL = normalizeEmptyStringPtr(input.InstagramURL)
 
-	// Validate image position range when provided
-	if input.ImagePositionX != nil && (*input.ImagePositionX < 0 || *input.ImagePositionX > 100) {
-		return nil, errors.New("image_position_x must be between 0 and 100")
-	}
-	if input.ImagePositionY != nil && (*input.ImagePositionY < 0 || *input.ImagePositionY > 100) {
-		return nil, errors.New("image_position_y must be between 0 and 100")
-	}
-	if input.BannerPositionY != nil && (*input.BannerPositionY < 0 || *input.BannerPositionY > 100) {
-		return nil, errors.New("banner_position_y must be between 0 and 100")
+	// A new email only takes effect once confirmed from the new address
+	if err := requestEmailChange(ctx, userID, input.Email); err != nil {
+		return nil, err
 	}
 
 	now := time.Now().Truncate(time.Second)
 
-	// Update ONLY safe fields - explicitly excludes role, status, username
+	// Update ONLY safe fields - explicitly excludes role, status, username and email
 	// COALESCE keeps existing values when client omits them (nil)
 	statement, err := database.DB().PrepareContext(ctx,
 		`UPDATE account
-		 SET email=$1, firstname=$2, lastname=$3, preferred_currency=$4,
-		     preferred_unit_system=$5, youtube_url=$6, instagram_url=$7,
-		     image_position_x=COALESCE($8, image_position_x),
-		     image_position_y=COALESCE($9, image_position_y),
-		     is_profile_public=COALESCE($10, is_profile_public),
-		     banner_position_y=COALESCE($11, banner_position_y),
+		 SET firstname=$1, lastname=$2, preferred_currency=COALESCE(NULLIF($3, ''), preferred_currency),
+		     preferred_unit_system=$4, youtube_url=$5, instagram_url=$6,
+		     image_position_x=COALESCE($7, image_position_x),
+		     image_position_y=COALESCE($8, image_position_y),
+		     is_profile_public=COALESCE($9, is_profile_public),
+		     banner_position_y=COALESCE($10, banner_position_y),
+		     preferred_language=COALESCE($11, preferred_language),
 		     updated_at=$12
 		 WHERE id=$13
 		 RETURNING id, username, email, firstname, lastname, role, status,
-		           preferred_currency, preferred_unit_system, youtube_url, instagram_url,
+		           preferred_currency, preferred_unit_system, preferred_language, youtube_url, instagram_url,
 		           image_position_x, image_position_y, is_profile_public,
-		           banner_position_y,
+		           banner_position_y, pending_email,
 		           created_at, updated_at;`)
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare statement: %w", err)
@@ -587,7 +656,6 @@ func updateMyAccount(ctx context.Context, userID uint, input *AccountUpdateInput
 
 	var account Account
 	err = statement.QueryRowContext(ctx,
-		input.Email,
 		input.Firstname,
 		input.Lastname,
 		input.PreferredCurrency,
@@ -598,6 +666,7 @@ func updateMyAccount(ctx context.Context, userID uint, input *AccountUpdateInput
 		input.ImagePositionY,
 		input.IsProfilePublic,
 		input.BannerPositionY,
+		input.PreferredLanguage,
 		now,
 		userID,
 	).Scan(
@@ -610,12 +679,14 @@ func updateMyAccount(ctx context.Context, userID uint, input *AccountUpdateInput
 		&account.Status,
 		&account.PreferredCurrency,
 		&account.PreferredUnitSystem,
+		&account.PreferredLanguage,
 		&account.YoutubeURL,
 		&account.InstagramURL,
 		&account.ImagePositionX,
 		&account.ImagePositionY,
 		&account.IsProfilePublic,
 		&account.BannerPositionY,
+		&account.PendingEmail,
 		&account.CreatedAt,
 		&account.UpdatedAt,
 	)
@@ -660,14 +731,22 @@ func deleteAccountByID(ctx context.Context, id string) error {
 	return nil
 }
 
-func getUserIDByEmail(ctx context.Context, email string) (uint, error) {
+// getUserIDByEmail returns the ID and the preferred language of the account using email
+func getUserIDByEmail(ctx context.Context, email string) (uint, string, error) {
 	var id uint
-	row := database.DB().QueryRowContext(ctx, "SELECT id FROM account WHERE email = $1;", email)
-	err := row.Scan(&id)
+	var lang string
+	row := database.DB().QueryRowContext(ctx, "SELECT id, preferred_language FROM account WHERE email = $1;", email)
+	err := row.Scan(&id, &lang)
 	if err != nil {
-		return 0, err
+		return 0, "", err
 	}
-	return id, nil
+	return id, lang, nil
+}
+
+// FindAccountByID returns the public information of an account
+// Returns ErrNoAccountFound if the account does not exist
+func FindAccountByID(ctx context.Context, id uint) (*Account, error) {
+	return findAccountByID(ctx, id)
 }
 
 // FindUserIDByUsername finds a user ID by username
@@ -11,22 +11,20 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strconv"
 	"testing"
 
 	"github.com/Angak0k/pimpmypack/pkg/config"
 	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/Angak0k/pimpmypack/pkg/mailer"
 	"github.com/Angak0k/pimpmypack/pkg/security"
 	"github.com/gin-gonic/gin"
 	"github.com/gruntwork-io/terratest/modules/random"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// mockEmailSender is a no-op email sender for tests.
-type mockEmailSender struct{}
-
-func (m *mockEmailSender) SendEmail(_, _, _, _ string) error {
-	return nil
-}
+// mailSink receives the emails sent during the tests.
+var mailSink = mailer.NewMemorySink()
 
 func TestMain(m *testing.M) {
 	// init env
@@ -47,8 +45,8 @@ func TestMain(m *testing.M) {
 		log.Fatalf("Error migrating database : %v", err)
 	}
 
-	// inject mock email sender to avoid real SMTP calls in tests
-	setEmailSender(&mockEmailSender{})
+	// inject an in-memory sink to avoid real SMTP calls and assert on sent emails
+	setEmailSender(mailSink)
 
 	// init dataset
 	println("Loading Account dataset...")
@@ -1361,3 +1359,110 @@ func testImagePositionOutOfRange(t *testing.T, router *gin.Engine, token string,
 		t.Errorf("Expected 400 but got %d: %s", w.Code, w.Body.String())
 	}
 }
+
+// postLoginFrom posts credentials to /login from the given client IP
+func postLoginFrom(t *testing.T, router *gin.Engine, ip, username, password string) *httptest.ResponseRecorder {
+	t.Helper()
+	body, err := json.Marshal(LoginInput{Username: username, Password: password})
+	if err != nil {
+		t.Fatalf("Failed to marshal login data: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, "/login", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = ip + ":12345"
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// lockAccountByLogins fails the login three times, then checks that even the
+// right password is rejected with 423 Locked
+func lockAccountByLogins(t *testing.T, router *gin.Engine, ip string, user User) {
+	t.Helper()
+	for range 3 {
+		w := postLoginFrom(t, router, ip, user.Username, "wrong-password")
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("Expected status code %d but got %d", http.StatusUnauthorized, w.Code)
+		}
+	}
+	w := postLoginFrom(t, router, ip, user.Username, user.Password)
+	if w.Code != http.StatusLocked {
+		t.Fatalf("Expected status code %d but got %d. Body: %s", http.StatusLocked, w.Code, w.Body.String())
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("Expected Retry-After header on locked account")
+	}
+}
+
+func TestLoginLockout(t *testing.T) {
+	router, newUser, _ := setupLoginTestData(t)
+	router.GET("/unlockaccount", UnlockAccount)
+	router.POST("/admin/accounts/:id/unlock", UnlockAccountByID)
+	ip := "198.51.100.60"
+
+	prevBackoff, prevLockout := config.LoginBackoffThreshold, config.LoginLockoutThreshold
+	config.LoginBackoffThreshold, config.LoginLockoutThreshold = 0, 3
+	t.Cleanup(func() {
+		config.LoginBackoffThreshold, config.LoginLockoutThreshold = prevBackoff, prevLockout
+	})
+
+	// code is the unlock code used by the first subtest, replayed by the next
+	var code string
+
+	t.Run("Unlock with emailed code", func(t *testing.T) {
+		lockAccountByLogins(t, router, ip, newUser)
+
+		err := database.DB().QueryRow(
+			`SELECT unlock_code FROM login_failure WHERE scope = 'account' AND subject = $1`,
+			strconv.FormatUint(uint64(newUser.ID), 10),
+		).Scan(&code)
+		if err != nil {
+			t.Fatalf("failed to read unlock code: %v", err)
+		}
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/unlockaccount?id=%d&code=%s", newUser.ID, code), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d but got %d", http.StatusOK, w.Code)
+		}
+
+		w = postLoginFrom(t, router, ip, newUser.Username, newUser.Password)
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status code %d after unlock but got %d", http.StatusOK, w.Code)
+		}
+	})
+
+	t.Run("Unlock link cannot be reused", func(t *testing.T) {
+		if code == "" {
+			t.Skip("no unlock code was used")
+		}
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/unlockaccount?id=%d&code=%s", newUser.ID, code), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d but got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("Admin unlock", func(t *testing.T) {
+		lockAccountByLogins(t, router, ip, newUser)
+
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/admin/accounts/%d/unlock", newUser.ID), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d but got %d", http.StatusOK, w.Code)
+		}
+
+		w = postLoginFrom(t, router, ip, newUser.Username, newUser.Password)
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status code %d after admin unlock but got %d", http.StatusOK, w.Code)
+		}
+	})
+}
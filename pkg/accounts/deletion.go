@@ -0,0 +1,239 @@
+package accounts
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Angak0k/pimpmypack/pkg/config"
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/Angak0k/pimpmypack/pkg/helper"
+	"github.com/Angak0k/pimpmypack/pkg/i18n"
+	"github.com/Angak0k/pimpmypack/pkg/oidc"
+	"github.com/Angak0k/pimpmypack/pkg/security"
+)
+
+// ErrDeletionAlreadyRequested is returned when the account is already scheduled for deletion.
+var ErrDeletionAlreadyRequested = errors.New("account deletion already requested")
+
+// ErrNoPendingDeletion is returned when restoring an account that is not scheduled for deletion.
+var ErrNoPendingDeletion = errors.New("account is not scheduled for deletion")
+
+// ErrReauthenticationFailed is returned when the OIDC login confirming an action is not the account's.
+var ErrReauthenticationFailed = errors.New("re-authentication failed")
+
+// deletionGracePeriod is the time a deleted account can still be restored
+func deletionGracePeriod() time.Duration {
+	return time.Duration(config.AccountDeletionGraceDays) * 24 * time.Hour
+}
+
+// requestAccountDeletion checks the password or the OIDC re-authentication,
+// deactivates the account and schedules its hard delete at the end of the
+// grace period. Live sessions are revoked and the owner is told how to
+// restore the account.
+func requestAccountDeletion(ctx context.Context, userID uint, input DeleteMyAccountInput) (time.Time, error) {
+	var u User
+	var storedPassword string
+	err := database.DB().QueryRowContext(ctx,
+		`SELECT p.password, a.id, a.username, a.email, a.preferred_language
+		FROM password AS p JOIN account AS a ON p.user_id = a.id
+		WHERE a.id = $1;`,
+		userID).Scan(&storedPassword, &u.ID, &u.Username, &u.Email, &u.PreferredLanguage)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, ErrNoAccountFound
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to query account: %w", err)
+	}
+
+	if input.OIDC != nil {
+		if err := verifyOIDCReauthentication(ctx, userID, input.OIDC); err != nil {
+			return time.Time{}, err
+		}
+	} else if err := security.VerifyPassword(input.Password, storedPassword); err != nil {
+		return time.Time{}, ErrInvalidCredentials
+	}
+
+	now := time.Now()
+	scheduledAt := now.Add(deletionGracePeriod())
+	result, err := database.DB().ExecContext(ctx,
+		`UPDATE account
+		SET status = 'inactive', deletion_requested_at = $2, deletion_scheduled_at = $3, updated_at = $4
+		WHERE id = $1 AND deletion_scheduled_at IS NULL;`,
+		userID, now, scheduledAt, now.Truncate(time.Second))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to schedule account deletion: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return time.Time{}, ErrDeletionAlreadyRequested
+	}
+
+	// Non-fatal: the account is already inactive and cannot log in
+	if _, err := security.RevokeAllUserTokens(ctx, userID); err != nil {
+		helper.LogAndSanitize(err, "account deletion: revoke refresh tokens failed")
+	}
+	if err := sendAccountDeletionEmail(u, scheduledAt); err != nil {
+		helper.LogAndSanitize(err, "account deletion: send notice email failed")
+	}
+
+	return scheduledAt, nil
+}
+
+// verifyOIDCReauthentication completes an OIDC login and checks that its
+// identity is linked to the account. Login states expire after oidcStateTTL,
+// so the login is recent: accounts provisioned by OIDC have a random
+// password their owner does not know.
+func verifyOIDCReauthentication(ctx context.Context, userID uint, input *OIDCReauthInput) error {
+	accountID, err := findOIDCReauthAccount(ctx, input)
+	if err != nil {
+		return err
+	}
+	if accountID != userID {
+		return ErrReauthenticationFailed
+	}
+	return nil
+}
+
+// findOIDCReauthAccount completes an OIDC login and returns the account its
+// identity is linked to, without the active account check of a login
+func findOIDCReauthAccount(ctx context.Context, input *OIDCReauthInput) (uint, error) {
+	issuer, claims, err := completeOIDCLogin(ctx, input.Code, input.State, input.LoginBinding)
+	switch {
+	case errors.Is(err, ErrOIDCDisabled):
+		return 0, err
+	case errors.Is(err, ErrInvalidOIDCState), errors.Is(err, oidc.ErrTokenExchange),
+		errors.Is(err, oidc.ErrInvalidIDToken):
+		helper.LogAndSanitize(err, "OIDC re-authentication failed")
+		return 0, ErrReauthenticationFailed
+	case err != nil:
+		return 0, err
+	}
+
+	var accountID uint
+	err = database.DB().QueryRowContext(ctx,
+		`SELECT account_id FROM account_identity WHERE issuer = $1 AND subject = $2;`,
+		issuer, claims.Subject).Scan(&accountID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrReauthenticationFailed
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to query account identity: %w", err)
+	}
+	return accountID, nil
+}
+
+func sendAccountDeletionEmail(u User, scheduledAt time.Time) error {
+	deletionDate := scheduledAt.UTC().Format("2006-01-02")
+
+	// LOCAL mode: Don't send email, just log the deletion details
+	if config.Stage == stageLocal {
+		//nolint:gosec // username and ID are read from the account table, not the request
+		log.Printf("LOCAL MODE: Account %s (ID: %d) scheduled for deletion on %s", u.Username, u.ID, deletionDate)
+		return nil
+	}
+
+	restoreURL := config.Scheme + "://" + config.HostName + "/restoreaccount.html"
+
+	mailRcpt := u.Email
+	lang := u.PreferredLanguage
+	mailSubject := i18n.T(lang, "email.account_deletion.subject")
+	mailTextBody := helper.BuildAccountDeletionEmailText(lang, u.Username, deletionDate, restoreURL)
+	mailHTMLBody := helper.BuildAccountDeletionEmailHTML(lang, u.Username, deletionDate, restoreURL)
+
+	err := getEmailSender().SendEmail(mailRcpt, mailSubject, mailTextBody, mailHTMLBody)
+	if err != nil {
+		return fmt.Errorf("failed to send account deletion email: %w", err)
+	}
+
+	return nil
+}
+
+// restoreAccount cancels a pending deletion. The account is inactive, so the
+// owner proves their identity with the same credentials and throttling as a
+// login, or with a fresh OIDC login for accounts provisioned by OIDC; the
+// returned ID is logged in by the caller.
+func restoreAccount(ctx context.Context, input RestoreAccountInput, ip string) (uint, error) {
+	var userID uint
+	var err error
+	if input.OIDC != nil {
+		userID, err = findOIDCReauthAccount(ctx, input.OIDC)
+	} else {
+		userID, err = checkRestoreCredentials(ctx, input.Username, input.Password, ip)
+	}
+	if err != nil {
+		return userID, err
+	}
+
+	now := time.Now()
+	result, err := database.DB().ExecContext(ctx,
+		`UPDATE account
+		SET status = 'active', deletion_requested_at = NULL, deletion_scheduled_at = NULL, updated_at = $2
+		WHERE id = $1 AND deletion_scheduled_at > $3;`,
+		userID, now.Truncate(time.Second), now)
+	if err != nil {
+		return userID, fmt.Errorf("failed to restore account: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return userID, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return userID, ErrNoPendingDeletion
+	}
+
+	return userID, nil
+}
+
+// checkRestoreCredentials checks the username or email and password of an
+// account to restore, and returns its ID
+func checkRestoreCredentials(ctx context.Context, username, password, ip string) (uint, error) {
+	var user User
+	var storedPassword string
+	err := database.DB().QueryRowContext(ctx,
+		`SELECT p.password, p.user_id, a.username, a.email
+		FROM password AS p JOIN account AS a ON p.user_id = a.id
+		WHERE a.username = $1 OR a.email = $1;`,
+		username).Scan(&storedPassword, &user.ID, &user.Username, &user.Email)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("failed to query user: %w", err)
+	}
+
+	if err := security.CheckLoginThrottle(ctx, user.ID, ip); err != nil {
+		return user.ID, err
+	}
+	if user.ID == 0 {
+		recordFailedLogin(ctx, user, ip)
+		return 0, ErrInvalidCredentials
+	}
+	if err := security.VerifyPassword(password, storedPassword); err != nil {
+		recordFailedLogin(ctx, user, ip)
+		return user.ID, ErrInvalidCredentials
+	}
+	resetFailedLogins(ctx, user.ID)
+
+	return user.ID, nil
+}
+
+// PurgeDeletedAccounts hard-deletes the accounts whose deletion grace period
+// is over. Inventory, packs, images and tokens go with them (ON DELETE CASCADE).
+func PurgeDeletedAccounts(ctx context.Context) (int64, error) {
+	result, err := database.DB().ExecContext(ctx,
+		`DELETE FROM account WHERE deletion_scheduled_at <= $1`, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted accounts: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
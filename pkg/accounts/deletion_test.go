@@ -0,0 +1,330 @@
+package accounts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/Angak0k/pimpmypack/pkg/oidc"
+	"github.com/Angak0k/pimpmypack/pkg/security"
+	"github.com/gin-gonic/gin"
+	"github.com/gruntwork-io/terratest/modules/random"
+)
+
+// createDisposableAccount inserts an active account that a test can freely modify or delete
+func createDisposableAccount(t *testing.T) User {
+	t.Helper()
+
+	user := User{
+		Username: "disposable-" + random.UniqueID(),
+		Email:    "disposable-" + random.UniqueID() + "@exemple.com",
+		Password: "password",
+	}
+	err := database.DB().QueryRow(
+		`INSERT INTO account (username, email, firstname, lastname, role, status, created_at, updated_at)
+		VALUES ($1,$2,'Del','Etable','standard','active',$3,$3) RETURNING id;`,
+		user.Username, user.Email, time.Now().Truncate(time.Second)).Scan(&user.ID)
+	if err != nil {
+		t.Fatalf("failed to insert account: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = database.DB().ExecContext(context.Background(), "DELETE FROM account WHERE id = $1", user.ID)
+	})
+
+	hashedPassword, err := security.HashPassword(user.Password)
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+	_, err = database.DB().Exec(
+		`INSERT INTO password (user_id, password, last_password, updated_at) VALUES ($1,$2,'',$3);`,
+		user.ID, hashedPassword, time.Now().Truncate(time.Second))
+	if err != nil {
+		t.Fatalf("failed to insert password: %v", err)
+	}
+
+	return user
+}
+
+func deleteMyAccountForTest(t *testing.T, router *gin.Engine, token, password string) *httptest.ResponseRecorder {
+	t.Helper()
+	jsonData, _ := json.Marshal(DeleteMyAccountInput{Password: password})
+	req, _ := http.NewRequest(http.MethodDelete, "/v1/myaccount", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestDeleteMyAccount_OIDCReauthentication(t *testing.T) {
+	router, mock := setupOIDCTest(t)
+	router.DELETE("/v1/myaccount", DeleteMyAccount)
+
+	user := createDisposableAccount(t)
+	token, err := security.GenerateToken(user.ID)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	identity := oidc.MockUser{Subject: "sub-" + random.UniqueID(), Email: user.Email, EmailVerified: true}
+	if w := oidcLogin(t, router, mock, identity); w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d but got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	deleteWith := func(input DeleteMyAccountInput) *httptest.ResponseRecorder {
+		jsonData, _ := json.Marshal(input)
+		req, _ := http.NewRequest(http.MethodDelete, "/v1/myaccount", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Another identity", func(t *testing.T) {
+		stranger := oidc.MockUser{Subject: "sub-" + random.UniqueID()}
+		w := deleteWith(DeleteMyAccountInput{OIDC: oidcReauthenticate(t, mock, stranger)})
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d but got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("Neither password nor re-authentication", func(t *testing.T) {
+		w := deleteWith(DeleteMyAccountInput{})
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d but got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("Linked identity", func(t *testing.T) {
+		w := deleteWith(DeleteMyAccountInput{OIDC: oidcReauthenticate(t, mock, identity)})
+		if w.Code != http.StatusAccepted {
+			t.Fatalf("Expected status code %d but got %d: %s", http.StatusAccepted, w.Code, w.Body.String())
+		}
+	})
+}
+
+// oidcReauthenticate runs an OIDC login of user to confirm an action
+func oidcReauthenticate(t *testing.T, mock *oidc.MockProvider, user oidc.MockUser) *OIDCReauthInput {
+	t.Helper()
+	authURL, binding, err := startOIDCLogin(context.Background())
+	if err != nil {
+		t.Fatalf("failed to start OIDC login: %v", err)
+	}
+	code, state, err := mock.Authorize(authURL, user)
+	if err != nil {
+		t.Fatalf("Mock provider rejected the authorization request: %v", err)
+	}
+	return &OIDCReauthInput{Code: code, State: state, LoginBinding: binding}
+}
+
+func TestRestoreAccount_OIDCReauthentication(t *testing.T) {
+	router, mock := setupOIDCTest(t)
+	router.DELETE("/v1/myaccount", DeleteMyAccount)
+	router.POST("/restoreaccount", RestoreAccount)
+
+	// The account is provisioned by OIDC: its owner does not know its password
+	identity := oidc.MockUser{
+		Subject:       "sub-" + random.UniqueID(),
+		Email:         "oidc-" + random.UniqueID() + "@exemple.com",
+		EmailVerified: true,
+	}
+	deleteAccountByEmail(t, identity.Email)
+	if w := oidcLogin(t, router, mock, identity); w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d but got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var accountID uint
+	err := database.DB().QueryRow(`SELECT id FROM account WHERE email = $1`, identity.Email).Scan(&accountID)
+	if err != nil {
+		t.Fatalf("provisioned account not found: %v", err)
+	}
+	token, err := security.GenerateToken(accountID)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+
+	send := func(method, path string, input any) *httptest.ResponseRecorder {
+		jsonData, _ := json.Marshal(input)
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(http.MethodDelete, "/v1/myaccount", DeleteMyAccountInput{OIDC: oidcReauthenticate(t, mock, identity)})
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status code %d but got %d: %s", http.StatusAccepted, w.Code, w.Body.String())
+	}
+	if w := oidcLogin(t, router, mock, identity); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status code %d for an OIDC login but got %d", http.StatusUnauthorized, w.Code)
+	}
+
+	t.Run("Another identity", func(t *testing.T) {
+		stranger := oidc.MockUser{Subject: "sub-" + random.UniqueID()}
+		w := send(http.MethodPost, "/restoreaccount", RestoreAccountInput{OIDC: oidcReauthenticate(t, mock, stranger)})
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status code %d but got %d", http.StatusUnauthorized, w.Code)
+		}
+	})
+
+	t.Run("Linked identity", func(t *testing.T) {
+		w := send(http.MethodPost, "/restoreaccount", RestoreAccountInput{OIDC: oidcReauthenticate(t, mock, identity)})
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d but got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var response map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		validateTokenPairResponse(t, response)
+
+		account, err := FindAccountByID(context.Background(), accountID)
+		if err != nil {
+			t.Fatalf("failed to find account: %v", err)
+		}
+		if account.Status != "active" || account.DeletionScheduledAt != nil {
+			t.Errorf("Expected a restored active account but got %s, %v", account.Status, account.DeletionScheduledAt)
+		}
+	})
+}
+
+func restoreAccountForTest(t *testing.T, router *gin.Engine, username, password string) *httptest.ResponseRecorder {
+	t.Helper()
+	jsonData, _ := json.Marshal(RestoreAccountInput{Username: username, Password: password})
+	req, _ := http.NewRequest(http.MethodPost, "/restoreaccount", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestDeleteMyAccount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.DELETE("/v1/myaccount", DeleteMyAccount)
+	router.POST("/restoreaccount", RestoreAccount)
+
+	user := createDisposableAccount(t)
+	token, err := security.GenerateToken(user.ID)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+
+	t.Run("Incorrect password", func(t *testing.T) {
+		w := deleteMyAccountForTest(t, router, token, "wrongpassword")
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d but got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("Restore without pending deletion", func(t *testing.T) {
+		w := restoreAccountForTest(t, router, user.Username, user.Password)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d but got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("Correct password", func(t *testing.T) {
+		testDeleteMyAccountOK(t, router, token, user)
+	})
+
+	t.Run("Already requested", func(t *testing.T) {
+		w := deleteMyAccountForTest(t, router, token, user.Password)
+		if w.Code != http.StatusConflict {
+			t.Errorf("Expected status code %d but got %d", http.StatusConflict, w.Code)
+		}
+	})
+
+	t.Run("Restore", func(t *testing.T) {
+		testRestoreAccountOK(t, router, user)
+	})
+}
+
+func testDeleteMyAccountOK(t *testing.T, router *gin.Engine, token string, user User) {
+	refreshToken, err := security.CreateRefreshToken(context.Background(), user.ID, false)
+	if err != nil {
+		t.Fatalf("failed to create refresh token: %v", err)
+	}
+
+	w := deleteMyAccountForTest(t, router, token, user.Password)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status code %d but got %d: %s", http.StatusAccepted, w.Code, w.Body.String())
+	}
+	var response AccountDeletionResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if response.DeletionScheduledAt.Before(time.Now().Add(deletionGracePeriod() - time.Minute)) {
+		t.Errorf("Expected deletion after the grace period but got %v", response.DeletionScheduledAt)
+	}
+
+	account, err := FindAccountByID(context.Background(), user.ID)
+	if err != nil {
+		t.Fatalf("failed to find account: %v", err)
+	}
+	if account.Status != "inactive" || account.DeletionScheduledAt == nil {
+		t.Errorf("Expected an inactive account pending deletion but got %s, %v",
+			account.Status, account.DeletionScheduledAt)
+	}
+
+	retrieved, err := security.GetRefreshToken(context.Background(), refreshToken.Token)
+	if err != nil {
+		t.Fatalf("failed to get refresh token: %v", err)
+	}
+	if !retrieved.Revoked {
+		t.Errorf("Expected refresh token to be revoked after deletion request")
+	}
+}
+
+func testRestoreAccountOK(t *testing.T, router *gin.Engine, user User) {
+	w := restoreAccountForTest(t, router, user.Username, user.Password)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d but got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var response map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	validateTokenPairResponse(t, response)
+
+	account, err := FindAccountByID(context.Background(), user.ID)
+	if err != nil {
+		t.Fatalf("failed to find account: %v", err)
+	}
+	if account.Status != "active" || account.DeletionScheduledAt != nil {
+		t.Errorf("Expected a restored active account but got %s, %v", account.Status, account.DeletionScheduledAt)
+	}
+}
+
+func TestPurgeDeletedAccounts(t *testing.T) {
+	expired := createDisposableAccount(t)
+	pending := createDisposableAccount(t)
+
+	_, err := database.DB().Exec(
+		`UPDATE account SET status = 'inactive', deletion_requested_at = $2, deletion_scheduled_at = $2
+		WHERE id = $1;`, expired.ID, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("failed to schedule deletion: %v", err)
+	}
+	input := DeleteMyAccountInput{Password: pending.Password}
+	if _, err := requestAccountDeletion(context.Background(), pending.ID, input); err != nil {
+		t.Fatalf("failed to request deletion: %v", err)
+	}
+
+	if _, err := PurgeDeletedAccounts(context.Background()); err != nil {
+		t.Fatalf("failed to purge deleted accounts: %v", err)
+	}
+
+	if _, err := FindAccountByID(context.Background(), expired.ID); err == nil {
+		t.Errorf("Expected account %d past its grace period to be deleted", expired.ID)
+	}
+	if _, err := FindAccountByID(context.Background(), pending.ID); err != nil {
+		t.Errorf("Expected account %d within its grace period to be kept: %v", pending.ID, err)
+	}
+}
@@ -0,0 +1,174 @@
+package accounts
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/Angak0k/pimpmypack/pkg/config"
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/Angak0k/pimpmypack/pkg/helper"
+	"github.com/Angak0k/pimpmypack/pkg/i18n"
+	"github.com/lib/pq"
+)
+
+// emailChangeValidity is how long the confirmation link of an email change stays valid
+const emailChangeValidity = 24 * time.Hour
+
+// requestEmailChange starts the change of the account email to newEmail: the
+// address is stored as pending and a confirmation link, reusing the
+// confirmation code of the registration, is sent to it, with a notice to the
+// current address. The email itself only changes in confirmEmailChange.
+// Submitting the current or already pending address is a no-op.
+func requestEmailChange(ctx context.Context, userID uint, newEmail string) error {
+	var u User
+	var pendingEmail sql.NullString
+	var requestedAt sql.NullTime
+	err := database.DB().QueryRowContext(ctx,
+		`SELECT id, username, email, preferred_language, pending_email, pending_email_requested_at
+		FROM account WHERE id = $1;`,
+		userID).Scan(&u.ID, &u.Username, &u.Email, &u.PreferredLanguage, &pendingEmail, &requestedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNoAccountFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to query account: %w", err)
+	}
+
+	if newEmail == u.Email {
+		return nil
+	}
+	if pendingEmail.String == newEmail && requestedAt.Valid && time.Since(requestedAt.Time) < emailChangeValidity {
+		return nil
+	}
+
+	var inUse bool
+	err = database.DB().QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM account WHERE email = $1 AND id <> $2);`,
+		newEmail, userID).Scan(&inUse)
+	if err != nil {
+		return fmt.Errorf("failed to check email availability: %w", err)
+	}
+	if inUse {
+		return ErrEmailAlreadyExists
+	}
+
+	confirmationCode, err := helper.GenerateRandomCode(30)
+	if err != nil {
+		return fmt.Errorf("failed to generate confirmation code: %w", err)
+	}
+
+	// A new code also invalidates the link of any previous request
+	_, err = database.DB().ExecContext(ctx,
+		`UPDATE account SET pending_email = $2, pending_email_requested_at = $3, confirmation_code = $4
+		WHERE id = $1;`,
+		userID, newEmail, time.Now(), confirmationCode)
+	if err != nil {
+		return fmt.Errorf("failed to store pending email: %w", err)
+	}
+
+	// Non-fatal: submitting the new address again sends a new link
+	if err := sendEmailChangeConfirmation(u, newEmail, confirmationCode); err != nil {
+		helper.LogAndSanitize(err, "email change: send confirmation email failed")
+	}
+	if err := sendEmailChangeNotice(u, newEmail); err != nil {
+		helper.LogAndSanitize(err, "email change: send notice email failed")
+	}
+
+	return nil
+}
+
+func sendEmailChangeConfirmation(u User, newEmail, code string) error {
+	// LOCAL mode: Don't send email, just log the confirmation details
+	if config.Stage == stageLocal {
+		log.Printf("LOCAL MODE: Email change requested for user %s (ID: %d)", u.Username, u.ID)
+		log.Printf("LOCAL MODE: Confirm at: /api/confirmemail?id=%d&code=%s", u.ID, code)
+		return nil
+	}
+
+	confirmURL := config.Scheme + "://" + config.HostName + "/confirmemail.html?id=" +
+		strconv.FormatUint(uint64(u.ID), 10) + "&code=" + code
+
+	mailRcpt := newEmail
+	lang := u.PreferredLanguage
+	mailSubject := i18n.T(lang, "email.email_change.subject")
+	mailTextBody := helper.BuildEmailChangeConfirmationEmailText(lang, u.Username, confirmURL)
+	mailHTMLBody := helper.BuildEmailChangeConfirmationEmailHTML(lang, u.Username, confirmURL)
+
+	err := getEmailSender().SendEmail(mailRcpt, mailSubject, mailTextBody, mailHTMLBody)
+	if err != nil {
+		return fmt.Errorf("failed to send email change confirmation: %w", err)
+	}
+
+	return nil
+}
+
+func sendEmailChangeNotice(u User, newEmail string) error {
+	if config.Stage == stageLocal {
+		return nil
+	}
+
+	mailRcpt := u.Email
+	lang := u.PreferredLanguage
+	mailSubject := i18n.T(lang, "email.email_change_notice.subject")
+	mailTextBody := helper.BuildEmailChangeNoticeEmailText(lang, u.Username, newEmail)
+	mailHTMLBody := helper.BuildEmailChangeNoticeEmailHTML(lang, u.Username, newEmail)
+
+	err := getEmailSender().SendEmail(mailRcpt, mailSubject, mailTextBody, mailHTMLBody)
+	if err != nil {
+		return fmt.Errorf("failed to send email change notice: %w", err)
+	}
+
+	return nil
+}
+
+// confirmEmailChange swaps the pending email in when id and code match a
+// change requested less than emailChangeValidity ago. It returns the account
+// ID, or 0 when the code is not a valid email change link. The code is
+// single-use.
+func confirmEmailChange(ctx context.Context, id string, code string) (uint, error) {
+	var userID uint
+	err := database.DB().QueryRowContext(ctx,
+		`UPDATE account
+		SET email = pending_email, pending_email = NULL, pending_email_requested_at = NULL,
+			confirmation_code = NULL, updated_at = $4
+		WHERE id = $1 AND confirmation_code = $2
+			AND pending_email IS NOT NULL AND pending_email_requested_at > $3
+		RETURNING id;`,
+		id, code, time.Now().Add(-emailChangeValidity), time.Now().Truncate(time.Second)).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		var pqErr *pq.Error
+		// Registered by someone else since the request
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "account_email_unique" {
+			return 0, ErrEmailAlreadyExists
+		}
+		return 0, fmt.Errorf("failed to confirm email change: %w", err)
+	}
+
+	return userID, nil
+}
+
+// CleanupExpiredEmailChanges drops the pending emails whose confirmation link has expired
+func CleanupExpiredEmailChanges(ctx context.Context) (int64, error) {
+	result, err := database.DB().ExecContext(ctx,
+		`UPDATE account SET pending_email = NULL, pending_email_requested_at = NULL
+		WHERE pending_email_requested_at <= $1;`,
+		time.Now().Add(-emailChangeValidity))
+	if err != nil {
+		return 0, fmt.Errorf("failed to cleanup expired email changes: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
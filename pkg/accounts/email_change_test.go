@@ -0,0 +1,161 @@
+package accounts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/Angak0k/pimpmypack/pkg/security"
+	"github.com/gin-gonic/gin"
+	"github.com/gruntwork-io/terratest/modules/random"
+)
+
+func putMyEmailForTest(t *testing.T, router *gin.Engine, token, email string) *httptest.ResponseRecorder {
+	t.Helper()
+	input := AccountUpdateInput{
+		Email:     email,
+		Firstname: "Del",
+		Lastname:  "Etable",
+	}
+	jsonData, _ := json.Marshal(input)
+	req, _ := http.NewRequest(http.MethodPut, "/v1/myaccount", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func confirmEmailForTest(t *testing.T, router *gin.Engine, userID uint, code string) *httptest.ResponseRecorder {
+	t.Helper()
+	path := fmt.Sprintf("/confirmemail?id=%d&code=%s", userID, code)
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func readEmailsForTest(t *testing.T, userID uint) (string, *string, string) {
+	t.Helper()
+	var email string
+	var pendingEmail, code *string
+	err := database.DB().QueryRow(
+		`SELECT email, pending_email, confirmation_code FROM account WHERE id = $1`, userID,
+	).Scan(&email, &pendingEmail, &code)
+	if err != nil {
+		t.Fatalf("failed to read account emails: %v", err)
+	}
+	if code == nil {
+		return email, pendingEmail, ""
+	}
+	return email, pendingEmail, *code
+}
+
+func TestEmailChange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.PUT("/v1/myaccount", PutMyAccount)
+	router.GET("/confirmemail", ConfirmEmail)
+
+	user := createDisposableAccount(t)
+	token, err := security.GenerateToken(user.ID)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	newEmail := "changed-" + random.UniqueID() + "@exemple.com"
+
+	t.Run("Email already in use", func(t *testing.T) {
+		w := putMyEmailForTest(t, router, token, users[0].Email)
+		if w.Code != http.StatusConflict {
+			t.Errorf("Expected status code %d but got %d", http.StatusConflict, w.Code)
+		}
+	})
+
+	t.Run("New email is pending", func(t *testing.T) {
+		testEmailChangePending(t, router, token, user, newEmail)
+	})
+
+	t.Run("Wrong code keeps the current email", func(t *testing.T) {
+		confirmEmailForTest(t, router, user.ID, "wrong-code")
+		email, _, _ := readEmailsForTest(t, user.ID)
+		if email != user.Email {
+			t.Errorf("Expected email %s but got %s", user.Email, email)
+		}
+	})
+
+	t.Run("Confirmation swaps the email", func(t *testing.T) {
+		_, _, code := readEmailsForTest(t, user.ID)
+		w := confirmEmailForTest(t, router, user.ID, code)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d but got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		email, pendingEmail, remainingCode := readEmailsForTest(t, user.ID)
+		if email != newEmail || pendingEmail != nil || remainingCode != "" {
+			t.Errorf("Expected email %s with no pending change but got %s, %v, %q",
+				newEmail, email, pendingEmail, remainingCode)
+		}
+	})
+}
+
+func TestEmailChange_ExpiredLink(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/confirmemail", ConfirmEmail)
+
+	user := createDisposableAccount(t)
+	_, err := database.DB().Exec(
+		`UPDATE account SET status = 'inactive', pending_email = $2, pending_email_requested_at = $3,
+			confirmation_code = 'expired-code'
+		WHERE id = $1;`,
+		user.ID, "expired-"+random.UniqueID()+"@exemple.com", time.Now().Add(-emailChangeValidity-time.Minute))
+	if err != nil {
+		t.Fatalf("failed to set pending email: %v", err)
+	}
+
+	confirmEmailForTest(t, router, user.ID, "expired-code")
+
+	var email, status string
+	err = database.DB().QueryRow(`SELECT email, status FROM account WHERE id = $1`, user.ID).Scan(&email, &status)
+	if err != nil {
+		t.Fatalf("failed to read account: %v", err)
+	}
+	if email != user.Email {
+		t.Errorf("Expected an expired link to keep email %s but got %s", user.Email, email)
+	}
+	if status != "inactive" {
+		t.Errorf("Expected an expired link not to reactivate the account but status is %s", status)
+	}
+}
+
+func testEmailChangePending(t *testing.T, router *gin.Engine, token string, user User, newEmail string) {
+	t.Helper()
+	w := putMyEmailForTest(t, router, token, newEmail)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d but got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var account Account
+	if err := json.Unmarshal(w.Body.Bytes(), &account); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if account.Email != user.Email {
+		t.Errorf("Expected email to stay %s until confirmed but got %s", user.Email, account.Email)
+	}
+	if account.PendingEmail == nil || *account.PendingEmail != newEmail {
+		t.Errorf("Expected pending email %s but got %v", newEmail, account.PendingEmail)
+	}
+
+	_, _, code := readEmailsForTest(t, user.ID)
+	confirmations := mailSink.MessagesTo(newEmail)
+	if len(confirmations) != 1 || !strings.Contains(confirmations[0].TextBody, "code="+code) {
+		t.Errorf("Expected one confirmation link sent to %s, got %+v", newEmail, confirmations)
+	}
+	if len(mailSink.MessagesTo(user.Email)) != 1 {
+		t.Errorf("Expected a notice sent to the current address %s", user.Email)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/Angak0k/pimpmypack/pkg/config"
 	"github.com/Angak0k/pimpmypack/pkg/database"
 	"github.com/Angak0k/pimpmypack/pkg/helper"
+	"github.com/Angak0k/pimpmypack/pkg/i18n"
+	"github.com/Angak0k/pimpmypack/pkg/oidc"
 	"github.com/Angak0k/pimpmypack/pkg/security"
 	"github.com/gin-gonic/gin"
 )
@@ -16,6 +18,7 @@ import (
 // Register a new user account
 // @Summary Register new user
 // @Description Register a new user with username, password, email, firstname, and lastname
+// @Description Emails are sent in preferred_language (en, fr), the Accept-Language header by default
 // @Tags Public
 // @Accept  json
 // @Produce  json
@@ -44,16 +47,25 @@ func Register(c *gin.Context) {
 		return
 	}
 
+	lang := input.PreferredLanguage
+	if lang == "" {
+		lang = i18n.Language(c)
+	} else if !i18n.IsSupported(lang) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": ErrUnsupportedLanguage.Error()})
+		return
+	}
+
 	user := User{
-		Email:     input.Email,
-		Username:  input.Username,
-		Password:  input.Password,
-		Firstname: input.Firstname,
-		Lastname:  input.Lastname,
-		Role:      "standard",
-		Status:    "pending",
-		CreatedAt: time.Now().Truncate(time.Second),
-		UpdatedAt: time.Now().Truncate(time.Second),
+		Email:             input.Email,
+		Username:          input.Username,
+		Password:          input.Password,
+		Firstname:         input.Firstname,
+		Lastname:          input.Lastname,
+		Role:              "standard",
+		Status:            "pending",
+		CreatedAt:         time.Now().Truncate(time.Second),
+		UpdatedAt:         time.Now().Truncate(time.Second),
+		PreferredLanguage: lang,
 	}
 
 	emailSended, err := registerUser(c.Request.Context(), user)
@@ -79,10 +91,12 @@ func Register(c *gin.Context) {
 // Confirm email address
 // @Summary Confirm email address
 // @Description Confirm email address by providing username and email
+// @Description The link of an email change swaps the new address in instead
 // @Tags Public
 // @Produce  json
 // @Success 200 {object} apitypes.OkResponse
 // @Failure 400 {object} apitypes.ErrorResponse
+// @Failure 409 {object} apitypes.ErrorResponse "New email already in use"
 // @Failure 500 {object} apitypes.ErrorResponse
 // @Router /confirmemail [get]
 func ConfirmEmail(c *gin.Context) {
@@ -119,7 +133,23 @@ func ConfirmEmail(c *gin.Context) {
 		return
 	}
 
-	err := confirmEmail(c.Request.Context(), userID, confirmationCode)
+	changedID, err := confirmEmailChange(c.Request.Context(), userID, confirmationCode)
+	if err != nil {
+		if errors.Is(err, ErrEmailAlreadyExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": "email already in use"})
+			return
+		}
+		helper.LogAndSanitize(err, "confirm email change failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+	if changedID != 0 {
+		security.AuditEmailChanged(c, changedID)
+		c.JSON(http.StatusOK, gin.H{"message": "email changed"})
+		return
+	}
+
+	err = confirmEmail(c.Request.Context(), userID, confirmationCode)
 
 	if err != nil {
 		helper.LogAndSanitize(err, "confirm email failed")
@@ -130,6 +160,33 @@ func ConfirmEmail(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "email confirmed"})
 }
 
+// Unlock account
+// @Summary Unlock account
+// @Description Lift an account lock early using the link sent by email when the account was locked
+// @Tags Public
+// @Produce  json
+// @Param   id    query  int     true  "Account ID"
+// @Param   code  query  string  true  "Unlock code"
+// @Success 200 {object} apitypes.OkResponse
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid or expired unlock link"
+// @Failure 500 {object} apitypes.ErrorResponse
+// @Router /unlockaccount [get]
+func UnlockAccount(c *gin.Context) {
+	userID, err := unlockAccountWithCode(c.Request.Context(), c.Query("id"), c.Query("code"))
+	if err != nil {
+		if errors.Is(err, ErrInvalidUnlockLink) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		helper.LogAndSanitize(err, "unlock account failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	security.AuditAccountUnlocked(c, userID, "email")
+	c.JSON(http.StatusOK, gin.H{"message": "account unlocked"})
+}
+
 // Reset password
 // @Summary Reset password
 // @Description Send a new password to the user's email
@@ -209,6 +266,8 @@ func ResendConfirmEmail(c *gin.Context) {
 // @Success 200 {object} security.TokenPairResponse "Login successful with access and refresh tokens"
 // @Failure 400 {object} apitypes.ErrorResponse "Bad request"
 // @Failure 401 {object} apitypes.ErrorResponse "Invalid credentials or account not confirmed"
+// @Failure 423 {object} apitypes.ErrorResponse "Account temporarily locked after repeated failed logins"
+// @Failure 429 {object} apitypes.ErrorResponse "Too many failed login attempts, retry later"
 // @Failure 500 {object} apitypes.ErrorResponse "Internal server error"
 // @Router /login [post]
 func Login(c *gin.Context) {
@@ -220,21 +279,34 @@ func Login(c *gin.Context) {
 		return
 	}
 
-	tokenPair, userID, pending, err := loginCheck(c.Request.Context(), input.Username, input.Password, input.RememberMe)
+	tokenPair, userID, pending, err := loginCheck(
+		c.Request.Context(), input.Username, input.Password, input.RememberMe, c.ClientIP(),
+	)
 
 	if err != nil {
+		var throttled *security.LoginThrottledError
+		if errors.As(err, &throttled) {
+			security.AuditLoginThrottled(c, userID, input.Username, throttled)
+			status := http.StatusTooManyRequests
+			if throttled.Locked {
+				status = http.StatusLocked
+			}
+			c.Header("Retry-After", strconv.Itoa(throttled.RetryAfterSeconds()))
+			c.JSON(status, gin.H{"error": throttled.Error(), "retry_after": throttled.RetryAfterSeconds()})
+			return
+		}
 		if errors.Is(err, ErrInvalidCredentials) {
-			security.AuditLoginFailed(c, input.Username, "invalid credentials")
+			security.AuditLoginFailed(c, userID, input.Username, "invalid credentials")
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "credentials are incorrect"})
 			return
 		}
-		security.AuditLoginFailed(c, input.Username, "authentication failed")
+		security.AuditLoginFailed(c, userID, input.Username, "authentication failed")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "authentication failed"})
 		return
 	}
 
 	if pending {
-		security.AuditLoginFailed(c, input.Username, "account not yet confirmed")
+		security.AuditLoginFailed(c, userID, input.Username, "account not yet confirmed")
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "account not yet confirmed"})
 		return
 	}
@@ -243,6 +315,109 @@ func Login(c *gin.Context) {
 	c.JSON(http.StatusOK, tokenPair)
 }
 
+// Start an OIDC login
+// @Summary Start OIDC login
+// @Description Returns the identity provider URL to send the user to and a login binding the browser
+// @Description keeps. The provider redirects back to the configured redirect URI with code and state,
+// @Description to be posted to /auth/oidc/callback with the login binding.
+// @Tags Public
+// @Produce  json
+// @Success 200 {object} OIDCAuthorizationResponse "Provider authorization URL"
+// @Failure 404 {object} apitypes.ErrorResponse "OIDC login is not enabled"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal server error"
+// @Router /auth/oidc/authorize [get]
+func OIDCAuthorize(c *gin.Context) {
+	authURL, binding, err := startOIDCLogin(c.Request.Context())
+	if err != nil {
+		if errors.Is(err, ErrOIDCDisabled) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		helper.LogAndSanitize(err, "oidc authorize: start login failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.JSON(http.StatusOK, OIDCAuthorizationResponse{AuthorizationURL: authURL, LoginBinding: binding})
+}
+
+// Complete an OIDC login
+// @Summary Complete OIDC login
+// @Description Exchanges the authorization code returned by the identity provider and logs the user in.
+// @Description The identity logs into the account it is linked to, else the account with the same
+// @Description verified email (which gets linked), else a new account when auto-provisioning is enabled.
+// @Description A pending account linked this way is activated and its password replaced by a random one.
+// @Tags Public
+// @Accept  json
+// @Produce  json
+// @Param   input  body    OIDCCallbackInput  true  "Code and state from the provider redirect"
+// @Success 200 {object} security.TokenPairResponse "Login successful with access and refresh tokens"
+// @Failure 400 {object} apitypes.ErrorResponse "Bad request or invalid state"
+// @Failure 401 {object} apitypes.ErrorResponse "Identity verification failed or account disabled"
+// @Failure 403 {object} apitypes.ErrorResponse "No account linked to this identity"
+// @Failure 404 {object} apitypes.ErrorResponse "OIDC login is not enabled"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal server error"
+// @Router /auth/oidc/callback [post]
+func OIDCCallback(c *gin.Context) {
+	var input OIDCCallbackInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		helper.LogAndSanitize(err, "oidc callback: bind JSON failed")
+		c.JSON(http.StatusBadRequest, gin.H{"error": helper.ErrMsgBadRequest})
+		return
+	}
+
+	issuer, claims, err := completeOIDCLogin(c.Request.Context(), input.Code, input.State, input.LoginBinding)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrOIDCDisabled):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrInvalidOIDCState):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, oidc.ErrTokenExchange), errors.Is(err, oidc.ErrInvalidIDToken):
+			helper.LogAndSanitize(err, "oidc callback: identity verification failed")
+			security.AuditLoginFailed(c, 0, "", "OIDC identity verification failed")
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "identity verification failed"})
+		default:
+			helper.LogAndSanitize(err, "oidc callback: complete login failed")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		}
+		return
+	}
+
+	account, err := resolveOIDCAccount(c.Request.Context(), issuer, claims)
+	if err != nil {
+		if errors.Is(err, ErrOIDCAccountNotFound) {
+			security.AuditLoginFailed(c, 0, claims.Email, "no account linked to OIDC identity")
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		helper.LogAndSanitize(err, "oidc callback: resolve account failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	if account.Linked {
+		security.AuditOIDCAccountLinked(c, account.ID, issuer, account.Provisioned)
+	}
+
+	if account.Status != statusActive {
+		security.AuditLoginFailed(c, account.ID, claims.Email, "account not active")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "account is not active"})
+		return
+	}
+
+	tokenPair, err := security.GenerateTokenPair(c.Request.Context(), account.ID, input.RememberMe)
+	if err != nil {
+		helper.LogAndSanitize(err, "oidc callback: generate token pair failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	security.AuditLoginSuccess(c, account.ID, input.RememberMe)
+	c.JSON(http.StatusOK, tokenPair)
+}
+
 // Get my account information
 // @Summary Get account info
 // @Description Get information of the currently logged-in user
@@ -344,6 +519,8 @@ func PutMyPassword(c *gin.Context) {
 // Update my account information
 // @Summary Update account info
 // @Description Update information of the currently logged-in user (email, name, preferences only)
+// @Description A new email is returned as pending_email and only replaces the current one once the
+// @Description link sent to it is confirmed (/confirmemail); the current address is notified
 // @Security Bearer
 // @Tags Accounts
 // @Accept  json
@@ -352,6 +529,7 @@ func PutMyPassword(c *gin.Context) {
 // @Success 200 {object} Account
 // @Failure 400 {object} apitypes.ErrorResponse
 // @Failure 401 {object} apitypes.ErrorResponse
+// @Failure 409 {object} apitypes.ErrorResponse "Email already in use"
 // @Failure 500 {object} apitypes.ErrorResponse
 // @Router /v1/myaccount [put]
 func PutMyAccount(c *gin.Context) {
@@ -378,6 +556,10 @@ func PutMyAccount(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Account not found"})
 			return
 		}
+		if errors.Is(err, ErrEmailAlreadyExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": "email already in use"})
+			return
+		}
 		// Return validation errors as 400
 		if isValidationError(err) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -391,6 +573,136 @@ func PutMyAccount(c *gin.Context) {
 	c.IndentedJSON(http.StatusOK, account)
 }
 
+// Delete my account
+// @Summary Delete my account
+// @Description Schedule the deletion of the currently logged-in user's account, confirmed with the password
+// @Description or, for accounts logging in through OIDC, with the code, state and login binding of a fresh
+// @Description OIDC login started with /auth/oidc/authorize.
+// @Description The account is deactivated at once and permanently deleted with all its data after the
+// @Description grace period (ACCOUNT_DELETION_GRACE_DAYS); until then it can be restored with /restoreaccount.
+// @Security Bearer
+// @Tags Accounts
+// @Accept  json
+// @Produce  json
+// @Param   input  body    DeleteMyAccountInput  true  "Password or OIDC re-authentication"
+// @Success 202 {object} AccountDeletionResponse "Deletion scheduled"
+// @Failure 400 {object} apitypes.ErrorResponse "Bad Request, incorrect password or failed re-authentication"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 404 {object} apitypes.ErrorResponse "OIDC login is not enabled"
+// @Failure 409 {object} apitypes.ErrorResponse "Deletion already requested"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/myaccount [delete]
+func DeleteMyAccount(c *gin.Context) {
+	var input DeleteMyAccountInput
+
+	userID, err := security.ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, "delete my account: extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		helper.LogAndSanitize(err, "delete my account: bind JSON failed")
+		c.JSON(http.StatusBadRequest, gin.H{"error": helper.ErrMsgBadRequest})
+		return
+	}
+
+	scheduledAt, err := requestAccountDeletion(c.Request.Context(), userID, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidCredentials):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "password is incorrect"})
+		case errors.Is(err, ErrReauthenticationFailed):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrOIDCDisabled):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrDeletionAlreadyRequested):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrNoAccountFound):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		default:
+			helper.LogAndSanitize(err, "delete my account: request deletion failed")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		}
+		return
+	}
+
+	security.AuditAccountDeletionRequested(c, userID, scheduledAt)
+	c.JSON(http.StatusAccepted, AccountDeletionResponse{
+		Message:             "account deactivated and scheduled for deletion",
+		DeletionScheduledAt: scheduledAt.UTC(),
+	})
+}
+
+// Restore an account scheduled for deletion
+// @Summary Restore account
+// @Description Cancel the pending deletion of an account during its grace period and log in.
+// @Description The username field accepts either a username or an email address. Accounts logging in
+// @Description through OIDC send a fresh OIDC login started with /auth/oidc/authorize as oidc instead.
+// @Tags Public
+// @Accept  json
+// @Produce  json
+// @Param   input  body    RestoreAccountInput  true  "Credentials of the account"
+// @Success 200 {object} security.TokenPairResponse "Account restored, with access and refresh tokens"
+// @Failure 400 {object} apitypes.ErrorResponse "Bad request or account not scheduled for deletion"
+// @Failure 401 {object} apitypes.ErrorResponse "Invalid credentials or re-authentication failed"
+// @Failure 404 {object} apitypes.ErrorResponse "OIDC login is not enabled"
+// @Failure 423 {object} apitypes.ErrorResponse "Account temporarily locked after repeated failed logins"
+// @Failure 429 {object} apitypes.ErrorResponse "Too many failed login attempts, retry later"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal server error"
+// @Router /restoreaccount [post]
+func RestoreAccount(c *gin.Context) {
+	var input RestoreAccountInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		helper.LogAndSanitize(err, "restore account: bind JSON failed")
+		c.JSON(http.StatusBadRequest, gin.H{"error": helper.ErrMsgBadRequest})
+		return
+	}
+
+	userID, err := restoreAccount(c.Request.Context(), input, c.ClientIP())
+	if err != nil {
+		var throttled *security.LoginThrottledError
+		switch {
+		case errors.As(err, &throttled):
+			security.AuditLoginThrottled(c, userID, input.Username, throttled)
+			status := http.StatusTooManyRequests
+			if throttled.Locked {
+				status = http.StatusLocked
+			}
+			c.Header("Retry-After", strconv.Itoa(throttled.RetryAfterSeconds()))
+			c.JSON(status, gin.H{"error": throttled.Error(), "retry_after": throttled.RetryAfterSeconds()})
+		case errors.Is(err, ErrInvalidCredentials):
+			security.AuditLoginFailed(c, userID, input.Username, "invalid credentials")
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "credentials are incorrect"})
+		case errors.Is(err, ErrReauthenticationFailed):
+			security.AuditLoginFailed(c, userID, input.Username, err.Error())
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrOIDCDisabled):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrNoPendingDeletion):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			helper.LogAndSanitize(err, "restore account: restore failed")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		}
+		return
+	}
+
+	security.AuditAccountRestored(c, userID)
+
+	tokenPair, err := security.GenerateTokenPair(c.Request.Context(), userID, false)
+	if err != nil {
+		helper.LogAndSanitize(err, "restore account: generate token pair failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	security.AuditLoginSuccess(c, userID, false)
+	c.JSON(http.StatusOK, tokenPair)
+}
+
 // Get all accounts
 // @Summary [ADMIN] Get all accounts
 // @Description Get all accounts - for admin use only
@@ -553,3 +865,37 @@ func DeleteAccountByID(c *gin.Context) {
 	}
 	c.IndentedJSON(http.StatusOK, gin.H{"message": "Account deleted"})
 }
+
+// Unlock account by ID
+// @Summary [ADMIN] Unlock account by ID
+// @Description Lift the lock and login backoff of an account - for admin use only
+// @Security Bearer
+// @Tags Internal
+// @Produce  json
+// @Param   id  path    int  true  "Account ID"
+// @Success 200 {object} apitypes.OkResponse
+// @Failure 400 {object} apitypes.ErrorResponse
+// @Failure 500 {object} apitypes.ErrorResponse
+// @Router /admin/accounts/{id}/unlock [post]
+func UnlockAccountByID(c *gin.Context) {
+	id, err := helper.StringToUint(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	locked, err := security.UnlockAccount(c.Request.Context(), id)
+	if err != nil {
+		helper.LogAndSanitize(err, "unlock account by ID: unlock account failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	if !locked {
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Account was not locked"})
+		return
+	}
+
+	security.AuditAccountUnlocked(c, id, "admin")
+	c.IndentedJSON(http.StatusOK, gin.H{"message": "Account unlocked"})
+}
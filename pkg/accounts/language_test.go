@@ -0,0 +1,163 @@
+package accounts
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/Angak0k/pimpmypack/pkg/i18n"
+	"github.com/Angak0k/pimpmypack/pkg/security"
+	"github.com/gin-gonic/gin"
+	"github.com/gruntwork-io/terratest/modules/random"
+)
+
+func registerForTest(t *testing.T, router *gin.Engine, input RegisterInput, acceptLanguage string) int {
+	t.Helper()
+	jsonData, _ := json.Marshal(input)
+	req, _ := http.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", acceptLanguage)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	t.Cleanup(func() {
+		_, _ = database.DB().Exec("DELETE FROM account WHERE username = $1", input.Username)
+	})
+	return w.Code
+}
+
+func readPreferredLanguageForTest(t *testing.T, username string) string {
+	t.Helper()
+	var lang string
+	err := database.DB().QueryRow(`SELECT preferred_language FROM account WHERE username = $1`, username).Scan(&lang)
+	if err != nil {
+		t.Fatalf("failed to read preferred language: %v", err)
+	}
+	return lang
+}
+
+func TestRegister_PreferredLanguage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.Use(i18n.Middleware())
+	router.POST("/register", Register)
+
+	newInput := func() RegisterInput {
+		id := random.UniqueID()
+		return RegisterInput{
+			Username:  "lang-" + id,
+			Password:  "password",
+			Email:     "lang-" + id + "@exemple.com",
+			Firstname: "Jeanne",
+			Lastname:  "Dupont",
+		}
+	}
+
+	t.Run("Accept-Language is the default", func(t *testing.T) {
+		input := newInput()
+		if code := registerForTest(t, router, input, "fr-FR,fr;q=0.9,en;q=0.8"); code != http.StatusOK {
+			t.Fatalf("Expected status code %d but got %d", http.StatusOK, code)
+		}
+		if lang := readPreferredLanguageForTest(t, input.Username); lang != "fr" {
+			t.Errorf("Expected preferred language fr but got %s", lang)
+		}
+		messages := mailSink.MessagesTo(input.Email)
+		if len(messages) != 1 || messages[0].Subject != i18n.T("fr", "email.confirmation.subject") {
+			t.Fatalf("Expected one French confirmation email, got %+v", messages)
+		}
+		if !strings.Contains(messages[0].TextBody, "Bienvenue, "+input.Username) {
+			t.Errorf("Expected a French body, got %s", messages[0].TextBody)
+		}
+	})
+
+	t.Run("Explicit preferred_language wins", func(t *testing.T) {
+		input := newInput()
+		input.PreferredLanguage = "en"
+		if code := registerForTest(t, router, input, "fr"); code != http.StatusOK {
+			t.Fatalf("Expected status code %d but got %d", http.StatusOK, code)
+		}
+		if lang := readPreferredLanguageForTest(t, input.Username); lang != "en" {
+			t.Errorf("Expected preferred language en but got %s", lang)
+		}
+	})
+
+	t.Run("Unsupported preferred_language", func(t *testing.T) {
+		input := newInput()
+		input.PreferredLanguage = "xx"
+		if code := registerForTest(t, router, input, ""); code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d but got %d", http.StatusBadRequest, code)
+		}
+	})
+}
+
+func putMyLanguageForTest(
+	t *testing.T, router *gin.Engine, token string, user User, lang string,
+) *httptest.ResponseRecorder {
+	t.Helper()
+	input := AccountUpdateInput{
+		Email:             user.Email,
+		Firstname:         "Del",
+		Lastname:          "Etable",
+		PreferredLanguage: &lang,
+	}
+	jsonData, _ := json.Marshal(input)
+	req, _ := http.NewRequest(http.MethodPut, "/v1/myaccount", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept-Language", "fr")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestPutMyAccount_PreferredLanguage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.Use(i18n.Middleware())
+	router.PUT("/v1/myaccount", PutMyAccount)
+	router.POST("/forgotpassword", ForgotPassword)
+
+	user := createDisposableAccount(t)
+	token, err := security.GenerateToken(user.ID)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+
+	t.Run("Unsupported language is rejected in the request language", func(t *testing.T) {
+		w := putMyLanguageForTest(t, router, token, user, "xx")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status code %d but got %d", http.StatusBadRequest, w.Code)
+		}
+		if !strings.Contains(w.Body.String(), "preferred_language non pris en charge") {
+			t.Errorf("Expected a French error message, got %s", w.Body.String())
+		}
+	})
+
+	t.Run("Emails follow the preferred language", func(t *testing.T) {
+		w := putMyLanguageForTest(t, router, token, user, "fr")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d but got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var account Account
+		if err := json.Unmarshal(w.Body.Bytes(), &account); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if account.PreferredLanguage != "fr" {
+			t.Errorf("Expected preferred language fr but got %s", account.PreferredLanguage)
+		}
+
+		jsonData, _ := json.Marshal(ForgotPasswordInput{Email: user.Email})
+		req, _ := http.NewRequest(http.MethodPost, "/forgotpassword", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		messages := mailSink.MessagesTo(user.Email)
+		if len(messages) == 0 || messages[len(messages)-1].Subject != i18n.T("fr", "email.password_reset.subject") {
+			t.Errorf("Expected a French password reset email, got %+v", messages)
+		}
+	})
+}
@@ -0,0 +1,410 @@
+package accounts
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Angak0k/pimpmypack/pkg/config"
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/Angak0k/pimpmypack/pkg/oidc"
+	"github.com/Angak0k/pimpmypack/pkg/security"
+)
+
+// ErrOIDCDisabled is returned when OIDC login is not configured.
+var ErrOIDCDisabled = errors.New("OIDC login is not enabled")
+
+// ErrInvalidOIDCState is returned when the callback state is unknown, already used or expired.
+var ErrInvalidOIDCState = errors.New("invalid or expired OIDC login state")
+
+// ErrOIDCAccountNotFound is returned when no account can be linked to or provisioned for the identity.
+var ErrOIDCAccountNotFound = errors.New("no account linked to this identity")
+
+// oidcStateTTL bounds the time between the authorization redirect and the callback
+const oidcStateTTL = 10 * time.Minute
+
+// maxUsernameAttempts bounds the suffixes tried when a provisioned username is taken
+const maxUsernameAttempts = 20
+
+// oidcProvider is discovered lazily at the first OIDC login, replaceable for testing.
+var (
+	oidcProviderMu sync.Mutex
+	oidcProvider   *oidc.Provider
+)
+
+// setOIDCProvider sets the OIDC provider (used in tests to inject a mock).
+func setOIDCProvider(p *oidc.Provider) {
+	oidcProviderMu.Lock()
+	defer oidcProviderMu.Unlock()
+	oidcProvider = p
+}
+
+// getOIDCProvider returns the configured provider, running discovery on first use.
+// A failed discovery is retried at the next login.
+func getOIDCProvider(ctx context.Context) (*oidc.Provider, error) {
+	oidcProviderMu.Lock()
+	defer oidcProviderMu.Unlock()
+
+	if oidcProvider != nil {
+		return oidcProvider, nil
+	}
+	if config.OIDCDiscoveryURL == "" {
+		return nil, ErrOIDCDisabled
+	}
+
+	provider, err := oidc.Discover(ctx, config.OIDCDiscoveryURL, oidc.ClientConfig{
+		ClientID:     config.OIDCClientID,
+		ClientSecret: config.OIDCClientSecret,
+		RedirectURL:  config.OIDCRedirectURL,
+		Scopes:       config.OIDCScopes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	oidcProvider = provider
+	return provider, nil
+}
+
+// startOIDCLogin stores a new login state, nonce and PKCE verifier and
+// returns the provider URL the user must be sent to, with the login binding
+// the browser keeps to complete the login
+func startOIDCLogin(ctx context.Context) (string, string, error) {
+	provider, err := getOIDCProvider(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err := oidc.RandomToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := oidc.RandomToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		return "", "", err
+	}
+	binding, err := oidc.RandomToken(32)
+	if err != nil {
+		return "", "", err
+	}
+
+	_, err = database.DB().ExecContext(ctx,
+		`INSERT INTO oidc_login_state (state, nonce, code_verifier, binding_hash, expires_at)
+		VALUES ($1, $2, $3, $4, $5);`,
+		state, nonce, verifier, hashLoginBinding(binding), time.Now().Add(oidcStateTTL))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to store OIDC login state: %w", err)
+	}
+
+	return provider.AuthCodeURL(state, nonce, oidc.CodeChallengeS256(verifier)), binding, nil
+}
+
+// hashLoginBinding returns the SHA-256 of a login binding, as stored
+func hashLoginBinding(binding string) string {
+	sum := sha256.Sum256([]byte(binding))
+	return hex.EncodeToString(sum[:])
+}
+
+// completeOIDCLogin consumes the login state and exchanges the authorization
+// code, returning the provider issuer and the verified ID token claims. The
+// login binding must be the one returned when the login was started, so a
+// state can only be completed by the browser that started the login.
+func completeOIDCLogin(ctx context.Context, code, state, binding string) (string, *oidc.Claims, error) {
+	provider, err := getOIDCProvider(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var nonce, verifier, bindingHash string
+	// The state is deleted whatever the outcome, so a callback cannot be replayed
+	err = database.DB().QueryRowContext(ctx,
+		`DELETE FROM oidc_login_state
+		WHERE state = $1 AND expires_at > $2
+		RETURNING nonce, code_verifier, binding_hash;`,
+		state, time.Now()).Scan(&nonce, &verifier, &bindingHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil, ErrInvalidOIDCState
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to consume OIDC login state: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(hashLoginBinding(binding)), []byte(bindingHash)) != 1 {
+		return "", nil, ErrInvalidOIDCState
+	}
+
+	claims, err := provider.Exchange(ctx, code, verifier, nonce)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return provider.Issuer(), claims, nil
+}
+
+// oidcAccount is the account an OIDC identity logs into
+type oidcAccount struct {
+	ID     uint
+	Status string
+	// Linked is set when the identity was linked to the account by this login
+	Linked bool
+	// Provisioned is set when the account was created by this login
+	Provisioned bool
+}
+
+// resolveOIDCAccount finds the account of an identity: the account it is
+// already linked to, else the account with the same verified email (linking
+// it), else a new account when auto-provisioning is enabled.
+func resolveOIDCAccount(ctx context.Context, issuer string, claims *oidc.Claims) (*oidcAccount, error) {
+	account, err := findAccountByIdentity(ctx, issuer, claims.Subject)
+	if err != nil || account != nil {
+		return account, err
+	}
+
+	// Without a verified email the identity cannot be matched to an account
+	// nor become one: anyone can claim an unverified address at the provider.
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, ErrOIDCAccountNotFound
+	}
+
+	account, err = linkAccountByEmail(ctx, issuer, claims)
+	if err != nil || account != nil {
+		return account, err
+	}
+
+	if !config.OIDCAutoProvision {
+		return nil, ErrOIDCAccountNotFound
+	}
+
+	return provisionOIDCAccount(ctx, issuer, claims)
+}
+
+// findAccountByIdentity returns the account linked to issuer/subject, or nil
+func findAccountByIdentity(ctx context.Context, issuer, subject string) (*oidcAccount, error) {
+	var account oidcAccount
+	err := database.DB().QueryRowContext(ctx,
+		`UPDATE account_identity AS ai SET last_login_at = $3
+		FROM account AS a
+		WHERE ai.account_id = a.id AND ai.issuer = $1 AND ai.subject = $2
+		RETURNING a.id, a.status;`,
+		issuer, subject, time.Now()).Scan(&account.ID, &account.Status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil //nolint:nilnil // no linked account is not an error
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query account identity: %w", err)
+	}
+	return &account, nil
+}
+
+// linkAccountByEmail links the identity to the account registered with its
+// verified email, or returns nil when there is none. The provider verified
+// the address, so a pending account is activated as email confirmation would.
+// Whoever registered a pending account never proved they own the address, so
+// its password is replaced by a random one and its sessions are revoked: the
+// registrant must not keep a login on the account of the address owner.
+func linkAccountByEmail(ctx context.Context, issuer string, claims *oidc.Claims) (*oidcAccount, error) {
+	tx, err := database.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	account := oidcAccount{Linked: true}
+	// An exact match wins over a case-insensitive one
+	err = tx.QueryRowContext(ctx,
+		`SELECT id, status FROM account
+		WHERE lower(email) = lower($1)
+		ORDER BY (email = $1) DESC, id
+		LIMIT 1
+		FOR UPDATE;`,
+		claims.Email).Scan(&account.ID, &account.Status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil //nolint:nilnil // no account with this email is not an error
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query account by email: %w", err)
+	}
+
+	if err := insertAccountIdentity(ctx, tx, account.ID, issuer, claims); err != nil {
+		return nil, err
+	}
+
+	pending := account.Status == "pending"
+	if pending {
+		if err := activatePendingAccount(ctx, tx, account.ID); err != nil {
+			return nil, err
+		}
+		account.Status = statusActive
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	if pending {
+		if _, err := security.RevokeAllUserTokens(ctx, account.ID); err != nil {
+			return nil, err
+		}
+	}
+	return &account, nil
+}
+
+// activatePendingAccount activates a pending account and replaces the
+// password its registrant chose with a random one
+func activatePendingAccount(ctx context.Context, tx *sql.Tx, accountID uint) error {
+	hashedPassword, err := randomPasswordHash()
+	if err != nil {
+		return err
+	}
+	now := time.Now().Truncate(time.Second)
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE account SET status = 'active', confirmation_code = NULL, updated_at = $2
+		WHERE id = $1;`,
+		accountID, now)
+	if err != nil {
+		return fmt.Errorf("failed to activate account: %w", err)
+	}
+	_, err = tx.ExecContext(ctx,
+		`UPDATE password SET password = $2, updated_at = $3 WHERE user_id = $1;`,
+		accountID, hashedPassword, now)
+	if err != nil {
+		return fmt.Errorf("failed to replace password: %w", err)
+	}
+	return nil
+}
+
+// randomPasswordHash hashes a random password nobody knows; its owner logs
+// in through the provider, or sets a password with the forgot-password flow
+func randomPasswordHash() (string, error) {
+	randomPassword, err := oidc.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	hashedPassword, err := security.HashPassword(randomPassword)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return hashedPassword, nil
+}
+
+// provisionOIDCAccount creates an active standard account for the identity,
+// with a random password
+func provisionOIDCAccount(ctx context.Context, issuer string, claims *oidc.Claims) (*oidcAccount, error) {
+	hashedPassword, err := randomPasswordHash()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := database.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := time.Now().Truncate(time.Second)
+	id, err := insertProvisionedAccount(ctx, tx, claims, now)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO password (user_id, password, updated_at) VALUES ($1, $2, $3);`,
+		id, hashedPassword, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert password: %w", err)
+	}
+
+	if err := insertAccountIdentity(ctx, tx, id, issuer, claims); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return &oidcAccount{ID: id, Status: statusActive, Linked: true, Provisioned: true}, nil
+}
+
+// insertProvisionedAccount inserts the account under the first free username
+// derived from the identity: base, base2, base3...
+func insertProvisionedAccount(ctx context.Context, tx *sql.Tx, claims *oidc.Claims, now time.Time) (uint, error) {
+	base := provisionedUsername(claims)
+
+	for attempt := 1; attempt <= maxUsernameAttempts; attempt++ {
+		username := base
+		if attempt > 1 {
+			username = base + strconv.Itoa(attempt)
+		}
+
+		var id int
+		err := tx.QueryRowContext(ctx,
+			`INSERT INTO account (username, email, firstname, lastname, role, status, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, 'standard', 'active', $5, $5)
+			ON CONFLICT (username) DO NOTHING
+			RETURNING id;`,
+			username, claims.Email, claims.GivenName, claims.FamilyName, now).Scan(&id)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert account: %w", err)
+		}
+		//nolint:gosec
+		return uint(id), nil
+	}
+
+	return 0, fmt.Errorf("failed to find a free username for %q", base)
+}
+
+// provisionedUsername derives a username from the preferred_username claim
+// or the email local part. Usernames must not contain '@'.
+func provisionedUsername(claims *oidc.Claims) string {
+	candidate := claims.PreferredUsername
+	if candidate == "" {
+		candidate = claims.Email
+	}
+	candidate, _, _ = strings.Cut(candidate, "@")
+	candidate = strings.Join(strings.Fields(candidate), "")
+	if candidate == "" {
+		return "user"
+	}
+	return candidate
+}
+
+func insertAccountIdentity(ctx context.Context, tx *sql.Tx, accountID uint, issuer string, claims *oidc.Claims) error {
+	now := time.Now()
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO account_identity (account_id, issuer, subject, email, created_at, last_login_at)
+		VALUES ($1, $2, $3, $4, $5, $5);`,
+		accountID, issuer, claims.Subject, claims.Email, now)
+	if err != nil {
+		return fmt.Errorf("failed to insert account identity: %w", err)
+	}
+	return nil
+}
+
+// CleanupExpiredOIDCStates deletes the login states of abandoned OIDC logins
+func CleanupExpiredOIDCStates(ctx context.Context) (int64, error) {
+	result, err := database.DB().ExecContext(ctx,
+		`DELETE FROM oidc_login_state WHERE expires_at < $1`, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to cleanup expired OIDC login states: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
@@ -0,0 +1,267 @@
+package accounts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Angak0k/pimpmypack/pkg/config"
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/Angak0k/pimpmypack/pkg/oidc"
+	"github.com/gin-gonic/gin"
+	"github.com/gruntwork-io/terratest/modules/random"
+)
+
+// setupOIDCTest starts a mock identity provider and injects it as the OIDC provider
+func setupOIDCTest(t *testing.T) (*gin.Engine, *oidc.MockProvider) {
+	t.Helper()
+
+	mock, err := oidc.NewMockProvider("pimpmypack-test", "test-secret")
+	if err != nil {
+		t.Fatalf("failed to start mock provider: %v", err)
+	}
+	provider, err := oidc.Discover(context.Background(), mock.DiscoveryURL(), oidc.ClientConfig{
+		ClientID:     mock.ClientID,
+		ClientSecret: mock.ClientSecret,
+		RedirectURL:  "http://localhost/oidc/callback",
+		Scopes:       []string{"openid", "email", "profile"},
+	})
+	if err != nil {
+		t.Fatalf("failed to discover mock provider: %v", err)
+	}
+	setOIDCProvider(provider)
+	t.Cleanup(func() {
+		setOIDCProvider(nil)
+		mock.Close()
+	})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/auth/oidc/authorize", OIDCAuthorize)
+	router.POST("/auth/oidc/callback", OIDCCallback)
+	return router, mock
+}
+
+// oidcLogin runs the whole flow: authorize, login at the provider, callback
+func oidcLogin(
+	t *testing.T, router *gin.Engine, mock *oidc.MockProvider, user oidc.MockUser,
+) *httptest.ResponseRecorder {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/oidc/authorize", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d but got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var authorization OIDCAuthorizationResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &authorization); err != nil {
+		t.Fatalf("Failed to unmarshal authorization response: %v", err)
+	}
+
+	code, state, err := mock.Authorize(authorization.AuthorizationURL, user)
+	if err != nil {
+		t.Fatalf("Mock provider rejected the authorization request: %v", err)
+	}
+
+	return postOIDCCallback(t, router, code, state, authorization.LoginBinding)
+}
+
+func postOIDCCallback(t *testing.T, router *gin.Engine, code, state, binding string) *httptest.ResponseRecorder {
+	t.Helper()
+	body, err := json.Marshal(OIDCCallbackInput{Code: code, State: state, LoginBinding: binding})
+	if err != nil {
+		t.Fatalf("Failed to marshal callback data: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/auth/oidc/callback", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// deleteAccountByEmail removes an account provisioned by a test
+func deleteAccountByEmail(t *testing.T, email string) {
+	t.Helper()
+	t.Cleanup(func() {
+		_, _ = database.DB().ExecContext(context.Background(), "DELETE FROM account WHERE email = $1", email)
+	})
+}
+
+func TestOIDCLogin_Provisioning(t *testing.T) {
+	router, mock := setupOIDCTest(t)
+	user := oidc.MockUser{
+		Subject:           "sub-" + random.UniqueID(),
+		Email:             "oidc-" + random.UniqueID() + "@exemple.com",
+		EmailVerified:     true,
+		PreferredUsername: users[0].Username, // taken: a suffix is added
+		GivenName:         "Olivia",
+		FamilyName:        "Idp",
+	}
+	deleteAccountByEmail(t, user.Email)
+
+	w := oidcLogin(t, router, mock, user)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d but got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var response map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	validateTokenPairResponse(t, response)
+
+	var accountID uint
+	var username, status, firstname string
+	err := database.DB().QueryRow(
+		`SELECT id, username, status, firstname FROM account WHERE email = $1`, user.Email,
+	).Scan(&accountID, &username, &status, &firstname)
+	if err != nil {
+		t.Fatalf("provisioned account not found: %v", err)
+	}
+	if username != users[0].Username+"2" {
+		t.Errorf("Expected username %s2 but got %s", users[0].Username, username)
+	}
+	if status != "active" || firstname != "Olivia" {
+		t.Errorf("Expected an active account for Olivia but got %s for %s", status, firstname)
+	}
+
+	t.Run("second login uses the linked account", func(t *testing.T) {
+		user.Email = "changed-" + user.Email
+		again := oidcLogin(t, router, mock, user)
+		if again.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d but got %d: %s", http.StatusOK, again.Code, again.Body.String())
+		}
+		var count int
+		err := database.DB().QueryRow(
+			`SELECT COUNT(*) FROM account_identity WHERE account_id = $1`, accountID,
+		).Scan(&count)
+		if err != nil || count != 1 {
+			t.Errorf("Expected one identity for account %d, got %d (%v)", accountID, count, err)
+		}
+	})
+
+	t.Run("unverified email is not provisioned", func(t *testing.T) {
+		stranger := oidc.MockUser{Subject: "sub-" + random.UniqueID(), Email: "x-" + random.UniqueID() + "@exemple.com"}
+		deleteAccountByEmail(t, stranger.Email)
+		denied := oidcLogin(t, router, mock, stranger)
+		if denied.Code != http.StatusForbidden {
+			t.Errorf("Expected status code %d but got %d", http.StatusForbidden, denied.Code)
+		}
+	})
+
+	t.Run("no provisioning when disabled", func(t *testing.T) {
+		prev := config.OIDCAutoProvision
+		config.OIDCAutoProvision = false
+		t.Cleanup(func() { config.OIDCAutoProvision = prev })
+
+		stranger := oidc.MockUser{
+			Subject: "sub-" + random.UniqueID(), Email: "y-" + random.UniqueID() + "@exemple.com", EmailVerified: true,
+		}
+		deleteAccountByEmail(t, stranger.Email)
+		denied := oidcLogin(t, router, mock, stranger)
+		if denied.Code != http.StatusForbidden {
+			t.Errorf("Expected status code %d but got %d", http.StatusForbidden, denied.Code)
+		}
+	})
+}
+
+func TestOIDCLogin_LinkByEmail(t *testing.T) {
+	router, mock := setupOIDCTest(t)
+
+	t.Run("pending account is linked and activated", func(t *testing.T) {
+		var previousHash string
+		err := database.DB().QueryRow(`SELECT password FROM password WHERE user_id = $1`, users[1].ID).Scan(&previousHash)
+		if err != nil {
+			t.Fatalf("failed to read password: %v", err)
+		}
+		w := oidcLogin(t, router, mock, oidc.MockUser{
+			Subject: "sub-" + random.UniqueID(), Email: users[1].Email, EmailVerified: true,
+		})
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d but got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var status string
+		if err := database.DB().QueryRow(`SELECT status FROM account WHERE id = $1`, users[1].ID).Scan(&status); err != nil {
+			t.Fatalf("failed to read account status: %v", err)
+		}
+		if status != "active" {
+			t.Errorf("Expected account to be activated but status is %s", status)
+		}
+		var hash string
+		err = database.DB().QueryRow(`SELECT password FROM password WHERE user_id = $1`, users[1].ID).Scan(&hash)
+		if err != nil {
+			t.Fatalf("failed to read password: %v", err)
+		}
+		if hash == previousHash {
+			t.Error("Expected the password chosen at registration to be replaced")
+		}
+	})
+
+	t.Run("inactive account cannot log in", func(t *testing.T) {
+		w := oidcLogin(t, router, mock, oidc.MockUser{
+			Subject: "sub-" + random.UniqueID(), Email: users[2].Email, EmailVerified: true,
+		})
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status code %d but got %d", http.StatusUnauthorized, w.Code)
+		}
+	})
+}
+
+func TestOIDCCallback_InvalidState(t *testing.T) {
+	router, mock := setupOIDCTest(t)
+
+	w := postOIDCCallback(t, router, "some-code", "unknown-state", "some-binding")
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d but got %d", http.StatusBadRequest, w.Code)
+	}
+
+	// A state is consumed by its first callback, even a failed one
+	authURL, binding, err := startOIDCLogin(context.Background())
+	if err != nil {
+		t.Fatalf("failed to start OIDC login: %v", err)
+	}
+	_, state, err := mock.Authorize(authURL, oidc.MockUser{Subject: "sub-" + random.UniqueID()})
+	if err != nil {
+		t.Fatalf("Mock provider rejected the authorization request: %v", err)
+	}
+	w = postOIDCCallback(t, router, "wrong-code", state, binding)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status code %d but got %d", http.StatusUnauthorized, w.Code)
+	}
+	w = postOIDCCallback(t, router, "wrong-code", state, binding)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d on replay but got %d", http.StatusBadRequest, w.Code)
+	}
+
+	// Another browser cannot complete the login
+	authURL, _, err = startOIDCLogin(context.Background())
+	if err != nil {
+		t.Fatalf("failed to start OIDC login: %v", err)
+	}
+	code, state, err := mock.Authorize(authURL, oidc.MockUser{Subject: "sub-" + random.UniqueID()})
+	if err != nil {
+		t.Fatalf("Mock provider rejected the authorization request: %v", err)
+	}
+	w = postOIDCCallback(t, router, code, state, binding)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d with another binding but got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestOIDCAuthorize_Disabled(t *testing.T) {
+	prev := config.OIDCDiscoveryURL
+	config.OIDCDiscoveryURL = ""
+	t.Cleanup(func() { config.OIDCDiscoveryURL = prev })
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/auth/oidc/authorize", OIDCAuthorize)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/oidc/authorize", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d but got %d", http.StatusNotFound, w.Code)
+	}
+}
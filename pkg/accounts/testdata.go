@@ -133,6 +133,13 @@ func cleanupAccountDataset() error {
 		}
 	}
 
+	// Test logins all come from the same address: clear the failure counters
+	// so repeated runs within the failure window do not throttle each other
+	_, err := database.DB().ExecContext(ctx, "DELETE FROM login_failure")
+	if err != nil {
+		return fmt.Errorf("failed to delete login failures: %w", err)
+	}
+
 	println("-> Account test data cleaned up...")
 	return nil
 }
@@ -167,6 +174,8 @@ func setupLoginTestData(t *testing.T) (*gin.Engine, User, []byte) {
 	if err != nil {
 		t.Fatalf("failed to insert user: %v", err)
 	}
+	//nolint:gosec
+	newUser.ID = uint(id)
 
 	hashedPassword, err := security.HashPassword(newUser.Password)
 	if err != nil {
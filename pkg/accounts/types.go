@@ -4,25 +4,28 @@ import "time"
 
 // Account represents a user account with public information
 type Account struct {
-	ID                  uint      `json:"id"`
-	Username            string    `json:"username"`
-	Email               string    `json:"email"`
-	Firstname           string    `json:"firstname"`
-	Lastname            string    `json:"lastname"`
-	Role                string    `json:"role"`
-	Status              string    `json:"status"`
-	PreferredCurrency   string    `json:"preferred_currency"`
-	PreferredUnitSystem string    `json:"preferred_unit_system"`
-	YoutubeURL          *string   `json:"youtube_url"`
-	InstagramURL        *string   `json:"instagram_url"`
-	HasProfileImage     bool      `json:"has_profile_image"`
-	ImagePositionX      int       `json:"image_position_x"`
-	ImagePositionY      int       `json:"image_position_y"`
-	IsProfilePublic     bool      `json:"is_profile_public"`
-	HasBannerImage      bool      `json:"has_banner_image"`
-	BannerPositionY     int       `json:"banner_position_y"`
-	CreatedAt           time.Time `json:"created_at"`
-	UpdatedAt           time.Time `json:"updated_at"`
+	ID                  uint       `json:"id"`
+	Username            string     `json:"username"`
+	Email               string     `json:"email"`
+	Firstname           string     `json:"firstname"`
+	Lastname            string     `json:"lastname"`
+	Role                string     `json:"role"`
+	Status              string     `json:"status"`
+	PreferredCurrency   string     `json:"preferred_currency"`
+	PreferredUnitSystem string     `json:"preferred_unit_system"`
+	PreferredLanguage   string     `json:"preferred_language"`
+	YoutubeURL          *string    `json:"youtube_url"`
+	InstagramURL        *string    `json:"instagram_url"`
+	HasProfileImage     bool       `json:"has_profile_image"`
+	ImagePositionX      int        `json:"image_position_x"`
+	ImagePositionY      int        `json:"image_position_y"`
+	IsProfilePublic     bool       `json:"is_profile_public"`
+	HasBannerImage      bool       `json:"has_banner_image"`
+	BannerPositionY     int        `json:"banner_position_y"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"` // set while a deletion is pending
+	PendingEmail        *string    `json:"pending_email,omitempty"`         // new email awaiting confirmation
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
 }
 
 // Accounts represents a collection of accounts
@@ -41,6 +44,7 @@ type User struct {
 	LastPassword        string    `json:"last_password"`
 	PreferredCurrency   string    `json:"preferred_currency"`
 	PreferredUnitSystem string    `json:"preferred_unit_system"`
+	PreferredLanguage   string    `json:"preferred_language"`
 	CreatedAt           time.Time `json:"created_at"`
 	UpdatedAt           time.Time `json:"updated_at"`
 }
@@ -52,6 +56,8 @@ type RegisterInput struct {
 	Email     string `json:"email" binding:"required"`
 	Firstname string `json:"firstname" binding:"required"`
 	Lastname  string `json:"lastname" binding:"required"`
+	// PreferredLanguage is optional, the Accept-Language of the request is used by default
+	PreferredLanguage string `json:"preferred_language"`
 }
 
 // LoginInput represents the data required to login
@@ -81,6 +87,7 @@ type AccountUpdateInput struct {
 	Lastname            string  `json:"lastname" binding:"required"`
 	PreferredCurrency   string  `json:"preferred_currency"`
 	PreferredUnitSystem string  `json:"preferred_unit_system"`
+	PreferredLanguage   *string `json:"preferred_language"`
 	YoutubeURL          *string `json:"youtube_url"`
 	InstagramURL        *string `json:"instagram_url"`
 	ImagePositionX      *int    `json:"image_position_x"`
@@ -98,3 +105,49 @@ type ResendConfirmEmailInput struct {
 type Token struct {
 	Token string `json:"token"`
 }
+
+// OIDCAuthorizationResponse holds the provider URL starting an OIDC login
+type OIDCAuthorizationResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+	// LoginBinding is kept by the browser, for example in session storage,
+	// and posted with the callback: only this browser can complete the login
+	LoginBinding string `json:"login_binding"`
+}
+
+// OIDCCallbackInput represents the parameters the provider sent to the redirect URI
+type OIDCCallbackInput struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+	// LoginBinding is the login_binding returned by /auth/oidc/authorize
+	LoginBinding string `json:"login_binding" binding:"required"`
+	RememberMe   bool   `json:"remember_me"` // optional, defaults to false
+}
+
+// DeleteMyAccountInput represents the confirmation required to delete one's account:
+// the password, or a fresh OIDC login for accounts without a known password
+type DeleteMyAccountInput struct {
+	Password string           `json:"password" binding:"required_without=OIDC"`
+	OIDC     *OIDCReauthInput `json:"oidc" binding:"required_without=Password"`
+}
+
+// OIDCReauthInput represents the parameters of an OIDC login started with
+// /auth/oidc/authorize to re-authenticate instead of logging in
+type OIDCReauthInput struct {
+	Code         string `json:"code" binding:"required"`
+	State        string `json:"state" binding:"required"`
+	LoginBinding string `json:"login_binding" binding:"required"`
+}
+
+// AccountDeletionResponse tells when a deleted account will be permanently removed
+type AccountDeletionResponse struct {
+	Message             string    `json:"message"`
+	DeletionScheduledAt time.Time `json:"deletion_scheduled_at"`
+}
+
+// RestoreAccountInput represents the credentials of an account scheduled for deletion:
+// the username and password, or a fresh OIDC login for accounts without a known password
+type RestoreAccountInput struct {
+	Username string           `json:"username" binding:"required_without=OIDC"`
+	Password string           `json:"password" binding:"required_without=OIDC"`
+	OIDC     *OIDCReauthInput `json:"oidc" binding:"required_without=Password"`
+}
@@ -0,0 +1,170 @@
+package categories
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/Angak0k/pimpmypack/pkg/config"
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/Angak0k/pimpmypack/pkg/security"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	err := config.EnvInit("../../.env")
+	if err != nil {
+		log.Fatalf("Error loading .env file or environment variable : %v", err)
+	}
+
+	err = database.Initialization()
+	if err != nil {
+		log.Fatalf("Error connecting database : %v", err)
+	}
+
+	err = database.Migrate()
+	if err != nil {
+		log.Fatalf("Error migrating database : %v", err)
+	}
+
+	err = loadingCategoryDataset()
+	if err != nil {
+		log.Fatalf("Error loading dataset : %v", err)
+	}
+
+	ret := m.Run()
+
+	if err := cleanupCategoryDataset(); err != nil {
+		log.Printf("Warning: Error cleaning up dataset : %v", err)
+	}
+
+	os.Exit(ret)
+}
+
+func TestCategoryOrder(t *testing.T) {
+	order, err := categoryOrder([]uint{1, 2, 3, 4}, []uint{3, 1, 3})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{3, 1, 2, 4}, order)
+
+	_, err = categoryOrder([]uint{1, 2}, []uint{5})
+	require.ErrorIs(t, err, ErrCategoryNotFound)
+}
+
+func TestValidateCategoryUpdate(t *testing.T) {
+	color, empty, long := "#1A2b3C", "", string(make([]rune, maxIconLength+1))
+	input := CategoryUpdateRequest{Name: "  Sleep ", Color: &color, Icon: &empty}
+	require.NoError(t, validateCategoryUpdate(&input))
+	assert.Equal(t, "Sleep", input.Name)
+	assert.Nil(t, input.Icon)
+
+	bad := "red"
+	require.ErrorIs(t, validateCategoryUpdate(&CategoryUpdateRequest{Name: "Sleep", Color: &bad}), ErrInvalidColor)
+	require.ErrorIs(t, validateCategoryUpdate(&CategoryUpdateRequest{Name: "Sleep", Icon: &long}), ErrInvalidIcon)
+	require.ErrorIs(t, validateCategoryUpdate(&CategoryUpdateRequest{Name: " "}), ErrEmptyCategoryName)
+}
+
+func categoryRequest(t *testing.T, userID uint, method, path string, payload any) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/mycategories", GetMyCategories)
+	router.PUT("/mycategories/order", PutMyCategoriesOrder)
+	router.POST("/mycategories/merge", PostMyCategoriesMerge)
+	router.PUT("/mycategories/:id", PutMyCategoryByID)
+	router.DELETE("/mycategories/:id", DeleteMyCategoryByID)
+
+	token, err := security.GenerateToken(userID)
+	require.NoError(t, err)
+	var body bytes.Buffer
+	if payload != nil {
+		require.NoError(t, json.NewEncoder(&body).Encode(payload))
+	}
+	req := httptest.NewRequest(method, path, &body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func listCategories(t *testing.T, userID uint) map[string]Category {
+	t.Helper()
+	w := categoryRequest(t, userID, http.MethodGet, "/mycategories", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var list Categories
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	byName := map[string]Category{}
+	for _, c := range list {
+		byName[c.Name] = c
+	}
+	return byName
+}
+
+func TestMyCategories(t *testing.T) {
+	userID := testUsers[0].ID
+
+	categories := listCategories(t, userID)
+	require.Len(t, categories, 5)
+	assert.Equal(t, 1, categories["Shelter"].ItemCount)
+
+	t.Run("rename moves items and rejects existing names", func(t *testing.T) {
+		color := "#ff8800"
+		path := "/mycategories/" + uintString(categories["Shelters"].ID)
+		w := categoryRequest(t, userID, http.MethodPut, path, CategoryUpdateRequest{Name: "Shelter"})
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		w = categoryRequest(t, userID, http.MethodPut, path, CategoryUpdateRequest{Name: "Stakes", Color: &color})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var renamed Category
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &renamed))
+		assert.Equal(t, "Stakes", renamed.Name)
+		assert.Equal(t, 1, renamed.ItemCount)
+		assert.Equal(t, &color, renamed.Color)
+	})
+
+	t.Run("merge folds sources into the target", func(t *testing.T) {
+		w := categoryRequest(t, userID, http.MethodPost, "/mycategories/merge", CategoryMergeRequest{
+			SourceIDs: []uint{categories["kitchen"].ID}, TargetID: categories["Kitchen"].ID,
+		})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var merged Category
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &merged))
+		assert.Equal(t, 2, merged.ItemCount)
+		assert.NotContains(t, listCategories(t, userID), "kitchen")
+	})
+
+	t.Run("order puts listed categories first", func(t *testing.T) {
+		w := categoryRequest(t, userID, http.MethodPut, "/mycategories/order", CategoryOrderRequest{
+			CategoryIDs: []uint{categories["Electronics"].ID, categories["Shelter"].ID},
+		})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var list Categories
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+		require.Len(t, list, 4)
+		assert.Equal(t, []string{"Electronics", "Shelter"}, []string{list[0].Name, list[1].Name})
+	})
+
+	t.Run("other users cannot touch the categories", func(t *testing.T) {
+		path := "/mycategories/" + uintString(categories["Shelter"].ID)
+		w := categoryRequest(t, testUsers[1].ID, http.MethodPut, path, CategoryUpdateRequest{Name: "Mine"})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		w = categoryRequest(t, testUsers[1].ID, http.MethodDelete, path, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("only empty categories can be deleted", func(t *testing.T) {
+		w := categoryRequest(t, userID, http.MethodDelete, "/mycategories/"+uintString(categories["Shelter"].ID), nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func uintString(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
@@ -0,0 +1,226 @@
+package categories
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Angak0k/pimpmypack/pkg/helper"
+	"github.com/Angak0k/pimpmypack/pkg/security"
+	"github.com/gin-gonic/gin"
+)
+
+// GetMyCategories lists the categories of the user
+// @Summary Get my categories
+// @Description Lists the inventory categories of the user in display order, with their color, icon and
+// @Description item count. Categories of new items are added after the existing ones.
+// @Security Bearer
+// @Tags Categories
+// @Produce json
+// @Success 200 {object} Categories
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/mycategories [get]
+func GetMyCategories(c *gin.Context) {
+	userID, err := security.ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, "get my categories: extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return
+	}
+
+	categories, err := returnCategoriesByUserID(c.Request.Context(), userID)
+	if err != nil {
+		helper.LogAndSanitize(err, "get my categories: return categories failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, categories)
+}
+
+// PutMyCategoryByID updates a category of the user
+// @Summary Update a category
+// @Description Updates the name, color and icon of a category. Renaming moves every item of the category.
+// @Security Bearer
+// @Tags Categories
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param category body CategoryUpdateRequest true "Category settings"
+// @Success 200 {object} Category
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid payload"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 404 {object} apitypes.ErrorResponse "Category not found"
+// @Failure 409 {object} apitypes.ErrorResponse "Category name already exists"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/mycategories/{id} [put]
+func PutMyCategoryByID(c *gin.Context) {
+	userID, err := security.ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, "put my category: extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return
+	}
+
+	id, err := helper.StringToUint(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	var input CategoryUpdateRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		helper.LogAndSanitize(err, "put my category: bind JSON failed")
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": helper.ErrMsgBadRequest})
+		return
+	}
+	if err := validateCategoryUpdate(&input); err != nil {
+		respondCategoryError(c, err, "put my category: validate failed")
+		return
+	}
+
+	category, err := updateCategory(c.Request.Context(), id, userID, &input)
+	if err != nil {
+		respondCategoryError(c, err, "put my category: update category failed")
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, category)
+}
+
+// PostMyCategoriesMerge merges categories of the user
+// @Summary Merge categories
+// @Description Moves every item of the source categories to the target category and deletes the sources.
+// @Security Bearer
+// @Tags Categories
+// @Accept json
+// @Produce json
+// @Param merge body CategoryMergeRequest true "Source and target categories"
+// @Success 200 {object} Category "Merged category"
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid payload or target among sources"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 404 {object} apitypes.ErrorResponse "Category not found"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/mycategories/merge [post]
+func PostMyCategoriesMerge(c *gin.Context) {
+	userID, err := security.ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, "post my categories merge: extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return
+	}
+
+	var input CategoryMergeRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		helper.LogAndSanitize(err, "post my categories merge: bind JSON failed")
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": helper.ErrMsgBadRequest})
+		return
+	}
+
+	category, err := mergeCategories(c.Request.Context(), userID, &input)
+	if err != nil {
+		respondCategoryError(c, err, "post my categories merge: merge categories failed")
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, category)
+}
+
+// PutMyCategoriesOrder sets the display order of the categories of the user
+// @Summary Order categories
+// @Description Puts the listed categories first, in order; the others keep their relative order after them.
+// @Description Pack contents are listed in this order.
+// @Security Bearer
+// @Tags Categories
+// @Accept json
+// @Produce json
+// @Param order body CategoryOrderRequest true "Category IDs in display order"
+// @Success 200 {object} Categories
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid payload"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 404 {object} apitypes.ErrorResponse "Category not found"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/mycategories/order [put]
+func PutMyCategoriesOrder(c *gin.Context) {
+	userID, err := security.ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, "put my categories order: extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return
+	}
+
+	var input CategoryOrderRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		helper.LogAndSanitize(err, "put my categories order: bind JSON failed")
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": helper.ErrMsgBadRequest})
+		return
+	}
+
+	if err := reorderCategories(c.Request.Context(), userID, input.CategoryIDs); err != nil {
+		respondCategoryError(c, err, "put my categories order: reorder failed")
+		return
+	}
+
+	categories, err := returnCategoriesByUserID(c.Request.Context(), userID)
+	if err != nil {
+		helper.LogAndSanitize(err, "put my categories order: return categories failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, categories)
+}
+
+// DeleteMyCategoryByID deletes an empty category of the user
+// @Summary Delete a category
+// @Description Deletes a category without items
+// @Security Bearer
+// @Tags Categories
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} apitypes.OkResponse "Category deleted"
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 404 {object} apitypes.ErrorResponse "Category not found"
+// @Failure 409 {object} apitypes.ErrorResponse "Category still has items"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/mycategories/{id} [delete]
+func DeleteMyCategoryByID(c *gin.Context) {
+	userID, err := security.ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, "delete my category: extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return
+	}
+
+	id, err := helper.StringToUint(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	if err := deleteCategory(c.Request.Context(), id, userID); err != nil {
+		respondCategoryError(c, err, "delete my category: delete category failed")
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, gin.H{"message": "Category deleted"})
+}
+
+// respondCategoryError maps category errors to an HTTP response
+func respondCategoryError(c *gin.Context, err error, logMsg string) {
+	switch {
+	case errors.Is(err, ErrCategoryNotFound):
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": ErrCategoryNotFound.Error()})
+	case errors.Is(err, ErrCategoryNameExists):
+		c.IndentedJSON(http.StatusConflict, gin.H{"error": ErrCategoryNameExists.Error()})
+	case errors.Is(err, ErrCategoryInUse):
+		c.IndentedJSON(http.StatusConflict, gin.H{"error": ErrCategoryInUse.Error()})
+	case errors.Is(err, ErrEmptyCategoryName), errors.Is(err, ErrInvalidColor),
+		errors.Is(err, ErrInvalidIcon), errors.Is(err, ErrSameCategory):
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		helper.LogAndSanitize(err, logMsg)
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+	}
+}
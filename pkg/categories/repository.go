@@ -0,0 +1,259 @@
+package categories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Angak0k/pimpmypack/pkg/database"
+)
+
+const categoryColumns = `c.id, c.name, c.position, c.color, c.icon,
+	(SELECT COUNT(*) FROM inventory i WHERE i.user_id = c.user_id AND i.category = c.name) AS item_count,
+	c.created_at, c.updated_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanCategory(row rowScanner) (Category, error) {
+	var c Category
+	err := row.Scan(&c.ID, &c.Name, &c.Position, &c.Color, &c.Icon, &c.ItemCount, &c.CreatedAt, &c.UpdatedAt)
+	return c, err
+}
+
+// syncCategories creates the categories of inventory items that have none yet,
+// alphabetically after the existing ones
+func syncCategories(ctx context.Context, userID uint) error {
+	_, err := database.DB().ExecContext(ctx,
+		`INSERT INTO category (user_id, name, position)
+		SELECT $1, missing.name,
+			(SELECT COALESCE(MAX(position), -1) FROM category WHERE user_id = $1)
+				+ ROW_NUMBER() OVER (ORDER BY missing.name)
+		FROM (
+			SELECT DISTINCT i.category AS name FROM inventory i
+			WHERE i.user_id = $1 AND i.category IS NOT NULL
+				AND NOT EXISTS (SELECT 1 FROM category c WHERE c.user_id = $1 AND c.name = i.category)
+		) AS missing
+		ON CONFLICT (user_id, name) DO NOTHING;`,
+		userID)
+	if err != nil {
+		return fmt.Errorf("failed to sync categories: %w", err)
+	}
+	return nil
+}
+
+func returnCategoriesByUserID(ctx context.Context, userID uint) (Categories, error) {
+	if err := syncCategories(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	rows, err := database.DB().QueryContext(ctx,
+		`SELECT `+categoryColumns+`
+		FROM category c
+		WHERE c.user_id = $1
+		ORDER BY c.position, c.name;`,
+		userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query categories: %w", err)
+	}
+	defer rows.Close()
+
+	categories := Categories{}
+	for rows.Next() {
+		c, err := scanCategory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		categories = append(categories, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate categories: %w", err)
+	}
+	return categories, nil
+}
+
+func findCategoryByID(ctx context.Context, q queryer, id, userID uint) (*Category, error) {
+	c, err := scanCategory(q.QueryRowContext(ctx,
+		`SELECT `+categoryColumns+` FROM category c WHERE c.id = $1 AND c.user_id = $2;`,
+		id, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query category: %w", err)
+	}
+	return &c, nil
+}
+
+// queryer is implemented by *sql.DB and *sql.Tx
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// updateCategory updates a category; a new name renames the category of all
+// the items of the user within the same transaction
+func updateCategory(ctx context.Context, id, userID uint, input *CategoryUpdateRequest) (*Category, error) {
+	tx, err := database.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	current, err := findCategoryByID(ctx, tx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != current.Name {
+		var exists bool
+		err = tx.QueryRowContext(ctx,
+			`SELECT EXISTS(SELECT 1 FROM category WHERE user_id = $1 AND name = $2);`,
+			userID, input.Name).Scan(&exists)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check category name: %w", err)
+		}
+		if exists {
+			return nil, ErrCategoryNameExists
+		}
+		if err = moveCategoryItems(ctx, tx, userID, current.Name, input.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE category SET name = $1, color = $2, icon = $3, updated_at = NOW() WHERE id = $4;`,
+		input.Name, input.Color, input.Icon, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update category: %w", err)
+	}
+
+	updated, err := findCategoryByID(ctx, tx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit category update: %w", err)
+	}
+	return updated, nil
+}
+
+// mergeCategories moves the items of the source categories to the target
+// category and deletes the sources, within a transaction
+func mergeCategories(ctx context.Context, userID uint, input *CategoryMergeRequest) (*Category, error) {
+	tx, err := database.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	target, err := findCategoryByID(ctx, tx, input.TargetID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sourceID := range input.SourceIDs {
+		if sourceID == input.TargetID {
+			return nil, ErrSameCategory
+		}
+		source, err := findCategoryByID(ctx, tx, sourceID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if err := moveCategoryItems(ctx, tx, userID, source.Name, target.Name); err != nil {
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM category WHERE id = $1;`, sourceID); err != nil {
+			return nil, fmt.Errorf("failed to delete merged category: %w", err)
+		}
+	}
+
+	merged, err := findCategoryByID(ctx, tx, input.TargetID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit category merge: %w", err)
+	}
+	return merged, nil
+}
+
+func moveCategoryItems(ctx context.Context, tx *sql.Tx, userID uint, from, to string) error {
+	_, err := tx.ExecContext(ctx,
+		`UPDATE inventory SET category = $1, updated_at = NOW() WHERE user_id = $2 AND category = $3;`,
+		to, userID, from)
+	if err != nil {
+		return fmt.Errorf("failed to move category items: %w", err)
+	}
+	return nil
+}
+
+// reorderCategories puts the listed categories first, in order, followed by the
+// other categories of the user in their current order
+func reorderCategories(ctx context.Context, userID uint, ids []uint) error {
+	tx, err := database.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	current, err := returnCategoryIDs(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+
+	order, err := categoryOrder(current, ids)
+	if err != nil {
+		return err
+	}
+	for position, id := range order {
+		_, err := tx.ExecContext(ctx,
+			`UPDATE category SET position = $1, updated_at = NOW() WHERE id = $2;`, position, id)
+		if err != nil {
+			return fmt.Errorf("failed to update category position: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit category order: %w", err)
+	}
+	return nil
+}
+
+func returnCategoryIDs(ctx context.Context, tx *sql.Tx, userID uint) ([]uint, error) {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT id FROM category WHERE user_id = $1 ORDER BY position, name;`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query categories: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uint
+	for rows.Next() {
+		var id uint
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate categories: %w", err)
+	}
+	return ids, nil
+}
+
+// deleteCategory deletes a category without items
+func deleteCategory(ctx context.Context, id, userID uint) error {
+	category, err := findCategoryByID(ctx, database.DB(), id, userID)
+	if err != nil {
+		return err
+	}
+	if category.ItemCount > 0 {
+		return ErrCategoryInUse
+	}
+	if _, err := database.DB().ExecContext(ctx, `DELETE FROM category WHERE id = $1;`, id); err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+	return nil
+}
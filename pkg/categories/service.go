@@ -0,0 +1,59 @@
+package categories
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// categoryOrder returns the current category IDs with the requested ones
+// first, in the requested order. Every requested ID must be a current one.
+func categoryOrder(current, requested []uint) ([]uint, error) {
+	known := make(map[uint]bool, len(current))
+	for _, id := range current {
+		known[id] = true
+	}
+
+	order := make([]uint, 0, len(current))
+	listed := make(map[uint]bool, len(requested))
+	for _, id := range requested {
+		if !known[id] {
+			return nil, fmt.Errorf("%w: %d", ErrCategoryNotFound, id)
+		}
+		if !listed[id] {
+			listed[id] = true
+			order = append(order, id)
+		}
+	}
+	for _, id := range current {
+		if !listed[id] {
+			order = append(order, id)
+		}
+	}
+	return order, nil
+}
+
+// validateCategoryUpdate trims the name and checks the color and icon;
+// empty colors and icons are cleared
+func validateCategoryUpdate(input *CategoryUpdateRequest) error {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		return ErrEmptyCategoryName
+	}
+	if input.Color != nil && *input.Color == "" {
+		input.Color = nil
+	}
+	if input.Icon != nil && *input.Icon == "" {
+		input.Icon = nil
+	}
+	if input.Color != nil && !colorPattern.MatchString(*input.Color) {
+		return ErrInvalidColor
+	}
+	if input.Icon != nil && utf8.RuneCountInString(*input.Icon) > maxIconLength {
+		return ErrInvalidIcon
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package categories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Angak0k/pimpmypack/pkg/accounts"
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/gruntwork-io/terratest/modules/random"
+)
+
+var testUsers = []accounts.User{
+	{
+		Username:  "categoryuser-" + random.UniqueID(),
+		Email:     "categoryuser-" + random.UniqueID() + "@example.com",
+		Firstname: "Category",
+		Lastname:  "Tester",
+		Role:      "standard",
+		Status:    "active",
+	},
+	{
+		Username:  "categoryother-" + random.UniqueID(),
+		Email:     "categoryother-" + random.UniqueID() + "@example.com",
+		Firstname: "Other",
+		Lastname:  "Tester",
+		Role:      "standard",
+		Status:    "active",
+	},
+}
+
+// testItems are inventory items of the first test user, by name and category
+var testItems = [][2]string{
+	{"Tent", "Shelter"},
+	{"Stakes", "Shelters"},
+	{"Stove", "Kitchen"},
+	{"Pot", "kitchen"},
+	{"Headlamp", "Electronics"},
+}
+
+func loadingCategoryDataset() error {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	for i := range testUsers {
+		err := database.DB().QueryRowContext(ctx,
+			`INSERT INTO account (username, email, firstname, lastname, role, status, created_at, updated_at)
+			VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+			RETURNING id;`,
+			testUsers[i].Username, testUsers[i].Email,
+			testUsers[i].Firstname, testUsers[i].Lastname,
+			testUsers[i].Role, testUsers[i].Status, now, now).Scan(&testUsers[i].ID)
+		if err != nil {
+			return fmt.Errorf("failed to insert user: %w", err)
+		}
+	}
+
+	for _, item := range testItems {
+		_, err := database.DB().ExecContext(ctx,
+			`INSERT INTO inventory (user_id, item_name, category, description, weight, url, price, currency,
+				created_at, updated_at)
+			VALUES ($1, $2, $3, '', 100, '', 0, 'EUR', $4, $5);`,
+			testUsers[0].ID, item[0], item[1], now, now)
+		if err != nil {
+			return fmt.Errorf("failed to insert inventory item %q: %w", item[0], err)
+		}
+	}
+	return nil
+}
+
+// cleanupCategoryDataset deletes the test users, their items and categories cascading
+func cleanupCategoryDataset() error {
+	for _, user := range testUsers {
+		if user.ID == 0 {
+			continue
+		}
+		if _, err := database.DB().ExecContext(context.Background(),
+			"DELETE FROM account WHERE id = $1", user.ID); err != nil {
+			return fmt.Errorf("failed to delete user %d: %w", user.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package categories
+
+import (
+	"errors"
+	"time"
+)
+
+// maxIconLength caps the length of a category icon name or emoji
+const maxIconLength = 64
+
+// Domain errors
+var (
+	ErrCategoryNotFound   = errors.New("category not found")
+	ErrCategoryNameExists = errors.New("category name already exists")
+	ErrEmptyCategoryName  = errors.New("category name must not be empty")
+	ErrCategoryInUse      = errors.New("category still has items")
+	ErrInvalidColor       = errors.New("color must be a hex color such as #1a2b3c")
+	ErrInvalidIcon        = errors.New("icon must be at most 64 characters")
+	ErrSameCategory       = errors.New("source and target categories must be different")
+)
+
+// Category holds the display settings of one of the inventory categories of a user.
+// Categories are ordered by Position, lowest first.
+type Category struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Position  int       `json:"position"`
+	Color     *string   `json:"color,omitempty"`
+	Icon      *string   `json:"icon,omitempty"`
+	ItemCount int       `json:"item_count"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Categories represents a collection of categories
+type Categories []Category
+
+// CategoryUpdateRequest represents the input for updating a category.
+// A new name renames the category of every item in it.
+type CategoryUpdateRequest struct {
+	Name  string  `json:"name" binding:"required"`
+	Color *string `json:"color"`
+	Icon  *string `json:"icon"`
+}
+
+// CategoryMergeRequest represents the input for merging categories into a target category
+type CategoryMergeRequest struct {
+	SourceIDs []uint `json:"source_ids" binding:"required,min=1,dive,gt=0"`
+	TargetID  uint   `json:"target_id" binding:"required,gt=0"`
+}
+
+// CategoryOrderRequest lists category IDs in display order; unlisted categories
+// keep their relative order after them
+type CategoryOrderRequest struct {
+	CategoryIDs []uint `json:"category_ids" binding:"required,min=1,dive,gt=0"`
+}
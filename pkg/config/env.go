@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/mail"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -37,6 +38,10 @@ var (
 	DBPort                              int
 	Stage                               string
 	APISecret                           string
+	JWTSigningAlg                       string
+	JWTSigningKeyID                     string
+	JWTPrivateKeyFile                   string
+	JWTVerificationKeysDir              string
 	TokenLifespan                       int
 	AccessTokenMinutes                  int
 	RefreshTokenDays                    int
@@ -47,7 +52,32 @@ var (
 	RefreshRateLimitWindowMinutes       int
 	ResendConfirmRateLimitRequests      int
 	ResendConfirmRateLimitWindowMinutes int
+	AuditRetentionDays                  int
+	AccountDeletionGraceDays            int
+	LoginFailureWindowMinutes           int
+	LoginBackoffThreshold               int
+	LoginBackoffBaseSeconds             int
+	LoginBackoffMaxSeconds              int
+	LoginLockoutThreshold               int
+	LoginLockoutMinutes                 int
+	LoginIPBackoffThreshold             int
+	RateLimitBackend                    string
+	ImportRateLimitRequests             int
+	ImportRateLimitWindowMinutes        int
+	UploadRateLimitRequests             int
+	UploadRateLimitWindowMinutes        int
+	OIDCDiscoveryURL                    string
+	OIDCClientID                        string
+	OIDCClientSecret                    string
+	OIDCRedirectURL                     string
+	OIDCScopes                          []string
+	OIDCAutoProvision                   bool
 	MailServerConfig                    MailServer
+	MailTransport                       string
+	MailSinkDir                         string
+	EmailMaxAttempts                    int
+	EmailRetryBaseSeconds               int
+	EmailRetryMaxSeconds                int
 	SeedOnStartup                       bool
 	FeatureItemPicturesUpload           bool
 )
@@ -58,6 +88,10 @@ type Config struct {
 	DBConfig                            DBConfig
 	Stage                               string
 	APISecret                           string
+	JWTSigningAlg                       string
+	JWTSigningKeyID                     string
+	JWTPrivateKeyFile                   string
+	JWTVerificationKeysDir              string
 	TokenLifespan                       int
 	AccessTokenMinutes                  int
 	RefreshTokenDays                    int
@@ -68,7 +102,32 @@ type Config struct {
 	RefreshRateLimitWindowMinutes       int
 	ResendConfirmRateLimitRequests      int
 	ResendConfirmRateLimitWindowMinutes int
+	AuditRetentionDays                  int
+	AccountDeletionGraceDays            int
+	LoginFailureWindowMinutes           int
+	LoginBackoffThreshold               int
+	LoginBackoffBaseSeconds             int
+	LoginBackoffMaxSeconds              int
+	LoginLockoutThreshold               int
+	LoginLockoutMinutes                 int
+	LoginIPBackoffThreshold             int
+	RateLimitBackend                    string
+	ImportRateLimitRequests             int
+	ImportRateLimitWindowMinutes        int
+	UploadRateLimitRequests             int
+	UploadRateLimitWindowMinutes        int
+	OIDCDiscoveryURL                    string
+	OIDCClientID                        string
+	OIDCClientSecret                    string
+	OIDCRedirectURL                     string
+	OIDCScopes                          []string
+	OIDCAutoProvision                   bool
 	MailServer                          MailServer
+	MailTransport                       string
+	MailSinkDir                         string
+	EmailMaxAttempts                    int
+	EmailRetryBaseSeconds               int
+	EmailRetryMaxSeconds                int
 	SeedOnStartup                       bool
 	FeatureItemPicturesUpload           bool
 }
@@ -88,6 +147,10 @@ func EnvInit(envFilePath string) error {
 	DBPort = newConfig.DBConfig.DBPort
 	Stage = newConfig.Stage
 	APISecret = newConfig.APISecret
+	JWTSigningAlg = newConfig.JWTSigningAlg
+	JWTSigningKeyID = newConfig.JWTSigningKeyID
+	JWTPrivateKeyFile = newConfig.JWTPrivateKeyFile
+	JWTVerificationKeysDir = newConfig.JWTVerificationKeysDir
 	TokenLifespan = newConfig.TokenLifespan
 	AccessTokenMinutes = newConfig.AccessTokenMinutes
 	RefreshTokenDays = newConfig.RefreshTokenDays
@@ -98,13 +161,43 @@ func EnvInit(envFilePath string) error {
 	RefreshRateLimitWindowMinutes = newConfig.RefreshRateLimitWindowMinutes
 	ResendConfirmRateLimitRequests = newConfig.ResendConfirmRateLimitRequests
 	ResendConfirmRateLimitWindowMinutes = newConfig.ResendConfirmRateLimitWindowMinutes
-	MailServerConfig = newConfig.MailServer
+	AuditRetentionDays = newConfig.AuditRetentionDays
+	AccountDeletionGraceDays = newConfig.AccountDeletionGraceDays
+	LoginFailureWindowMinutes = newConfig.LoginFailureWindowMinutes
+	LoginBackoffThreshold = newConfig.LoginBackoffThreshold
+	LoginBackoffBaseSeconds = newConfig.LoginBackoffBaseSeconds
+	LoginBackoffMaxSeconds = newConfig.LoginBackoffMaxSeconds
+	LoginLockoutThreshold = newConfig.LoginLockoutThreshold
+	LoginLockoutMinutes = newConfig.LoginLockoutMinutes
+	LoginIPBackoffThreshold = newConfig.LoginIPBackoffThreshold
+	RateLimitBackend = newConfig.RateLimitBackend
+	ImportRateLimitRequests = newConfig.ImportRateLimitRequests
+	ImportRateLimitWindowMinutes = newConfig.ImportRateLimitWindowMinutes
+	UploadRateLimitRequests = newConfig.UploadRateLimitRequests
+	UploadRateLimitWindowMinutes = newConfig.UploadRateLimitWindowMinutes
+	OIDCDiscoveryURL = newConfig.OIDCDiscoveryURL
+	OIDCClientID = newConfig.OIDCClientID
+	OIDCClientSecret = newConfig.OIDCClientSecret
+	OIDCRedirectURL = newConfig.OIDCRedirectURL
+	OIDCScopes = newConfig.OIDCScopes
+	OIDCAutoProvision = newConfig.OIDCAutoProvision
+	setMailGlobals(newConfig)
 	SeedOnStartup = newConfig.SeedOnStartup
 	FeatureItemPicturesUpload = newConfig.FeatureItemPicturesUpload
 
 	return nil
 }
 
+// setMailGlobals publishes the mail delivery settings
+func setMailGlobals(cfg Config) {
+	MailServerConfig = cfg.MailServer
+	MailTransport = cfg.MailTransport
+	MailSinkDir = cfg.MailSinkDir
+	EmailMaxAttempts = cfg.EmailMaxAttempts
+	EmailRetryBaseSeconds = cfg.EmailRetryBaseSeconds
+	EmailRetryMaxSeconds = cfg.EmailRetryMaxSeconds
+}
+
 func initConfig(envFilePath string) (Config, error) {
 	if err := loadEnv(envFilePath); err != nil {
 		return Config{}, err
@@ -134,6 +227,8 @@ func loadEnv(envFilePath string) error {
 func newConfig() Config {
 	return Config{
 		Scheme:                              "https",
+		JWTSigningAlg:                       "HS256",
+		JWTSigningKeyID:                     "primary",
 		TokenLifespan:                       1,
 		AccessTokenMinutes:                  15,
 		RefreshTokenDays:                    1,
@@ -144,6 +239,22 @@ func newConfig() Config {
 		RefreshRateLimitWindowMinutes:       1,
 		ResendConfirmRateLimitRequests:      1,
 		ResendConfirmRateLimitWindowMinutes: 1,
+		AuditRetentionDays:                  90,
+		AccountDeletionGraceDays:            30,
+		LoginFailureWindowMinutes:           15,
+		LoginBackoffThreshold:               3,
+		LoginBackoffBaseSeconds:             1,
+		LoginBackoffMaxSeconds:              60,
+		LoginLockoutThreshold:               10,
+		LoginLockoutMinutes:                 30,
+		LoginIPBackoffThreshold:             20,
+		RateLimitBackend:                    "memory",
+		ImportRateLimitRequests:             10,
+		ImportRateLimitWindowMinutes:        1,
+		UploadRateLimitRequests:             30,
+		UploadRateLimitWindowMinutes:        1,
+		OIDCScopes:                          []string{"openid", "email", "profile"},
+		OIDCAutoProvision:                   true,
 		Stage:                               "DEV",
 		HostName:                            "localhost",
 		DBConfig: DBConfig{
@@ -152,6 +263,10 @@ func newConfig() Config {
 		MailServer: MailServer{
 			MailPort: 587,
 		},
+		MailTransport:             "smtp",
+		EmailMaxAttempts:          8,
+		EmailRetryBaseSeconds:     30,
+		EmailRetryMaxSeconds:      3600,
 		FeatureItemPicturesUpload: true,
 	}
 }
@@ -166,6 +281,10 @@ func setEnvVars(cfg *Config) {
 	cfg.DBConfig.DBPort, _ = strconv.Atoi(ifEnvEmpty(os.Getenv("DB_PORT"), strconv.Itoa(cfg.DBConfig.DBPort)))
 	cfg.Stage = ifEnvEmpty(os.Getenv("STAGE"), cfg.Stage)
 	cfg.APISecret = ifEnvEmpty(os.Getenv("API_SECRET"), cfg.APISecret)
+	cfg.JWTSigningAlg = ifEnvEmpty(os.Getenv("JWT_SIGNING_ALG"), cfg.JWTSigningAlg)
+	cfg.JWTSigningKeyID = ifEnvEmpty(os.Getenv("JWT_SIGNING_KEY_ID"), cfg.JWTSigningKeyID)
+	cfg.JWTPrivateKeyFile = os.Getenv("JWT_PRIVATE_KEY_FILE")
+	cfg.JWTVerificationKeysDir = os.Getenv("JWT_VERIFICATION_KEYS_DIR")
 	cfg.TokenLifespan, _ = strconv.Atoi(ifEnvEmpty(os.Getenv("TOKEN_HOUR_LIFESPAN"), strconv.Itoa(cfg.TokenLifespan)))
 	cfg.AccessTokenMinutes, _ = strconv.Atoi(
 		ifEnvEmpty(os.Getenv("ACCESS_TOKEN_MINUTES"), strconv.Itoa(cfg.AccessTokenMinutes)))
@@ -186,14 +305,80 @@ func setEnvVars(cfg *Config) {
 	resendWindowDefault := strconv.Itoa(cfg.ResendConfirmRateLimitWindowMinutes)
 	cfg.ResendConfirmRateLimitWindowMinutes, _ = strconv.Atoi(
 		ifEnvEmpty(os.Getenv("RESEND_CONFIRM_RATE_LIMIT_WINDOW_MINUTES"), resendWindowDefault))
+	cfg.AuditRetentionDays, _ = strconv.Atoi(
+		ifEnvEmpty(os.Getenv("AUDIT_RETENTION_DAYS"), strconv.Itoa(cfg.AuditRetentionDays)))
+	cfg.AccountDeletionGraceDays, _ = strconv.Atoi(
+		ifEnvEmpty(os.Getenv("ACCOUNT_DELETION_GRACE_DAYS"), strconv.Itoa(cfg.AccountDeletionGraceDays)))
+	setLoginThrottleEnvVars(cfg)
+	setRateLimitEnvVars(cfg)
+	setOIDCEnvVars(cfg)
+	setMailEnvVars(cfg)
+	cfg.SeedOnStartup = os.Getenv("SEED_ON_STARTUP") == "true"
+	if v, err := strconv.ParseBool(os.Getenv("FEATURE_ITEM_PICTURES_UPLOAD")); err == nil {
+		cfg.FeatureItemPicturesUpload = v
+	}
+}
+
+// setMailEnvVars reads the SMTP server, the delivery transport and the outbox retry policy
+func setMailEnvVars(cfg *Config) {
 	cfg.MailServer.MailIdentity = os.Getenv("MAIL_IDENTITY")
 	cfg.MailServer.MailUsername = os.Getenv("MAIL_USERNAME")
 	cfg.MailServer.MailPassword = os.Getenv("MAIL_PASSWORD")
 	cfg.MailServer.MailServer = os.Getenv("MAIL_SERVER")
 	cfg.MailServer.MailPort, _ = strconv.Atoi(ifEnvEmpty(os.Getenv("MAIL_PORT"), strconv.Itoa(cfg.MailServer.MailPort)))
-	cfg.SeedOnStartup = os.Getenv("SEED_ON_STARTUP") == "true"
-	if v, err := strconv.ParseBool(os.Getenv("FEATURE_ITEM_PICTURES_UPLOAD")); err == nil {
-		cfg.FeatureItemPicturesUpload = v
+	cfg.MailTransport = ifEnvEmpty(os.Getenv("MAIL_TRANSPORT"), cfg.MailTransport)
+	cfg.MailSinkDir = os.Getenv("MAIL_SINK_DIR")
+	cfg.EmailMaxAttempts, _ = strconv.Atoi(
+		ifEnvEmpty(os.Getenv("EMAIL_MAX_ATTEMPTS"), strconv.Itoa(cfg.EmailMaxAttempts)))
+	cfg.EmailRetryBaseSeconds, _ = strconv.Atoi(
+		ifEnvEmpty(os.Getenv("EMAIL_RETRY_BASE_SECONDS"), strconv.Itoa(cfg.EmailRetryBaseSeconds)))
+	cfg.EmailRetryMaxSeconds, _ = strconv.Atoi(
+		ifEnvEmpty(os.Getenv("EMAIL_RETRY_MAX_SECONDS"), strconv.Itoa(cfg.EmailRetryMaxSeconds)))
+}
+
+// setLoginThrottleEnvVars reads the brute-force protection thresholds
+func setLoginThrottleEnvVars(cfg *Config) {
+	cfg.LoginFailureWindowMinutes, _ = strconv.Atoi(
+		ifEnvEmpty(os.Getenv("LOGIN_FAILURE_WINDOW_MINUTES"), strconv.Itoa(cfg.LoginFailureWindowMinutes)))
+	cfg.LoginBackoffThreshold, _ = strconv.Atoi(
+		ifEnvEmpty(os.Getenv("LOGIN_BACKOFF_THRESHOLD"), strconv.Itoa(cfg.LoginBackoffThreshold)))
+	cfg.LoginBackoffBaseSeconds, _ = strconv.Atoi(
+		ifEnvEmpty(os.Getenv("LOGIN_BACKOFF_BASE_SECONDS"), strconv.Itoa(cfg.LoginBackoffBaseSeconds)))
+	cfg.LoginBackoffMaxSeconds, _ = strconv.Atoi(
+		ifEnvEmpty(os.Getenv("LOGIN_BACKOFF_MAX_SECONDS"), strconv.Itoa(cfg.LoginBackoffMaxSeconds)))
+	cfg.LoginLockoutThreshold, _ = strconv.Atoi(
+		ifEnvEmpty(os.Getenv("LOGIN_LOCKOUT_THRESHOLD"), strconv.Itoa(cfg.LoginLockoutThreshold)))
+	cfg.LoginLockoutMinutes, _ = strconv.Atoi(
+		ifEnvEmpty(os.Getenv("LOGIN_LOCKOUT_MINUTES"), strconv.Itoa(cfg.LoginLockoutMinutes)))
+	cfg.LoginIPBackoffThreshold, _ = strconv.Atoi(
+		ifEnvEmpty(os.Getenv("LOGIN_IP_BACKOFF_THRESHOLD"), strconv.Itoa(cfg.LoginIPBackoffThreshold)))
+}
+
+// setRateLimitEnvVars reads the rate limiter backend and the per-user limits
+func setRateLimitEnvVars(cfg *Config) {
+	cfg.RateLimitBackend = ifEnvEmpty(os.Getenv("RATE_LIMIT_BACKEND"), cfg.RateLimitBackend)
+	cfg.ImportRateLimitRequests, _ = strconv.Atoi(
+		ifEnvEmpty(os.Getenv("IMPORT_RATE_LIMIT_REQUESTS"), strconv.Itoa(cfg.ImportRateLimitRequests)))
+	cfg.ImportRateLimitWindowMinutes, _ = strconv.Atoi(
+		ifEnvEmpty(os.Getenv("IMPORT_RATE_LIMIT_WINDOW_MINUTES"), strconv.Itoa(cfg.ImportRateLimitWindowMinutes)))
+	cfg.UploadRateLimitRequests, _ = strconv.Atoi(
+		ifEnvEmpty(os.Getenv("UPLOAD_RATE_LIMIT_REQUESTS"), strconv.Itoa(cfg.UploadRateLimitRequests)))
+	cfg.UploadRateLimitWindowMinutes, _ = strconv.Atoi(
+		ifEnvEmpty(os.Getenv("UPLOAD_RATE_LIMIT_WINDOW_MINUTES"), strconv.Itoa(cfg.UploadRateLimitWindowMinutes)))
+}
+
+// setOIDCEnvVars reads the OpenID Connect login settings; OIDC login is
+// enabled when OIDC_DISCOVERY_URL is set
+func setOIDCEnvVars(cfg *Config) {
+	cfg.OIDCDiscoveryURL = os.Getenv("OIDC_DISCOVERY_URL")
+	cfg.OIDCClientID = os.Getenv("OIDC_CLIENT_ID")
+	cfg.OIDCClientSecret = os.Getenv("OIDC_CLIENT_SECRET")
+	cfg.OIDCRedirectURL = os.Getenv("OIDC_REDIRECT_URL")
+	if scopes := strings.Fields(os.Getenv("OIDC_SCOPES")); len(scopes) > 0 {
+		cfg.OIDCScopes = scopes
+	}
+	if v, err := strconv.ParseBool(os.Getenv("OIDC_AUTO_PROVISION")); err == nil {
+		cfg.OIDCAutoProvision = v
 	}
 }
 
@@ -222,18 +407,60 @@ func validateConfig(cfg Config) error {
 		return errors.New("API_SECRET must not use a default placeholder value")
 	case len(cfg.APISecret) < 32:
 		return errors.New("API_SECRET must be at least 32 bytes")
+	case cfg.JWTSigningAlg != "HS256" && cfg.JWTSigningAlg != "EdDSA" && cfg.JWTSigningAlg != "RS256":
+		return errors.New("JWT_SIGNING_ALG must be HS256, EdDSA or RS256")
+	case cfg.JWTSigningAlg != "HS256" && cfg.JWTPrivateKeyFile == "":
+		return errors.New("JWT_PRIVATE_KEY_FILE is required with JWT_SIGNING_ALG " + cfg.JWTSigningAlg)
+	case cfg.RateLimitBackend != "memory" && cfg.RateLimitBackend != "postgres":
+		return errors.New("RATE_LIMIT_BACKEND must be memory or postgres")
+	case cfg.Stage == "LOCAL" && cfg.DBConfig.DBHost != "localhost" && cfg.DBConfig.DBHost != "127.0.0.1":
+		return errors.New("STAGE=LOCAL is not allowed with a non-local DB_HOST")
+	}
+	if err := validateMailConfig(cfg); err != nil {
+		return err
+	}
+	return validateOIDCConfig(cfg)
+}
+
+// validateMailConfig checks the sender address, the settings of the selected
+// transport (SMTP credentials are only needed with smtp) and the retry policy
+func validateMailConfig(cfg Config) error {
+	switch {
 	case cfg.MailServer.MailIdentity == "":
 		return errors.New("MAIL_IDENTITY is not set")
 	case !isValidMailAddress(cfg.MailServer.MailIdentity):
 		return errors.New("MAIL_IDENTITY must be a valid email address (used as sender address)")
+	case cfg.MailTransport != "smtp" && cfg.MailTransport != "dir" && cfg.MailTransport != "memory":
+		return errors.New("MAIL_TRANSPORT must be smtp, dir or memory")
+	case cfg.MailTransport == "dir" && cfg.MailSinkDir == "":
+		return errors.New("MAIL_SINK_DIR is required with MAIL_TRANSPORT dir")
+	case cfg.EmailMaxAttempts < 1:
+		return errors.New("EMAIL_MAX_ATTEMPTS must be at least 1")
+	case cfg.EmailRetryBaseSeconds < 1 || cfg.EmailRetryMaxSeconds < cfg.EmailRetryBaseSeconds:
+		return errors.New("EMAIL_RETRY_BASE_SECONDS must be positive and at most EMAIL_RETRY_MAX_SECONDS")
+	case cfg.MailTransport != "smtp":
+		return nil
 	case cfg.MailServer.MailUsername == "":
 		return errors.New("MAIL_USERNAME is not set")
 	case cfg.MailServer.MailPassword == "":
 		return errors.New("MAIL_PASSWORD is not set")
 	case cfg.MailServer.MailServer == "":
 		return errors.New("MAIL_SERVER is not set")
-	case cfg.Stage == "LOCAL" && cfg.DBConfig.DBHost != "localhost" && cfg.DBConfig.DBHost != "127.0.0.1":
-		return errors.New("STAGE=LOCAL is not allowed with a non-local DB_HOST")
+	}
+	return nil
+}
+
+// validateOIDCConfig checks the OIDC settings when OIDC login is enabled
+func validateOIDCConfig(cfg Config) error {
+	switch {
+	case cfg.OIDCDiscoveryURL == "":
+		return nil
+	case cfg.OIDCClientID == "":
+		return errors.New("OIDC_CLIENT_ID is required with OIDC_DISCOVERY_URL")
+	case cfg.OIDCRedirectURL == "":
+		return errors.New("OIDC_REDIRECT_URL is required with OIDC_DISCOVERY_URL")
+	case !slices.Contains(cfg.OIDCScopes, "openid"):
+		return errors.New("OIDC_SCOPES must include openid")
 	}
 	return nil
 }
@@ -468,6 +468,87 @@ func TestEnvInit(t *testing.T) {
 			},
 			wantError: false,
 		},
+		{
+			name: "Unknown RATE_LIMIT_BACKEND should fail",
+			envSlice: []string{
+				"SCHEME=http",
+				"HOSTNAME=localhost",
+				"DB_HOST=localhost",
+				"DB_USER=db_user",
+				"DB_PASSWORD=db_password",
+				"DB_NAME=db_name",
+				"DB_PORT=5432",
+				"STAGE=dev",
+				"API_SECRET=averylongsecretthatis32byteslong",
+				"TOKEN_HOUR_LIFESPAN=1",
+				"MAIL_IDENTITY=identity@exemple.com",
+				"MAIL_USERNAME=username",
+				"MAIL_PASSWORD=password",
+				"MAIL_SERVER=smtp.exemple.com",
+				"MAIL_PORT=587",
+				"RATE_LIMIT_BACKEND=redis",
+			},
+			wantError: true,
+		},
+		{
+			name: "OIDC_DISCOVERY_URL without OIDC_CLIENT_ID should fail",
+			envSlice: []string{
+				"SCHEME=http",
+				"HOSTNAME=localhost",
+				"DB_HOST=localhost",
+				"DB_USER=db_user",
+				"DB_PASSWORD=db_password",
+				"DB_NAME=db_name",
+				"DB_PORT=5432",
+				"STAGE=dev",
+				"API_SECRET=averylongsecretthatis32byteslong",
+				"TOKEN_HOUR_LIFESPAN=1",
+				"MAIL_IDENTITY=identity@exemple.com",
+				"MAIL_USERNAME=username",
+				"MAIL_PASSWORD=password",
+				"MAIL_SERVER=smtp.exemple.com",
+				"MAIL_PORT=587",
+				"OIDC_DISCOVERY_URL=https://idp.exemple.com/.well-known/openid-configuration",
+				"OIDC_REDIRECT_URL=https://app.exemple.com/oidc/callback",
+			},
+			wantError: true,
+		},
+		{
+			name: "MAIL_TRANSPORT memory does not need SMTP credentials",
+			envSlice: []string{
+				"SCHEME=http",
+				"HOSTNAME=localhost",
+				"DB_HOST=localhost",
+				"DB_USER=db_user",
+				"DB_PASSWORD=db_password",
+				"DB_NAME=db_name",
+				"DB_PORT=5432",
+				"STAGE=dev",
+				"API_SECRET=averylongsecretthatis32byteslong",
+				"TOKEN_HOUR_LIFESPAN=1",
+				"MAIL_IDENTITY=identity@exemple.com",
+				"MAIL_TRANSPORT=memory",
+			},
+			wantError: false,
+		},
+		{
+			name: "MAIL_TRANSPORT dir without MAIL_SINK_DIR should fail",
+			envSlice: []string{
+				"SCHEME=http",
+				"HOSTNAME=localhost",
+				"DB_HOST=localhost",
+				"DB_USER=db_user",
+				"DB_PASSWORD=db_password",
+				"DB_NAME=db_name",
+				"DB_PORT=5432",
+				"STAGE=dev",
+				"API_SECRET=averylongsecretthatis32byteslong",
+				"TOKEN_HOUR_LIFESPAN=1",
+				"MAIL_IDENTITY=identity@exemple.com",
+				"MAIL_TRANSPORT=dir",
+			},
+			wantError: true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,178 @@
+package currencies
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/Angak0k/pimpmypack/pkg/config"
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	// init env
+	err := config.EnvInit("../../.env")
+	if err != nil {
+		log.Fatalf("Error loading .env file or environment variable : %v", err)
+	}
+
+	// init DB
+	err = database.Initialization()
+	if err != nil {
+		log.Fatalf("Error connecting database : %v", err)
+	}
+
+	// init DB migration
+	err = database.Migrate()
+	if err != nil {
+		log.Fatalf("Error migrating database : %v", err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		want    string
+		wantErr bool
+	}{
+		{"upper case", "CHF", "CHF", false},
+		{"lower case with spaces", " jpy ", "JPY", false},
+		{"unknown code", "ABC", "", true},
+		{"symbol", "$", "", true},
+		{"empty", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.code)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidCurrency)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	cv := NewConverter(map[string]float64{"USD": 1.25, "JPY": 160, "KWD": 0.33})
+
+	tests := []struct {
+		name   string
+		amount int
+		from   string
+		to     string
+		want   int
+		wantOK bool
+	}{
+		{"same currency", 1234, "CHF", "CHF", 1234, true},
+		{"EUR to USD", 10000, "EUR", "USD", 12500, true},
+		{"USD to EUR", 12500, "USD", "EUR", 10000, true},
+		{"EUR cents to JPY without decimals", 1000, "EUR", "JPY", 1600, true},
+		{"JPY to EUR cents", 1600, "JPY", "EUR", 1000, true},
+		{"EUR to KWD with three decimals", 10000, "EUR", "KWD", 33000, true},
+		{"missing source rate", 1000, "CHF", "EUR", 0, false},
+		{"missing target rate", 1000, "EUR", "CHF", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := cv.Convert(tt.amount, tt.from, tt.to)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	assert.Nil(t, cv.ConvertPtr(1000, "CHF", "EUR"))
+	require.NotNil(t, cv.ConvertPtr(1000, "EUR", "USD"))
+	assert.Equal(t, 1250, *cv.ConvertPtr(1000, "EUR", "USD"))
+}
+
+func exchangeRatesRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/exchangerates", GetExchangeRates)
+	router.PUT("/admin/exchangerates", PutExchangeRates)
+	router.DELETE("/admin/exchangerates/:currency", DeleteExchangeRate)
+	return router
+}
+
+func putRates(t *testing.T, router *gin.Engine, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/admin/exchangerates", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestPutExchangeRates(t *testing.T) {
+	router := exchangeRatesRouter()
+	t.Cleanup(func() {
+		_, _ = database.DB().ExecContext(context.Background(),
+			`DELETE FROM exchange_rate WHERE currency IN ('XPF', 'MUR')`)
+	})
+
+	t.Run("creates and updates rates", func(t *testing.T) {
+		w := putRates(t, router, `{"rates":[{"currency":"xpf","rate":119.33},{"currency":"MUR","rate":50}]}`)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		w = putRates(t, router, `{"rates":[{"currency":"MUR","rate":51.5}]}`)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var rates ExchangeRates
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rates))
+		found := map[string]float64{}
+		for _, r := range rates {
+			found[r.Currency] = r.Rate
+		}
+		assert.InDelta(t, 119.33, found["XPF"], 1e-9)
+		assert.InDelta(t, 51.5, found["MUR"], 1e-9)
+
+		cv, err := LoadConverter(context.Background())
+		require.NoError(t, err)
+		assert.True(t, cv.CanConvert("XPF"))
+	})
+
+	t.Run("rejects invalid uploads", func(t *testing.T) {
+		for _, body := range []string{
+			`{"rates":[{"currency":"ZZZ","rate":2}]}`,
+			`{"rates":[{"currency":"EUR","rate":1}]}`,
+			`{"rates":[{"currency":"MUR","rate":0}]}`,
+			`{"rates":[]}`,
+		} {
+			w := putRates(t, router, body)
+			assert.Equal(t, http.StatusBadRequest, w.Code, body)
+		}
+	})
+}
+
+func TestDeleteExchangeRate(t *testing.T) {
+	router := exchangeRatesRouter()
+	w := putRates(t, router, `{"rates":[{"currency":"MGA","rate":5000}]}`)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/admin/exchangerates/mga", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/admin/exchangerates/MGA", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/admin/exchangerates/XX", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
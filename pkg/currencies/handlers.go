@@ -0,0 +1,112 @@
+package currencies
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Angak0k/pimpmypack/pkg/helper"
+	"github.com/gin-gonic/gin"
+)
+
+// GetExchangeRates handles GET /admin/exchangerates
+// @Summary [ADMIN] List exchange rates
+// @Description List the exchange rates against EUR (amount of currency worth 1 EUR) - for admin use only
+// @Security Bearer
+// @Tags Internal
+// @Produce json
+// @Success 200 {object} ExchangeRates
+// @Failure 500 {object} apitypes.ErrorResponse "Internal server error"
+// @Router /admin/exchangerates [get]
+func GetExchangeRates(c *gin.Context) {
+	rates, err := returnExchangeRates(c.Request.Context())
+	if err != nil {
+		helper.LogAndSanitize(err, "get exchange rates: return exchange rates failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, rates)
+}
+
+// PutExchangeRates handles PUT /admin/exchangerates
+// @Summary [ADMIN] Upload exchange rates
+// @Description Create or update a set of exchange rates against EUR - for admin use only.
+// @Description Rates not in the upload are left unchanged.
+// @Security Bearer
+// @Tags Internal
+// @Accept json
+// @Produce json
+// @Param rates body ExchangeRatesUploadRequest true "Exchange rates"
+// @Success 200 {object} ExchangeRates
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid currency code or rate"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal server error"
+// @Router /admin/exchangerates [put]
+func PutExchangeRates(c *gin.Context) {
+	var input ExchangeRatesUploadRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		helper.LogAndSanitize(err, "put exchange rates: bind JSON failed")
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": helper.ErrMsgBadRequest})
+		return
+	}
+
+	for i, r := range input.Rates {
+		currency, err := Normalize(r.Currency)
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": ErrInvalidCurrency.Error()})
+			return
+		}
+		if currency == BaseCurrency {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": ErrBaseCurrencyRate.Error()})
+			return
+		}
+		input.Rates[i].Currency = currency
+	}
+
+	if err := upsertExchangeRates(c.Request.Context(), input.Rates); err != nil {
+		helper.LogAndSanitize(err, "put exchange rates: upsert failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	rates, err := returnExchangeRates(c.Request.Context())
+	if err != nil {
+		helper.LogAndSanitize(err, "put exchange rates: return exchange rates failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, rates)
+}
+
+// DeleteExchangeRate handles DELETE /admin/exchangerates/:currency
+// @Summary [ADMIN] Delete an exchange rate
+// @Description Delete the exchange rate of a currency; its prices are no longer converted - for admin use only
+// @Security Bearer
+// @Tags Internal
+// @Produce json
+// @Param currency path string true "ISO 4217 currency code"
+// @Success 200 {object} apitypes.OkResponse
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid currency code"
+// @Failure 404 {object} apitypes.ErrorResponse "Exchange rate not found"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal server error"
+// @Router /admin/exchangerates/{currency} [delete]
+func DeleteExchangeRate(c *gin.Context) {
+	currency, err := Normalize(c.Param("currency"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": ErrInvalidCurrency.Error()})
+		return
+	}
+
+	err = deleteExchangeRate(c.Request.Context(), currency)
+	if err != nil {
+		if errors.Is(err, ErrRateNotFound) {
+			c.IndentedJSON(http.StatusNotFound, gin.H{"error": ErrRateNotFound.Error()})
+			return
+		}
+		helper.LogAndSanitize(err, "delete exchange rate: delete failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, gin.H{"message": "exchange rate deleted"})
+}
@@ -0,0 +1,81 @@
+package currencies
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Angak0k/pimpmypack/pkg/database"
+)
+
+func returnExchangeRates(ctx context.Context) (ExchangeRates, error) {
+	rows, err := database.DB().QueryContext(ctx,
+		`SELECT currency, rate, updated_at FROM exchange_rate ORDER BY currency;`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query exchange rates: %w", err)
+	}
+	defer rows.Close()
+
+	rates := ExchangeRates{}
+	for rows.Next() {
+		var r ExchangeRate
+		if err := rows.Scan(&r.Currency, &r.Rate, &r.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan exchange rate: %w", err)
+		}
+		rates = append(rates, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate exchange rates: %w", err)
+	}
+	return rates, nil
+}
+
+// upsertExchangeRates creates or updates all the rates in one transaction
+func upsertExchangeRates(ctx context.Context, rates []ExchangeRateInput) error {
+	tx, err := database.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := time.Now()
+	for _, r := range rates {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO exchange_rate (currency, rate, updated_at) VALUES ($1, $2, $3)
+			ON CONFLICT (currency) DO UPDATE SET rate = EXCLUDED.rate, updated_at = EXCLUDED.updated_at;`,
+			r.Currency, r.Rate, now)
+		if err != nil {
+			return fmt.Errorf("failed to upsert exchange rate %s: %w", r.Currency, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit exchange rates: %w", err)
+	}
+	return nil
+}
+
+func deleteExchangeRate(ctx context.Context, currency string) error {
+	result, err := database.DB().ExecContext(ctx, `DELETE FROM exchange_rate WHERE currency = $1;`, currency)
+	if err != nil {
+		return fmt.Errorf("failed to delete exchange rate: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrRateNotFound
+	}
+	return nil
+}
+
+func findPreferredCurrency(ctx context.Context, userID uint) (string, error) {
+	var currency string
+	err := database.DB().QueryRowContext(ctx,
+		`SELECT preferred_currency FROM account WHERE id = $1;`, userID).Scan(&currency)
+	if err != nil {
+		return "", fmt.Errorf("failed to query preferred currency: %w", err)
+	}
+	return currency, nil
+}
@@ -0,0 +1,100 @@
+package currencies
+
+import (
+	"context"
+	"math"
+)
+
+// Converter converts prices with a snapshot of the exchange rates
+type Converter struct {
+	// rates maps a currency to the amount of it worth one BaseCurrency
+	rates map[string]float64
+}
+
+// NewConverter returns a converter for the given rates; BaseCurrency is
+// always convertible
+func NewConverter(rates map[string]float64) *Converter {
+	cv := &Converter{rates: map[string]float64{BaseCurrency: 1}}
+	for currency, rate := range rates {
+		if rate > 0 {
+			cv.rates[currency] = rate
+		}
+	}
+	return cv
+}
+
+// LoadConverter returns a converter with the current exchange rates
+func LoadConverter(ctx context.Context) (*Converter, error) {
+	stored, err := returnExchangeRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rates := make(map[string]float64, len(stored))
+	for _, r := range stored {
+		rates[r.Currency] = r.Rate
+	}
+	return NewConverter(rates), nil
+}
+
+// PreferredCurrency returns the preferred currency of a user, BaseCurrency
+// when the stored value is not a valid code
+func PreferredCurrency(ctx context.Context, userID uint) (string, error) {
+	currency, err := findPreferredCurrency(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if !IsValid(currency) {
+		return BaseCurrency, nil
+	}
+	return currency, nil
+}
+
+// ConverterFor returns the preferred currency of a user and a converter with
+// the current exchange rates
+func ConverterFor(ctx context.Context, userID uint) (string, *Converter, error) {
+	currency, err := PreferredCurrency(ctx, userID)
+	if err != nil {
+		return "", nil, err
+	}
+	cv, err := LoadConverter(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	return currency, cv, nil
+}
+
+// CanConvert reports whether a rate is known for the currency
+func (cv *Converter) CanConvert(currency string) bool {
+	_, ok := cv.rates[currency]
+	return ok
+}
+
+// Convert converts an amount in minor units of from into minor units of to,
+// rounded to the nearest unit. It returns false when a rate is missing.
+func (cv *Converter) Convert(amount int, from, to string) (int, bool) {
+	if from == to {
+		return amount, true
+	}
+	fromRate, ok := cv.rates[from]
+	if !ok {
+		return 0, false
+	}
+	toRate, ok := cv.rates[to]
+	if !ok {
+		return 0, false
+	}
+
+	major := float64(amount) / math.Pow10(MinorUnits(from))
+	converted := major / fromRate * toRate
+	return int(math.Round(converted * math.Pow10(MinorUnits(to)))), true
+}
+
+// ConvertPtr is Convert returning nil when a rate is missing, for the
+// optional price_in_preferred_currency fields
+func (cv *Converter) ConvertPtr(amount int, from, to string) *int {
+	converted, ok := cv.Convert(amount, from, to)
+	if !ok {
+		return nil
+	}
+	return &converted
+}
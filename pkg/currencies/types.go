@@ -0,0 +1,33 @@
+package currencies
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrBaseCurrencyRate is returned when a rate is uploaded for the base currency
+var ErrBaseCurrencyRate = errors.New("EUR is the base currency, its rate is always 1")
+
+// ErrRateNotFound is returned when no exchange rate is stored for a currency
+var ErrRateNotFound = errors.New("exchange rate not found")
+
+// ExchangeRate is the amount of a currency worth one BaseCurrency
+type ExchangeRate struct {
+	Currency  string    `json:"currency"`
+	Rate      float64   `json:"rate"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ExchangeRates represents a collection of exchange rates
+type ExchangeRates []ExchangeRate
+
+// ExchangeRateInput is one rate of an upload
+type ExchangeRateInput struct {
+	Currency string  `json:"currency" binding:"required"`
+	Rate     float64 `json:"rate" binding:"required,gt=0"`
+}
+
+// ExchangeRatesUploadRequest creates or updates a set of exchange rates at once
+type ExchangeRatesUploadRequest struct {
+	Rates []ExchangeRateInput `json:"rates" binding:"required,min=1,dive"`
+}
@@ -0,0 +1,261 @@
+package customfields
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/Angak0k/pimpmypack/pkg/config"
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/Angak0k/pimpmypack/pkg/security"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	err := config.EnvInit("../../.env")
+	if err != nil {
+		log.Fatalf("Error loading .env file or environment variable : %v", err)
+	}
+
+	err = database.Initialization()
+	if err != nil {
+		log.Fatalf("Error connecting database : %v", err)
+	}
+
+	err = database.Migrate()
+	if err != nil {
+		log.Fatalf("Error migrating database : %v", err)
+	}
+
+	err = loadingCustomFieldDataset()
+	if err != nil {
+		log.Fatalf("Error loading dataset : %v", err)
+	}
+
+	ret := m.Run()
+
+	if err := cleanupCustomFieldDataset(); err != nil {
+		log.Printf("Warning: Error cleaning up dataset : %v", err)
+	}
+
+	os.Exit(ret)
+}
+
+func TestValidateFieldCreate(t *testing.T) {
+	unit := " °C "
+	input := FieldCreateRequest{Key: " temp_rating", Name: " Temperature ", Type: TypeNumber, Unit: &unit}
+	require.NoError(t, validateFieldCreate(&input))
+	assert.Equal(t, "temp_rating", input.Key)
+	assert.Equal(t, "Temperature", input.Name)
+	assert.Equal(t, "°C", *input.Unit)
+
+	input = FieldCreateRequest{Key: "season", Name: "Season", Type: TypeEnum, Options: []string{" Summer", "Winter"}}
+	require.NoError(t, validateFieldCreate(&input))
+	assert.Equal(t, []string{"Summer", "Winter"}, input.Options)
+
+	tests := []struct {
+		input   FieldCreateRequest
+		wantErr error
+	}{
+		{FieldCreateRequest{Key: "Temp", Name: "Temp", Type: TypeNumber}, ErrInvalidFieldKey},
+		{FieldCreateRequest{Key: "temp", Name: " ", Type: TypeNumber}, ErrEmptyFieldName},
+		{FieldCreateRequest{Key: "temp", Name: "Temp", Type: "date"}, ErrInvalidFieldType},
+		{FieldCreateRequest{Key: "fabric", Name: "Fabric", Type: TypeText, Unit: &unit}, ErrUnitNotAllowed},
+		{FieldCreateRequest{Key: "fabric", Name: "Fabric", Type: TypeText, Options: []string{"nylon"}},
+			ErrOptionsNotAllowed},
+		{FieldCreateRequest{Key: "season", Name: "Season", Type: TypeEnum}, ErrInvalidOptions},
+		{FieldCreateRequest{Key: "season", Name: "Season", Type: TypeEnum, Options: []string{"a", " a"}},
+			ErrInvalidOptions},
+	}
+	for _, tt := range tests {
+		assert.ErrorIs(t, validateFieldCreate(&tt.input), tt.wantErr, "%+v", tt.input)
+	}
+}
+
+var testFields = Fields{
+	{Key: "fabric", Type: TypeText},
+	{Key: "temp_rating", Type: TypeNumber},
+	{Key: "freestanding", Type: TypeBoolean},
+	{Key: "season", Type: TypeEnum, Options: []string{"Summer", "Winter"}},
+}
+
+func TestNormalize(t *testing.T) {
+	values, err := testFields.Normalize(Values{
+		"fabric": " 20D nylon ", "temp_rating": "-5,5", "freestanding": "true", "season": "winter",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, Values{"fabric": "20D nylon", "temp_rating": -5.5, "freestanding": true, "season": "Winter"},
+		values)
+
+	values, err = testFields.Normalize(Values{"fabric": "", "temp_rating": nil, "freestanding": false})
+	require.NoError(t, err)
+	assert.Equal(t, Values{"freestanding": false}, values)
+
+	for values, wantErr := range map[string]error{
+		`{"volume": 30}`:           ErrUnknownField,
+		`{"temp_rating": "cold"}`:  ErrInvalidFieldValue,
+		`{"freestanding": 1}`:      ErrInvalidFieldValue,
+		`{"season": "Spring"}`:     ErrInvalidFieldValue,
+		`{"fabric": ["nylon"]}`:    ErrInvalidFieldValue,
+		`{"temp_rating": "1e999"}`: ErrInvalidFieldValue,
+	} {
+		var input Values
+		require.NoError(t, json.Unmarshal([]byte(values), &input))
+		_, err := testFields.Normalize(input)
+		assert.ErrorIs(t, err, wantErr, values)
+	}
+}
+
+func TestParseFilters(t *testing.T) {
+	quilt := Values{"fabric": "10D Nylon", "temp_rating": 5.0, "freestanding": false, "season": "Summer"}
+	bag := Values{"temp_rating": -10.0, "season": "Winter"}
+
+	tests := map[string][2]bool{
+		"field.fabric=nylon":                  {true, false},
+		"field.temp_rating=5":                 {true, false},
+		"field.temp_rating=..0":               {false, true},
+		"field.temp_rating=-10..":             {true, true},
+		"field.temp_rating=0..10":             {true, false},
+		"field.temp_rating=5..5":              {true, false},
+		"field.temp_rating=-10..-10":          {false, true},
+		"field.freestanding=false":            {true, false},
+		"field.season=winter":                 {false, true},
+		"field.season=Summer&field.fabric=10": {true, false},
+		"units=metric":                        {true, true},
+	}
+	for query, want := range tests {
+		params, err := url.ParseQuery(query)
+		require.NoError(t, err)
+		filters, err := testFields.ParseFilters(params)
+		require.NoError(t, err, query)
+		for i, values := range []Values{quilt, bag} {
+			matches := true
+			for _, f := range filters {
+				matches = matches && f.Matches(values)
+			}
+			assert.Equal(t, want[i], matches, "%s on item %d", query, i)
+		}
+	}
+
+	for query, wantErr := range map[string]error{
+		"field.volume=30":        ErrUnknownField,
+		"field.temp_rating=warm": ErrInvalidFieldValue,
+		"field.temp_rating=a..b": ErrInvalidFieldValue,
+		"field.season=Spring":    ErrInvalidFieldValue,
+		"field.freestanding=":    ErrInvalidFieldValue,
+	} {
+		params, err := url.ParseQuery(query)
+		require.NoError(t, err)
+		_, err = testFields.ParseFilters(params)
+		assert.ErrorIs(t, err, wantErr, query)
+	}
+}
+
+func customFieldRequest(t *testing.T, userID uint, method, path string, payload any) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/mycustomfields", GetMyCustomFields)
+	router.POST("/mycustomfields", PostMyCustomField)
+	router.PUT("/mycustomfields/:id", PutMyCustomFieldByID)
+	router.DELETE("/mycustomfields/:id", DeleteMyCustomFieldByID)
+
+	token, err := security.GenerateToken(userID)
+	require.NoError(t, err)
+	var body bytes.Buffer
+	if payload != nil {
+		require.NoError(t, json.NewEncoder(&body).Encode(payload))
+	}
+	req := httptest.NewRequest(method, path, &body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// itemValues returns the custom field values of the items of the first test user by name
+func itemValues(t *testing.T) map[string]Values {
+	t.Helper()
+	rows, err := database.DB().QueryContext(context.Background(),
+		`SELECT item_name, custom_fields FROM inventory WHERE user_id = $1;`, testUsers[0].ID)
+	require.NoError(t, err)
+	defer rows.Close()
+	byName := map[string]Values{}
+	for rows.Next() {
+		var name string
+		var values Values
+		require.NoError(t, rows.Scan(&name, &values))
+		byName[name] = values
+	}
+	require.NoError(t, rows.Err())
+	return byName
+}
+
+func TestMyCustomFields(t *testing.T) {
+	userID := testUsers[0].ID
+	created := map[string]Field{}
+
+	t.Run("create fields and count the items using them", func(t *testing.T) {
+		for _, input := range []FieldCreateRequest{
+			{Key: "fabric", Name: "Fabric", Type: TypeText},
+			{Key: "season", Name: "Season", Type: TypeEnum, Options: []string{"Summer", "Winter"}},
+		} {
+			w := customFieldRequest(t, userID, http.MethodPost, "/mycustomfields", input)
+			require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+			var field Field
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &field))
+			created[field.Key] = field
+		}
+		assert.Equal(t, 3, created["season"].ItemCount)
+
+		w := customFieldRequest(t, userID, http.MethodPost, "/mycustomfields",
+			FieldCreateRequest{Key: "fabric", Name: "Shell", Type: TypeText})
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		w = customFieldRequest(t, userID, http.MethodGet, "/mycustomfields", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var list Fields
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+		assert.Len(t, list, 2)
+	})
+
+	t.Run("dropped enum options are removed from the items", func(t *testing.T) {
+		path := "/mycustomfields/" + uintString(created["season"].ID)
+		w := customFieldRequest(t, userID, http.MethodPut, path,
+			FieldUpdateRequest{Name: "Season", Options: []string{"Summer", "3-season"}})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var field Field
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &field))
+		assert.Equal(t, 2, field.ItemCount)
+		assert.NotContains(t, itemValues(t)["Bag"], "season")
+		assert.Equal(t, "Summer", itemValues(t)["Quilt"]["season"])
+	})
+
+	t.Run("other users cannot touch the fields", func(t *testing.T) {
+		path := "/mycustomfields/" + uintString(created["fabric"].ID)
+		w := customFieldRequest(t, testUsers[1].ID, http.MethodPut, path, FieldUpdateRequest{Name: "Mine"})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		w = customFieldRequest(t, testUsers[1].ID, http.MethodDelete, path, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("delete removes the values from the items", func(t *testing.T) {
+		w := customFieldRequest(t, userID, http.MethodDelete, "/mycustomfields/"+uintString(created["fabric"].ID), nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, Values{"season": "Summer"}, itemValues(t)["Quilt"])
+	})
+}
+
+func uintString(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
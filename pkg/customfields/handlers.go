@@ -0,0 +1,190 @@
+package customfields
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Angak0k/pimpmypack/pkg/helper"
+	"github.com/Angak0k/pimpmypack/pkg/security"
+	"github.com/gin-gonic/gin"
+)
+
+// GetMyCustomFields lists the custom fields of the user
+// @Summary Get my custom fields
+// @Description Lists the custom field definitions of the user, with the number of items that have a value
+// @Security Bearer
+// @Tags Custom fields
+// @Produce json
+// @Success 200 {object} Fields
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/mycustomfields [get]
+func GetMyCustomFields(c *gin.Context) {
+	userID, err := security.ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, "get my custom fields: extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return
+	}
+
+	fields, err := returnFieldsByUserID(c.Request.Context(), userID)
+	if err != nil {
+		helper.LogAndSanitize(err, "get my custom fields: return fields failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, fields)
+}
+
+// PostMyCustomField creates a custom field for the user
+// @Summary Create a custom field
+// @Description Defines a custom field for the inventory items of the user: text, number with an optional
+// @Description unit, boolean, or enum with its options. Items hold their values under the field key.
+// @Security Bearer
+// @Tags Custom fields
+// @Accept json
+// @Produce json
+// @Param field body FieldCreateRequest true "Custom field"
+// @Success 201 {object} Field
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid payload"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 409 {object} apitypes.ErrorResponse "Custom field key already exists"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/mycustomfields [post]
+func PostMyCustomField(c *gin.Context) {
+	userID, err := security.ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, "post my custom field: extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return
+	}
+
+	var input FieldCreateRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		helper.LogAndSanitize(err, "post my custom field: bind JSON failed")
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": helper.ErrMsgBadRequest})
+		return
+	}
+	if err := validateFieldCreate(&input); err != nil {
+		respondFieldError(c, err, "post my custom field: validate failed")
+		return
+	}
+
+	field, err := insertField(c.Request.Context(), userID, &input)
+	if err != nil {
+		respondFieldError(c, err, "post my custom field: insert field failed")
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, field)
+}
+
+// PutMyCustomFieldByID updates a custom field of the user
+// @Summary Update a custom field
+// @Description Updates the name, unit and options of a custom field; its key and type cannot change.
+// @Description Item values no longer among the options of an enum field are removed.
+// @Security Bearer
+// @Tags Custom fields
+// @Accept json
+// @Produce json
+// @Param id path int true "Custom field ID"
+// @Param field body FieldUpdateRequest true "Custom field settings"
+// @Success 200 {object} Field
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid payload"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 404 {object} apitypes.ErrorResponse "Custom field not found"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/mycustomfields/{id} [put]
+func PutMyCustomFieldByID(c *gin.Context) {
+	userID, err := security.ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, "put my custom field: extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return
+	}
+
+	id, err := helper.StringToUint(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	var input FieldUpdateRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		helper.LogAndSanitize(err, "put my custom field: bind JSON failed")
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": helper.ErrMsgBadRequest})
+		return
+	}
+
+	field, err := updateField(c.Request.Context(), id, userID, &input)
+	if err != nil {
+		respondFieldError(c, err, "put my custom field: update field failed")
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, field)
+}
+
+// DeleteMyCustomFieldByID deletes a custom field of the user
+// @Summary Delete a custom field
+// @Description Deletes a custom field and its values on every item
+// @Security Bearer
+// @Tags Custom fields
+// @Produce json
+// @Param id path int true "Custom field ID"
+// @Success 200 {object} apitypes.OkResponse "Custom field deleted"
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 404 {object} apitypes.ErrorResponse "Custom field not found"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/mycustomfields/{id} [delete]
+func DeleteMyCustomFieldByID(c *gin.Context) {
+	userID, err := security.ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, "delete my custom field: extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return
+	}
+
+	id, err := helper.StringToUint(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	if err := deleteField(c.Request.Context(), id, userID); err != nil {
+		respondFieldError(c, err, "delete my custom field: delete field failed")
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, gin.H{"message": "Custom field deleted"})
+}
+
+// RespondValueError responds with the key of an invalid custom field value or
+// filter and returns true, or returns false for other errors
+func RespondValueError(c *gin.Context, err error) bool {
+	var valueErr *ValueError
+	if !errors.As(err, &valueErr) {
+		return false
+	}
+	c.IndentedJSON(http.StatusBadRequest, gin.H{"error": valueErr.Err.Error(), "field": valueErr.Key})
+	return true
+}
+
+// respondFieldError maps custom field errors to an HTTP response
+func respondFieldError(c *gin.Context, err error, logMsg string) {
+	switch {
+	case errors.Is(err, ErrFieldNotFound):
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": ErrFieldNotFound.Error()})
+	case errors.Is(err, ErrFieldKeyExists):
+		c.IndentedJSON(http.StatusConflict, gin.H{"error": ErrFieldKeyExists.Error()})
+	case errors.Is(err, ErrInvalidFieldKey), errors.Is(err, ErrEmptyFieldName), errors.Is(err, ErrInvalidFieldType),
+		errors.Is(err, ErrInvalidOptions), errors.Is(err, ErrUnitNotAllowed), errors.Is(err, ErrOptionsNotAllowed),
+		errors.Is(err, ErrTooManyFields):
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		helper.LogAndSanitize(err, logMsg)
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+	}
+}
@@ -0,0 +1,187 @@
+package customfields
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/lib/pq"
+)
+
+const fieldColumns = `f.id, f.key, f.name, f.field_type, f.unit, f.options,
+	(SELECT COUNT(*) FROM inventory i WHERE i.user_id = f.user_id AND i.custom_fields ? f.key) AS item_count,
+	f.created_at, f.updated_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// queryer is implemented by *sql.DB and *sql.Tx
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func scanField(row rowScanner) (Field, error) {
+	var f Field
+	err := row.Scan(&f.ID, &f.Key, &f.Name, &f.Type, &f.Unit, pq.Array(&f.Options), &f.ItemCount,
+		&f.CreatedAt, &f.UpdatedAt)
+	return f, err
+}
+
+func returnFieldsByUserID(ctx context.Context, userID uint) (Fields, error) {
+	rows, err := database.DB().QueryContext(ctx,
+		`SELECT `+fieldColumns+`
+		FROM custom_field f
+		WHERE f.user_id = $1
+		ORDER BY f.id;`,
+		userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query custom fields: %w", err)
+	}
+	defer rows.Close()
+
+	fields := Fields{}
+	for rows.Next() {
+		f, err := scanField(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan custom field: %w", err)
+		}
+		fields = append(fields, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate custom fields: %w", err)
+	}
+	return fields, nil
+}
+
+func findFieldByID(ctx context.Context, q queryer, id, userID uint) (*Field, error) {
+	f, err := scanField(q.QueryRowContext(ctx,
+		`SELECT `+fieldColumns+` FROM custom_field f WHERE f.id = $1 AND f.user_id = $2;`,
+		id, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrFieldNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query custom field: %w", err)
+	}
+	return &f, nil
+}
+
+// insertField creates a custom field, up to maxFieldsPerUser per user
+func insertField(ctx context.Context, userID uint, input *FieldCreateRequest) (*Field, error) {
+	tx, err := database.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var count int
+	var exists bool
+	err = tx.QueryRowContext(ctx,
+		`SELECT COUNT(*), COALESCE(BOOL_OR(key = $2), false) FROM custom_field WHERE user_id = $1;`,
+		userID, input.Key).Scan(&count, &exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count custom fields: %w", err)
+	}
+	if exists {
+		return nil, ErrFieldKeyExists
+	}
+	if count >= maxFieldsPerUser {
+		return nil, ErrTooManyFields
+	}
+
+	var id uint
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO custom_field (user_id, key, name, field_type, unit, options)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id;`,
+		userID, input.Key, input.Name, input.Type, input.Unit, pq.Array(input.Options)).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert custom field: %w", err)
+	}
+
+	field, err := findFieldByID(ctx, tx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit custom field: %w", err)
+	}
+	return field, nil
+}
+
+// updateField updates a custom field; the values of an enum field that are no
+// longer among its options are removed from the items
+func updateField(ctx context.Context, id, userID uint, input *FieldUpdateRequest) (*Field, error) {
+	tx, err := database.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	current, err := findFieldByID(ctx, tx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateFieldUpdate(current.Type, input); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE custom_field SET name = $1, unit = $2, options = $3, updated_at = NOW() WHERE id = $4;`,
+		input.Name, input.Unit, pq.Array(input.Options), id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update custom field: %w", err)
+	}
+
+	if current.Type == TypeEnum {
+		_, err = tx.ExecContext(ctx,
+			`UPDATE inventory SET custom_fields = custom_fields - $2::text, updated_at = NOW()
+			WHERE user_id = $1 AND custom_fields ? $2::text
+				AND NOT (custom_fields ->> $2::text = ANY($3));`,
+			userID, current.Key, pq.Array(input.Options))
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove dropped options: %w", err)
+		}
+	}
+
+	updated, err := findFieldByID(ctx, tx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit custom field update: %w", err)
+	}
+	return updated, nil
+}
+
+// deleteField deletes a custom field and its values on the items of the user
+func deleteField(ctx context.Context, id, userID uint) error {
+	tx, err := database.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	field, err := findFieldByID(ctx, tx, id, userID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx,
+		`UPDATE inventory SET custom_fields = custom_fields - $2::text, updated_at = NOW()
+		WHERE user_id = $1 AND custom_fields ? $2::text;`,
+		userID, field.Key)
+	if err != nil {
+		return fmt.Errorf("failed to remove custom field values: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM custom_field WHERE id = $1;`, id); err != nil {
+		return fmt.Errorf("failed to delete custom field: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit custom field deletion: %w", err)
+	}
+	return nil
+}
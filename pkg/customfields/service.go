@@ -0,0 +1,270 @@
+package customfields
+
+import (
+	"context"
+	"math"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FilterPrefix prefixes the custom field filters of inventory listings
+const FilterPrefix = "field."
+
+var keyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,39}$`)
+
+// ReturnFieldsByUserID retrieves the custom fields of a user
+func ReturnFieldsByUserID(ctx context.Context, userID uint) (Fields, error) {
+	return returnFieldsByUserID(ctx, userID)
+}
+
+// validateFieldCreate trims the input and checks it against the field type
+func validateFieldCreate(input *FieldCreateRequest) error {
+	input.Key = strings.TrimSpace(input.Key)
+	if !keyPattern.MatchString(input.Key) {
+		return ErrInvalidFieldKey
+	}
+	switch input.Type {
+	case TypeText, TypeNumber, TypeBoolean, TypeEnum:
+	default:
+		return ErrInvalidFieldType
+	}
+	name, unit, options, err := validateFieldSettings(input.Type, input.Name, input.Unit, input.Options)
+	input.Name, input.Unit, input.Options = name, unit, options
+	return err
+}
+
+// validateFieldUpdate trims the input and checks it against the type of the field
+func validateFieldUpdate(fieldType string, input *FieldUpdateRequest) error {
+	name, unit, options, err := validateFieldSettings(fieldType, input.Name, input.Unit, input.Options)
+	input.Name, input.Unit, input.Options = name, unit, options
+	return err
+}
+
+func validateFieldSettings(
+	fieldType, name string, unit *string, options []string,
+) (string, *string, []string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", nil, nil, ErrEmptyFieldName
+	}
+	if unit != nil {
+		trimmed := strings.TrimSpace(*unit)
+		unit = &trimmed
+		if trimmed == "" {
+			unit = nil
+		}
+	}
+	if unit != nil && fieldType != TypeNumber {
+		return "", nil, nil, ErrUnitNotAllowed
+	}
+
+	if fieldType != TypeEnum {
+		if len(options) > 0 {
+			return "", nil, nil, ErrOptionsNotAllowed
+		}
+		return name, unit, []string{}, nil
+	}
+	trimmed := make([]string, 0, len(options))
+	for _, option := range options {
+		option = strings.TrimSpace(option)
+		if option == "" || slices.Contains(trimmed, option) {
+			return "", nil, nil, ErrInvalidOptions
+		}
+		trimmed = append(trimmed, option)
+	}
+	if len(trimmed) == 0 {
+		return "", nil, nil, ErrInvalidOptions
+	}
+	return name, unit, trimmed, nil
+}
+
+// find returns the field with the given key
+func (fs Fields) find(key string) (Field, bool) {
+	for _, f := range fs {
+		if f.Key == key {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// Normalize checks values against the fields and converts them to the field
+// types; numbers and booleans may be given as strings, as in CSV files. Null
+// and empty values are left out.
+func (fs Fields) Normalize(values Values) (Values, error) {
+	normalized := Values{}
+	for key, value := range values {
+		field, ok := fs.find(key)
+		if !ok {
+			return nil, &ValueError{Key: key, Err: ErrUnknownField}
+		}
+		v, err := field.normalize(value)
+		if err != nil {
+			return nil, &ValueError{Key: key, Err: err}
+		}
+		if v != nil {
+			normalized[key] = v
+		}
+	}
+	return normalized, nil
+}
+
+func (f Field) normalize(value any) (any, error) {
+	if s, ok := value.(string); ok {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return nil, nil
+		}
+		value = s
+	}
+	if value == nil {
+		return nil, nil
+	}
+
+	switch f.Type {
+	case TypeNumber:
+		return toNumber(value)
+	case TypeBoolean:
+		return toBoolean(value)
+	case TypeEnum:
+		s, ok := value.(string)
+		if !ok {
+			return nil, ErrInvalidFieldValue
+		}
+		for _, option := range f.Options {
+			if strings.EqualFold(option, s) {
+				return option, nil
+			}
+		}
+		return nil, ErrInvalidFieldValue
+	default:
+		s, ok := value.(string)
+		if !ok || utf8.RuneCountInString(s) > maxTextLength {
+			return nil, ErrInvalidFieldValue
+		}
+		return s, nil
+	}
+}
+
+func toNumber(value any) (float64, error) {
+	var n float64
+	switch v := value.(type) {
+	case float64:
+		n = v
+	case string:
+		parsed, err := strconv.ParseFloat(strings.Replace(v, ",", ".", 1), 64)
+		if err != nil {
+			return 0, ErrInvalidFieldValue
+		}
+		n = parsed
+	default:
+		return 0, ErrInvalidFieldValue
+	}
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, ErrInvalidFieldValue
+	}
+	return n, nil
+}
+
+func toBoolean(value any) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, ErrInvalidFieldValue
+		}
+		return b, nil
+	default:
+		return false, ErrInvalidFieldValue
+	}
+}
+
+// Filter selects items by the value of a custom field
+type Filter struct {
+	field    Field
+	value    any
+	min, max *float64
+}
+
+// ParseFilters reads the field.<key>=<value> parameters of a query. Text
+// filters match values containing them, ignoring case; number filters also
+// take min..max ranges with optional bounds.
+func (fs Fields) ParseFilters(query url.Values) ([]Filter, error) {
+	var filters []Filter
+	for param, values := range query {
+		key, ok := strings.CutPrefix(param, FilterPrefix)
+		if !ok || len(values) == 0 {
+			continue
+		}
+		field, ok := fs.find(key)
+		if !ok {
+			return nil, &ValueError{Key: key, Err: ErrUnknownField}
+		}
+		filter, err := field.parseFilter(values[0])
+		if err != nil {
+			return nil, &ValueError{Key: key, Err: err}
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+func (f Field) parseFilter(raw string) (Filter, error) {
+	filter := Filter{field: f}
+	if lower, upper, ok := strings.Cut(raw, ".."); ok && f.Type == TypeNumber {
+		if err := parseBound(lower, &filter.min); err != nil {
+			return filter, err
+		}
+		if err := parseBound(upper, &filter.max); err != nil {
+			return filter, err
+		}
+		return filter, nil
+	}
+
+	value, err := f.normalize(raw)
+	if err != nil {
+		return filter, err
+	}
+	if value == nil {
+		return filter, ErrInvalidFieldValue
+	}
+	filter.value = value
+	return filter, nil
+}
+
+// parseBound parses a bound of a min..max range into target, left nil when
+// the bound is omitted
+func parseBound(bound string, target **float64) error {
+	if strings.TrimSpace(bound) == "" {
+		return nil
+	}
+	n, err := toNumber(bound)
+	if err != nil {
+		return err
+	}
+	*target = &n
+	return nil
+}
+
+// Matches tells whether the values of an item pass the filter
+func (f Filter) Matches(values Values) bool {
+	value, ok := values[f.field.Key]
+	if !ok {
+		return false
+	}
+	if f.value == nil {
+		n, ok := value.(float64)
+		return ok && (f.min == nil || n >= *f.min) && (f.max == nil || n <= *f.max)
+	}
+	if f.field.Type == TypeText {
+		s, ok := value.(string)
+		return ok && strings.Contains(strings.ToLower(s), strings.ToLower(f.value.(string)))
+	}
+	return value == f.value
+}
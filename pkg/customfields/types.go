@@ -0,0 +1,122 @@
+package customfields
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Field types
+const (
+	TypeText    = "text"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+	TypeEnum    = "enum"
+)
+
+const (
+	// maxFieldsPerUser caps the custom fields of a user
+	maxFieldsPerUser = 50
+	// maxTextLength caps the length of text values
+	maxTextLength = 500
+)
+
+// Domain errors
+var (
+	ErrFieldNotFound     = errors.New("custom field not found")
+	ErrFieldKeyExists    = errors.New("custom field key already exists")
+	ErrInvalidFieldKey   = errors.New("key must be lowercase letters, digits and underscores, starting with a letter")
+	ErrEmptyFieldName    = errors.New("custom field name must not be empty")
+	ErrInvalidFieldType  = errors.New("type must be text, number, boolean or enum")
+	ErrInvalidOptions    = errors.New("enum fields need distinct, non-empty options")
+	ErrUnitNotAllowed    = errors.New("only number fields have a unit")
+	ErrOptionsNotAllowed = errors.New("only enum fields have options")
+	ErrTooManyFields     = errors.New("too many custom fields")
+	ErrUnknownField      = errors.New("unknown custom field")
+	ErrInvalidFieldValue = errors.New("custom field value does not match the field type")
+)
+
+// Field is a custom field definition of a user. Unit applies to number
+// fields and Options list the values of enum fields.
+type Field struct {
+	ID        uint      `json:"id"`
+	Key       string    `json:"key"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type" enums:"text,number,boolean,enum"`
+	Unit      *string   `json:"unit,omitempty"`
+	Options   []string  `json:"options,omitempty"`
+	ItemCount int       `json:"item_count"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Fields represents a collection of custom fields
+type Fields []Field
+
+// FieldCreateRequest represents the input for creating a custom field.
+// The key names the values of the field on items and in exports.
+type FieldCreateRequest struct {
+	Key     string   `json:"key" binding:"required" example:"temp_rating"`
+	Name    string   `json:"name" binding:"required" example:"Temperature rating"`
+	Type    string   `json:"type" binding:"required" enums:"text,number,boolean,enum"`
+	Unit    *string  `json:"unit" example:"°C"`
+	Options []string `json:"options"`
+}
+
+// FieldUpdateRequest represents the input for updating a custom field. The key
+// and type cannot change; item values no longer among the options are removed.
+type FieldUpdateRequest struct {
+	Name    string   `json:"name" binding:"required"`
+	Unit    *string  `json:"unit"`
+	Options []string `json:"options"`
+}
+
+// Values holds the custom field values of an item by field key: strings for
+// text and enum fields, numbers and booleans for the others
+type Values map[string]any
+
+// Scan implements sql.Scanner for a JSONB column
+func (v *Values) Scan(src any) error {
+	var data []byte
+	switch s := src.(type) {
+	case []byte:
+		data = s
+	case string:
+		data = []byte(s)
+	case nil:
+		*v = Values{}
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into custom field values", src)
+	}
+	values := Values{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	*v = values
+	return nil
+}
+
+// Value implements driver.Valuer for a JSONB column
+func (v Values) Value() (driver.Value, error) {
+	if v == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// ValueError reports the key of an invalid custom field value or filter
+type ValueError struct {
+	Key string
+	Err error
+}
+
+func (e *ValueError) Error() string { return e.Err.Error() + ": " + e.Key }
+
+func (e *ValueError) Unwrap() error { return e.Err }
@@ -55,3 +55,10 @@ func Migrate() error {
 func DB() *sql.DB {
 	return db
 }
+
+// Queryer is implemented by *sql.DB and *sql.Tx, so that a write can run on
+// its own or as part of a transaction
+type Queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
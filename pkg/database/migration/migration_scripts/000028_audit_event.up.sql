@@ -0,0 +1,30 @@
+-- Persistent security audit trail. Rows are append-only: the API inserts
+-- them and the retention cleanup job deletes them, nothing ever updates one.
+-- account_id deliberately has no foreign key: the trail must outlive the
+-- account it describes (failed logins for unknown usernames have none).
+CREATE TABLE audit_event (
+    id          BIGSERIAL PRIMARY KEY,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    event_type  TEXT NOT NULL,
+    account_id  INTEGER,
+    username    TEXT,
+    ip          TEXT NOT NULL,
+    user_agent  TEXT,
+    message     TEXT NOT NULL,
+    remember_me BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX idx_audit_event_created_at ON audit_event(created_at);
+CREATE INDEX idx_audit_event_account_id ON audit_event(account_id, created_at);
+CREATE INDEX idx_audit_event_event_type ON audit_event(event_type, created_at);
+CREATE INDEX idx_audit_event_ip ON audit_event(ip, created_at);
+
+CREATE FUNCTION audit_event_reject_update() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_event is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_event_append_only
+    BEFORE UPDATE ON audit_event
+    FOR EACH ROW EXECUTE FUNCTION audit_event_reject_update();
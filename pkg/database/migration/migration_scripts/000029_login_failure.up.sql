@@ -0,0 +1,16 @@
+-- Failed-login tracking for brute-force protection. One row per throttled
+-- subject: scope 'account' (subject = account id) or 'ip' (subject = client IP).
+-- failure_count counts failures inside the sliding failure window; an account
+-- row is locked while locked_until is in the future and can be unlocked early
+-- with unlock_code (emailed to the owner) or by an admin.
+CREATE TABLE login_failure (
+    scope           TEXT NOT NULL,
+    subject         TEXT NOT NULL,
+    failure_count   INTEGER NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMPTZ NOT NULL,
+    locked_until    TIMESTAMPTZ,
+    unlock_code     TEXT,
+    PRIMARY KEY (scope, subject)
+);
+
+CREATE INDEX idx_login_failure_last_failure_at ON login_failure(last_failure_at);
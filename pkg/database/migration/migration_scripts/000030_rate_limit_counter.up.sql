@@ -0,0 +1,14 @@
+-- Fixed-window request counters for the shared (RATE_LIMIT_BACKEND=postgres)
+-- rate limiter, so limits hold across replicas. One row per limiter bucket
+-- (endpoint group), key (client IP or user) and window; rows past expires_at
+-- are deleted by the periodic cleanup job.
+CREATE TABLE rate_limit_counter (
+    bucket       TEXT NOT NULL,
+    key          TEXT NOT NULL,
+    window_start TIMESTAMPTZ NOT NULL,
+    expires_at   TIMESTAMPTZ NOT NULL,
+    count        INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (bucket, key, window_start)
+);
+
+CREATE INDEX idx_rate_limit_counter_expires_at ON rate_limit_counter(expires_at);
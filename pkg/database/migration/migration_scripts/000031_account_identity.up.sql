@@ -0,0 +1,27 @@
+-- External identities (OpenID Connect) linked to an account. The subject is
+-- only unique within its issuer, so (issuer, subject) identifies the user at
+-- the provider; email is the address asserted at link time, for reference.
+CREATE TABLE account_identity (
+    id            SERIAL PRIMARY KEY,
+    account_id    INTEGER NOT NULL REFERENCES account(id) ON DELETE CASCADE,
+    issuer        TEXT NOT NULL,
+    subject       TEXT NOT NULL,
+    email         TEXT,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_login_at TIMESTAMPTZ,
+    UNIQUE (issuer, subject)
+);
+
+CREATE INDEX idx_account_identity_account_id ON account_identity(account_id);
+
+-- Pending OIDC logins: the state sent to the provider with the nonce and
+-- PKCE code verifier it must be completed with. Rows are consumed by the
+-- callback and expired ones deleted by the periodic cleanup job.
+CREATE TABLE oidc_login_state (
+    state         TEXT PRIMARY KEY,
+    nonce         TEXT NOT NULL,
+    code_verifier TEXT NOT NULL,
+    expires_at    TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_oidc_login_state_expires_at ON oidc_login_state(expires_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_account_deletion_scheduled_at;
+ALTER TABLE account DROP COLUMN IF EXISTS deletion_scheduled_at;
+ALTER TABLE account DROP COLUMN IF EXISTS deletion_requested_at;
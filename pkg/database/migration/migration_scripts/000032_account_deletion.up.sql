@@ -0,0 +1,9 @@
+-- Self-service account deletion. A deletion request makes the account
+-- inactive and sets deletion_scheduled_at to the end of the grace period;
+-- until then the owner can restore it, afterwards the periodic cleanup job
+-- deletes the account and, by cascade, all its data.
+ALTER TABLE account ADD COLUMN deletion_requested_at TIMESTAMPTZ;
+ALTER TABLE account ADD COLUMN deletion_scheduled_at TIMESTAMPTZ;
+
+CREATE INDEX idx_account_deletion_scheduled_at ON account(deletion_scheduled_at)
+    WHERE deletion_scheduled_at IS NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE account DROP COLUMN IF EXISTS pending_email_requested_at;
+ALTER TABLE account DROP COLUMN IF EXISTS pending_email;
@@ -0,0 +1,5 @@
+-- Email change re-confirmation. A new address is kept in pending_email, and
+-- the confirmation link sent to it (confirmation_code) swaps it into email.
+-- pending_email_requested_at bounds the validity of the link.
+ALTER TABLE account ADD COLUMN pending_email TEXT;
+ALTER TABLE account ADD COLUMN pending_email_requested_at TIMESTAMPTZ;
@@ -0,0 +1,21 @@
+-- Durable email outbox. Handlers insert a pending row instead of talking to
+-- SMTP; the worker sends due rows and, on failure, pushes next_attempt_at back
+-- exponentially until max attempts, when the row is dead-lettered (status
+-- 'dead') for an admin to inspect and requeue. Bodies are cleared once sent:
+-- some emails carry secrets (password reset).
+CREATE TABLE email_outbox (
+    id              BIGSERIAL PRIMARY KEY,
+    recipient       TEXT NOT NULL,
+    subject         TEXT NOT NULL,
+    text_body       TEXT NOT NULL,
+    html_body       TEXT NOT NULL,
+    status          TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'dead')),
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    last_error      TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at         TIMESTAMPTZ
+);
+
+CREATE INDEX idx_email_outbox_due ON email_outbox(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_email_outbox_status ON email_outbox(status, created_at);
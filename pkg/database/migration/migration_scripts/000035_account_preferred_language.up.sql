@@ -0,0 +1,3 @@
+-- Language of the emails sent to an account (en, fr). API responses follow
+-- the Accept-Language header of each request instead.
+ALTER TABLE account ADD COLUMN preferred_language VARCHAR(5) NOT NULL DEFAULT 'en';
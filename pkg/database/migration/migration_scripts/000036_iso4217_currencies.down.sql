@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS exchange_rate;
+
+CREATE TYPE "Currency" AS ENUM ('USD', 'GBP', 'EUR');
+ALTER TABLE inventory DROP CONSTRAINT IF EXISTS inventory_currency_format;
+ALTER TABLE inventory ALTER COLUMN currency DROP DEFAULT;
+ALTER TABLE inventory ALTER COLUMN currency TYPE "Currency"
+    USING (CASE WHEN currency IN ('USD', 'GBP', 'EUR') THEN currency ELSE 'EUR' END)::"Currency";
+ALTER TABLE inventory ALTER COLUMN currency SET DEFAULT E'EUR';
@@ -0,0 +1,15 @@
+-- Currencies are ISO 4217 codes validated by the API instead of the
+-- "Currency" enum, which only allowed USD, GBP and EUR.
+ALTER TABLE inventory ALTER COLUMN currency DROP DEFAULT;
+ALTER TABLE inventory ALTER COLUMN currency TYPE CHAR(3) USING currency::TEXT;
+ALTER TABLE inventory ALTER COLUMN currency SET DEFAULT 'EUR';
+ALTER TABLE inventory ADD CONSTRAINT inventory_currency_format CHECK (currency ~ '^[A-Z]{3}$');
+DROP TYPE "Currency";
+
+-- Exchange rates maintained by admins, against the euro: rate is the amount
+-- of currency worth 1 EUR. EUR itself is implicit.
+CREATE TABLE exchange_rate (
+    currency   CHAR(3)         PRIMARY KEY CHECK (currency ~ '^[A-Z]{3}$'),
+    rate       NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+    updated_at TIMESTAMPTZ     NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS inventory_user_external_key;
+ALTER TABLE inventory DROP COLUMN IF EXISTS external_key;
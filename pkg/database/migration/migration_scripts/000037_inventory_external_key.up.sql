@@ -0,0 +1,5 @@
+-- Key set by users to match their inventory items with the rows of an
+-- imported spreadsheet or JSON file. Unique per user.
+ALTER TABLE inventory ADD COLUMN external_key TEXT;
+CREATE UNIQUE INDEX inventory_user_external_key ON inventory (user_id, external_key)
+    WHERE external_key IS NOT NULL;
@@ -0,0 +1,18 @@
+-- Per-user categories: display order, color and icon of the free-text
+-- inventory.category values, which stay the source of truth for items.
+CREATE TABLE category (
+    id         SERIAL      PRIMARY KEY,
+    user_id    INTEGER     NOT NULL REFERENCES account(id) ON DELETE CASCADE,
+    name       TEXT        NOT NULL,
+    position   INTEGER     NOT NULL DEFAULT 0,
+    color      TEXT        CHECK (color ~ '^#[0-9a-fA-F]{6}$'),
+    icon       TEXT,
+    created_at TIMESTAMP   NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP   NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, name)
+);
+
+-- Backfill from the existing categories, alphabetically ordered
+INSERT INTO category (user_id, name, position)
+SELECT user_id, category, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY category) - 1
+FROM (SELECT DISTINCT user_id, category FROM inventory WHERE category IS NOT NULL) AS existing;
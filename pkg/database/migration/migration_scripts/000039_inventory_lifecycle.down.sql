@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS inventory_maintenance;
+ALTER TABLE inventory DROP COLUMN IF EXISTS maintenance_interval_days;
+ALTER TABLE inventory DROP COLUMN IF EXISTS retired;
+ALTER TABLE inventory DROP COLUMN IF EXISTS condition;
+ALTER TABLE inventory DROP COLUMN IF EXISTS purchase_date;
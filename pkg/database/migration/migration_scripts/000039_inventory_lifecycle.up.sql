@@ -0,0 +1,21 @@
+-- Item lifecycle: retired items stay in the packs that use them but are
+-- hidden when picking gear for a pack.
+ALTER TABLE inventory ADD COLUMN purchase_date DATE;
+ALTER TABLE inventory ADD COLUMN condition TEXT
+    CHECK (condition IN ('new', 'good', 'fair', 'poor', 'broken'));
+ALTER TABLE inventory ADD COLUMN retired BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE inventory ADD COLUMN maintenance_interval_days INTEGER
+    CHECK (maintenance_interval_days > 0);
+
+CREATE TABLE inventory_maintenance (
+    id           SERIAL    PRIMARY KEY,
+    item_id      INTEGER   NOT NULL REFERENCES inventory(id) ON DELETE CASCADE,
+    performed_on DATE      NOT NULL,
+    action       TEXT      NOT NULL,
+    notes        TEXT      NOT NULL DEFAULT '',
+    cost         INTEGER   NOT NULL DEFAULT 0 CHECK (cost >= 0),
+    currency     CHAR(3)   NOT NULL CHECK (currency ~ '^[A-Z]{3}$'),
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_inventory_maintenance_item ON inventory_maintenance(item_id, performed_on);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS pack_trip_item;
+DROP TABLE IF EXISTS pack_trip;
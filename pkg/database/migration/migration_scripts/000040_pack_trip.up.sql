@@ -0,0 +1,25 @@
+-- Trips logged on a pack. The pack content is copied to pack_trip_item when
+-- the trip is logged, so later pack edits and pack deletions keep the usage
+-- history of the items.
+CREATE TABLE pack_trip (
+    id          SERIAL    PRIMARY KEY,
+    user_id     INTEGER   NOT NULL REFERENCES account(id) ON DELETE CASCADE,
+    pack_id     INTEGER   REFERENCES pack(id) ON DELETE SET NULL,
+    start_date  DATE      NOT NULL,
+    end_date    DATE      NOT NULL CHECK (end_date >= start_date),
+    nights      INTEGER   NOT NULL CHECK (nights >= 0),
+    distance_km INTEGER   CHECK (distance_km >= 0),
+    notes       TEXT      NOT NULL DEFAULT '',
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_pack_trip_pack ON pack_trip(pack_id, start_date);
+
+CREATE TABLE pack_trip_item (
+    trip_id  INTEGER NOT NULL REFERENCES pack_trip(id) ON DELETE CASCADE,
+    item_id  INTEGER NOT NULL REFERENCES inventory(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL DEFAULT 1,
+    PRIMARY KEY (trip_id, item_id)
+);
+
+CREATE INDEX idx_pack_trip_item_item ON pack_trip_item(item_id);
@@ -0,0 +1,16 @@
+-- User-defined custom fields. Item values are stored by field key in
+-- inventory.custom_fields and checked against the definitions by the API.
+CREATE TABLE custom_field (
+    id         SERIAL    PRIMARY KEY,
+    user_id    INTEGER   NOT NULL REFERENCES account(id) ON DELETE CASCADE,
+    key        TEXT      NOT NULL CHECK (key ~ '^[a-z][a-z0-9_]{0,39}$'),
+    name       TEXT      NOT NULL,
+    field_type TEXT      NOT NULL CHECK (field_type IN ('text', 'number', 'boolean', 'enum')),
+    unit       TEXT,
+    options    TEXT[]    NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, key)
+);
+
+ALTER TABLE inventory ADD COLUMN custom_fields JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,2 @@
+ALTER TABLE inventory DROP COLUMN IF EXISTS product_id;
+DROP TABLE IF EXISTS product;
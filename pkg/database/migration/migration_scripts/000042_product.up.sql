@@ -0,0 +1,19 @@
+-- Shared gear catalog. Products come from an admin import or from items that
+-- several users have in their inventories; weight is the official weight.
+CREATE TABLE product (
+    id         SERIAL    PRIMARY KEY,
+    brand      TEXT      NOT NULL CHECK (brand <> ''),
+    model      TEXT      NOT NULL CHECK (model <> ''),
+    variant    TEXT      NOT NULL DEFAULT '',
+    weight     INTEGER   CHECK (weight >= 0),
+    url        TEXT      NOT NULL DEFAULT '',
+    source     TEXT      NOT NULL CHECK (source IN ('admin', 'community')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_product_identity ON product(LOWER(brand), LOWER(model), LOWER(variant));
+
+ALTER TABLE inventory ADD COLUMN product_id INTEGER REFERENCES product(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_inventory_product ON inventory(product_id);
@@ -0,0 +1 @@
+ALTER TABLE inventory DROP COLUMN IF EXISTS wishlist;
@@ -0,0 +1,3 @@
+-- Wishlist items are gear a user plans to buy: they can be put in packs to
+-- compare setups but are not listed in the owned inventory.
+ALTER TABLE inventory ADD COLUMN wishlist BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS pack_variant_change;
+DROP TABLE IF EXISTS pack_variant;
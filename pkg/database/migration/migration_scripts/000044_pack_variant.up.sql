@@ -0,0 +1,23 @@
+-- Named variants of a pack. A variant stores only its changes to the pack
+-- contents, applied when it is read, so it follows the edits of the pack.
+CREATE TABLE pack_variant (
+    id         SERIAL    PRIMARY KEY,
+    pack_id    INTEGER   NOT NULL REFERENCES pack(id) ON DELETE CASCADE,
+    name       TEXT      NOT NULL CHECK (name <> ''),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_pack_variant_name ON pack_variant(pack_id, LOWER(name));
+
+CREATE TABLE pack_variant_change (
+    variant_id INTEGER NOT NULL REFERENCES pack_variant(id) ON DELETE CASCADE,
+    item_id    INTEGER NOT NULL REFERENCES inventory(id) ON DELETE CASCADE,
+    action     TEXT    NOT NULL CHECK (action IN ('add', 'remove', 'update')),
+    quantity   INTEGER CHECK (quantity > 0),
+    worn       BOOLEAN,
+    consumable BOOLEAN,
+    PRIMARY KEY (variant_id, item_id)
+);
+
+CREATE INDEX idx_pack_variant_change_item ON pack_variant_change(item_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_pack_template;
+ALTER TABLE pack DROP COLUMN IF EXISTS template;
@@ -0,0 +1,5 @@
+-- Packs marked as templates are listed to every user as starting points.
+-- Official templates are marked by admins.
+ALTER TABLE pack ADD COLUMN template TEXT CHECK (template IN ('user', 'official'));
+
+CREATE INDEX idx_pack_template ON pack(template) WHERE template IS NOT NULL;
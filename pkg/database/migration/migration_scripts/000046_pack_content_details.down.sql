@@ -0,0 +1,4 @@
+ALTER TABLE pack_content
+    DROP COLUMN IF EXISTS optional,
+    DROP COLUMN IF EXISTS position,
+    DROP COLUMN IF EXISTS notes;
@@ -0,0 +1,6 @@
+-- Per pack item notes (such as "rent on site"), an explicit position within
+-- the category and an optional flag for "maybe" items left out of the totals.
+ALTER TABLE pack_content
+    ADD COLUMN notes TEXT NOT NULL DEFAULT '',
+    ADD COLUMN position INTEGER CHECK (position >= 0),
+    ADD COLUMN optional BOOLEAN NOT NULL DEFAULT FALSE;
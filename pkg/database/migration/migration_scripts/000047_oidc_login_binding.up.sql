@@ -0,0 +1,6 @@
+-- Bind each pending OIDC login to the browser that started it: the callback
+-- must present the login binding returned by the authorize call, whose
+-- SHA-256 is stored here, so a login CSRF cannot complete another flow.
+-- Logins started before this migration cannot be completed anymore.
+DELETE FROM oidc_login_state;
+ALTER TABLE oidc_login_state ADD COLUMN binding_hash TEXT NOT NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_email_outbox_dead;
+ALTER TABLE email_outbox DROP COLUMN IF EXISTS dead_at;
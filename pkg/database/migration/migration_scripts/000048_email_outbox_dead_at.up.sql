@@ -0,0 +1,6 @@
+-- Dead-lettered emails keep their bodies, which may carry reset links, only
+-- while an admin can still requeue them: dead_at records when delivery was
+-- given up, and the cleanup job deletes dead emails past their retention.
+ALTER TABLE email_outbox ADD COLUMN dead_at TIMESTAMPTZ;
+UPDATE email_outbox SET dead_at = NOW() WHERE status = 'dead';
+CREATE INDEX idx_email_outbox_dead ON email_outbox(dead_at) WHERE status = 'dead';
@@ -0,0 +1 @@
+ALTER TABLE inventory DROP COLUMN IF EXISTS weight_from_product;
@@ -0,0 +1,4 @@
+-- Items created from a catalog product without a weight take the weight of
+-- the product. Such a weight was not measured by the owner, so it is flagged
+-- and left out of the community median weight until the owner changes it.
+ALTER TABLE inventory ADD COLUMN weight_from_product BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,202 @@
+// Package dataexport builds the archive of all the data of an account, for
+// the user's right of access and data portability (GDPR).
+package dataexport
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/Angak0k/pimpmypack/pkg/accounts"
+	"github.com/Angak0k/pimpmypack/pkg/customfields"
+	"github.com/Angak0k/pimpmypack/pkg/images"
+	"github.com/Angak0k/pimpmypack/pkg/inventories"
+	"github.com/Angak0k/pimpmypack/pkg/packs"
+)
+
+// imageStores are the storage backends the images are read from
+var imageStores = struct {
+	packs     images.ImageStorage
+	profile   images.AccountImageStorage
+	banner    images.BannerImageStorage
+	inventory images.InventoryImageStorage
+}{
+	packs:     images.NewDBImageStorage(),
+	profile:   images.NewDBAccountImageStorage(),
+	banner:    images.NewDBBannerImageStorage(),
+	inventory: images.NewDBInventoryImageStorage(),
+}
+
+// PackContentsExport holds the contents of one pack in pack_contents.json
+type PackContentsExport struct {
+	PackID   uint                       `json:"pack_id"`
+	PackName string                     `json:"pack_name"`
+	Contents packs.PackContentWithItems `json:"contents"`
+}
+
+// archive writes the entries of the export ZIP
+type archive struct {
+	zw       *zip.Writer
+	modified time.Time
+}
+
+// writeExport writes the ZIP export of the account to w:
+//
+//	account.json, inventory.json, custom_fields.json, packs.json, pack_contents.json
+//	images/profile.jpg, images/banner.jpg
+//	images/packs/<pack id>.jpg, images/inventory/<item id>.jpg
+func writeExport(ctx context.Context, w io.Writer, userID uint) error {
+	account, err := accounts.FindAccountByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+	inventory, err := inventories.ReturnInventoriesByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get inventory: %w", err)
+	}
+	fields, err := customfields.ReturnFieldsByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get custom fields: %w", err)
+	}
+	userPacks, err := packs.FindPacksByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get packs: %w", err)
+	}
+
+	contents := make([]PackContentsExport, 0, len(*userPacks))
+	for _, pack := range *userPacks {
+		items, err := packs.ReturnPackContentsByPackID(ctx, pack.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get contents of pack %d: %w", pack.ID, err)
+		}
+		contents = append(contents, PackContentsExport{
+			PackID:   pack.ID,
+			PackName: pack.PackName,
+			Contents: nonNil(*items),
+		})
+	}
+
+	a := &archive{zw: zip.NewWriter(w), modified: time.Now()}
+
+	if err := a.writeJSON("account.json", account); err != nil {
+		return err
+	}
+	if err := a.writeJSON("inventory.json", nonNil(*inventory)); err != nil {
+		return err
+	}
+	if err := a.writeJSON("custom_fields.json", fields); err != nil {
+		return err
+	}
+	if err := a.writeJSON("packs.json", nonNil(*userPacks)); err != nil {
+		return err
+	}
+	if err := a.writeJSON("pack_contents.json", contents); err != nil {
+		return err
+	}
+
+	if err := writeImages(ctx, a, account, *inventory, *userPacks); err != nil {
+		return err
+	}
+
+	if err := a.zw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize export archive: %w", err)
+	}
+	return nil
+}
+
+// writeImages adds every image of the account; the has_image flags tell
+// which ones exist, so absent images cost no storage lookup
+func writeImages(
+	ctx context.Context, a *archive, account *accounts.Account, inventory inventories.Inventories, userPacks packs.Packs,
+) error {
+	if account.HasProfileImage {
+		if err := a.writeImage(ctx, "images/profile", imageStores.profile.Get, account.ID); err != nil {
+			return err
+		}
+	}
+	if account.HasBannerImage {
+		if err := a.writeImage(ctx, "images/banner", imageStores.banner.Get, account.ID); err != nil {
+			return err
+		}
+	}
+	for _, pack := range userPacks {
+		if !pack.HasImage {
+			continue
+		}
+		name := "images/packs/" + strconv.FormatUint(uint64(pack.ID), 10)
+		if err := a.writeImage(ctx, name, imageStores.packs.Get, pack.ID); err != nil {
+			return err
+		}
+	}
+	for _, item := range inventory {
+		if !item.HasImage {
+			continue
+		}
+		name := "images/inventory/" + strconv.FormatUint(uint64(item.ID), 10)
+		if err := a.writeImage(ctx, name, imageStores.inventory.Get, item.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *archive) writeJSON(name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+	return a.write(name, zip.Deflate, data)
+}
+
+// writeImage adds the image of ownerID under name plus its file extension.
+// An image deleted since the listing is skipped.
+func (a *archive) writeImage(
+	ctx context.Context, name string, get func(context.Context, uint) (*images.Image, error), ownerID uint,
+) error {
+	img, err := get(ctx, ownerID)
+	if errors.Is(err, images.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get image %s: %w", name, err)
+	}
+	// Images are already compressed
+	return a.write(name+imageExtension(img.Metadata.MimeType), zip.Store, img.Data)
+}
+
+func (a *archive) write(name string, method uint16, data []byte) error {
+	f, err := a.zw.CreateHeader(&zip.FileHeader{Name: name, Method: method, Modified: a.modified})
+	if err != nil {
+		return fmt.Errorf("failed to add %s to export archive: %w", name, err)
+	}
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s to export archive: %w", name, err)
+	}
+	return nil
+}
+
+// imageExtension maps the stored MIME type to a file extension; uploads are
+// converted to JPEG, so that is the fallback
+func imageExtension(mimeType string) string {
+	switch mimeType {
+	case "image/png":
+		return ".png"
+	case "image/webp":
+		return ".webp"
+	default:
+		return ".jpg"
+	}
+}
+
+// nonNil makes empty lists encode as [] rather than null
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
@@ -0,0 +1,184 @@
+package dataexport
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Angak0k/pimpmypack/pkg/config"
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/Angak0k/pimpmypack/pkg/images"
+	"github.com/Angak0k/pimpmypack/pkg/security"
+	"github.com/gin-gonic/gin"
+	"github.com/gruntwork-io/terratest/modules/random"
+)
+
+func TestMain(m *testing.M) {
+	// init env
+	err := config.EnvInit("../../.env")
+	if err != nil {
+		log.Fatalf("Error loading .env file or environment variable : %v", err)
+	}
+
+	// init DB
+	err = database.Initialization()
+	if err != nil {
+		log.Fatalf("Error connecting database : %v", err)
+	}
+
+	// init DB migration
+	err = database.Migrate()
+	if err != nil {
+		log.Fatalf("Error migrating database : %v", err)
+	}
+
+	os.Exit(m.Run())
+}
+
+// exportDataset is an account with one item and one pack, both with an image
+type exportDataset struct {
+	userID uint
+	itemID uint
+	packID uint
+}
+
+func loadingExportDataset(t *testing.T) exportDataset {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	var d exportDataset
+
+	err := database.DB().QueryRowContext(ctx,
+		`INSERT INTO account (username, email, firstname, lastname, role, status, created_at, updated_at)
+		VALUES ($1,$2,'Export','Er','standard','active',$3,$3) RETURNING id;`,
+		"export-"+random.UniqueID(), "export-"+random.UniqueID()+"@exemple.com", now).Scan(&d.userID)
+	if err != nil {
+		t.Fatalf("failed to insert account: %v", err)
+	}
+	// Inventory, packs and images cascade
+	t.Cleanup(func() {
+		_, _ = database.DB().ExecContext(ctx, "DELETE FROM account WHERE id = $1", d.userID)
+	})
+
+	err = database.DB().QueryRowContext(ctx,
+		`INSERT INTO inventory (user_id, item_name, category, description, weight, url, price, currency,
+			created_at, updated_at)
+		VALUES ($1,'Tent','Shelter','Two person tent',1200,'',300,'EUR',$2,$2) RETURNING id;`,
+		d.userID, now).Scan(&d.itemID)
+	if err != nil {
+		t.Fatalf("failed to insert inventory: %v", err)
+	}
+	err = database.DB().QueryRowContext(ctx,
+		`INSERT INTO pack (user_id, pack_name, pack_description, created_at, updated_at)
+		VALUES ($1,'Weekend','Two nights out',$2,$2) RETURNING id;`,
+		d.userID, now).Scan(&d.packID)
+	if err != nil {
+		t.Fatalf("failed to insert pack: %v", err)
+	}
+	_, err = database.DB().ExecContext(ctx,
+		`INSERT INTO pack_content (pack_id, item_id, quantity, worn, consumable, created_at, updated_at)
+		VALUES ($1,$2,1,false,false,$3,$3);`,
+		d.packID, d.itemID, now)
+	if err != nil {
+		t.Fatalf("failed to insert pack content: %v", err)
+	}
+
+	metadata := images.ImageMetadata{MimeType: "image/jpeg", FileSize: 3, Width: 1, Height: 1}
+	if err := images.NewDBInventoryImageStorage().Save(ctx, d.itemID, []byte("inv"), metadata); err != nil {
+		t.Fatalf("failed to save inventory image: %v", err)
+	}
+	if err := images.NewDBImageStorage().Save(ctx, d.packID, []byte("pck"), metadata); err != nil {
+		t.Fatalf("failed to save pack image: %v", err)
+	}
+
+	return d
+}
+
+func TestGetMyAccountExport(t *testing.T) {
+	d := loadingExportDataset(t)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/v1/myaccount/export", GetMyAccountExport)
+
+	token, err := security.GenerateToken(d.userID)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, "/v1/myaccount/export", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d but got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/zip" {
+		t.Errorf("Expected Content-Type application/zip but got %s", ct)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	if err != nil {
+		t.Fatalf("Failed to read export archive: %v", err)
+	}
+	entries := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("Failed to open %s: %v", f.Name, err)
+		}
+		entries[f.Name], err = io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", f.Name, err)
+		}
+	}
+
+	itemImage := "images/inventory/" + strconv.FormatUint(uint64(d.itemID), 10) + ".jpg"
+	packImage := "images/packs/" + strconv.FormatUint(uint64(d.packID), 10) + ".jpg"
+	for _, name := range []string{
+		"account.json", "inventory.json", "custom_fields.json", "packs.json", "pack_contents.json",
+		itemImage, packImage,
+	} {
+		if _, ok := entries[name]; !ok {
+			t.Errorf("Expected %s in the export archive", name)
+		}
+	}
+	if _, ok := entries["images/profile.jpg"]; ok {
+		t.Errorf("Expected no profile image for an account without one")
+	}
+	if string(entries[itemImage]) != "inv" {
+		t.Errorf("Expected the inventory image data but got %q", entries[itemImage])
+	}
+
+	var contents []PackContentsExport
+	if err := json.Unmarshal(entries["pack_contents.json"], &contents); err != nil {
+		t.Fatalf("Failed to unmarshal pack_contents.json: %v", err)
+	}
+	if len(contents) != 1 || contents[0].PackID != d.packID || len(contents[0].Contents) != 1 {
+		t.Errorf("Expected one pack with one item in pack_contents.json but got %+v", contents)
+	}
+}
+
+func TestImageExtension(t *testing.T) {
+	tests := map[string]string{
+		"image/jpeg": ".jpg",
+		"image/png":  ".png",
+		"image/webp": ".webp",
+		"":           ".jpg",
+	}
+	for mimeType, want := range tests {
+		if got := imageExtension(mimeType); got != want {
+			t.Errorf("imageExtension(%q) = %q, want %q", mimeType, got, want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package dataexport
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Angak0k/pimpmypack/pkg/accounts"
+	"github.com/Angak0k/pimpmypack/pkg/helper"
+	"github.com/Angak0k/pimpmypack/pkg/security"
+	"github.com/gin-gonic/gin"
+)
+
+// Export all my data
+// @Summary Export my data
+// @Description Download a ZIP archive of all the data of the currently logged-in user:
+// @Description account.json, inventory.json, custom_fields.json, packs.json, pack_contents.json and every image
+// @Description (images/profile, images/banner, images/packs/<pack id>, images/inventory/<item id>)
+// @Security Bearer
+// @Tags Accounts
+// @Produce  application/zip
+// @Success 200 {file} file "ZIP archive"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/myaccount/export [get]
+func GetMyAccountExport(c *gin.Context) {
+	userID, err := security.ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, "get my account export: extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return
+	}
+
+	// Built in memory so that a failure midway still gets a proper error status
+	var buf bytes.Buffer
+	if err := writeExport(c.Request.Context(), &buf, userID); err != nil {
+		if errors.Is(err, accounts.ErrNoAccountFound) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+			return
+		}
+		helper.LogAndSanitize(err, "get my account export: build export failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	filename := "pimpmypack-export-" + time.Now().UTC().Format("20060102") + ".zip"
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
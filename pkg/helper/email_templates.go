@@ -4,15 +4,17 @@ import (
 	"fmt"
 	"html"
 	"strings"
+
+	"github.com/Angak0k/pimpmypack/pkg/i18n"
 )
 
-// BuildEmailHTML wraps body content in a branded HTML email layout.
+// BuildEmailHTML wraps body content in a branded HTML email layout in lang.
 // title and preheaderText are HTML-escaped; bodyContent is trusted pre-built HTML.
-func BuildEmailHTML(title, preheaderText, bodyContent string) string {
+func BuildEmailHTML(lang, title, preheaderText, bodyContent string) string {
 	safeTitle := html.EscapeString(title)
 	safePreheader := html.EscapeString(preheaderText)
 	return fmt.Sprintf(`<!DOCTYPE html>
-<html lang="en" xmlns="http://www.w3.org/1999/xhtml">
+<html lang="%s" xmlns="http://www.w3.org/1999/xhtml">
 <head>
 <meta charset="utf-8">
 <meta name="viewport" content="width=device-width, initial-scale=1.0">
@@ -44,40 +46,78 @@ font-family:'Segoe UI',Roboto,'Helvetica Neue',Arial,sans-serif;">
 <!-- Footer -->
 <tr><td style="padding:24px 32px;text-align:center;">
 <p style="margin:0;font-size:13px;color:#78716c;line-height:1.5;">
-&copy; PimpMyPack &mdash; Optimize your pack, enjoy the trail.
+&copy; PimpMyPack &mdash; %s
 </p>
 </td></tr>
 </table>
 </td></tr>
 </table>
 </body>
-</html>`, safeTitle, safePreheader, bodyContent)
+</html>`, html.EscapeString(i18n.Normalize(lang)), safeTitle, safePreheader, bodyContent,
+		html.EscapeString(i18n.T(lang, "email.footer")))
 }
 
-// BuildConfirmationEmailHTML returns the branded HTML body for a confirmation email.
-func BuildConfirmationEmailHTML(username, confirmURL string) string {
-	safeName := html.EscapeString(username)
-	safeURL := html.EscapeString(confirmURL)
-	body := fmt.Sprintf(`<h1 style="margin:0 0 16px;font-size:22px;color:#292524;">Welcome, %s!</h1>
-<p style="margin:0 0 24px;font-size:16px;color:#292524;line-height:1.6;">
-Thanks for signing up for PimpMyPack. Please confirm your email address to activate your account.
-</p>
-<table role="presentation" cellpadding="0" cellspacing="0" border="0" style="margin:0 auto 24px;">
+// The helpers below build the recurring blocks of the email bodies. Their
+// text arguments are plain text, HTML-escaped here.
+
+func emailHeading(text string) string {
+	return fmt.Sprintf(`<h1 style="margin:0 0 16px;font-size:22px;color:#292524;">%s</h1>`, html.EscapeString(text))
+}
+
+// emailParagraph renders lines as one paragraph; margin is its CSS margin
+func emailParagraph(margin string, lines ...string) string {
+	escaped := make([]string, len(lines))
+	for i, line := range lines {
+		escaped[i] = html.EscapeString(line)
+	}
+	return fmt.Sprintf(`<p style="margin:%s;font-size:16px;color:#292524;line-height:1.6;">
+%s
+</p>`, margin, strings.Join(escaped, "\n"))
+}
+
+func emailNote(text string) string {
+	return fmt.Sprintf(`<p style="margin:0;font-size:13px;color:#78716c;line-height:1.5;">
+%s
+</p>`, html.EscapeString(text))
+}
+
+func emailButton(label, url string) string {
+	return fmt.Sprintf(`<table role="presentation" cellpadding="0" cellspacing="0" border="0" style="margin:0 auto 24px;">
 <tr><td style="border-radius:8px;background-color:#16a34a;text-align:center;">
 <a href="%s" target="_blank"
 style="display:inline-block;padding:14px 32px;font-size:16px;
 font-weight:600;color:#ffffff;text-decoration:none;
-border-radius:8px;">Confirm my email</a>
+border-radius:8px;">%s</a>
 </td></tr>
-</table>
-<p style="margin:0;font-size:13px;color:#78716c;line-height:1.5;word-break:break-all;">
-If the button doesn't work, copy and paste this link into your browser:<br>
+</table>`, html.EscapeString(url), html.EscapeString(label))
+}
+
+// emailLinkFallback repeats a button link for clients that do not render it
+func emailLinkFallback(lang, margin, url string) string {
+	safeURL := html.EscapeString(url)
+	return fmt.Sprintf(`<p style="margin:%s;font-size:13px;color:#78716c;line-height:1.5;word-break:break-all;">
+%s<br>
 <a href="%s" style="color:#16a34a;">%s</a>
-</p>`, safeName, safeURL, safeURL, safeURL)
+</p>`, margin, html.EscapeString(i18n.T(lang, "email.link_fallback")), safeURL, safeURL)
+}
+
+// textFooter is the signature of the plain-text emails
+func textFooter(lang string) string {
+	return "--\nPimpMyPack - " + i18n.T(lang, "email.footer") + "\n"
+}
+
+// BuildConfirmationEmailHTML returns the branded HTML body for a confirmation email.
+func BuildConfirmationEmailHTML(lang, username, confirmURL string) string {
+	body := strings.Join([]string{
+		emailHeading(i18n.T(lang, "email.confirmation.heading", username)),
+		emailParagraph("0 0 24px", i18n.T(lang, "email.confirmation.body")),
+		emailButton(i18n.T(lang, "email.confirmation.button"), confirmURL),
+		emailLinkFallback(lang, "0", confirmURL),
+	}, "\n")
 
-	return BuildEmailHTML(
-		"Confirm your email — PimpMyPack",
-		"Please confirm your email address to activate your PimpMyPack account.",
+	return BuildEmailHTML(lang,
+		i18n.T(lang, "email.confirmation.title"),
+		i18n.T(lang, "email.confirmation.preheader"),
 		body,
 	)
 }
@@ -90,59 +130,167 @@ func sanitizeTextContent(v string) string {
 }
 
 // BuildConfirmationEmailText returns the plain-text body for a confirmation email.
-func BuildConfirmationEmailText(username, confirmURL string) string {
-	return fmt.Sprintf(`Welcome, %s!
-
-Thanks for signing up for PimpMyPack. Please confirm your email address to activate your account.
-
-Confirm your email:
-%s
-
---
-PimpMyPack - Optimize your pack, enjoy the trail.
-`, sanitizeTextContent(username), confirmURL)
+func BuildConfirmationEmailText(lang, username, confirmURL string) string {
+	return fmt.Sprintf("%s\n\n%s\n\n%s\n%s\n\n%s",
+		i18n.T(lang, "email.confirmation.heading", sanitizeTextContent(username)),
+		i18n.T(lang, "email.confirmation.body"),
+		i18n.T(lang, "email.confirmation.text_link"), confirmURL,
+		textFooter(lang))
 }
 
 // BuildPasswordResetEmailHTML returns the branded HTML body for a password reset email.
-func BuildPasswordResetEmailHTML(newPassword string) string {
+func BuildPasswordResetEmailHTML(lang, newPassword string) string {
 	safePassword := html.EscapeString(newPassword)
-	body := fmt.Sprintf(`<h1 style="margin:0 0 16px;font-size:22px;color:#292524;">Password Reset</h1>
-<p style="margin:0 0 16px;font-size:16px;color:#292524;line-height:1.6;">
-Your password has been reset. Here is your new temporary password:
-</p>
-<table role="presentation" cellpadding="0" cellspacing="0" border="0" width="100%%" style="margin:0 0 24px;">
+	passwordBox := fmt.Sprintf(
+		`<table role="presentation" cellpadding="0" cellspacing="0" border="0" width="100%%" style="margin:0 0 24px;">
 <tr><td style="background-color:#fafaf9;border:1px solid #e7e5e4;
 border-radius:8px;padding:16px 24px;text-align:center;">
 <code style="font-size:20px;font-family:'Courier New',Courier,monospace;color:#292524;letter-spacing:1px;">%s</code>
 </td></tr>
-</table>
-<p style="margin:0 0 8px;font-size:16px;color:#292524;line-height:1.6;">
-We recommend changing your password after logging in.
-</p>
-<p style="margin:0;font-size:13px;color:#78716c;line-height:1.5;">
-If you did not request this password reset, please contact us immediately.
-</p>`, safePassword)
+</table>`, safePassword)
+	body := strings.Join([]string{
+		emailHeading(i18n.T(lang, "email.password_reset.heading")),
+		emailParagraph("0 0 16px", i18n.T(lang, "email.password_reset.body")),
+		passwordBox,
+		emailParagraph("0 0 8px", i18n.T(lang, "email.password_reset.advice")),
+		emailNote(i18n.T(lang, "email.password_reset.warning")),
+	}, "\n")
 
-	return BuildEmailHTML(
-		"Password Reset — PimpMyPack",
-		"Your PimpMyPack password has been reset.",
+	return BuildEmailHTML(lang,
+		i18n.T(lang, "email.password_reset.title"),
+		i18n.T(lang, "email.password_reset.preheader"),
 		body,
 	)
 }
 
 // BuildPasswordResetEmailText returns the plain-text body for a password reset email.
-func BuildPasswordResetEmailText(newPassword string) string {
-	return fmt.Sprintf(`Password Reset
+func BuildPasswordResetEmailText(lang, newPassword string) string {
+	return fmt.Sprintf("%s\n\n%s\n\n    %s\n\n%s\n\n%s\n\n%s",
+		i18n.T(lang, "email.password_reset.heading"),
+		i18n.T(lang, "email.password_reset.body"),
+		newPassword,
+		i18n.T(lang, "email.password_reset.advice"),
+		i18n.T(lang, "email.password_reset.warning"),
+		textFooter(lang))
+}
 
-Your password has been reset. Here is your new temporary password:
+// BuildAccountLockedEmailHTML returns the branded HTML body for the notice sent
+// when an account is locked after repeated failed logins.
+func BuildAccountLockedEmailHTML(lang, username, lockedUntil, unlockURL string) string {
+	body := strings.Join([]string{
+		emailHeading(i18n.T(lang, "email.account_locked.heading")),
+		emailParagraph("0 0 16px",
+			i18n.T(lang, "email.account_locked.body", username),
+			i18n.T(lang, "email.account_locked.until", lockedUntil)),
+		emailParagraph("0 0 24px", i18n.T(lang, "email.account_locked.prompt")),
+		emailButton(i18n.T(lang, "email.account_locked.button"), unlockURL),
+		emailLinkFallback(lang, "0 0 8px", unlockURL),
+		emailNote(i18n.T(lang, "email.account_locked.warning")),
+	}, "\n")
 
-    %s
+	return BuildEmailHTML(lang,
+		i18n.T(lang, "email.account_locked.title"),
+		i18n.T(lang, "email.account_locked.preheader"),
+		body,
+	)
+}
 
-We recommend changing your password after logging in.
+// BuildAccountLockedEmailText returns the plain-text body for the account locked notice.
+func BuildAccountLockedEmailText(lang, username, lockedUntil, unlockURL string) string {
+	return fmt.Sprintf("%s\n\n%s\n%s\n\n%s\n%s\n\n%s\n\n%s",
+		i18n.T(lang, "email.account_locked.heading"),
+		i18n.T(lang, "email.account_locked.body", sanitizeTextContent(username)),
+		i18n.T(lang, "email.account_locked.until", lockedUntil),
+		i18n.T(lang, "email.account_locked.prompt"), unlockURL,
+		i18n.T(lang, "email.account_locked.warning"),
+		textFooter(lang))
+}
 
-If you did not request this password reset, please contact us immediately.
+// BuildAccountDeletionEmailHTML returns the branded HTML body for the notice sent
+// when a user requests the deletion of their account.
+func BuildAccountDeletionEmailHTML(lang, username, deletionDate, restoreURL string) string {
+	body := strings.Join([]string{
+		emailHeading(i18n.T(lang, "email.account_deletion.heading")),
+		emailParagraph("0 0 16px",
+			i18n.T(lang, "email.account_deletion.body", username),
+			i18n.T(lang, "email.account_deletion.date", deletionDate)),
+		emailParagraph("0 0 24px", i18n.T(lang, "email.account_deletion.prompt")),
+		emailButton(i18n.T(lang, "email.account_deletion.button"), restoreURL),
+		emailLinkFallback(lang, "0 0 8px", restoreURL),
+		emailNote(i18n.T(lang, "email.account_deletion.warning")),
+	}, "\n")
+
+	return BuildEmailHTML(lang,
+		i18n.T(lang, "email.account_deletion.title"),
+		i18n.T(lang, "email.account_deletion.preheader"),
+		body,
+	)
+}
+
+// BuildAccountDeletionEmailText returns the plain-text body for the account deletion notice.
+func BuildAccountDeletionEmailText(lang, username, deletionDate, restoreURL string) string {
+	return fmt.Sprintf("%s\n\n%s\n%s\n\n%s\n%s\n\n%s\n\n%s",
+		i18n.T(lang, "email.account_deletion.heading"),
+		i18n.T(lang, "email.account_deletion.body", sanitizeTextContent(username)),
+		i18n.T(lang, "email.account_deletion.date", deletionDate),
+		i18n.T(lang, "email.account_deletion.prompt"), restoreURL,
+		i18n.T(lang, "email.account_deletion.warning"),
+		textFooter(lang))
+}
+
+// BuildEmailChangeConfirmationEmailHTML returns the branded HTML body for the
+// confirmation link sent to the new address of an email change.
+func BuildEmailChangeConfirmationEmailHTML(lang, username, confirmURL string) string {
+	body := strings.Join([]string{
+		emailHeading(i18n.T(lang, "email.email_change.heading")),
+		emailParagraph("0 0 24px",
+			i18n.T(lang, "email.email_change.body", username),
+			i18n.T(lang, "email.email_change.validity")),
+		emailButton(i18n.T(lang, "email.email_change.button"), confirmURL),
+		emailLinkFallback(lang, "0", confirmURL),
+	}, "\n")
+
+	return BuildEmailHTML(lang,
+		i18n.T(lang, "email.email_change.title"),
+		i18n.T(lang, "email.email_change.preheader"),
+		body,
+	)
+}
+
+// BuildEmailChangeConfirmationEmailText returns the plain-text body for the email change confirmation.
+func BuildEmailChangeConfirmationEmailText(lang, username, confirmURL string) string {
+	return fmt.Sprintf("%s\n\n%s\n%s\n\n%s\n%s\n\n%s",
+		i18n.T(lang, "email.email_change.heading"),
+		i18n.T(lang, "email.email_change.body", sanitizeTextContent(username)),
+		i18n.T(lang, "email.email_change.validity"),
+		i18n.T(lang, "email.email_change.text_link"), confirmURL,
+		textFooter(lang))
+}
+
+// BuildEmailChangeNoticeEmailHTML returns the branded HTML body for the notice
+// sent to the current address when an email change is requested.
+func BuildEmailChangeNoticeEmailHTML(lang, username, newEmail string) string {
+	body := strings.Join([]string{
+		emailHeading(i18n.T(lang, "email.email_change_notice.heading")),
+		emailParagraph("0 0 16px",
+			i18n.T(lang, "email.email_change_notice.body", username, newEmail),
+			i18n.T(lang, "email.email_change_notice.effect")),
+		emailNote(i18n.T(lang, "email.email_change_notice.warning")),
+	}, "\n")
+
+	return BuildEmailHTML(lang,
+		i18n.T(lang, "email.email_change_notice.title"),
+		i18n.T(lang, "email.email_change_notice.preheader"),
+		body,
+	)
+}
 
---
-PimpMyPack - Optimize your pack, enjoy the trail.
-`, newPassword)
+// BuildEmailChangeNoticeEmailText returns the plain-text body for the email change notice.
+func BuildEmailChangeNoticeEmailText(lang, username, newEmail string) string {
+	return fmt.Sprintf("%s\n\n%s\n%s\n\n%s\n\n%s",
+		i18n.T(lang, "email.email_change_notice.heading"),
+		i18n.T(lang, "email.email_change_notice.body", sanitizeTextContent(username), sanitizeTextContent(newEmail)),
+		i18n.T(lang, "email.email_change_notice.effect"),
+		i18n.T(lang, "email.email_change_notice.warning"),
+		textFooter(lang))
 }
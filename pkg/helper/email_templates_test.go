@@ -6,7 +6,7 @@ import (
 )
 
 func TestBuildConfirmationEmailHTML(t *testing.T) {
-	html := BuildConfirmationEmailHTML("testuser", "https://example.com/confirm?id=1&code=abc")
+	html := BuildConfirmationEmailHTML("en", "testuser", "https://example.com/confirm?id=1&code=abc")
 
 	checks := []struct {
 		name   string
@@ -30,7 +30,7 @@ func TestBuildConfirmationEmailHTML(t *testing.T) {
 }
 
 func TestBuildConfirmationEmailText(t *testing.T) {
-	text := BuildConfirmationEmailText("testuser", "https://example.com/confirm?id=1&code=abc")
+	text := BuildConfirmationEmailText("en", "testuser", "https://example.com/confirm?id=1&code=abc")
 
 	checks := []struct {
 		name   string
@@ -51,7 +51,7 @@ func TestBuildConfirmationEmailText(t *testing.T) {
 }
 
 func TestBuildPasswordResetEmailHTML(t *testing.T) {
-	html := BuildPasswordResetEmailHTML("s3cretP@ss")
+	html := BuildPasswordResetEmailHTML("en", "s3cretP@ss")
 
 	checks := []struct {
 		name   string
@@ -74,7 +74,7 @@ func TestBuildPasswordResetEmailHTML(t *testing.T) {
 
 func TestBuildConfirmationEmailText_UsernameInjection(t *testing.T) {
 	malicious := "alice\r\n\r\nInjected body content"
-	text := BuildConfirmationEmailText(malicious, "https://example.com/confirm")
+	text := BuildConfirmationEmailText("en", malicious, "https://example.com/confirm")
 
 	// CRLF must be stripped so the username appears as a single token
 	if strings.Contains(text, "alice\r\n") || strings.Contains(text, "alice\n") {
@@ -89,7 +89,7 @@ func TestBuildConfirmationEmailText_UsernameInjection(t *testing.T) {
 
 func TestBuildConfirmationEmailHTML_UsernameInjection(t *testing.T) {
 	malicious := "<script>alert('xss')</script>"
-	html := BuildConfirmationEmailHTML(malicious, "https://example.com/confirm")
+	html := BuildConfirmationEmailHTML("en", malicious, "https://example.com/confirm")
 
 	if strings.Contains(html, "<script>") {
 		t.Error("HTML body must escape script tags in username")
@@ -97,7 +97,7 @@ func TestBuildConfirmationEmailHTML_UsernameInjection(t *testing.T) {
 }
 
 func TestBuildPasswordResetEmailText(t *testing.T) {
-	text := BuildPasswordResetEmailText("s3cretP@ss")
+	text := BuildPasswordResetEmailText("en", "s3cretP@ss")
 
 	checks := []struct {
 		name   string
@@ -116,3 +116,139 @@ func TestBuildPasswordResetEmailText(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildAccountLockedEmailHTML(t *testing.T) {
+	html := BuildAccountLockedEmailHTML("en", "<b>bob</b>", "2026-01-02 15:04 UTC",
+		"https://example.com/unlockaccount.html?id=1&code=abc")
+
+	checks := []struct {
+		name   string
+		substr string
+	}{
+		{"escaped username", "&lt;b&gt;bob&lt;/b&gt;"},
+		{"locked until", "2026-01-02 15:04 UTC"},
+		{"unlock URL href", `href="https://example.com/unlockaccount.html?id=1&amp;code=abc"`},
+		{"CTA button text", "Unlock my account"},
+		{"branding", "PimpMyPack"},
+	}
+
+	for _, c := range checks {
+		t.Run(c.name, func(t *testing.T) {
+			if !strings.Contains(html, c.substr) {
+				t.Errorf("expected HTML to contain %q", c.substr)
+			}
+		})
+	}
+}
+
+func TestBuildAccountLockedEmailText(t *testing.T) {
+	text := BuildAccountLockedEmailText("en", "bob\r\nInjected: yes", "2026-01-02 15:04 UTC",
+		"https://example.com/unlockaccount.html?id=1&code=abc")
+
+	if strings.Contains(text, "bob\r\n") || strings.Contains(text, "bob\n") {
+		t.Error("text body must strip newlines from username")
+	}
+	for _, substr := range []string{"2026-01-02 15:04 UTC", "https://example.com/unlockaccount.html?id=1&code=abc"} {
+		if !strings.Contains(text, substr) {
+			t.Errorf("expected text to contain %q", substr)
+		}
+	}
+}
+
+func TestBuildAccountDeletionEmailHTML(t *testing.T) {
+	html := BuildAccountDeletionEmailHTML("en", "<b>bob</b>", "2026-02-01", "https://example.com/restoreaccount.html")
+
+	checks := []struct {
+		name   string
+		substr string
+	}{
+		{"escaped username", "&lt;b&gt;bob&lt;/b&gt;"},
+		{"deletion date", "2026-02-01"},
+		{"restore URL href", `href="https://example.com/restoreaccount.html"`},
+		{"CTA button text", "Restore my account"},
+		{"branding", "PimpMyPack"},
+	}
+
+	for _, c := range checks {
+		t.Run(c.name, func(t *testing.T) {
+			if !strings.Contains(html, c.substr) {
+				t.Errorf("expected HTML to contain %q", c.substr)
+			}
+		})
+	}
+}
+
+func TestBuildAccountDeletionEmailText(t *testing.T) {
+	text := BuildAccountDeletionEmailText("en", "bob\r\nInjected: yes", "2026-02-01",
+		"https://example.com/restoreaccount.html")
+
+	if strings.Contains(text, "bob\r\n") || strings.Contains(text, "bob\n") {
+		t.Error("text body must strip newlines from username")
+	}
+	for _, substr := range []string{"2026-02-01", "https://example.com/restoreaccount.html"} {
+		if !strings.Contains(text, substr) {
+			t.Errorf("expected text to contain %q", substr)
+		}
+	}
+}
+
+func TestBuildEmailChangeConfirmationEmail(t *testing.T) {
+	confirmURL := "https://example.com/confirmemail.html?id=1&code=abc"
+	html := BuildEmailChangeConfirmationEmailHTML("en", "<b>bob</b>", confirmURL)
+	for _, substr := range []string{
+		"&lt;b&gt;bob&lt;/b&gt;",
+		`href="https://example.com/confirmemail.html?id=1&amp;code=abc"`,
+		"Confirm my new email",
+	} {
+		if !strings.Contains(html, substr) {
+			t.Errorf("expected HTML to contain %q", substr)
+		}
+	}
+
+	text := BuildEmailChangeConfirmationEmailText("en", "bob\r\nInjected: yes", confirmURL)
+	if strings.Contains(text, "bob\r\n") || strings.Contains(text, "bob\n") {
+		t.Error("text body must strip newlines from username")
+	}
+	if !strings.Contains(text, confirmURL) {
+		t.Errorf("expected text to contain %q", confirmURL)
+	}
+}
+
+func TestBuildEmailChangeNoticeEmail(t *testing.T) {
+	html := BuildEmailChangeNoticeEmailHTML("en", "bob", "<new>@example.com")
+	if !strings.Contains(html, "&lt;new&gt;@example.com") {
+		t.Error("expected HTML to contain the escaped new address")
+	}
+
+	text := BuildEmailChangeNoticeEmailText("en", "bob", "new@example.com\r\nInjected: yes")
+	if strings.Contains(text, "example.com\r\n") || strings.Contains(text, "example.com\n") {
+		t.Error("text body must strip newlines from the new address")
+	}
+}
+
+func TestBuildEmails_French(t *testing.T) {
+	html := BuildConfirmationEmailHTML("fr", "alice", "https://example.com/confirm")
+	for _, substr := range []string{
+		`<html lang="fr"`, "Bienvenue, alice !", "Confirmer mon email", "Optimisez votre sac",
+	} {
+		if !strings.Contains(html, substr) {
+			t.Errorf("expected French HTML to contain %q", substr)
+		}
+	}
+
+	text := BuildPasswordResetEmailText("fr", "s3cretP@ss")
+	for _, substr := range []string{"Réinitialisation du mot de passe", "s3cretP@ss", "Optimisez votre sac"} {
+		if !strings.Contains(text, substr) {
+			t.Errorf("expected French text to contain %q", substr)
+		}
+	}
+}
+
+func TestBuildEmails_UnsupportedLanguage(t *testing.T) {
+	html := BuildAccountLockedEmailHTML("de", "bob", "2026-01-02 15:04 UTC", "https://example.com/unlock")
+	for _, substr := range []string{`<html lang="en"`, "Unlock my account"} {
+		if !strings.Contains(html, substr) {
+			t.Errorf("expected English fallback HTML to contain %q", substr)
+		}
+	}
+}
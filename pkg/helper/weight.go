@@ -5,8 +5,9 @@ import "fmt"
 // Weight conversion constants
 const (
 	// Grams to other units
-	GramsPerOunce = 28.3495
-	GramsPerPound = 453.592
+	GramsPerOunce    = 28.3495
+	GramsPerPound    = 453.592
+	GramsPerKilogram = 1000
 )
 
 // ConvertToGrams converts a weight from any unit to grams
@@ -16,6 +17,8 @@ func ConvertToGrams(weight float64, fromUnit string) float64 {
 		return weight * GramsPerOunce
 	case "lb":
 		return weight * GramsPerPound
+	case "kg":
+		return weight * GramsPerKilogram
 	case "g":
 		return weight
 	default:
@@ -30,6 +33,8 @@ func ConvertFromGrams(weight float64, toUnit string) float64 {
 		return weight / GramsPerOunce
 	case "lb":
 		return weight / GramsPerPound
+	case "kg":
+		return weight / GramsPerKilogram
 	case "g":
 		return weight
 	default:
@@ -0,0 +1,77 @@
+package i18n
+
+// catalogEN is the reference catalog. API error messages are not listed:
+// their key is the English message itself.
+//
+//nolint:gosec // password_reset keys are message keys, not credentials
+var catalogEN = map[string]string{
+	// Shared email layout
+	"email.footer":        "Optimize your pack, enjoy the trail.",
+	"email.link_fallback": "If the button doesn't work, copy and paste this link into your browser:",
+
+	// Confirmation of a new account
+	"email.confirmation.subject":   "PimpMyPack - Confirm your email address",
+	"email.confirmation.title":     "Confirm your email — PimpMyPack",
+	"email.confirmation.preheader": "Please confirm your email address to activate your PimpMyPack account.",
+	"email.confirmation.heading":   "Welcome, %s!",
+	"email.confirmation.body": "Thanks for signing up for PimpMyPack. " +
+		"Please confirm your email address to activate your account.",
+	"email.confirmation.button":    "Confirm my email",
+	"email.confirmation.text_link": "Confirm your email:",
+
+	// Password reset
+	"email.password_reset.subject":   "PimpMyPack - Your password has been reset",
+	"email.password_reset.title":     "Password Reset — PimpMyPack",
+	"email.password_reset.preheader": "Your PimpMyPack password has been reset.",
+	"email.password_reset.heading":   "Password Reset",
+	"email.password_reset.body":      "Your password has been reset. Here is your new temporary password:",
+	"email.password_reset.advice":    "We recommend changing your password after logging in.",
+	"email.password_reset.warning":   "If you did not request this password reset, please contact us immediately.",
+
+	// Account locked after failed logins
+	"email.account_locked.subject":   "PimpMyPack - Your account has been locked",
+	"email.account_locked.title":     "Account locked — PimpMyPack",
+	"email.account_locked.preheader": "Your PimpMyPack account was locked after several failed login attempts.",
+	"email.account_locked.heading":   "Account Locked",
+	"email.account_locked.body": "Hi %s, we temporarily locked your PimpMyPack account " +
+		"after several failed login attempts.",
+	"email.account_locked.until":  "Logins are blocked until %s.",
+	"email.account_locked.prompt": "If these attempts were yours, you can unlock your account right away:",
+	"email.account_locked.button": "Unlock my account",
+	"email.account_locked.warning": "If you did not try to log in, someone may be guessing your password. " +
+		"We recommend changing it.",
+
+	// Account deletion scheduled
+	"email.account_deletion.subject":   "PimpMyPack - Your account will be deleted",
+	"email.account_deletion.title":     "Account deletion scheduled — PimpMyPack",
+	"email.account_deletion.preheader": "Your PimpMyPack account will be permanently deleted.",
+	"email.account_deletion.heading":   "Account Deletion Scheduled",
+	"email.account_deletion.body":      "Hi %s, your PimpMyPack account has been deactivated at your request.",
+	"email.account_deletion.date":      "It will be permanently deleted, with your inventory, packs and pictures, on %s.",
+	"email.account_deletion.prompt": "Changed your mind? " +
+		"Until then you can restore your account with your username and password:",
+	"email.account_deletion.button": "Restore my account",
+	"email.account_deletion.warning": "If you did not request this, " +
+		"restore your account and change your password right away.",
+
+	// Confirmation of a new email address
+	"email.email_change.subject":   "PimpMyPack - Confirm your new email address",
+	"email.email_change.title":     "Confirm your new email — PimpMyPack",
+	"email.email_change.preheader": "Please confirm the new email address of your PimpMyPack account.",
+	"email.email_change.heading":   "Confirm your new email",
+	"email.email_change.body":      "Hi %s, please confirm that this is the new email address of your PimpMyPack account.",
+	"email.email_change.validity":  "Your current address stays in use until you do. The link is valid for 24 hours.",
+	"email.email_change.button":    "Confirm my new email",
+	"email.email_change.text_link": "Confirm your new email:",
+
+	// Notice of an email change, sent to the current address
+	"email.email_change_notice.subject":   "PimpMyPack - Email change requested",
+	"email.email_change_notice.title":     "Email change requested — PimpMyPack",
+	"email.email_change_notice.preheader": "A change of the email address of your PimpMyPack account was requested.",
+	"email.email_change_notice.heading":   "Email Change Requested",
+	"email.email_change_notice.body": "Hi %s, a change of the email address of your PimpMyPack account " +
+		"to %s was requested.",
+	"email.email_change_notice.effect": "It takes effect once confirmed from the new address.",
+	"email.email_change_notice.warning": "If you did not request this, change your password right away: " +
+		"someone else may have access to your account.",
+}
@@ -0,0 +1,293 @@
+package i18n
+
+// catalogFR is the French catalog. API error messages are keyed by their
+// English text, as returned by the handlers.
+//
+//nolint:gosec // password_reset keys are message keys, not credentials
+var catalogFR = map[string]string{
+	// Shared email layout
+	"email.footer":        "Optimisez votre sac, profitez du sentier.",
+	"email.link_fallback": "Si le bouton ne fonctionne pas, copiez et collez ce lien dans votre navigateur :",
+
+	// Confirmation of a new account
+	"email.confirmation.subject":   "PimpMyPack - Confirmez votre adresse email",
+	"email.confirmation.title":     "Confirmez votre email — PimpMyPack",
+	"email.confirmation.preheader": "Confirmez votre adresse email pour activer votre compte PimpMyPack.",
+	"email.confirmation.heading":   "Bienvenue, %s !",
+	"email.confirmation.body": "Merci de votre inscription à PimpMyPack. " +
+		"Confirmez votre adresse email pour activer votre compte.",
+	"email.confirmation.button":    "Confirmer mon email",
+	"email.confirmation.text_link": "Confirmez votre email :",
+
+	// Password reset
+	"email.password_reset.subject":   "PimpMyPack - Votre mot de passe a été réinitialisé",
+	"email.password_reset.title":     "Réinitialisation du mot de passe — PimpMyPack",
+	"email.password_reset.preheader": "Votre mot de passe PimpMyPack a été réinitialisé.",
+	"email.password_reset.heading":   "Réinitialisation du mot de passe",
+	"email.password_reset.body": "Votre mot de passe a été réinitialisé. " +
+		"Voici votre nouveau mot de passe temporaire :",
+	"email.password_reset.advice": "Nous vous recommandons de changer votre mot de passe après vous être connecté.",
+	"email.password_reset.warning": "Si vous n'êtes pas à l'origine de cette réinitialisation, " +
+		"contactez-nous immédiatement.",
+
+	// Account locked after failed logins
+	"email.account_locked.subject":   "PimpMyPack - Votre compte a été verrouillé",
+	"email.account_locked.title":     "Compte verrouillé — PimpMyPack",
+	"email.account_locked.preheader": "Votre compte PimpMyPack a été verrouillé après plusieurs échecs de connexion.",
+	"email.account_locked.heading":   "Compte verrouillé",
+	"email.account_locked.body": "Bonjour %s, nous avons temporairement verrouillé votre compte PimpMyPack " +
+		"après plusieurs échecs de connexion.",
+	"email.account_locked.until": "Les connexions sont bloquées jusqu'au %s.",
+	"email.account_locked.prompt": "Si ces tentatives venaient de vous, " +
+		"vous pouvez déverrouiller votre compte dès maintenant :",
+	"email.account_locked.button": "Déverrouiller mon compte",
+	"email.account_locked.warning": "Si vous n'avez pas essayé de vous connecter, quelqu'un tente peut-être " +
+		"de deviner votre mot de passe. Nous vous recommandons de le changer.",
+
+	// Account deletion scheduled
+	"email.account_deletion.subject":   "PimpMyPack - Votre compte va être supprimé",
+	"email.account_deletion.title":     "Suppression du compte programmée — PimpMyPack",
+	"email.account_deletion.preheader": "Votre compte PimpMyPack va être définitivement supprimé.",
+	"email.account_deletion.heading":   "Suppression du compte programmée",
+	"email.account_deletion.body":      "Bonjour %s, votre compte PimpMyPack a été désactivé à votre demande.",
+	"email.account_deletion.date": "Il sera définitivement supprimé, avec votre inventaire, vos sacs " +
+		"et vos photos, le %s.",
+	"email.account_deletion.prompt": "Vous avez changé d'avis ? D'ici là, vous pouvez restaurer votre compte " +
+		"avec votre nom d'utilisateur et votre mot de passe :",
+	"email.account_deletion.button": "Restaurer mon compte",
+	"email.account_deletion.warning": "Si vous n'êtes pas à l'origine de cette demande, restaurez votre compte " +
+		"et changez votre mot de passe immédiatement.",
+
+	// Confirmation of a new email address
+	"email.email_change.subject":   "PimpMyPack - Confirmez votre nouvelle adresse email",
+	"email.email_change.title":     "Confirmez votre nouvel email — PimpMyPack",
+	"email.email_change.preheader": "Confirmez la nouvelle adresse email de votre compte PimpMyPack.",
+	"email.email_change.heading":   "Confirmez votre nouvel email",
+	"email.email_change.body": "Bonjour %s, confirmez qu'il s'agit bien de la nouvelle adresse email " +
+		"de votre compte PimpMyPack.",
+	"email.email_change.validity":  "Votre adresse actuelle reste utilisée d'ici là. Le lien est valable 24 heures.",
+	"email.email_change.button":    "Confirmer mon nouvel email",
+	"email.email_change.text_link": "Confirmez votre nouvel email :",
+
+	// Notice of an email change, sent to the current address
+	"email.email_change_notice.subject":   "PimpMyPack - Changement d'email demandé",
+	"email.email_change_notice.title":     "Changement d'email demandé — PimpMyPack",
+	"email.email_change_notice.preheader": "Un changement de l'adresse email de votre compte PimpMyPack a été demandé.",
+	"email.email_change_notice.heading":   "Changement d'email demandé",
+	"email.email_change_notice.body": "Bonjour %s, le remplacement de l'adresse email de votre compte PimpMyPack " +
+		"par %s a été demandé.",
+	"email.email_change_notice.effect": "Il prendra effet une fois confirmé depuis la nouvelle adresse.",
+	"email.email_change_notice.warning": "Si vous n'êtes pas à l'origine de cette demande, changez votre mot de passe " +
+		"immédiatement : quelqu'un d'autre a peut-être accès à votre compte.",
+
+	// Generic API errors (helper.ErrMsg*)
+	"Internal server error":  "Erreur interne du serveur",
+	"Unauthorized":           "Non autorisé",
+	"Resource not found":     "Ressource introuvable",
+	"Invalid request":        "Requête invalide",
+	"Access forbidden":       "Accès interdit",
+	"bad request":            "Requête invalide",
+	"Rate limit exceeded":    "Trop de requêtes",
+	"request body too large": "Corps de la requête trop volumineux",
+
+	// Request validation
+	"Invalid ID format":      "Format d'identifiant invalide",
+	"Invalid pack ID format": "Format d'identifiant de sac invalide",
+	"Invalid Pack ID format": "Format d'identifiant de sac invalide",
+	"Invalid item ID format": "Format d'identifiant d'article invalide",
+	"Invalid Item ID format": "Format d'identifiant d'article invalide",
+	"Invalid user_id format": "Format de user_id invalide",
+	"Invalid Body format":    "Format du corps de la requête invalide",
+	"Invalid Payload":        "Contenu de la requête invalide",
+	"payload is empty":       "Le contenu de la requête est vide",
+
+	// Accounts and authentication
+	"Account not found":                    "Compte introuvable",
+	"Account object is null":               "Le compte est vide",
+	"Profile not found":                    "Profil introuvable",
+	"credentials are incorrect":            "Identifiants incorrects",
+	"invalid credentials":                  "Identifiants invalides",
+	"authentication failed":                "Échec de l'authentification",
+	"identity verification failed":         "Échec de la vérification d'identité",
+	"account not yet confirmed":            "Compte pas encore confirmé",
+	"account is not active":                "Le compte n'est pas actif",
+	"invalid email":                        "Email invalide",
+	"invalid email format":                 "Format d'email invalide",
+	"email already in use":                 "Email déjà utilisé",
+	"email already exists":                 "Email déjà utilisé",
+	"username must not contain @":          "Le nom d'utilisateur ne doit pas contenir @",
+	"password is incorrect":                "Mot de passe incorrect",
+	"current password is incorrect":        "Le mot de passe actuel est incorrect",
+	"Failed to get current password":       "Impossible de lire le mot de passe actuel",
+	"Invalid confirmation code or user ID": "Code de confirmation ou identifiant invalide",
+	"Invalid refresh token":                "Jeton de rafraîchissement invalide",
+	"Refresh token has expired":            "Le jeton de rafraîchissement a expiré",
+	"invalid or expired unlock link":       "Lien de déverrouillage invalide ou expiré",
+	"unsupported preferred_language":       "preferred_language non pris en charge",
+	"new password must be different from current password": "Le nouveau mot de passe doit être différent " +
+		"de l'actuel",
+	"account deletion already requested":          "Suppression du compte déjà demandée",
+	"account is not scheduled for deletion":       "La suppression du compte n'est pas programmée",
+	"OIDC login is not enabled":                   "La connexion OIDC n'est pas activée",
+	"invalid or expired OIDC login state":         "État de connexion OIDC invalide ou expiré",
+	"no account linked to this identity":          "Aucun compte n'est lié à cette identité",
+	"re-authentication failed":                    "Échec de la ré-authentification",
+	"image_position_x must be between 0 and 100":  "image_position_x doit être compris entre 0 et 100",
+	"image_position_y must be between 0 and 100":  "image_position_y doit être compris entre 0 et 100",
+	"banner_position_y must be between 0 and 100": "banner_position_y doit être compris entre 0 et 100",
+
+	// Packs and inventories
+	"Pack not found":                               "Sac introuvable",
+	"No pack found":                                "Aucun sac trouvé",
+	"No packs founded":                             "Aucun sac trouvé",
+	"shared pack not found":                        "Sac partagé introuvable",
+	"shared pack has no items":                     "Le sac partagé ne contient aucun article",
+	"This pack does not belong to you":             "Ce sac ne vous appartient pas",
+	"Pack content not found":                       "Contenu du sac introuvable",
+	"Pack item not found":                          "Article du sac introuvable",
+	"Pack Item not found":                          "Article du sac introuvable",
+	"Inventory not found":                          "Inventaire introuvable",
+	"No Inventory Found":                           "Aucun inventaire trouvé",
+	"No inventories empty":                         "Aucun inventaire trouvé",
+	"Inventory item not found":                     "Article d'inventaire introuvable",
+	"This item does not belong to you":             "Cet article ne vous appartient pas",
+	"Source inventory item not found":              "Article d'inventaire source introuvable",
+	"Target inventory item not found":              "Article d'inventaire cible introuvable",
+	"Source and target items must be different":    "Les articles source et cible doivent être différents",
+	"Invalid CSV format - wrong number of columns": "Format CSV invalide - nombre de colonnes incorrect",
+	"Failed to read the CSV header":                "Impossible de lire l'en-tête CSV",
+	"failed to fetch LighterPack page":             "Impossible de récupérer la page LighterPack",
+
+	// Inventory batch merges
+	"Source items must be different": "Les articles source doivent être différents",
+	"Too many items to merge":        "Trop d'articles à fusionner",
+	"image_source_item_id must be one of the source items": "image_source_item_id doit être l'un des " +
+		"articles source",
+
+	// Inventory imports and exports
+	"format must be csv or json": "format doit valoir csv ou json",
+	"unreadable file, expected a CSV or JSON inventory": "Fichier illisible, un inventaire CSV ou JSON " +
+		"est attendu",
+	"no item_name column found": "Aucune colonne item_name trouvée",
+	"no valid rows to import":   "Aucune ligne valide à importer",
+	"too many items to import":  "Trop d'articles à importer",
+
+	// Categories
+	"category not found":                             "Catégorie introuvable",
+	"category name already exists":                   "Ce nom de catégorie existe déjà",
+	"category name must not be empty":                "Le nom de la catégorie ne doit pas être vide",
+	"category still has items":                       "La catégorie contient encore des articles",
+	"color must be a hex color such as #1a2b3c":      "color doit être une couleur hexadécimale comme #1a2b3c",
+	"icon must be at most 64 characters":             "icon doit contenir au plus 64 caractères",
+	"source and target categories must be different": "Les catégories source et cible doivent être différentes",
+
+	// Item lifecycle and maintenance
+	"dates must be formatted as YYYY-MM-DD": "Les dates doivent être au format AAAA-MM-JJ",
+	"condition must be new, good, fair, poor or broken": "condition doit valoir new, good, fair, poor " +
+		"ou broken",
+	"maintenance_interval_days must be between 0 and 36500": "maintenance_interval_days doit être compris " +
+		"entre 0 et 36500",
+	"maintenance entry not found": "Entrée de maintenance introuvable",
+	"Invalid within_days":         "within_days invalide",
+
+	// Trips
+	"trip dates must be formatted as YYYY-MM-DD, end_date not before start_date": "Les dates du voyage " +
+		"doivent être au format AAAA-MM-JJ, end_date non antérieure à start_date",
+	"nights and distance_km must not be negative": "nights et distance_km ne doivent pas être négatifs",
+	"trip not found": "Voyage introuvable",
+
+	// Custom fields
+	"custom field not found":              "Champ personnalisé introuvable",
+	"custom field key already exists":     "Cette clé de champ personnalisé existe déjà",
+	"custom field name must not be empty": "Le nom du champ personnalisé ne doit pas être vide",
+	"key must be lowercase letters, digits and underscores, starting with a letter": "key doit contenir " +
+		"des minuscules, des chiffres et des tirets bas, en commençant par une lettre",
+	"type must be text, number, boolean or enum":   "type doit être text, number, boolean ou enum",
+	"enum fields need distinct, non-empty options": "Les champs enum nécessitent des options distinctes et non vides",
+	"only number fields have a unit":               "Seuls les champs number ont une unité",
+	"only enum fields have options":                "Seuls les champs enum ont des options",
+	"too many custom fields":                       "Trop de champs personnalisés",
+	"unknown custom field":                         "Champ personnalisé inconnu",
+	"custom field value does not match the field type": "La valeur ne correspond pas au type du champ " +
+		"personnalisé",
+
+	// Products
+	"product not found":                            "Produit introuvable",
+	"brand and model must not be empty":            "brand et model ne doivent pas être vides",
+	"weight must not be negative":                  "weight ne doit pas être négatif",
+	"too many products to import":                  "Trop de produits à importer",
+	"search query must have at least 2 characters": "La recherche doit contenir au moins 2 caractères",
+	"the import lists the same product twice":      "L'import contient deux fois le même produit",
+	"Invalid limit":                                "limit invalide",
+
+	// Suggestions
+	"limit must be between 1 and 10": "limit doit être compris entre 1 et 10",
+
+	// Pack contents
+	"the order lists the same pack content twice": "L'ordre contient deux fois le même contenu de sac",
+	"the order lists a pack content that is not in this pack": "L'ordre contient un contenu absent de " +
+		"ce sac",
+	"item notes must be 500 characters or less": "Les notes d'un article ne doivent pas dépasser " +
+		"500 caractères",
+	"an inventory item can only be imported once per pack": "Un article ne peut être importé qu'une fois par sac",
+
+	// Templates
+	"template not found":    "Modèle introuvable",
+	"template has no items": "Le modèle ne contient aucun article",
+
+	// Variants
+	"variant not found":                       "Variante introuvable",
+	"variant name must not be empty":          "Le nom de la variante ne doit pas être vide",
+	"a variant with this name already exists": "Une variante de ce nom existe déjà",
+	"a variant changes each item once":        "Une variante modifie chaque article une seule fois",
+	"too many variant changes":                "Trop de modifications dans la variante",
+	"variant changes an item that is not in your inventory": "La variante modifie un article absent de " +
+		"votre inventaire",
+	"changes need an add, remove or update action and a quantity of 1 or more": "Les modifications doivent " +
+		"avoir une action add, remove ou update et une quantité d'au moins 1",
+
+	// Trails
+	"Trail not found":                        "Sentier introuvable",
+	"No trails found":                        "Aucun sentier trouvé",
+	"Trail name already exists":              "Ce nom de sentier existe déjà",
+	"Trail is in use by one or more packs":   "Le sentier est utilisé par un ou plusieurs sacs",
+	"One or more trails are in use by packs": "Un ou plusieurs sentiers sont utilisés par des sacs",
+	"One or more trail names already exist":  "Un ou plusieurs noms de sentier existent déjà",
+	"One or more trail IDs not found":        "Un ou plusieurs identifiants de sentier sont introuvables",
+
+	// Images
+	"No image file provided": "Aucun fichier image fourni",
+	"Invalid image format. Only JPEG, PNG, and WebP are supported": "Format d'image invalide. " +
+		"Seuls JPEG, PNG et WebP sont pris en charge",
+	"File size exceeds maximum allowed":         "La taille du fichier dépasse le maximum autorisé",
+	"Corrupted or invalid image file":           "Fichier image corrompu ou invalide",
+	"Failed to save image":                      "Impossible d'enregistrer l'image",
+	"Failed to delete image":                    "Impossible de supprimer l'image",
+	"Item picture upload is currently disabled": "L'envoi de photos d'articles est actuellement désactivé",
+
+	// Currencies
+	"invalid currency code":   "Code de devise invalide",
+	"exchange rate not found": "Taux de change introuvable",
+	"EUR is the base currency, its rate is always 1": "EUR est la devise de référence, son taux vaut " +
+		"toujours 1",
+	"preferred_currency must be an ISO 4217 currency code": "preferred_currency doit être un code " +
+		"de devise ISO 4217",
+	"too many items in import": "Trop d'articles dans l'import",
+
+	// Spreadsheet imports
+	"unreadable spreadsheet, expected a CSV or XLSX file": "Tableur illisible, un fichier CSV ou XLSX " +
+		"est attendu",
+	"no header row with an item name column found": "Aucune ligne d'en-tête avec une colonne de nom " +
+		"d'article trouvée",
+	"invalid column mapping":              "Correspondance de colonnes invalide",
+	"no valid rows in spreadsheet":        "Aucune ligne valide dans le tableur",
+	"weight_unit must be g, kg, oz or lb": "weight_unit doit valoir g, kg, oz ou lb",
+
+	// Import reconciliation
+	"every import item needs a reuse or create action": "Chaque article importé doit être réutilisé (reuse) " +
+		"ou créé (create)",
+	"inventory item to reuse not found": "Article d'inventaire à réutiliser introuvable",
+
+	// Units
+	"units must be preferred, metric or imperial": "units doit valoir preferred, metric ou imperial",
+}
@@ -0,0 +1,102 @@
+// Package i18n holds the translated strings of the application - email
+// templates and API error messages - and picks the language of a request or
+// of an account. English is the reference: a string missing from another
+// catalog falls back to English, and a key missing from every catalog is
+// returned as is, so an untranslated API error still reads in English.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLanguage is used when no supported language is requested
+const DefaultLanguage = "en"
+
+// catalogs maps a language to its strings, keyed by message key
+var catalogs = map[string]map[string]string{
+	"en": catalogEN,
+	"fr": catalogFR,
+}
+
+// SupportedLanguages returns the supported language codes, sorted
+func SupportedLanguages() []string {
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// IsSupported reports whether lang is the code of a supported language
+func IsSupported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Normalize maps a language tag ("fr", "fr-CA", "FR_fr") to a supported
+// language code, or to DefaultLanguage when its primary language is not supported
+func Normalize(tag string) string {
+	lang, ok := primaryLanguage(tag)
+	if !ok {
+		return DefaultLanguage
+	}
+	return lang
+}
+
+// primaryLanguage returns the supported language of a tag, if any
+func primaryLanguage(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	if !IsSupported(tag) {
+		return "", false
+	}
+	return tag, true
+}
+
+// FromAcceptLanguage picks the supported language the client prefers from an
+// Accept-Language header ("fr-CA,fr;q=0.9,en;q=0.8"); ties keep header order.
+// It returns DefaultLanguage when the header names no supported language.
+func FromAcceptLanguage(header string) string {
+	best, bestQ := DefaultLanguage, 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		lang, ok := primaryLanguage(tag)
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if name, value, found := strings.Cut(strings.TrimSpace(params), "="); found && strings.TrimSpace(name) == "q" {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > bestQ {
+			best, bestQ = lang, q
+		}
+	}
+	return best
+}
+
+// T returns the message of key in lang, formatted with args when given.
+// It falls back to English, then to the key itself.
+func T(lang, key string, args ...any) string {
+	msg, ok := catalogs[lang][key]
+	if !ok {
+		msg, ok = catalogs[DefaultLanguage][key]
+	}
+	if !ok {
+		msg = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
@@ -0,0 +1,110 @@
+package i18n
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestFromAcceptLanguage(t *testing.T) {
+	tests := map[string]string{
+		"":                         "en",
+		"fr":                       "fr",
+		"fr-CA,fr;q=0.9,en;q=0.8":  "fr",
+		"en-US,en;q=0.9,fr;q=0.8":  "en",
+		"de-DE,de;q=0.9,fr;q=0.5":  "fr",
+		"de,es":                    "en",
+		"en;q=0.4,fr;q=0.7":        "fr",
+		"fr;q=0":                   "en",
+		"fr;q=abc,en":              "en",
+		"*":                        "en",
+		"FR_fr ; q=1 , en ; q=0.2": "fr",
+	}
+	for header, want := range tests {
+		if got := FromAcceptLanguage(header); got != want {
+			t.Errorf("FromAcceptLanguage(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{"fr": "fr", "fr-BE": "fr", "EN": "en", "de": "en", "": "en"}
+	for tag, want := range tests {
+		if got := Normalize(tag); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", tag, got, want)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	if got := T("fr", "email.confirmation.heading", "alice"); got != "Bienvenue, alice !" {
+		t.Errorf("Expected the French heading, got %q", got)
+	}
+	if got := T("de", "email.confirmation.heading", "alice"); got != "Welcome, alice!" {
+		t.Errorf("Expected the English fallback for an unsupported language, got %q", got)
+	}
+	if got := T("fr", "Pack not found"); got != "Sac introuvable" {
+		t.Errorf("Expected the French API error, got %q", got)
+	}
+	if got := T("fr", "some untranslated error"); got != "some untranslated error" {
+		t.Errorf("Expected the key itself when untranslated, got %q", got)
+	}
+}
+
+// TestCatalogsComplete makes sure every English email string has a translation
+func TestCatalogsComplete(t *testing.T) {
+	for _, lang := range SupportedLanguages() {
+		for key := range catalogEN {
+			if _, ok := catalogs[lang][key]; !ok {
+				t.Errorf("Missing %s translation for %q", lang, key)
+			}
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/missing", func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pack not found"})
+	})
+	router.GET("/ok", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "Pack not found", "language": Language(c)})
+	})
+
+	tests := []struct {
+		name, path, acceptLanguage, field, want string
+		status                                  int
+	}{
+		{"error in French", "/missing", "fr-FR,fr;q=0.9", "error", "Sac introuvable", http.StatusNotFound},
+		{"error in English", "/missing", "en-US", "error", "Pack not found", http.StatusNotFound},
+		{"error without header", "/missing", "", "error", "Pack not found", http.StatusNotFound},
+		{"success untouched", "/ok", "fr", "message", "Pack not found", http.StatusOK},
+		{"language exposed", "/ok", "fr", "language", "fr", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("Expected status code %d but got %d", tt.status, w.Code)
+			}
+			var body map[string]string
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if body[tt.field] != tt.want {
+				t.Errorf("Expected %s %q but got %q", tt.field, tt.want, body[tt.field])
+			}
+		})
+	}
+}
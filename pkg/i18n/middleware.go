@@ -0,0 +1,96 @@
+package i18n
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// languageKey is the gin context key of the negotiated language
+const languageKey = "language"
+
+// Middleware negotiates the language of the request from Accept-Language and
+// translates the "error" field of JSON error responses (status >= 400) into
+// it. Handlers keep returning English messages; Language gives them the
+// negotiated language for anything else they localize.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := FromAcceptLanguage(c.GetHeader("Accept-Language"))
+		c.Set(languageKey, lang)
+		c.Writer.Header().Add("Vary", "Accept-Language")
+
+		if lang == DefaultLanguage {
+			c.Next()
+			return
+		}
+
+		w := &translatingWriter{ResponseWriter: c.Writer, lang: lang}
+		c.Writer = w
+		c.Next()
+		w.flush()
+	}
+}
+
+// Language returns the language negotiated by Middleware, or the one of the
+// Accept-Language header when the middleware is not installed
+func Language(c *gin.Context) string {
+	if lang := c.GetString(languageKey); lang != "" {
+		return lang
+	}
+	return FromAcceptLanguage(c.GetHeader("Accept-Language"))
+}
+
+// translatingWriter buffers JSON error bodies so that their "error" field
+// can be translated once the handler is done; other responses pass through
+type translatingWriter struct {
+	gin.ResponseWriter
+	lang      string
+	buffering bool
+	buf       bytes.Buffer
+}
+
+func (w *translatingWriter) isJSONError() bool {
+	return w.Status() >= http.StatusBadRequest &&
+		strings.HasPrefix(w.Header().Get("Content-Type"), "application/json")
+}
+
+func (w *translatingWriter) Write(data []byte) (int, error) {
+	if w.buffering || (!w.ResponseWriter.Written() && w.isJSONError()) {
+		w.buffering = true
+		return w.buf.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *translatingWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *translatingWriter) Written() bool {
+	return w.buffering || w.ResponseWriter.Written()
+}
+
+// flush writes the buffered error body, translated when it is a JSON object
+// with a string "error" field
+func (w *translatingWriter) flush() {
+	if !w.buffering {
+		return
+	}
+	body := w.buf.Bytes()
+
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err == nil {
+		if msg, ok := payload["error"].(string); ok {
+			payload["error"] = T(w.lang, msg)
+			if translated, err := json.Marshal(payload); err == nil {
+				body = translated
+			}
+		}
+	}
+
+	w.Header().Set("Content-Language", w.lang)
+	_, _ = w.ResponseWriter.Write(body)
+}
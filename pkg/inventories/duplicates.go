@@ -0,0 +1,189 @@
+package inventories
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// Duplicate detection: every pair of items of a user is scored on name
+// similarity, category, weight and URL; pairs above duplicateThreshold are
+// clustered into groups.
+
+// Reasons a pair of items is considered duplicate
+const (
+	DuplicateReasonName     = "similar_name"
+	DuplicateReasonCategory = "same_category"
+	DuplicateReasonWeight   = "close_weight"
+	DuplicateReasonURL      = "same_url"
+)
+
+// maxBatchMergeItems caps the source items of a batch merge
+const maxBatchMergeItems = 50
+
+const (
+	duplicateThreshold = 0.55
+	// minDuplicateNameSimilarity skips pairs with unrelated names and different URLs
+	minDuplicateNameSimilarity = 0.3
+	// weightTolerance is the relative weight difference still considered close
+	weightTolerance = 0.1
+
+	nameScore     = 0.5
+	categoryScore = 0.15
+	weightScore   = 0.15
+	urlScore      = 0.2
+)
+
+// scoreDuplicatePair returns the confidence that two items are the same piece
+// of gear and the signals they share
+func scoreDuplicatePair(a, b Inventory) (float64, []string) {
+	sameURL := a.URL != "" && normalizeDuplicateURL(a.URL) == normalizeDuplicateURL(b.URL)
+	similarity := NameSimilarity(a.ItemName, b.ItemName)
+	if similarity < minDuplicateNameSimilarity && !sameURL {
+		return 0, nil
+	}
+
+	score := nameScore * similarity
+	var reasons []string
+	if similarity >= minDuplicateNameSimilarity {
+		reasons = append(reasons, DuplicateReasonName)
+	}
+	if strings.EqualFold(strings.TrimSpace(a.Category), strings.TrimSpace(b.Category)) {
+		score += categoryScore
+		reasons = append(reasons, DuplicateReasonCategory)
+	}
+	if closeWeights(a.Weight, b.Weight) {
+		score += weightScore
+		reasons = append(reasons, DuplicateReasonWeight)
+	}
+	if sameURL {
+		score += urlScore
+		reasons = append(reasons, DuplicateReasonURL)
+	}
+	return score, reasons
+}
+
+// closeWeights reports whether two known weights differ by at most weightTolerance
+func closeWeights(a, b int) bool {
+	if a <= 0 || b <= 0 {
+		return false
+	}
+	return math.Abs(float64(a-b)) <= weightTolerance*math.Max(float64(a), float64(b))
+}
+
+func normalizeDuplicateURL(url string) string {
+	url = strings.ToLower(strings.TrimSpace(url))
+	url = strings.TrimPrefix(strings.TrimPrefix(url, "https://"), "http://")
+	url = strings.TrimPrefix(url, "www.")
+	return strings.TrimSuffix(url, "/")
+}
+
+// duplicatePair links two items, by index, scoring above duplicateThreshold
+type duplicatePair struct {
+	a, b    int
+	score   float64
+	reasons []string
+}
+
+// duplicateCluster gathers the items linked, directly or not, by duplicate pairs
+type duplicateCluster struct {
+	members []int
+	total   float64
+	links   int
+	reasons map[string]bool
+}
+
+// findDuplicateGroups clusters the likely duplicates of an inventory, most
+// confident groups first
+func findDuplicateGroups(items Inventories) []DuplicateGroup {
+	// Union-find over the items linked by a duplicate pair
+	parent := make([]int, len(items))
+	for i := range parent {
+		parent[i] = i
+	}
+	var root func(int) int
+	root = func(i int) int {
+		if parent[i] != i {
+			parent[i] = root(parent[i])
+		}
+		return parent[i]
+	}
+
+	pairs := findDuplicatePairs(items)
+	for _, p := range pairs {
+		parent[root(p.b)] = root(p.a)
+	}
+
+	clusters := map[int]*duplicateCluster{}
+	for i := range items {
+		r := root(i)
+		if clusters[r] == nil {
+			clusters[r] = &duplicateCluster{reasons: map[string]bool{}}
+		}
+		clusters[r].members = append(clusters[r].members, i)
+	}
+	for _, p := range pairs {
+		cl := clusters[root(p.a)]
+		cl.total += p.score
+		cl.links++
+		for _, reason := range p.reasons {
+			cl.reasons[reason] = true
+		}
+	}
+
+	groups := []DuplicateGroup{}
+	for _, cl := range clusters {
+		if len(cl.members) > 1 {
+			groups = append(groups, cl.group(items))
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Confidence != groups[j].Confidence {
+			return groups[i].Confidence > groups[j].Confidence
+		}
+		return groups[i].SuggestedTargetID < groups[j].SuggestedTargetID
+	})
+	return groups
+}
+
+func findDuplicatePairs(items Inventories) []duplicatePair {
+	var pairs []duplicatePair
+	for i := range items {
+		for j := i + 1; j < len(items); j++ {
+			score, reasons := scoreDuplicatePair(items[i], items[j])
+			if score >= duplicateThreshold {
+				pairs = append(pairs, duplicatePair{a: i, b: j, score: score, reasons: reasons})
+			}
+		}
+	}
+	return pairs
+}
+
+func (cl *duplicateCluster) group(items Inventories) DuplicateGroup {
+	group := DuplicateGroup{
+		Confidence: math.Round(cl.total/float64(cl.links)*100) / 100,
+		Reasons:    make([]string, 0, len(cl.reasons)),
+		Items:      make(Inventories, 0, len(cl.members)),
+	}
+	for reason := range cl.reasons {
+		group.Reasons = append(group.Reasons, reason)
+	}
+	sort.Strings(group.Reasons)
+	for _, m := range cl.members {
+		group.Items = append(group.Items, items[m])
+	}
+	group.SuggestedTargetID = suggestedMergeTarget(group.Items)
+	return group
+}
+
+// suggestedMergeTarget picks the item used in the most packs, the oldest on ties
+func suggestedMergeTarget(items Inventories) uint {
+	best := items[0]
+	for _, item := range items[1:] {
+		if item.PackCount > best.PackCount || (item.PackCount == best.PackCount && item.ID < best.ID) {
+			best = item
+		}
+	}
+	return best.ID
+}
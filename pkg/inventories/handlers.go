@@ -1,12 +1,18 @@
 package inventories
 
 import (
+	"bytes"
 	"errors"
+	"io"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/Angak0k/pimpmypack/pkg/customfields"
 	"github.com/Angak0k/pimpmypack/pkg/helper"
+	"github.com/Angak0k/pimpmypack/pkg/products"
 	"github.com/Angak0k/pimpmypack/pkg/security"
+	"github.com/Angak0k/pimpmypack/pkg/units"
 	"github.com/gin-gonic/gin"
 )
 
@@ -42,11 +48,16 @@ func GetInventories(c *gin.Context) {
 
 // GetMyInventory gets all inventories of the user
 // @Summary Get all inventories of the user
-// @Description Retrieves a list of all inventories of the user
+// @Description Retrieves a list of all inventories of the user. Wishlist items are listed apart, with wishlist=true.
 // @Security Bearer
 // @Tags Inventories
 // @Produce json
+// @Param units query string false "Add weight displays in this unit system" Enums(preferred, metric, imperial)
+// @Param include_retired query bool false "Include retired items, hidden by default"
+// @Param wishlist query bool false "List the wishlist items instead of the owned ones"
+// @Param field.key query string false "Filter on a custom field by key: text contained, exact value, or min..max"
 // @Success 200 {object} inventories.Inventories
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid units or custom field filter"
 // @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
 // @Failure 404 {object} apitypes.ErrorResponse "No Inventory Found"
 // @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
@@ -60,6 +71,12 @@ func GetMyInventory(c *gin.Context) {
 		return
 	}
 
+	system, err := units.FromRequest(c, userID)
+	if err != nil {
+		units.RespondError(c, err, "get my inventory: resolve units failed")
+		return
+	}
+
 	inventories, err := returnInventoriesByUserID(c.Request.Context(), userID)
 
 	if err != nil {
@@ -67,6 +84,31 @@ func GetMyInventory(c *gin.Context) {
 		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
 		return
 	}
+	wishlist, _ := strconv.ParseBool(c.Query("wishlist"))
+	*inventories = onWishlist(*inventories, wishlist)
+	if includeRetired, _ := strconv.ParseBool(c.Query("include_retired")); !includeRetired {
+		*inventories = withoutRetired(*inventories)
+	}
+	*inventories, err = filterByCustomFields(c.Request.Context(), userID, c.Request.URL.Query(), *inventories)
+	if err != nil {
+		if !customfields.RespondValueError(c, err) {
+			helper.LogAndSanitize(err, "get my inventory: filter custom fields failed")
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		}
+		return
+	}
+
+	if err := setPreferredPrices(c.Request.Context(), userID, *inventories); err != nil {
+		helper.LogAndSanitize(err, "get my inventory: convert prices failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+	if err := setProductMedianWeights(c.Request.Context(), *inventories); err != nil {
+		helper.LogAndSanitize(err, "get my inventory: product median weights failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+	setWeightDisplays(*inventories, system)
 
 	if len(*inventories) != 0 {
 		c.IndentedJSON(http.StatusOK, *inventories)
@@ -121,7 +163,9 @@ func GetInventoryByID(c *gin.Context) {
 // @Tags Inventories
 // @Produce json
 // @Param id path int true "Inventory ID"
+// @Param units query string false "Add weight displays in this unit system" Enums(preferred, metric, imperial)
 // @Success 200 {object} inventories.Inventory "Inventory"
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format or units"
 // @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
 // @Failure 403 {object} apitypes.ErrorResponse "This item does not belong to you"
 // @Failure 404 {object} apitypes.ErrorResponse "Inventory not found"
@@ -141,6 +185,12 @@ func GetMyInventoryByID(c *gin.Context) {
 		return
 	}
 
+	system, err := units.FromRequest(c, userID)
+	if err != nil {
+		units.RespondError(c, err, "get my inventory by ID: resolve units failed")
+		return
+	}
+
 	// Check existence first
 	inventory, err := findInventoryByID(c.Request.Context(), id)
 	if err != nil {
@@ -159,7 +209,20 @@ func GetMyInventoryByID(c *gin.Context) {
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, inventory)
+	items := Inventories{*inventory}
+	if err := setPreferredPrices(c.Request.Context(), userID, items); err != nil {
+		helper.LogAndSanitize(err, "get my inventory by ID: convert prices failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+	if err := setProductMedianWeights(c.Request.Context(), items); err != nil {
+		helper.LogAndSanitize(err, "get my inventory by ID: product median weights failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+	setWeightDisplays(items, system)
+
+	c.IndentedJSON(http.StatusOK, items[0])
 }
 
 // PostInventory creates an inventory
@@ -183,9 +246,10 @@ func PostInventory(c *gin.Context) {
 		return
 	}
 
-	currency := input.Currency
-	if currency == "" {
-		currency = DefaultCurrency
+	currency, err := normalizeCurrency(input.Currency)
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
 	newInventory := Inventory{
@@ -199,7 +263,14 @@ func PostInventory(c *gin.Context) {
 		Currency:    currency,
 	}
 
-	err := InsertInventory(c.Request.Context(), &newInventory)
+	err = applyItemDetails(c.Request.Context(), &newInventory, input.InventoryLifecycleInput, input.CustomFields,
+		input.ProductID)
+	if err != nil {
+		respondItemDetailsError(c, err, "post inventory: apply item details failed")
+		return
+	}
+
+	err = InsertInventory(c.Request.Context(), &newInventory)
 	if err != nil {
 		helper.LogAndSanitize(err, "post inventory: insert inventory failed")
 		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
@@ -211,7 +282,8 @@ func PostInventory(c *gin.Context) {
 
 // PostMyInventory creates an inventory
 // @Summary Create an inventory
-// @Description Creates an inventory
+// @Description Creates an inventory. A product_id found with GET /v1/products links the item to the
+// @Description product, which fills a zero weight and an empty URL.
 // @Security Bearer
 // @Tags Inventories
 // @Accept json
@@ -241,9 +313,10 @@ func PostMyInventory(c *gin.Context) {
 
 	// SECURITY FIX: Map input to Inventory AFTER binding
 	// UserID from JWT cannot be overridden by client
-	currency := input.Currency
-	if currency == "" {
-		currency = DefaultCurrency
+	currency, err := normalizeCurrency(input.Currency)
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
 	newInventory := Inventory{
@@ -257,6 +330,13 @@ func PostMyInventory(c *gin.Context) {
 		Currency:    currency,
 	}
 
+	err = applyItemDetails(c.Request.Context(), &newInventory, input.InventoryLifecycleInput, input.CustomFields,
+		input.ProductID)
+	if err != nil {
+		respondItemDetailsError(c, err, "post my inventory: apply item details failed")
+		return
+	}
+
 	err = InsertInventory(c.Request.Context(), &newInventory)
 	if err != nil {
 		helper.LogAndSanitize(err, "post my inventory: insert inventory failed")
@@ -308,9 +388,10 @@ func PutInventoryByID(c *gin.Context) {
 		return
 	}
 
-	currency := input.Currency
-	if currency == "" {
-		currency = DefaultCurrency
+	currency, err := normalizeCurrency(input.Currency)
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
 	updatedInventory := Inventory{
@@ -326,6 +407,14 @@ func PutInventoryByID(c *gin.Context) {
 		CreatedAt:   existingInventory.CreatedAt, // Preserve existing CreatedAt
 		UpdatedAt:   time.Now().Truncate(time.Second),
 	}
+	keepDetails(&updatedInventory, existingInventory)
+
+	err = applyItemDetails(c.Request.Context(), &updatedInventory, input.InventoryLifecycleInput, input.CustomFields,
+		input.ProductID)
+	if err != nil {
+		respondItemDetailsError(c, err, "put inventory by ID: apply item details failed")
+		return
+	}
 
 	err = updateInventoryByID(c.Request.Context(), id, &updatedInventory)
 	if err != nil {
@@ -396,9 +485,10 @@ func PutMyInventoryByID(c *gin.Context) {
 
 	// SECURITY FIX: Map input to Inventory AFTER binding
 	// UserID from JWT cannot be overridden by client
-	currency := input.Currency
-	if currency == "" {
-		currency = DefaultCurrency
+	currency, err := normalizeCurrency(input.Currency)
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
 	updatedInventory := Inventory{
@@ -414,6 +504,14 @@ func PutMyInventoryByID(c *gin.Context) {
 		CreatedAt:   existingInventory.CreatedAt, // Preserve existing CreatedAt
 		UpdatedAt:   time.Now().Truncate(time.Second),
 	}
+	keepDetails(&updatedInventory, existingInventory)
+
+	err = applyItemDetails(c.Request.Context(), &updatedInventory, input.InventoryLifecycleInput, input.CustomFields,
+		input.ProductID)
+	if err != nil {
+		respondItemDetailsError(c, err, "put my inventory by ID: apply item details failed")
+		return
+	}
 
 	err = updateInventoryByID(c.Request.Context(), id, &updatedInventory)
 	if err != nil {
@@ -454,9 +552,10 @@ func PostMyInventoryMerge(c *gin.Context) {
 		return
 	}
 
-	// Default currency if empty
-	if input.Currency == "" {
-		input.Currency = DefaultCurrency
+	input.Currency, err = normalizeCurrency(input.Currency)
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
 	// Verify source != target
@@ -508,6 +607,298 @@ func PostMyInventoryMerge(c *gin.Context) {
 	c.IndentedJSON(http.StatusOK, mergedItem)
 }
 
+// GetMyInventoryDuplicates lists the likely duplicate items of the user
+// @Summary Find duplicate inventory items
+// @Description Clusters inventory items that are likely the same piece of gear, based on name similarity,
+// @Description category, weight and URL. Each group has a confidence from 0 to 1, the shared signals and
+// @Description the suggested merge target (the item used in the most packs). Wishlist items are left out.
+// @Security Bearer
+// @Tags Inventories
+// @Produce json
+// @Success 200 {array} inventories.DuplicateGroup "Duplicate groups, most confident first"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/myinventory/duplicates [get]
+func GetMyInventoryDuplicates(c *gin.Context) {
+	userID, err := security.ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, "get my inventory duplicates: extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return
+	}
+
+	items, err := returnInventoriesByUserID(c.Request.Context(), userID)
+	if err != nil {
+		helper.LogAndSanitize(err, "get my inventory duplicates: return inventories failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, findDuplicateGroups(onWishlist(*items, false)))
+}
+
+// PostMyInventoryBatchMerge merges several inventory items into one
+// @Summary Merge several inventory items
+// @Description Merges every source inventory item into the target item. Pack references are consolidated.
+// @Description With image_source "source", the image of image_source_item_id is kept.
+// @Security Bearer
+// @Tags Inventories
+// @Accept json
+// @Produce json
+// @Param merge body inventories.BatchMergeInventoryRequest true "Batch merge request"
+// @Success 200 {object} inventories.Inventory "Merged inventory item"
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid payload or target among sources"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 403 {object} apitypes.ErrorResponse "Item does not belong to you"
+// @Failure 404 {object} apitypes.ErrorResponse "Item not found"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/myinventory/merge/batch [post]
+func PostMyInventoryBatchMerge(c *gin.Context) {
+	userID, err := security.ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, "post my inventory batch merge: extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return
+	}
+
+	var input BatchMergeInventoryRequest
+	if err := c.BindJSON(&input); err != nil {
+		helper.LogAndSanitize(err, "post my inventory batch merge: bind JSON failed")
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": helper.ErrMsgBadRequest})
+		return
+	}
+
+	input.Currency, err = normalizeCurrency(input.Currency)
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if msg := validateBatchMerge(&input); msg != "" {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	// Verify ownership of every item
+	if !checkMergeItemOwned(c, input.TargetItemID, userID, "Target inventory item not found") {
+		return
+	}
+	for _, sourceID := range input.SourceItemIDs {
+		if !checkMergeItemOwned(c, sourceID, userID, "Source inventory item not found") {
+			return
+		}
+	}
+
+	mergedItem, err := batchMergeInventoryItems(c.Request.Context(), &input)
+	if err != nil {
+		helper.LogAndSanitize(err, "post my inventory batch merge: merge items failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, mergedItem)
+}
+
+// validateBatchMerge returns why a batch merge request is invalid, "" when it is valid
+func validateBatchMerge(input *BatchMergeInventoryRequest) string {
+	if len(input.SourceItemIDs) > maxBatchMergeItems {
+		return "Too many items to merge"
+	}
+	seen := map[uint]bool{}
+	for _, id := range input.SourceItemIDs {
+		if id == input.TargetItemID {
+			return "Source and target items must be different"
+		}
+		if seen[id] {
+			return "Source items must be different"
+		}
+		seen[id] = true
+	}
+	if input.ImageSource == ImageSourceSource && !seen[input.ImageSourceItemID] {
+		return "image_source_item_id must be one of the source items"
+	}
+	return ""
+}
+
+// checkMergeItemOwned responds with an error and returns false unless the item belongs to the user
+func checkMergeItemOwned(c *gin.Context, id, userID uint, notFoundMsg string) bool {
+	item, err := findInventoryByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrNoItemFound) {
+			c.IndentedJSON(http.StatusNotFound, gin.H{"error": notFoundMsg})
+			return false
+		}
+		helper.LogAndSanitize(err, "post my inventory batch merge: find item failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return false
+	}
+	if item.UserID != userID {
+		c.IndentedJSON(http.StatusForbidden, gin.H{"error": "This item does not belong to you"})
+		return false
+	}
+	return true
+}
+
+// ImportMyInventory creates or updates inventory items from a CSV or JSON file
+// @Summary Import inventory items
+// @Description Create or update inventory items from a CSV file (header row with external_key, item_name,
+// @Description category, description, weight, url, price, currency), a JSON file, or a JSON body
+// @Description {"items": [...]}. Weights are in grams, prices in the minor unit of their currency.
+// @Description Items are matched by external_key, then by item name, category and description; unmatched
+// @Description items are created. Description, weight, url and price missing from a row keep the value of
+// @Description the matched item. Rows that cannot be read are skipped and reported in errors.
+// @Security Bearer
+// @Tags Inventories
+// @Accept multipart/form-data,json
+// @Produce json
+// @Param file formData file false "CSV or JSON file, unless the items are sent as a JSON body"
+// @Success 200 {object} inventories.InventoryImportResponse "Created and updated counts with skipped rows"
+// @Failure 400 {object} inventories.InventoryImportErrorResponse "Unreadable file or no valid row"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/myinventory/import [post]
+func ImportMyInventory(c *gin.Context) {
+	userID, err := security.ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, "import my inventory: extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return
+	}
+
+	rows, rowErrors, err := readInventoryImport(c)
+	if err != nil {
+		respondInventoryImportError(c, err, rowErrors)
+		return
+	}
+
+	fields, err := customfields.ReturnFieldsByUserID(c.Request.Context(), userID)
+	if err != nil {
+		helper.LogAndSanitize(err, "import my inventory: return custom fields failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+	rows, rowErrors, err = normalizeImportCustomFields(rows, rowErrors, fields)
+	if err != nil {
+		respondInventoryImportError(c, err, rowErrors)
+		return
+	}
+
+	created, updated, err := importInventoryItems(c.Request.Context(), userID, rows)
+	if err != nil {
+		helper.LogAndSanitize(err, "import my inventory: import items failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.JSON(http.StatusOK, InventoryImportResponse{
+		Message: "Inventory imported successfully",
+		Created: created,
+		Updated: updated,
+		Errors:  rowErrors,
+	})
+}
+
+// readInventoryImport reads the items of an import from a JSON body or an uploaded file
+func readInventoryImport(c *gin.Context) ([]inventoryImportRow, []InventoryImportRowError, error) {
+	if c.ContentType() == "application/json" {
+		data, err := c.GetRawData()
+		if err != nil {
+			return nil, nil, ErrUnreadableInventoryFile
+		}
+		return parseInventoryFile(data, "inventory.json")
+	}
+
+	file, fileHeader, err := c.Request.FormFile("file")
+	if err != nil {
+		return nil, nil, ErrUnreadableInventoryFile
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, nil, ErrUnreadableInventoryFile
+	}
+	return parseInventoryFile(data, fileHeader.Filename)
+}
+
+// respondInventoryImportError maps inventory import reading errors to an HTTP response
+func respondInventoryImportError(c *gin.Context, err error, rowErrors []InventoryImportRowError) {
+	for _, known := range []error{
+		ErrUnreadableInventoryFile, ErrNoItemNameColumn, ErrNoValidInventoryRows, ErrTooManyInventoryRows,
+	} {
+		if errors.Is(err, known) {
+			if rowErrors == nil {
+				rowErrors = []InventoryImportRowError{}
+			}
+			c.JSON(http.StatusBadRequest, InventoryImportErrorResponse{Error: known.Error(), Errors: rowErrors})
+			return
+		}
+	}
+	helper.LogAndSanitize(err, "import my inventory: read items failed")
+	c.JSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+}
+
+// ExportMyInventory exports the inventory of the user
+// @Summary Export inventory items
+// @Description Download the inventory as CSV or JSON, with the number of packs using each item.
+// @Description Custom field values are exported as custom_fields in JSON and field.<key> columns in CSV.
+// @Description The file can be edited and imported back with /v1/myinventory/import.
+// @Security Bearer
+// @Tags Inventories
+// @Produce json,text/csv
+// @Param format query string false "Export format, json by default" Enums(csv, json)
+// @Success 200 {array} inventories.InventoryTransferItem "Inventory items"
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid format"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/myinventory/export [get]
+func ExportMyInventory(c *gin.Context) {
+	userID, err := security.ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, "export my inventory: extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return
+	}
+
+	format := c.DefaultQuery("format", FormatJSON)
+	if format != FormatCSV && format != FormatJSON {
+		c.JSON(http.StatusBadRequest, gin.H{"error": ErrInvalidExportFormat.Error()})
+		return
+	}
+
+	items, err := returnInventoryExport(c.Request.Context(), userID)
+	if err != nil {
+		helper.LogAndSanitize(err, "export my inventory: return inventory failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="inventory.`+format+`"`)
+	if format == FormatJSON {
+		c.JSON(http.StatusOK, items)
+		return
+	}
+
+	fields, err := customfields.ReturnFieldsByUserID(c.Request.Context(), userID)
+	if err != nil {
+		helper.LogAndSanitize(err, "export my inventory: return custom fields failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+	fieldKeys := make([]string, 0, len(fields))
+	for _, field := range fields {
+		fieldKeys = append(fieldKeys, field.Key)
+	}
+
+	var buf bytes.Buffer
+	if err := writeInventoryCSV(&buf, items, fieldKeys); err != nil {
+		helper.LogAndSanitize(err, "export my inventory: write csv failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
 // DeleteInventoryByID deletes an inventory by ID
 // @Summary [ADMIN] Delete an inventory by ID
 // @Description Deletes an inventory by ID - for admin use only
@@ -591,3 +982,258 @@ func DeleteMyInventoryByID(c *gin.Context) {
 
 	c.IndentedJSON(http.StatusOK, gin.H{"message": "Inventory deleted"})
 }
+
+// GetMyInventoryMaintenance gets the maintenance log of an item
+// @Summary Get the maintenance log of an item
+// @Description Retrieves the maintenance entries of an item of the user, most recent first
+// @Security Bearer
+// @Tags Inventories
+// @Produce json
+// @Param id path int true "Inventory ID"
+// @Success 200 {array} inventories.MaintenanceEntry
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 403 {object} apitypes.ErrorResponse "This item does not belong to you"
+// @Failure 404 {object} apitypes.ErrorResponse "Inventory not found"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/myinventory/{id}/maintenance [get]
+func GetMyInventoryMaintenance(c *gin.Context) {
+	item, ok := findMyItem(c, "get my inventory maintenance")
+	if !ok {
+		return
+	}
+
+	entries, err := returnMaintenanceLog(c.Request.Context(), item.ID)
+	if err != nil {
+		helper.LogAndSanitize(err, "get my inventory maintenance: return maintenance log failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, entries)
+}
+
+// PostMyInventoryMaintenance logs a maintenance of an item
+// @Summary Log a maintenance of an item
+// @Description Adds an entry to the maintenance log of an item of the user.
+// @Description The cost is in the minor unit of the currency, the item currency by default.
+// @Security Bearer
+// @Tags Inventories
+// @Accept json
+// @Produce json
+// @Param id path int true "Inventory ID"
+// @Param entry body inventories.MaintenanceEntryRequest true "Maintenance entry"
+// @Success 201 {object} inventories.MaintenanceEntry
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid payload"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 403 {object} apitypes.ErrorResponse "This item does not belong to you"
+// @Failure 404 {object} apitypes.ErrorResponse "Inventory not found"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/myinventory/{id}/maintenance [post]
+func PostMyInventoryMaintenance(c *gin.Context) {
+	item, ok := findMyItem(c, "post my inventory maintenance")
+	if !ok {
+		return
+	}
+
+	var input MaintenanceEntryRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		helper.LogAndSanitize(err, "post my inventory maintenance: bind JSON failed")
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": helper.ErrMsgBadRequest})
+		return
+	}
+
+	if err := validateDate(input.PerformedOn); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	currency := item.Currency
+	if input.Currency != "" {
+		normalized, err := normalizeCurrency(input.Currency)
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		currency = normalized
+	}
+
+	entry := MaintenanceEntry{
+		ItemID:      item.ID,
+		PerformedOn: input.PerformedOn,
+		Action:      input.Action,
+		Notes:       input.Notes,
+		Cost:        input.Cost,
+		Currency:    currency,
+	}
+	if err := insertMaintenanceEntry(c.Request.Context(), &entry); err != nil {
+		helper.LogAndSanitize(err, "post my inventory maintenance: insert entry failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, entry)
+}
+
+// DeleteMyInventoryMaintenance deletes a maintenance entry of an item
+// @Summary Delete a maintenance entry
+// @Description Removes an entry from the maintenance log of an item of the user
+// @Security Bearer
+// @Tags Inventories
+// @Produce json
+// @Param id path int true "Inventory ID"
+// @Param entry_id path int true "Maintenance entry ID"
+// @Success 200 {object} apitypes.OkResponse "Maintenance entry deleted"
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 403 {object} apitypes.ErrorResponse "This item does not belong to you"
+// @Failure 404 {object} apitypes.ErrorResponse "Inventory or maintenance entry not found"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/myinventory/{id}/maintenance/{entry_id} [delete]
+func DeleteMyInventoryMaintenance(c *gin.Context) {
+	item, ok := findMyItem(c, "delete my inventory maintenance")
+	if !ok {
+		return
+	}
+
+	entryID, err := helper.StringToUint(c.Param("entry_id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	err = deleteMaintenanceEntry(c.Request.Context(), item.ID, entryID)
+	if err != nil {
+		if errors.Is(err, ErrMaintenanceEntryNotFound) {
+			c.IndentedJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		helper.LogAndSanitize(err, "delete my inventory maintenance: delete entry failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, gin.H{"message": "Maintenance entry deleted"})
+}
+
+// GetMyMaintenanceDue gets the items due for maintenance
+// @Summary Get the items due for maintenance
+// @Description Lists the items in service of the user whose maintenance interval has elapsed,
+// @Description or elapses within within_days days, counted from the last maintenance,
+// @Description else the purchase date, else the creation of the item. Most overdue first.
+// @Security Bearer
+// @Tags Inventories
+// @Produce json
+// @Param within_days query int false "Also list the items due within this number of days"
+// @Success 200 {array} inventories.MaintenanceDue
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid within_days"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/myinventory/maintenance/due [get]
+func GetMyMaintenanceDue(c *gin.Context) {
+	userID, err := security.ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, "get my maintenance due: extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return
+	}
+
+	withinDays := 0
+	if raw := c.Query("within_days"); raw != "" {
+		withinDays, err = strconv.Atoi(raw)
+		if err != nil || withinDays < 0 || withinDays > maxMaintenanceWithinDays {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid within_days"})
+			return
+		}
+	}
+
+	due, err := returnMaintenanceDue(c.Request.Context(), userID, withinDays)
+	if err != nil {
+		helper.LogAndSanitize(err, "get my maintenance due: return due items failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, due)
+}
+
+// findMyItem returns the item of the id path parameter, or responds with an
+// error and returns false unless it exists and belongs to the user
+func findMyItem(c *gin.Context, logCtx string) (*Inventory, bool) {
+	userID, err := security.ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, logCtx+": extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return nil, false
+	}
+
+	id, err := helper.StringToUint(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return nil, false
+	}
+
+	item, err := findInventoryByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrNoItemFound) {
+			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Inventory not found"})
+			return nil, false
+		}
+		helper.LogAndSanitize(err, logCtx+": find inventory failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return nil, false
+	}
+	if item.UserID != userID {
+		c.IndentedJSON(http.StatusForbidden, gin.H{"error": "This item does not belong to you"})
+		return nil, false
+	}
+	return item, true
+}
+
+// GetMyInventoryUsage gets the usage of an item
+// @Summary Get the usage of an item
+// @Description Sums the trips, nights and kilometres of the trips logged on packs containing the item,
+// @Description with the item price divided by each of them
+// @Security Bearer
+// @Tags Inventories
+// @Produce json
+// @Param id path int true "Inventory ID"
+// @Success 200 {object} inventories.ItemUsage
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 403 {object} apitypes.ErrorResponse "This item does not belong to you"
+// @Failure 404 {object} apitypes.ErrorResponse "Inventory not found"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/myinventory/{id}/usage [get]
+func GetMyInventoryUsage(c *gin.Context) {
+	item, ok := findMyItem(c, "get my inventory usage")
+	if !ok {
+		return
+	}
+
+	usage, err := returnItemUsage(c.Request.Context(), item)
+	if err != nil {
+		helper.LogAndSanitize(err, "get my inventory usage: return usage failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, usage)
+}
+
+// respondItemDetailsError maps applyItemDetails errors to an HTTP response
+func respondItemDetailsError(c *gin.Context, err error, logMsg string) {
+	if customfields.RespondValueError(c, err) {
+		return
+	}
+	for _, known := range []error{
+		ErrInvalidDate, ErrInvalidCondition, ErrInvalidMaintenanceInterval, products.ErrProductNotFound,
+	} {
+		if errors.Is(err, known) {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": known.Error()})
+			return
+		}
+	}
+	helper.LogAndSanitize(err, logMsg)
+	c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+}
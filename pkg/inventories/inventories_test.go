@@ -11,14 +11,17 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/Angak0k/pimpmypack/pkg/config"
+	"github.com/Angak0k/pimpmypack/pkg/customfields"
 	"github.com/Angak0k/pimpmypack/pkg/database"
 	"github.com/Angak0k/pimpmypack/pkg/security"
 	"github.com/gin-gonic/gin"
 	"github.com/google/go-cmp/cmp"
+	"github.com/lib/pq"
 )
 
 func TestMain(m *testing.M) {
@@ -283,6 +286,60 @@ func TestGetMyInventory(t *testing.T) {
 	})
 }
 
+func TestGetMyInventoryUnits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/myinventory", GetMyInventory)
+
+	token, err := security.GenerateToken(users[0].ID)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	getWithUnits := func(t *testing.T, query string) *httptest.ResponseRecorder {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, "/myinventory"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Imperial Weights Added", func(t *testing.T) {
+		w := getWithUnits(t, "?units=imperial")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d but got %d", http.StatusOK, w.Code)
+		}
+		var myInventories Inventories
+		if err := json.Unmarshal(w.Body.Bytes(), &myInventories); err != nil {
+			t.Fatalf("Failed to unmarshal response body: %v", err)
+		}
+		for _, item := range myInventories {
+			if item.WeightDisplay == nil || (item.WeightDisplay.Unit != "oz" && item.WeightDisplay.Unit != "lb") {
+				t.Errorf("Expected an imperial weight display for item %d but got %+v", item.ID, item.WeightDisplay)
+			}
+		}
+	})
+
+	t.Run("No Weights Without Units", func(t *testing.T) {
+		w := getWithUnits(t, "")
+		var myInventories Inventories
+		if err := json.Unmarshal(w.Body.Bytes(), &myInventories); err != nil {
+			t.Fatalf("Failed to unmarshal response body: %v", err)
+		}
+		if len(myInventories) > 0 && myInventories[0].WeightDisplay != nil {
+			t.Errorf("Expected no weight display but got %+v", myInventories[0].WeightDisplay)
+		}
+	})
+
+	t.Run("Invalid Units", func(t *testing.T) {
+		w := getWithUnits(t, "?units=stone")
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d but got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+}
+
 func TestGetInventoryByID(t *testing.T) {
 	// Set Gin to test mode
 	gin.SetMode(gin.TestMode)
@@ -457,6 +514,53 @@ func TestPostInventory(t *testing.T) {
 	})
 }
 
+func TestPostInventoryCurrency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.POST("/inventories", PostInventory)
+
+	postWithCurrency := func(t *testing.T, currency string) *httptest.ResponseRecorder {
+		t.Helper()
+		body, err := json.Marshal(InventoryCreateAdminRequest{
+			UserID:   users[0].ID,
+			ItemName: "Spork",
+			Category: "Kitchen",
+			Weight:   12,
+			Price:    1500,
+			Currency: currency,
+		})
+		if err != nil {
+			t.Fatalf("Failed to marshal inventory data: %v", err)
+		}
+		req, _ := http.NewRequest(http.MethodPost, "/inventories", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Normalize ISO 4217 Currency", func(t *testing.T) {
+		w := postWithCurrency(t, "chf")
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status code %d but got %d", http.StatusCreated, w.Code)
+		}
+		var receivedInventory Inventory
+		if err := json.Unmarshal(w.Body.Bytes(), &receivedInventory); err != nil {
+			t.Fatalf("Failed to unmarshal response body: %v", err)
+		}
+		if receivedInventory.Currency != "CHF" {
+			t.Errorf("Expected Currency CHF but got %v", receivedInventory.Currency)
+		}
+	})
+
+	t.Run("Reject Unknown Currency", func(t *testing.T) {
+		w := postWithCurrency(t, "XYZ")
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d but got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+}
+
 func TestPutInventoryByID(t *testing.T) {
 	// Set Gin to test mode
 	gin.SetMode(gin.TestMode)
@@ -1184,3 +1288,925 @@ func TestFindInventoryItemByAttributes(t *testing.T) {
 		testFindEmptyDescription(ctx, t)
 	})
 }
+
+func TestNameSimilarity(t *testing.T) {
+	if got := NameSimilarity("Sawyer Squeeze", "sawyer squeeze"); got != 1 {
+		t.Errorf("expected identical names to score 1, got %v", got)
+	}
+	if got := NameSimilarity("Tent", ""); got != 0 {
+		t.Errorf("expected an empty name to score 0, got %v", got)
+	}
+
+	near := NameSimilarity("Sawyer Squeeze", "Sawyer Squeeze Filter")
+	far := NameSimilarity("Sawyer Squeeze", "Titanium Pot")
+	if near < 0.4 {
+		t.Errorf("expected near duplicates to score at least 0.4, got %v", near)
+	}
+	if far >= 0.4 {
+		t.Errorf("expected unrelated names to score below 0.4, got %v", far)
+	}
+	if got := NameSimilarity("Sawyer-Squeeze", "Squeeze, Sawyer"); got != 1 {
+		t.Errorf("expected word order and punctuation to be ignored, got %v", got)
+	}
+}
+
+func TestFindDuplicateGroups(t *testing.T) {
+	items := Inventories{
+		{ID: 1, ItemName: "Sawyer Squeeze", Category: "Water", Weight: 85, PackCount: 1},
+		{ID: 2, ItemName: "Sawyer Squeeze Filter", Category: "water", Weight: 90, PackCount: 3},
+		{ID: 3, ItemName: "Squeeze filter (Sawyer)", Category: "Water", Weight: 0},
+		{ID: 4, ItemName: "Titanium Pot", Category: "Kitchen", Weight: 110},
+		{ID: 5, ItemName: "Toaks 750", Category: "Kitchen", Weight: 103, URL: "https://www.toaks.com/pot-750/"},
+		{ID: 6, ItemName: "Toaks Pot", Category: "Cooking", Weight: 103, URL: "http://toaks.com/pot-750"},
+		{ID: 7, ItemName: "Head Lamp", Category: "Electronics", Weight: 45},
+	}
+
+	groups := findDuplicateGroups(items)
+	if len(groups) != 2 {
+		t.Fatalf("Expected 2 duplicate groups, got %d: %+v", len(groups), groups)
+	}
+
+	ids := func(g DuplicateGroup) []uint {
+		var out []uint
+		for _, item := range g.Items {
+			out = append(out, item.ID)
+		}
+		return out
+	}
+	byTarget := map[uint]DuplicateGroup{}
+	for _, g := range groups {
+		byTarget[g.SuggestedTargetID] = g
+	}
+
+	sawyer, ok := byTarget[2]
+	if !ok {
+		t.Fatalf("Expected the filter group to suggest item 2, the most used, got %+v", groups)
+	}
+	if diff := cmp.Diff([]uint{1, 2, 3}, ids(sawyer)); diff != "" {
+		t.Errorf("Unexpected filter group items (-want +got):\n%s", diff)
+	}
+
+	toaks, ok := byTarget[5]
+	if !ok {
+		t.Fatalf("Expected a group for the items sharing a URL, got %+v", groups)
+	}
+	want := []string{DuplicateReasonWeight, DuplicateReasonURL, DuplicateReasonName}
+	if diff := cmp.Diff(want, toaks.Reasons); diff != "" {
+		t.Errorf("Unexpected reasons for the URL group (-want +got):\n%s", diff)
+	}
+
+	for _, g := range groups {
+		if g.Confidence < duplicateThreshold || g.Confidence > 1 {
+			t.Errorf("Expected confidence between %v and 1, got %v", duplicateThreshold, g.Confidence)
+		}
+	}
+	if groups[0].Confidence < groups[1].Confidence {
+		t.Errorf("Expected groups sorted by confidence, got %v then %v", groups[0].Confidence, groups[1].Confidence)
+	}
+}
+
+func TestPostMyInventoryBatchMerge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	t.Run("Merge two sources - quantities summed and selected image kept", func(t *testing.T) {
+		testBatchMerge(ctx, t)
+	})
+	t.Run("Merge fails - invalid requests", func(t *testing.T) {
+		testBatchMergeInvalid(ctx, t)
+	})
+}
+
+func testBatchMerge(ctx context.Context, t *testing.T) {
+	if err := createMergeTestData(ctx); err != nil {
+		t.Fatalf("Failed to create merge test data: %v", err)
+	}
+	defer func() {
+		if err := cleanupMergeTestData(ctx); err != nil {
+			t.Errorf("Failed to cleanup merge test data: %v", err)
+		}
+	}()
+
+	source := mergeTestItems[0]
+	target := mergeTestItems[1]
+	third := Inventory{UserID: users[0].ID, ItemName: "Merge Third", Category: "Test", Weight: 150, Currency: "USD"}
+	if err := InsertInventory(ctx, &third); err != nil {
+		t.Fatalf("Failed to insert third item: %v", err)
+	}
+	mergeTestItems = append(mergeTestItems, third)
+	if _, err := database.DB().ExecContext(ctx,
+		`INSERT INTO pack_content (pack_id, item_id, quantity, worn, consumable, created_at, updated_at)
+		VALUES ($1, $2, 4, false, false, NOW(), NOW());`,
+		mergeTestPackIDs[0], third.ID); err != nil {
+		t.Fatalf("Failed to insert third item into shared pack: %v", err)
+	}
+	now := time.Now()
+	insertTestImage(ctx, t, target.ID, "fake-target-image", now)
+	insertTestImage(ctx, t, third.ID, "fake-third-image", now)
+
+	w := executeBatchMergeRequest(t, BatchMergeInventoryRequest{
+		SourceItemIDs:     []uint{source.ID, third.ID},
+		TargetItemID:      target.ID,
+		ItemName:          "Batch Merged",
+		Category:          "Test",
+		Weight:            150,
+		Currency:          "USD",
+		ImageSource:       ImageSourceSource,
+		ImageSourceItemID: third.ID,
+	}, users[0].ID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d but got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var mergedItem Inventory
+	if err := json.Unmarshal(w.Body.Bytes(), &mergedItem); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if mergedItem.ItemName != "Batch Merged" || mergedItem.PackCount != 2 || !mergedItem.HasImage {
+		t.Errorf("Unexpected merged item: %+v", mergedItem)
+	}
+
+	for _, id := range []uint{source.ID, third.ID} {
+		if _, err := findInventoryByID(ctx, id); !errors.Is(err, ErrNoItemFound) {
+			t.Errorf("Expected source item %d to be deleted, got err: %v", id, err)
+		}
+	}
+
+	// Shared pack quantities are summed (3 + 2 + 4 = 9)
+	var quantity int
+	var imageData []byte
+	if err := database.DB().QueryRowContext(ctx,
+		`SELECT quantity FROM pack_content WHERE pack_id = $1 AND item_id = $2`,
+		mergeTestPackIDs[0], target.ID).Scan(&quantity); err != nil {
+		t.Fatalf("Failed to query pack_content: %v", err)
+	}
+	if quantity != 9 {
+		t.Errorf("Expected quantity 9 in shared pack but got %d", quantity)
+	}
+	if err := database.DB().QueryRowContext(ctx,
+		`SELECT image_data FROM inventory_images WHERE item_id = $1`, target.ID).Scan(&imageData); err != nil {
+		t.Fatalf("Failed to query image: %v", err)
+	}
+	if string(imageData) != "fake-third-image" {
+		t.Errorf("Expected the image of the selected source, got %q", imageData)
+	}
+}
+
+func testBatchMergeInvalid(ctx context.Context, t *testing.T) {
+	if err := createMergeTestData(ctx); err != nil {
+		t.Fatalf("Failed to create merge test data: %v", err)
+	}
+	defer func() {
+		if err := cleanupMergeTestData(ctx); err != nil {
+			t.Errorf("Failed to cleanup merge test data: %v", err)
+		}
+	}()
+
+	source := mergeTestItems[0].ID
+	target := mergeTestItems[1].ID
+	base := BatchMergeInventoryRequest{TargetItemID: target, ItemName: "X", Category: "Test", ImageSource: "target"}
+	tests := []struct {
+		name    string
+		sources []uint
+		image   uint
+		userID  uint
+		status  int
+	}{
+		{"target among sources", []uint{source, target}, 0, users[0].ID, http.StatusBadRequest},
+		{"repeated source", []uint{source, source}, 0, users[0].ID, http.StatusBadRequest},
+		{"image of an item not merged", []uint{source}, 999999, users[0].ID, http.StatusBadRequest},
+		{"source not found", []uint{999999}, 0, users[0].ID, http.StatusNotFound},
+		{"items of another user", []uint{source}, 0, users[1].ID, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := base
+		req.SourceItemIDs = tt.sources
+		if tt.image != 0 {
+			req.ImageSource = ImageSourceSource
+			req.ImageSourceItemID = tt.image
+		}
+		if w := executeBatchMergeRequest(t, req, tt.userID); w.Code != tt.status {
+			t.Errorf("%s: expected status %d but got %d", tt.name, tt.status, w.Code)
+		}
+	}
+}
+
+// executeBatchMergeRequest sends a batch merge request and returns the recorder.
+func executeBatchMergeRequest(
+	t *testing.T,
+	mergeReq BatchMergeInventoryRequest,
+	userID uint,
+) *httptest.ResponseRecorder {
+	t.Helper()
+
+	router := gin.Default()
+	router.POST("/myinventory/merge/batch", PostMyInventoryBatchMerge)
+
+	token, err := security.GenerateToken(userID)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	jsonData, err := json.Marshal(mergeReq)
+	if err != nil {
+		t.Fatalf("Failed to marshal merge request: %v", err)
+	}
+
+	req, err := http.NewRequest(
+		http.MethodPost, "/myinventory/merge/batch", bytes.NewBuffer(jsonData))
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestParseInventoryFile(t *testing.T) {
+	csvData := []byte("\ufeffItem_Name,category,weight,price,currency,external_key,pack_count\n" +
+		"Tent,Shelter,1200,34999,usd,tent-1,2\n" +
+		",Shelter,10,,,,\n" +
+		"\n" +
+		"Stove,Kitchen,heavy,,,,\n" +
+		"Pot,Kitchen,110,,XYZ,,\n" +
+		"Tarp,Shelter,300,,,tent-1,\n")
+
+	rows, rowErrors, err := parseInventoryFile(csvData, "closet.csv")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := []inventoryImportRow{{row: 2, item: InventoryTransferItem{
+		ExternalKey: "tent-1", ItemName: "Tent", Category: "Shelter", Weight: 1200, Price: 34999, Currency: "USD",
+	}, present: map[string]bool{"weight": true, "price": true}}}
+	if diff := cmp.Diff(want, rows, cmp.AllowUnexported(inventoryImportRow{})); diff != "" {
+		t.Errorf("Unexpected rows (-want +got):\n%s", diff)
+	}
+	wantErrors := []InventoryImportRowError{
+		{Row: 3, Error: "missing item_name"},
+		{Row: 5, Error: `invalid weight "heavy"`},
+		{Row: 6, Error: `invalid currency "XYZ"`},
+		{Row: 7, Error: "external_key already used on row 2"},
+	}
+	if diff := cmp.Diff(wantErrors, rowErrors); diff != "" {
+		t.Errorf("Unexpected row errors (-want +got):\n%s", diff)
+	}
+
+	for name, data := range map[string]string{
+		"array":  `[{"item_name":"Quilt","category":"Sleep","weight":560}]`,
+		"object": `{"items":[{"item_name":"Quilt","category":"Sleep","weight":560}]}`,
+	} {
+		jsonRows, _, err := parseInventoryFile([]byte(data), "closet.json")
+		if err != nil || len(jsonRows) != 1 || jsonRows[0].row != 1 || jsonRows[0].item.Weight != 560 {
+			t.Errorf("%s: unexpected rows %+v, err %v", name, jsonRows, err)
+		}
+		if diff := cmp.Diff(map[string]bool{"weight": true}, jsonRows[0].present); diff != "" {
+			t.Errorf("%s: unexpected present fields (-want +got):\n%s", name, diff)
+		}
+	}
+
+	for data, wantErr := range map[string]error{
+		"name,weight\nTent,1\n":    ErrNoItemNameColumn,
+		"item_name,category\n,x\n": ErrNoValidInventoryRows,
+		`[{"item_name": 1}]`:       ErrUnreadableInventoryFile,
+	} {
+		if _, _, err := parseInventoryFile([]byte(data), "closet"); !errors.Is(err, wantErr) {
+			t.Errorf("Expected %v for %q, got %v", wantErr, data, err)
+		}
+	}
+}
+
+func TestWriteInventoryCSV(t *testing.T) {
+	items := []InventoryTransferItem{{
+		ExternalKey: "tent-1", ItemName: "Tent, 2p", Category: "Shelter", Weight: 1200, Price: 34999,
+		Currency: "USD", PackCount: 3, CustomFields: customfields.Values{"temp_rating": -5.5, "freestanding": true},
+	}}
+	fields := customfields.Fields{
+		{Key: "temp_rating", Type: customfields.TypeNumber},
+		{Key: "freestanding", Type: customfields.TypeBoolean},
+		{Key: "fabric", Type: customfields.TypeText},
+	}
+	var buf bytes.Buffer
+	if err := writeInventoryCSV(&buf, items, []string{"temp_rating", "freestanding", "fabric"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := "external_key,item_name,category,description,weight,url,price,currency,pack_count," +
+		"field.temp_rating,field.freestanding,field.fabric\n" +
+		"tent-1,\"Tent, 2p\",Shelter,,1200,,34999,USD,3,-5.5,true,\n"
+	if buf.String() != want {
+		t.Errorf("Unexpected CSV:\n%s", buf.String())
+	}
+
+	rows, _, err := parseInventoryFile(buf.Bytes(), "inventory.csv")
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("Expected the export to import back, got %+v, err %v", rows, err)
+	}
+	rows[0].item.CustomFields, err = fields.Normalize(rows[0].item.CustomFields)
+	if err != nil {
+		t.Fatalf("Expected the custom field values to normalize, got %v", err)
+	}
+	items[0].PackCount = 0
+	if diff := cmp.Diff(items[0], rows[0].item); diff != "" {
+		t.Errorf("Unexpected round trip (-want +got):\n%s", diff)
+	}
+}
+
+func TestImportExportMyInventory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	userID := users[0].ID
+	defer func() {
+		if _, err := database.DB().ExecContext(ctx,
+			`DELETE FROM inventory WHERE user_id = $1 AND item_name LIKE 'Closet %'`, userID); err != nil {
+			t.Errorf("Failed to cleanup imported items: %v", err)
+		}
+	}()
+
+	router := gin.New()
+	router.POST("/myinventory/import", ImportMyInventory)
+	router.GET("/myinventory/export", ExportMyInventory)
+	token, err := security.GenerateToken(userID)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	importItems := func(items []InventoryTransferItem) InventoryImportResponse {
+		t.Helper()
+		body, _ := json.Marshal(InventoryImportRequest{Items: items})
+		req, _ := http.NewRequest(http.MethodPost, "/myinventory/import", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d but got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var response InventoryImportResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		return response
+	}
+
+	first := importItems([]InventoryTransferItem{
+		{ExternalKey: "closet-tent", ItemName: "Closet Tent", Category: "Shelter", Weight: 1200},
+		{ItemName: "Closet Mug", Category: "Kitchen", Weight: 80, Currency: "usd"},
+	})
+	if first.Created != 2 || first.Updated != 0 {
+		t.Fatalf("Expected 2 created items, got %+v", first)
+	}
+
+	// Renamed item matched by key, unchanged item matched by attributes
+	second := importItems([]InventoryTransferItem{
+		{ExternalKey: "closet-tent", ItemName: "Closet Tent 2P", Category: "Shelter", Weight: 1150},
+		{ItemName: "Closet Mug", Category: "Kitchen", Weight: 75},
+	})
+	if second.Created != 0 || second.Updated != 2 {
+		t.Fatalf("Expected 2 updated items, got %+v", second)
+	}
+
+	// Fields missing from the file keep their value
+	partial := `{"items":[{"external_key":"closet-tent","item_name":"Closet Tent 2P","category":"Shelter"}]}`
+	req, _ := http.NewRequest(http.MethodPost, "/myinventory/import", bytes.NewBufferString(partial))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d but got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "/myinventory/export?format=csv", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d but got %d", http.StatusOK, w.Code)
+	}
+	for _, line := range []string{
+		"closet-tent,Closet Tent 2P,Shelter,,1150,,0,EUR,0\n",
+		",Closet Mug,Kitchen,,75,,0,USD,0\n",
+	} {
+		if !bytes.Contains(w.Body.Bytes(), []byte(line)) {
+			t.Errorf("Expected export to contain %q, got:\n%s", line, w.Body.String())
+		}
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "/myinventory/export?format=xml", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an unknown format but got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestInventoryLifecycleInputApply(t *testing.T) {
+	date, empty, condition, badCondition := "2024-05-01", "", "fair", "worn out"
+	interval, zero, negative, tooLong := 90, 0, -1, 36501
+	retired := true
+
+	item := Inventory{}
+	input := InventoryLifecycleInput{
+		PurchaseDate: &date, Condition: &condition, Retired: &retired, MaintenanceIntervalDays: &interval,
+	}
+	if err := input.applyTo(&item); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if *item.PurchaseDate != date || *item.Condition != condition || !item.Retired ||
+		*item.MaintenanceIntervalDays != interval {
+		t.Fatalf("Lifecycle fields not applied: %+v", item)
+	}
+
+	// Omitted fields are kept, empty ones are cleared
+	input = InventoryLifecycleInput{PurchaseDate: &empty, MaintenanceIntervalDays: &zero}
+	if err := input.applyTo(&item); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if item.PurchaseDate != nil || item.MaintenanceIntervalDays != nil {
+		t.Errorf("Expected purchase date and interval to be cleared: %+v", item)
+	}
+	if item.Condition == nil || !item.Retired {
+		t.Errorf("Expected condition and retired to be kept: %+v", item)
+	}
+
+	invalidDate := "01/05/2024"
+	for input, want := range map[*InventoryLifecycleInput]error{
+		{PurchaseDate: &invalidDate}:         ErrInvalidDate,
+		{Condition: &badCondition}:           ErrInvalidCondition,
+		{MaintenanceIntervalDays: &negative}: ErrInvalidMaintenanceInterval,
+		{MaintenanceIntervalDays: &tooLong}:  ErrInvalidMaintenanceInterval,
+	} {
+		if err := input.applyTo(&Inventory{}); !errors.Is(err, want) {
+			t.Errorf("Expected %v, got %v", want, err)
+		}
+	}
+}
+
+func TestInventoryMaintenance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	userID := users[0].ID
+
+	purchased := time.Now().AddDate(0, 0, -60).Format(dateLayout)
+	interval := 30
+	due := Inventory{
+		UserID: userID, ItemName: "Maintained Stove", Category: "Kitchen", Weight: 90, Currency: "EUR",
+		PurchaseDate: &purchased, MaintenanceIntervalDays: &interval,
+	}
+	retired := Inventory{
+		UserID: userID, ItemName: "Retired Stove", Category: "Kitchen", Weight: 120, Currency: "EUR",
+		PurchaseDate: &purchased, MaintenanceIntervalDays: &interval, Retired: true,
+	}
+	for _, item := range []*Inventory{&due, &retired} {
+		if err := InsertInventory(ctx, item); err != nil {
+			t.Fatalf("Failed to insert item: %v", err)
+		}
+	}
+	defer func() {
+		if _, err := database.DB().ExecContext(ctx,
+			`DELETE FROM inventory WHERE id = ANY($1)`, pq.Array([]int64{int64(due.ID), int64(retired.ID)})); err != nil {
+			t.Errorf("Failed to cleanup items: %v", err)
+		}
+	}()
+
+	router := gin.New()
+	router.GET("/myinventory", GetMyInventory)
+	router.GET("/myinventory/maintenance/due", GetMyMaintenanceDue)
+	router.GET("/myinventory/:id/maintenance", GetMyInventoryMaintenance)
+	router.POST("/myinventory/:id/maintenance", PostMyInventoryMaintenance)
+	router.DELETE("/myinventory/:id/maintenance/:entry_id", DeleteMyInventoryMaintenance)
+	token, err := security.GenerateToken(userID)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	// Retired items are hidden from the inventory and the due list
+	w := serveMaintenanceRequest(t, router, token, http.MethodGet, "/myinventory", nil)
+	if bytes.Contains(w.Body.Bytes(), []byte("Retired Stove")) {
+		t.Error("Expected retired item to be hidden from the inventory")
+	}
+	w = serveMaintenanceRequest(t, router, token, http.MethodGet, "/myinventory?include_retired=true", nil)
+	if !bytes.Contains(w.Body.Bytes(), []byte("Retired Stove")) {
+		t.Error("Expected retired item to be listed with include_retired")
+	}
+	if ids := maintenanceDueItemIDs(t, router, token); !slices.Contains(ids, due.ID) || slices.Contains(ids, retired.ID) {
+		t.Errorf("Expected only the item in service to be due, got %v", ids)
+	}
+
+	t.Run("maintenance log", func(t *testing.T) {
+		testMaintenanceLog(t, router, token, due.ID)
+	})
+}
+
+func testMaintenanceLog(t *testing.T, router *gin.Engine, token string, itemID uint) {
+	url := fmt.Sprintf("/myinventory/%d/maintenance", itemID)
+
+	// Logging a maintenance today postpones the next one
+	w := serveMaintenanceRequest(t, router, token, http.MethodPost, url, MaintenanceEntryRequest{
+		PerformedOn: time.Now().Format(dateLayout), Action: "Cleaned jet", Cost: 500,
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d but got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var entry MaintenanceEntry
+	if err := json.Unmarshal(w.Body.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if entry.Currency != "EUR" {
+		t.Errorf("Expected the item currency, got %q", entry.Currency)
+	}
+	if ids := maintenanceDueItemIDs(t, router, token); slices.Contains(ids, itemID) {
+		t.Errorf("Expected item not to be due after maintenance, got %v", ids)
+	}
+
+	w = serveMaintenanceRequest(t, router, token, http.MethodPost, url, MaintenanceEntryRequest{
+		PerformedOn: "yesterday", Action: "Cleaned jet",
+	})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an invalid date but got %d", http.StatusBadRequest, w.Code)
+	}
+
+	w = serveMaintenanceRequest(t, router, token, http.MethodGet, url, nil)
+	var entries []MaintenanceEntry
+	if err := json.Unmarshal(w.Body.Bytes(), &entries); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Action != "Cleaned jet" {
+		t.Errorf("Expected the logged entry, got %+v", entries)
+	}
+
+	entryURL := fmt.Sprintf("%s/%d", url, entry.ID)
+	if w = serveMaintenanceRequest(t, router, token, http.MethodDelete, entryURL, nil); w.Code != http.StatusOK {
+		t.Errorf("Expected status %d but got %d", http.StatusOK, w.Code)
+	}
+	if w = serveMaintenanceRequest(t, router, token, http.MethodDelete, entryURL, nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for a deleted entry but got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func serveMaintenanceRequest(
+	t *testing.T, router *gin.Engine, token, method, url string, body any,
+) *httptest.ResponseRecorder {
+	t.Helper()
+	payload, _ := json.Marshal(body)
+	req, _ := http.NewRequest(method, url, bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func maintenanceDueItemIDs(t *testing.T, router *gin.Engine, token string) []uint {
+	t.Helper()
+	w := serveMaintenanceRequest(t, router, token, http.MethodGet, "/myinventory/maintenance/due", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d but got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var items []MaintenanceDue
+	if err := json.Unmarshal(w.Body.Bytes(), &items); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	ids := []uint{}
+	for _, item := range items {
+		ids = append(ids, item.ItemID)
+	}
+	return ids
+}
+
+func TestItemUsageCostsPerUse(t *testing.T) {
+	usage := ItemUsage{Trips: 3, Nights: 4, DistanceKm: 200, Price: 15000, Currency: "EUR"}
+	usage.setCostsPerUse()
+	if *usage.CostPerUse != 5000 || *usage.CostPerNight != 3750 || *usage.CostPerKm != 75 {
+		t.Errorf("Unexpected costs per use: %d, %d, %d", *usage.CostPerUse, *usage.CostPerNight, *usage.CostPerKm)
+	}
+
+	unused := ItemUsage{Price: 15000}
+	unused.setCostsPerUse()
+	if unused.CostPerUse != nil || unused.CostPerNight != nil || unused.CostPerKm != nil {
+		t.Errorf("Expected no costs per use without trips: %+v", unused)
+	}
+}
+
+func TestGetMyInventoryUsage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	userID := users[0].ID
+
+	shoes := Inventory{
+		UserID: userID, ItemName: "Trail Shoes", Category: "Footwear", Weight: 600, Price: 15000, Currency: "EUR",
+	}
+	if err := InsertInventory(ctx, &shoes); err != nil {
+		t.Fatalf("Failed to insert item: %v", err)
+	}
+	long, short := 180, 20
+	var tripIDs []int64
+	defer func() {
+		if _, err := database.DB().ExecContext(ctx, `DELETE FROM pack_trip WHERE id = ANY($1)`,
+			pq.Array(tripIDs)); err != nil {
+			t.Errorf("Failed to cleanup trips: %v", err)
+		}
+		if err := deleteInventoryByID(ctx, shoes.ID); err != nil {
+			t.Errorf("Failed to cleanup item: %v", err)
+		}
+	}()
+
+	for _, trip := range []struct {
+		start, end string
+		nights     int
+		distance   *int
+	}{
+		{"2024-07-01", "2024-07-04", 3, &long},
+		{"2024-08-10", "2024-08-11", 1, &short},
+		{"2024-09-01", "2024-09-01", 0, nil},
+	} {
+		var tripID int64
+		err := database.DB().QueryRowContext(ctx,
+			`INSERT INTO pack_trip (user_id, pack_id, start_date, end_date, nights, distance_km)
+			VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+			userID, testPackIDs[0], trip.start, trip.end, trip.nights, trip.distance).Scan(&tripID)
+		if err != nil {
+			t.Fatalf("Failed to insert trip: %v", err)
+		}
+		tripIDs = append(tripIDs, tripID)
+		if _, err := database.DB().ExecContext(ctx,
+			`INSERT INTO pack_trip_item (trip_id, item_id, quantity) VALUES ($1, $2, 1)`, tripID, shoes.ID); err != nil {
+			t.Fatalf("Failed to insert trip item: %v", err)
+		}
+	}
+
+	router := gin.New()
+	router.GET("/myinventory/:id/usage", GetMyInventoryUsage)
+	token, err := security.GenerateToken(userID)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	w := serveMaintenanceRequest(t, router, token, http.MethodGet, fmt.Sprintf("/myinventory/%d/usage", shoes.ID), nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d but got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var usage ItemUsage
+	if err := json.Unmarshal(w.Body.Bytes(), &usage); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if usage.Trips != 3 || usage.Nights != 4 || usage.DistanceKm != 200 {
+		t.Errorf("Unexpected usage totals: %+v", usage)
+	}
+	if usage.CostPerUse == nil || *usage.CostPerUse != 5000 {
+		t.Errorf("Expected a cost per use of 5000, got %+v", usage)
+	}
+	if usage.FirstUsed == nil || *usage.FirstUsed != "2024-07-01" || usage.LastUsed == nil ||
+		*usage.LastUsed != "2024-09-01" {
+		t.Errorf("Unexpected first and last use: %+v", usage)
+	}
+}
+
+func TestMyInventoryCustomFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	userID := users[0].ID
+
+	if _, err := database.DB().ExecContext(ctx,
+		`INSERT INTO custom_field (user_id, key, name, field_type, unit) VALUES
+			($1, 'temp_rating', 'Temperature rating', 'number', '°C'),
+			($1, 'fabric', 'Fabric', 'text', NULL);`, userID); err != nil {
+		t.Fatalf("Failed to insert custom fields: %v", err)
+	}
+	var itemIDs []int64
+	defer func() {
+		if _, err := database.DB().ExecContext(ctx,
+			`DELETE FROM inventory WHERE id = ANY($1)`, pq.Array(itemIDs)); err != nil {
+			t.Errorf("Failed to cleanup items: %v", err)
+		}
+		if _, err := database.DB().ExecContext(ctx,
+			`DELETE FROM custom_field WHERE user_id = $1`, userID); err != nil {
+			t.Errorf("Failed to cleanup custom fields: %v", err)
+		}
+	}()
+
+	router := gin.New()
+	router.GET("/myinventory", GetMyInventory)
+	router.POST("/myinventory", PostMyInventory)
+	router.PUT("/myinventory/:id", PutMyInventoryByID)
+	token, err := security.GenerateToken(userID)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	for name, values := range map[string]customfields.Values{
+		"Summer Quilt": {"temp_rating": "10", "fabric": "10D nylon"},
+		"Winter Bag":   {"temp_rating": -12.5},
+	} {
+		w := serveMaintenanceRequest(t, router, token, http.MethodPost, "/myinventory", InventoryCreateRequest{
+			ItemName: name, Category: "Sleep", Weight: 700, Currency: "EUR", CustomFields: values,
+		})
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d but got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+		var item Inventory
+		if err := json.Unmarshal(w.Body.Bytes(), &item); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		itemIDs = append(itemIDs, int64(item.ID))
+	}
+
+	w := serveMaintenanceRequest(t, router, token, http.MethodPost, "/myinventory", InventoryCreateRequest{
+		ItemName: "Bad Quilt", Category: "Sleep", Weight: 700, Currency: "EUR",
+		CustomFields: customfields.Values{"temp_rating": "cold"},
+	})
+	if w.Code != http.StatusBadRequest || !bytes.Contains(w.Body.Bytes(), []byte("temp_rating")) {
+		t.Errorf("Expected a bad request naming the field, got %d: %s", w.Code, w.Body.String())
+	}
+
+	filtered := func(query string) []string {
+		t.Helper()
+		w := serveMaintenanceRequest(t, router, token, http.MethodGet, "/myinventory?"+query, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d for %q but got %d. Body: %s", http.StatusOK, query, w.Code, w.Body.String())
+		}
+		var items Inventories
+		if err := json.Unmarshal(w.Body.Bytes(), &items); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		var names []string
+		for _, item := range items {
+			names = append(names, item.ItemName)
+		}
+		return names
+	}
+	if names := filtered("field.temp_rating=..0"); !slices.Equal(names, []string{"Winter Bag"}) {
+		t.Errorf("Expected only the winter bag below 0°C, got %v", names)
+	}
+	if names := filtered("field.fabric=NYLON&field.temp_rating=5.."); !slices.Equal(names, []string{"Summer Quilt"}) {
+		t.Errorf("Expected only the nylon quilt, got %v", names)
+	}
+	if w := serveMaintenanceRequest(t, router, token, http.MethodGet, "/myinventory?field.volume=30", nil); w.Code !=
+		http.StatusBadRequest {
+		t.Errorf("Expected status %d for an unknown field but got %d", http.StatusBadRequest, w.Code)
+	}
+
+	// Updates without custom_fields keep the values
+	w = serveMaintenanceRequest(t, router, token, http.MethodPut, fmt.Sprintf("/myinventory/%d", itemIDs[1]),
+		InventoryUpdateRequest{ItemName: "Winter Bag", Category: "Sleep", Weight: 650, Currency: "EUR"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d but got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	item, err := findInventoryByID(ctx, uint(itemIDs[1]))
+	if err != nil {
+		t.Fatalf("Failed to find item: %v", err)
+	}
+	if diff := cmp.Diff(customfields.Values{"temp_rating": -12.5}, item.CustomFields); diff != "" {
+		t.Errorf("Unexpected custom field values (-want +got):\n%s", diff)
+	}
+}
+
+func TestPostMyInventoryProduct(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	userID := users[0].ID
+
+	var productID uint
+	err := database.DB().QueryRowContext(ctx,
+		`INSERT INTO product (brand, model, variant, weight, url, source)
+		VALUES ('Inventorytest', 'Duplex', 'Zip', 539, 'https://example.com/duplex', 'admin') RETURNING id`,
+	).Scan(&productID)
+	if err != nil {
+		t.Fatalf("Failed to insert product: %v", err)
+	}
+	defer func() {
+		if _, err := database.DB().ExecContext(ctx, `DELETE FROM inventory WHERE product_id = $1`, productID); err != nil {
+			t.Errorf("Failed to cleanup items: %v", err)
+		}
+		if _, err := database.DB().ExecContext(ctx, `DELETE FROM product WHERE id = $1`, productID); err != nil {
+			t.Errorf("Failed to cleanup product: %v", err)
+		}
+	}()
+
+	router := gin.New()
+	router.POST("/myinventory", PostMyInventory)
+	router.GET("/myinventory/:id", GetMyInventoryByID)
+	token, err := security.GenerateToken(userID)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	unknown := productID + 1000000
+	w := serveMaintenanceRequest(t, router, token, http.MethodPost, "/myinventory", InventoryCreateRequest{
+		ItemName: "Catalog Tent", Category: "Shelter", Currency: "EUR", ProductID: &unknown,
+	})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an unknown product but got %d", http.StatusBadRequest, w.Code)
+	}
+
+	w = serveMaintenanceRequest(t, router, token, http.MethodPost, "/myinventory", InventoryCreateRequest{
+		ItemName: "Catalog Tent", Category: "Shelter", Currency: "EUR", ProductID: &productID,
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d but got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var created Inventory
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if created.Weight != 539 || created.URL != "https://example.com/duplex" {
+		t.Errorf("Expected the weight and URL of the product, got %d and %q", created.Weight, created.URL)
+	}
+
+	getItem := func(id uint) Inventory {
+		t.Helper()
+		w := serveMaintenanceRequest(t, router, token, http.MethodGet, fmt.Sprintf("/myinventory/%d", id), nil)
+		var item Inventory
+		if err := json.Unmarshal(w.Body.Bytes(), &item); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		return item
+	}
+
+	// The weight taken from the product was not measured: there is no median yet
+	item := getItem(created.ID)
+	if item.ProductID == nil || *item.ProductID != productID || item.ProductMedianWeight != nil {
+		t.Errorf("Expected the product without a median weight, got %+v", item)
+	}
+
+	w = serveMaintenanceRequest(t, router, token, http.MethodPost, "/myinventory", InventoryCreateRequest{
+		ItemName: "Catalog Tent", Category: "Shelter", Currency: "EUR", Weight: 560, ProductID: &productID,
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d but got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	item = getItem(created.ID)
+	if item.ProductMedianWeight == nil || *item.ProductMedianWeight != 560 {
+		t.Errorf("Expected the median of the measured item only, got %+v", item)
+	}
+}
+
+func TestMyInventoryWishlist(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/myinventory", GetMyInventory)
+	router.POST("/myinventory", PostMyInventory)
+	router.PUT("/myinventory/:id", PutMyInventoryByID)
+	router.DELETE("/myinventory/:id", DeleteMyInventoryByID)
+	token, err := security.GenerateToken(users[0].ID)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	wishlist := true
+	w := serveMaintenanceRequest(t, router, token, http.MethodPost, "/myinventory", InventoryCreateRequest{
+		ItemName: "Dream Tarp", Category: "Shelter", Weight: 250, Currency: "EUR",
+		InventoryLifecycleInput: InventoryLifecycleInput{Wishlist: &wishlist},
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d but got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var created Inventory
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	defer serveMaintenanceRequest(t, router, token, http.MethodDelete, fmt.Sprintf("/myinventory/%d", created.ID), nil)
+	if !created.Wishlist {
+		t.Errorf("Expected a wishlist item, got %+v", created)
+	}
+
+	listed := func(path string) (bool, bool) {
+		t.Helper()
+		w := serveMaintenanceRequest(t, router, token, http.MethodGet, path, nil)
+		var items Inventories
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &items); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+		}
+		found, owned := false, false
+		for _, item := range items {
+			found = found || item.ID == created.ID
+			owned = owned || !item.Wishlist
+		}
+		return found, owned
+	}
+
+	if found, _ := listed("/myinventory"); found {
+		t.Error("Expected the wishlist item to be hidden from the inventory")
+	}
+	if found, owned := listed("/myinventory?wishlist=true"); !found || owned {
+		t.Errorf("Expected only wishlist items with wishlist=true, found %v, owned %v", found, owned)
+	}
+
+	bought, purchaseDate := false, "2024-05-01"
+	w = serveMaintenanceRequest(t, router, token, http.MethodPut, fmt.Sprintf("/myinventory/%d", created.ID),
+		InventoryUpdateRequest{
+			ItemName: "Dream Tarp", Category: "Shelter", Weight: 250, Currency: "EUR",
+			InventoryLifecycleInput: InventoryLifecycleInput{Wishlist: &bought, PurchaseDate: &purchaseDate},
+		})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d but got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if found, _ := listed("/myinventory"); !found {
+		t.Error("Expected the bought item in the inventory")
+	}
+}
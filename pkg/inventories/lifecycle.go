@@ -0,0 +1,122 @@
+package inventories
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"time"
+)
+
+// dateLayout is the format of purchase and maintenance dates
+const dateLayout = "2006-01-02"
+
+// maxMaintenanceWithinDays bounds the look-ahead of the due maintenance listing
+const maxMaintenanceWithinDays = 3650
+
+// maxMaintenanceIntervalDays bounds the maintenance interval to a century,
+// keeping due dates computed from it within the date range
+const maxMaintenanceIntervalDays = 36500
+
+var (
+	// ErrInvalidDate is returned for a date not formatted as YYYY-MM-DD
+	ErrInvalidDate = errors.New("dates must be formatted as YYYY-MM-DD")
+	// ErrInvalidCondition is returned for an unknown item condition
+	ErrInvalidCondition = errors.New("condition must be new, good, fair, poor or broken")
+	// ErrInvalidMaintenanceInterval is returned for a negative or too long maintenance interval
+	ErrInvalidMaintenanceInterval = errors.New("maintenance_interval_days must be between 0 and 36500")
+	// ErrMaintenanceEntryNotFound is returned when a maintenance entry does not exist for the item
+	ErrMaintenanceEntryNotFound = errors.New("maintenance entry not found")
+)
+
+// applyTo validates the lifecycle fields that are set and copies them to the item
+func (in InventoryLifecycleInput) applyTo(i *Inventory) error {
+	if in.PurchaseDate != nil {
+		i.PurchaseDate = nil
+		if *in.PurchaseDate != "" {
+			if err := validateDate(*in.PurchaseDate); err != nil {
+				return err
+			}
+			i.PurchaseDate = in.PurchaseDate
+		}
+	}
+	if in.Condition != nil {
+		i.Condition = nil
+		if *in.Condition != "" {
+			if !slices.Contains(itemConditions, *in.Condition) {
+				return ErrInvalidCondition
+			}
+			i.Condition = in.Condition
+		}
+	}
+	if in.MaintenanceIntervalDays != nil {
+		if *in.MaintenanceIntervalDays < 0 || *in.MaintenanceIntervalDays > maxMaintenanceIntervalDays {
+			return ErrInvalidMaintenanceInterval
+		}
+		i.MaintenanceIntervalDays = nil
+		if *in.MaintenanceIntervalDays > 0 {
+			i.MaintenanceIntervalDays = in.MaintenanceIntervalDays
+		}
+	}
+	if in.Retired != nil {
+		i.Retired = *in.Retired
+	}
+	if in.Wishlist != nil {
+		i.Wishlist = *in.Wishlist
+	}
+	return nil
+}
+
+// keepDetails copies the lifecycle fields, custom field values and product of
+// an existing item, before an update applies the ones it sets
+func keepDetails(i *Inventory, existing *Inventory) {
+	i.PurchaseDate = existing.PurchaseDate
+	i.Condition = existing.Condition
+	i.Retired = existing.Retired
+	i.Wishlist = existing.Wishlist
+	i.MaintenanceIntervalDays = existing.MaintenanceIntervalDays
+	i.CustomFields = existing.CustomFields
+	i.ProductID = existing.ProductID
+}
+
+func validateDate(date string) error {
+	if _, err := time.Parse(dateLayout, date); err != nil {
+		return ErrInvalidDate
+	}
+	return nil
+}
+
+// withoutRetired returns the items in service
+func withoutRetired(items Inventories) Inventories {
+	inService := make(Inventories, 0, len(items))
+	for _, item := range items {
+		if !item.Retired {
+			inService = append(inService, item)
+		}
+	}
+	return inService
+}
+
+// onWishlist returns the wishlist items, or the owned ones when wishlist is false
+func onWishlist(items Inventories, wishlist bool) Inventories {
+	selected := make(Inventories, 0, len(items))
+	for _, item := range items {
+		if item.Wishlist == wishlist {
+			selected = append(selected, item)
+		}
+	}
+	return selected
+}
+
+// setCostsPerUse divides the item price by its usage
+func (u *ItemUsage) setCostsPerUse() {
+	costPer := func(count int) *int {
+		if count == 0 {
+			return nil
+		}
+		cost := int(math.Round(float64(u.Price) / float64(count)))
+		return &cost
+	}
+	u.CostPerUse = costPer(u.Trips)
+	u.CostPerNight = costPer(u.Nights)
+	u.CostPerKm = costPer(u.DistanceKm)
+}
@@ -25,6 +25,13 @@ func returnInventories(ctx context.Context) (*Inventories, error) {
 			i.url,
 			i.price,
 			i.currency,
+			TO_CHAR(i.purchase_date, 'YYYY-MM-DD'),
+			i.condition,
+			i.retired,
+			i.wishlist,
+			i.maintenance_interval_days,
+			i.custom_fields,
+			i.product_id,
 			CASE WHEN ii.item_id IS NOT NULL THEN true ELSE false END as has_image,
 			(SELECT COUNT(DISTINCT pc.pack_id) FROM pack_content pc WHERE pc.item_id = i.id) as pack_count,
 			i.created_at,
@@ -48,6 +55,13 @@ func returnInventories(ctx context.Context) (*Inventories, error) {
 			&inventory.URL,
 			&inventory.Price,
 			&inventory.Currency,
+			&inventory.PurchaseDate,
+			&inventory.Condition,
+			&inventory.Retired,
+			&inventory.Wishlist,
+			&inventory.MaintenanceIntervalDays,
+			&inventory.CustomFields,
+			&inventory.ProductID,
 			&inventory.HasImage,
 			&inventory.PackCount,
 			&inventory.CreatedAt,
@@ -79,6 +93,13 @@ func returnInventoriesByUserID(ctx context.Context, userID uint) (*Inventories,
 			i.url,
 			i.price,
 			i.currency,
+			TO_CHAR(i.purchase_date, 'YYYY-MM-DD'),
+			i.condition,
+			i.retired,
+			i.wishlist,
+			i.maintenance_interval_days,
+			i.custom_fields,
+			i.product_id,
 			CASE WHEN ii.item_id IS NOT NULL THEN true ELSE false END as has_image,
 			(SELECT COUNT(DISTINCT pc.pack_id) FROM pack_content pc WHERE pc.item_id = i.id) as pack_count,
 			i.created_at,
@@ -104,6 +125,13 @@ func returnInventoriesByUserID(ctx context.Context, userID uint) (*Inventories,
 			&inventory.URL,
 			&inventory.Price,
 			&inventory.Currency,
+			&inventory.PurchaseDate,
+			&inventory.Condition,
+			&inventory.Retired,
+			&inventory.Wishlist,
+			&inventory.MaintenanceIntervalDays,
+			&inventory.CustomFields,
+			&inventory.ProductID,
 			&inventory.HasImage,
 			&inventory.PackCount,
 			&inventory.CreatedAt,
@@ -135,6 +163,13 @@ func findInventoryByID(ctx context.Context, id uint) (*Inventory, error) {
 			i.url,
 			i.price,
 			i.currency,
+			TO_CHAR(i.purchase_date, 'YYYY-MM-DD'),
+			i.condition,
+			i.retired,
+			i.wishlist,
+			i.maintenance_interval_days,
+			i.custom_fields,
+			i.product_id,
 			CASE WHEN ii.item_id IS NOT NULL THEN true ELSE false END as has_image,
 			(SELECT COUNT(DISTINCT pc.pack_id) FROM pack_content pc WHERE pc.item_id = i.id) as pack_count,
 			i.created_at,
@@ -153,6 +188,13 @@ func findInventoryByID(ctx context.Context, id uint) (*Inventory, error) {
 		&inventory.URL,
 		&inventory.Price,
 		&inventory.Currency,
+		&inventory.PurchaseDate,
+		&inventory.Condition,
+		&inventory.Retired,
+		&inventory.Wishlist,
+		&inventory.MaintenanceIntervalDays,
+		&inventory.CustomFields,
+		&inventory.ProductID,
 		&inventory.HasImage,
 		&inventory.PackCount,
 		&inventory.CreatedAt,
@@ -187,6 +229,13 @@ func findInventoryItemByAttributes(
 			i.url,
 			i.price,
 			i.currency,
+			TO_CHAR(i.purchase_date, 'YYYY-MM-DD'),
+			i.condition,
+			i.retired,
+			i.wishlist,
+			i.maintenance_interval_days,
+			i.custom_fields,
+			i.product_id,
 			CASE WHEN ii.item_id IS NOT NULL THEN true ELSE false END as has_image,
 			(SELECT COUNT(DISTINCT pc.pack_id) FROM pack_content pc WHERE pc.item_id = i.id) as pack_count,
 			i.created_at,
@@ -207,6 +256,13 @@ func findInventoryItemByAttributes(
 		&inventory.URL,
 		&inventory.Price,
 		&inventory.Currency,
+		&inventory.PurchaseDate,
+		&inventory.Condition,
+		&inventory.Retired,
+		&inventory.Wishlist,
+		&inventory.MaintenanceIntervalDays,
+		&inventory.CustomFields,
+		&inventory.ProductID,
 		&inventory.HasImage,
 		&inventory.PackCount,
 		&inventory.CreatedAt,
@@ -223,15 +279,17 @@ func findInventoryItemByAttributes(
 }
 
 // insertInventory inserts a new inventory item into the database
-func insertInventory(ctx context.Context, i *Inventory) error {
+func insertInventory(ctx context.Context, q database.Queryer, i *Inventory) error {
 	if i == nil {
 		return errors.New("payload is empty")
 	}
 
-	err := database.DB().QueryRowContext(ctx,
+	err := q.QueryRowContext(ctx,
 		`INSERT INTO inventory
-		(user_id, item_name, category, description, weight, url, price, currency, created_at, updated_at)
-		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+		(user_id, item_name, category, description, weight, url, price, currency,
+			purchase_date, condition, retired, wishlist, maintenance_interval_days, custom_fields, product_id,
+			weight_from_product, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9::date,$10,$11,$12,$13,$14,$15,$16,$17,$18)
 		RETURNING id;`,
 		i.UserID,
 		i.ItemName,
@@ -241,6 +299,14 @@ func insertInventory(ctx context.Context, i *Inventory) error {
 		i.URL,
 		i.Price,
 		i.Currency,
+		i.PurchaseDate,
+		i.Condition,
+		i.Retired,
+		i.Wishlist,
+		i.MaintenanceIntervalDays,
+		i.CustomFields,
+		i.ProductID,
+		i.WeightFromProduct,
 		i.CreatedAt,
 		i.UpdatedAt).Scan(&i.ID)
 
@@ -251,7 +317,8 @@ func insertInventory(ctx context.Context, i *Inventory) error {
 	return nil
 }
 
-// updateInventoryByID updates an existing inventory item in the database
+// updateInventoryByID updates an existing inventory item in the database. A
+// weight taken from the product stays flagged while weight and product are kept.
 func updateInventoryByID(ctx context.Context, id uint, i *Inventory) error {
 	if i == nil {
 		return errors.New("payload is empty")
@@ -267,8 +334,16 @@ func updateInventoryByID(ctx context.Context, id uint, i *Inventory) error {
 			url=$6,
 			price=$7,
 			currency=$8,
-			updated_at=$9
-		WHERE id=$10;`)
+			purchase_date=$9::date,
+			condition=$10,
+			retired=$11,
+			wishlist=$12,
+			maintenance_interval_days=$13,
+			custom_fields=$14,
+			product_id=$15,
+			weight_from_product = weight_from_product AND weight = $5 AND product_id IS NOT DISTINCT FROM $15,
+			updated_at=$16
+		WHERE id=$17;`)
 	if err != nil {
 		return err
 	}
@@ -284,6 +359,13 @@ func updateInventoryByID(ctx context.Context, id uint, i *Inventory) error {
 		i.URL,
 		i.Price,
 		i.Currency,
+		i.PurchaseDate,
+		i.Condition,
+		i.Retired,
+		i.Wishlist,
+		i.MaintenanceIntervalDays,
+		i.CustomFields,
+		i.ProductID,
 		i.UpdatedAt,
 		id)
 	if err != nil {
@@ -350,13 +432,80 @@ func mergeInventoryItems(ctx context.Context, req *MergeInventoryRequest) (*Inve
 	return inventory, nil
 }
 
+// batchMergeInventoryItems merges several source inventory items into the target item within a
+// transaction, applying the single-item merge steps to each source in turn.
+func batchMergeInventoryItems(ctx context.Context, req *BatchMergeInventoryRequest) (*Inventory, error) {
+	tx, err := database.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	for _, sourceID := range req.SourceItemIDs {
+		step := MergeInventoryRequest{
+			SourceItemID: sourceID,
+			TargetItemID: req.TargetItemID,
+			ItemName:     req.ItemName,
+			Category:     req.Category,
+			Description:  req.Description,
+			Weight:       req.Weight,
+			URL:          req.URL,
+			Price:        req.Price,
+			Currency:     req.Currency,
+			ImageSource:  batchMergeImageSource(req, sourceID),
+		}
+
+		if err = mergeUpdateTargetAndPackContent(ctx, tx, &step); err != nil {
+			return nil, err
+		}
+
+		if err = mergeHandleImages(ctx, tx, &step); err != nil {
+			return nil, err
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE FROM inventory WHERE id = $1;`, sourceID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	inventory, err := mergeQueryResult(ctx, tx, req.TargetItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return inventory, nil
+}
+
+// batchMergeImageSource translates the image selection of a batch merge to the
+// image_source of merging one source: only the selected source keeps its image.
+func batchMergeImageSource(req *BatchMergeInventoryRequest, sourceID uint) string {
+	switch {
+	case req.ImageSource == ImageSourceNone:
+		return ImageSourceNone
+	case req.ImageSource == ImageSourceSource && req.ImageSourceItemID == sourceID:
+		return ImageSourceSource
+	default:
+		return ImageSourceTarget
+	}
+}
+
 // mergeUpdateTargetAndPackContent updates the target item properties and consolidates pack_content rows.
 func mergeUpdateTargetAndPackContent(ctx context.Context, tx *sql.Tx, req *MergeInventoryRequest) error {
 	// Update target item with merged property values
 	_, err := tx.ExecContext(ctx,
 		`UPDATE inventory
 		SET item_name = $1, category = $2, description = $3, weight = $4,
-			url = $5, price = $6, currency = $7, updated_at = NOW()
+			url = $5, price = $6, currency = $7, updated_at = NOW(),
+			weight_from_product = weight_from_product AND weight = $4
 		WHERE id = $8;`,
 		req.ItemName, req.Category, req.Description, req.Weight,
 		req.URL, req.Price, req.Currency, req.TargetItemID)
@@ -388,13 +537,50 @@ func mergeUpdateTargetAndPackContent(ctx context.Context, tx *sql.Tx, req *Merge
 	_, err = tx.ExecContext(ctx,
 		`UPDATE pack_content SET item_id = $1, updated_at = NOW() WHERE item_id = $2;`,
 		req.TargetItemID, req.SourceItemID)
+	if err != nil {
+		return err
+	}
+
+	// Keep the maintenance history of the source
+	_, err = tx.ExecContext(ctx,
+		`UPDATE inventory_maintenance SET item_id = $1 WHERE item_id = $2;`,
+		req.TargetItemID, req.SourceItemID)
+	if err != nil {
+		return err
+	}
+
+	return mergeTripUsage(ctx, tx, req.SourceItemID, req.TargetItemID)
+}
+
+// mergeTripUsage moves the trips of the source item to the target, like its pack_content rows
+func mergeTripUsage(ctx context.Context, tx *sql.Tx, sourceID, targetID uint) error {
+	_, err := tx.ExecContext(ctx,
+		`UPDATE pack_trip_item AS t
+		SET quantity = t.quantity + s.quantity
+		FROM pack_trip_item AS s
+		WHERE s.item_id = $1 AND t.item_id = $2 AND s.trip_id = t.trip_id;`,
+		sourceID, targetID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`DELETE FROM pack_trip_item WHERE item_id = $1
+		AND trip_id IN (SELECT trip_id FROM pack_trip_item WHERE item_id = $2);`,
+		sourceID, targetID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE pack_trip_item SET item_id = $1 WHERE item_id = $2;`, targetID, sourceID)
 	return err
 }
 
 // mergeHandleImages handles image reassignment based on the image_source selection.
 func mergeHandleImages(ctx context.Context, tx *sql.Tx, req *MergeInventoryRequest) error {
 	switch req.ImageSource {
-	case "source":
+	case ImageSourceSource:
 		if _, err := tx.ExecContext(ctx,
 			`DELETE FROM inventory_images WHERE item_id = $1;`, req.TargetItemID); err != nil {
 			return err
@@ -403,11 +589,11 @@ func mergeHandleImages(ctx context.Context, tx *sql.Tx, req *MergeInventoryReque
 			`UPDATE inventory_images SET item_id = $1 WHERE item_id = $2;`,
 			req.TargetItemID, req.SourceItemID)
 		return err
-	case "target":
+	case ImageSourceTarget:
 		_, err := tx.ExecContext(ctx,
 			`DELETE FROM inventory_images WHERE item_id = $1;`, req.SourceItemID)
 		return err
-	case "none":
+	case ImageSourceNone:
 		_, err := tx.ExecContext(ctx,
 			`DELETE FROM inventory_images WHERE item_id IN ($1, $2);`,
 			req.SourceItemID, req.TargetItemID)
@@ -422,6 +608,9 @@ func mergeQueryResult(ctx context.Context, tx *sql.Tx, targetID uint) (*Inventor
 	err := tx.QueryRowContext(ctx,
 		`SELECT i.id, i.user_id, i.item_name, i.category, i.description,
 			i.weight, i.url, i.price, i.currency,
+			TO_CHAR(i.purchase_date, 'YYYY-MM-DD'), i.condition, i.retired, i.wishlist,
+			i.maintenance_interval_days,
+			i.custom_fields, i.product_id,
 			CASE WHEN ii.item_id IS NOT NULL THEN true ELSE false END as has_image,
 			(SELECT COUNT(DISTINCT pc.pack_id) FROM pack_content pc WHERE pc.item_id = i.id) as pack_count,
 			i.created_at, i.updated_at
@@ -431,7 +620,9 @@ func mergeQueryResult(ctx context.Context, tx *sql.Tx, targetID uint) (*Inventor
 		targetID).Scan(
 		&inventory.ID, &inventory.UserID, &inventory.ItemName, &inventory.Category,
 		&inventory.Description, &inventory.Weight, &inventory.URL, &inventory.Price,
-		&inventory.Currency, &inventory.HasImage, &inventory.PackCount,
+		&inventory.Currency, &inventory.PurchaseDate, &inventory.Condition, &inventory.Retired, &inventory.Wishlist,
+		&inventory.MaintenanceIntervalDays, &inventory.CustomFields, &inventory.ProductID,
+		&inventory.HasImage, &inventory.PackCount,
 		&inventory.CreatedAt, &inventory.UpdatedAt)
 	if err != nil {
 		return nil, err
@@ -456,3 +647,260 @@ func checkInventoryOwnership(ctx context.Context, id uint, userID uint) (bool, e
 
 	return true, nil
 }
+
+// returnInventoryExport retrieves the inventory of a user as exported, with the pack count of each item
+func returnInventoryExport(ctx context.Context, userID uint) ([]InventoryTransferItem, error) {
+	rows, err := database.DB().QueryContext(ctx,
+		`SELECT COALESCE(i.external_key, ''), i.item_name, i.category, i.description,
+			i.weight, i.url, i.price, i.currency,
+			(SELECT COUNT(DISTINCT pc.pack_id) FROM pack_content pc WHERE pc.item_id = i.id) as pack_count,
+			i.custom_fields
+		FROM inventory i
+		WHERE i.user_id = $1 AND NOT i.wishlist ORDER BY i.category, i.item_name, i.id;`,
+		userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []InventoryTransferItem{}
+	for rows.Next() {
+		var item InventoryTransferItem
+		err := rows.Scan(&item.ExternalKey, &item.ItemName, &item.Category, &item.Description,
+			&item.Weight, &item.URL, &item.Price, &item.Currency, &item.PackCount, &item.CustomFields)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// importInventoryItems upserts imported items within a transaction. An item is
+// updated when its external key matches, or when name, category and description
+// match an item without another external key; otherwise it is created.
+func importInventoryItems(ctx context.Context, userID uint, rows []inventoryImportRow) (int, int, error) {
+	tx, err := database.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	created, updated := 0, 0
+	for _, r := range rows {
+		var id uint
+		id, err = findImportTarget(ctx, tx, userID, &r.item)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if id == 0 {
+			err = insertImportedItem(ctx, tx, userID, &r.item)
+			created++
+		} else {
+			err = updateImportedItem(ctx, tx, id, &r)
+			updated++
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, 0, err
+	}
+
+	return created, updated, nil
+}
+
+// findImportTarget returns the ID of the inventory item an imported item updates, 0 when none
+func findImportTarget(ctx context.Context, tx *sql.Tx, userID uint, item *InventoryTransferItem) (uint, error) {
+	var id uint
+	if item.ExternalKey != "" {
+		err := tx.QueryRowContext(ctx,
+			`SELECT id FROM inventory WHERE user_id = $1 AND external_key = $2;`,
+			userID, item.ExternalKey).Scan(&id)
+		if err == nil {
+			return id, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return 0, err
+		}
+	}
+
+	err := tx.QueryRowContext(ctx,
+		`SELECT id FROM inventory
+		WHERE user_id = $1 AND item_name = $2 AND category = $3 AND description = $4
+			AND ($5 = '' OR external_key IS NULL) AND NOT wishlist
+		ORDER BY id LIMIT 1;`,
+		userID, item.ItemName, item.Category, item.Description, item.ExternalKey).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return id, err
+}
+
+func insertImportedItem(ctx context.Context, tx *sql.Tx, userID uint, item *InventoryTransferItem) error {
+	currency := item.Currency
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO inventory
+		(user_id, item_name, category, description, weight, url, price, currency, external_key,
+			custom_fields, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,NULLIF($9, ''),$10,NOW(),NOW());`,
+		userID, item.ItemName, item.Category, item.Description, item.Weight,
+		item.URL, item.Price, currency, item.ExternalKey, item.CustomFields)
+	return err
+}
+
+// updateImportedItem updates an item from an import row; the
+// updatableImportFields the row does not have are passed as NULL and kept
+func updateImportedItem(ctx context.Context, tx *sql.Tx, id uint, r *inventoryImportRow) error {
+	item := &r.item
+	ifPresent := func(name string, value any) any {
+		if r.present[name] {
+			return value
+		}
+		return nil
+	}
+	_, err := tx.ExecContext(ctx,
+		`UPDATE inventory
+		SET item_name = $1, category = $2,
+			description = COALESCE($3, description), weight = COALESCE($4, weight),
+			url = COALESCE($5, url), price = COALESCE($6, price),
+			weight_from_product = weight_from_product AND weight = COALESCE($4, weight),
+			currency = COALESCE(NULLIF($7, ''), currency),
+			external_key = COALESCE(NULLIF($8, ''), external_key),
+			custom_fields = custom_fields || $9::jsonb,
+			updated_at = NOW()
+		WHERE id = $10;`,
+		item.ItemName, item.Category, ifPresent("description", item.Description), ifPresent("weight", item.Weight),
+		ifPresent("url", item.URL), ifPresent("price", item.Price),
+		item.Currency, item.ExternalKey, item.CustomFields, id)
+	return err
+}
+
+// returnMaintenanceLog retrieves the maintenance entries of an item, most recent first
+func returnMaintenanceLog(ctx context.Context, itemID uint) ([]MaintenanceEntry, error) {
+	rows, err := database.DB().QueryContext(ctx,
+		`SELECT id, item_id, TO_CHAR(performed_on, 'YYYY-MM-DD'), action, notes, cost, currency, created_at
+		FROM inventory_maintenance
+		WHERE item_id = $1
+		ORDER BY performed_on DESC, id DESC;`,
+		itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []MaintenanceEntry{}
+	for rows.Next() {
+		var e MaintenanceEntry
+		err := rows.Scan(&e.ID, &e.ItemID, &e.PerformedOn, &e.Action, &e.Notes, &e.Cost, &e.Currency, &e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// insertMaintenanceEntry adds an entry to the maintenance log of an item
+func insertMaintenanceEntry(ctx context.Context, e *MaintenanceEntry) error {
+	return database.DB().QueryRowContext(ctx,
+		`INSERT INTO inventory_maintenance (item_id, performed_on, action, notes, cost, currency)
+		VALUES ($1, $2::date, $3, $4, $5, $6)
+		RETURNING id, created_at;`,
+		e.ItemID, e.PerformedOn, e.Action, e.Notes, e.Cost, e.Currency).Scan(&e.ID, &e.CreatedAt)
+}
+
+// deleteMaintenanceEntry removes an entry from the maintenance log of an item
+func deleteMaintenanceEntry(ctx context.Context, itemID, entryID uint) error {
+	result, err := database.DB().ExecContext(ctx,
+		`DELETE FROM inventory_maintenance WHERE id = $1 AND item_id = $2;`, entryID, itemID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrMaintenanceEntryNotFound
+	}
+	return nil
+}
+
+// returnMaintenanceDue retrieves the items in service of a user whose maintenance
+// is due within the given number of days, most overdue first
+func returnMaintenanceDue(ctx context.Context, userID uint, withinDays int) ([]MaintenanceDue, error) {
+	rows, err := database.DB().QueryContext(ctx,
+		`SELECT id, item_name, category, maintenance_interval_days,
+			TO_CHAR(last_maintenance, 'YYYY-MM-DD'), TO_CHAR(due_on, 'YYYY-MM-DD'), CURRENT_DATE - due_on
+		FROM (
+			SELECT i.id, i.item_name, COALESCE(i.category, '') AS category, i.maintenance_interval_days,
+				m.last_maintenance,
+				COALESCE(m.last_maintenance, i.purchase_date, i.created_at::date)
+					+ i.maintenance_interval_days AS due_on
+			FROM inventory i
+			LEFT JOIN (
+				SELECT item_id, MAX(performed_on) AS last_maintenance
+				FROM inventory_maintenance GROUP BY item_id
+			) m ON m.item_id = i.id
+			WHERE i.user_id = $1 AND NOT i.retired AND NOT i.wishlist AND i.maintenance_interval_days IS NOT NULL
+		) AS scheduled
+		WHERE due_on <= CURRENT_DATE + $2::integer
+		ORDER BY due_on, id;`,
+		userID, withinDays)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	due := []MaintenanceDue{}
+	for rows.Next() {
+		var d MaintenanceDue
+		err := rows.Scan(&d.ItemID, &d.ItemName, &d.Category, &d.MaintenanceIntervalDays,
+			&d.LastMaintenance, &d.DueOn, &d.DaysOverdue)
+		if err != nil {
+			return nil, err
+		}
+		due = append(due, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return due, nil
+}
+
+// returnItemUsage sums the trips logged with an item
+func returnItemUsage(ctx context.Context, item *Inventory) (*ItemUsage, error) {
+	usage := ItemUsage{ItemID: item.ID, Price: item.Price, Currency: item.Currency}
+	err := database.DB().QueryRowContext(ctx,
+		`SELECT COUNT(*), COALESCE(SUM(t.nights), 0), COALESCE(SUM(t.distance_km), 0),
+			TO_CHAR(MIN(t.start_date), 'YYYY-MM-DD'), TO_CHAR(MAX(t.end_date), 'YYYY-MM-DD')
+		FROM pack_trip_item ti
+		JOIN pack_trip t ON t.id = ti.trip_id
+		WHERE ti.item_id = $1;`,
+		item.ID).Scan(&usage.Trips, &usage.Nights, &usage.DistanceKm, &usage.FirstUsed, &usage.LastUsed)
+	if err != nil {
+		return nil, err
+	}
+	usage.setCostsPerUse()
+	return &usage, nil
+}
@@ -2,7 +2,16 @@ package inventories
 
 import (
 	"context"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
+
+	"github.com/Angak0k/pimpmypack/pkg/currencies"
+	"github.com/Angak0k/pimpmypack/pkg/customfields"
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/Angak0k/pimpmypack/pkg/products"
+	"github.com/Angak0k/pimpmypack/pkg/units"
 )
 
 // FindInventoryItemByAttributes finds an existing inventory item for a user
@@ -18,9 +27,15 @@ func FindInventoryItemByAttributes(
 
 // InsertInventory creates a new inventory item with timestamps
 func InsertInventory(ctx context.Context, i *Inventory) error {
+	return InsertInventoryTx(ctx, database.DB(), i)
+}
+
+// InsertInventoryTx creates a new inventory item with q, a transaction of
+// the caller or the database
+func InsertInventoryTx(ctx context.Context, q database.Queryer, i *Inventory) error {
 	i.CreatedAt = time.Now().Truncate(time.Second)
 	i.UpdatedAt = time.Now().Truncate(time.Second)
-	return insertInventory(ctx, i)
+	return insertInventory(ctx, q, i)
 }
 
 // FindInventoryByID retrieves a single inventory item by ID.
@@ -29,6 +44,11 @@ func FindInventoryByID(ctx context.Context, id uint) (*Inventory, error) {
 	return findInventoryByID(ctx, id)
 }
 
+// ReturnInventoriesByUserID retrieves all inventory items of a user
+func ReturnInventoriesByUserID(ctx context.Context, userID uint) (*Inventories, error) {
+	return returnInventoriesByUserID(ctx, userID)
+}
+
 // CheckInventoryOwnership verifies if an inventory item belongs to a specific user.
 // Returns true if the item belongs to the user, false otherwise.
 func CheckInventoryOwnership(ctx context.Context, id uint, userID uint) (bool, error) {
@@ -45,3 +65,154 @@ func FindItemIDByItemName(inventories Inventories, itemname string) uint {
 	}
 	return 0
 }
+
+// normalizeCurrency validates an ISO 4217 code, DefaultCurrency when empty
+func normalizeCurrency(code string) (string, error) {
+	if code == "" {
+		return DefaultCurrency, nil
+	}
+	return currencies.Normalize(code)
+}
+
+// setPreferredPrices fills PriceInPreferredCurrency for the items of a user;
+// it stays nil for the currencies without an exchange rate
+func setPreferredPrices(ctx context.Context, userID uint, items Inventories) error {
+	preferred, cv, err := currencies.ConverterFor(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		items[i].PriceInPreferredCurrency = cv.ConvertPtr(items[i].Price, items[i].Currency, preferred)
+	}
+	return nil
+}
+
+// setWeightDisplays fills WeightDisplay in the unit system, none when system is ""
+func setWeightDisplays(items Inventories, system string) {
+	for i := range items {
+		items[i].WeightDisplay = units.NewWeight(items[i].Weight, system)
+	}
+}
+
+// applyItemDetails applies the lifecycle fields, custom field values and
+// product of a create or update request to the item; nil values keep the
+// current ones
+func applyItemDetails(
+	ctx context.Context, item *Inventory, lifecycle InventoryLifecycleInput, values customfields.Values,
+	productID *uint,
+) error {
+	if err := lifecycle.applyTo(item); err != nil {
+		return err
+	}
+	if err := applyProduct(ctx, item, productID); err != nil {
+		return err
+	}
+	if values == nil {
+		if item.CustomFields == nil {
+			item.CustomFields = customfields.Values{}
+		}
+		return nil
+	}
+
+	fields, err := customfields.ReturnFieldsByUserID(ctx, item.UserID)
+	if err != nil {
+		return err
+	}
+	normalized, err := fields.Normalize(values)
+	if err != nil {
+		return err
+	}
+	item.CustomFields = normalized
+	return nil
+}
+
+// applyProduct links the item to a catalog product, or unlinks it for 0. An
+// item not yet created takes a zero weight from the official or else the
+// median weight of the product, flagged as not measured, and an empty URL
+// from the product URL.
+func applyProduct(ctx context.Context, item *Inventory, productID *uint) error {
+	if productID == nil {
+		return nil
+	}
+	if *productID == 0 {
+		item.ProductID = nil
+		return nil
+	}
+
+	product, err := products.FindProductByID(ctx, *productID)
+	if err != nil {
+		return err
+	}
+	item.ProductID = &product.ID
+	if item.ID != 0 {
+		return nil
+	}
+	if item.Weight == 0 {
+		switch {
+		case product.Weight != nil:
+			item.Weight = *product.Weight
+			item.WeightFromProduct = true
+		case product.MedianWeight != nil:
+			item.Weight = *product.MedianWeight
+			item.WeightFromProduct = true
+		}
+	}
+	if item.URL == "" {
+		item.URL = product.URL
+	}
+	return nil
+}
+
+// setProductMedianWeights fills ProductMedianWeight for the items linked to a
+// product with measured items
+func setProductMedianWeights(ctx context.Context, items Inventories) error {
+	var productIDs []uint
+	for _, item := range items {
+		if item.ProductID != nil && !slices.Contains(productIDs, *item.ProductID) {
+			productIDs = append(productIDs, *item.ProductID)
+		}
+	}
+	medians, err := products.ReturnMedianWeights(ctx, productIDs)
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		if items[i].ProductID == nil {
+			continue
+		}
+		if median, ok := medians[*items[i].ProductID]; ok {
+			items[i].ProductMedianWeight = &median
+		}
+	}
+	return nil
+}
+
+// filterByCustomFields keeps the items matching every field.<key> filter of
+// the query; the custom fields are only loaded when there are filters
+func filterByCustomFields(ctx context.Context, userID uint, query url.Values, items Inventories) (Inventories, error) {
+	hasFilters := false
+	for param := range query {
+		hasFilters = hasFilters || strings.HasPrefix(param, customfields.FilterPrefix)
+	}
+	if !hasFilters {
+		return items, nil
+	}
+
+	fields, err := customfields.ReturnFieldsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	filters, err := fields.ParseFilters(query)
+	if err != nil {
+		return nil, err
+	}
+
+	matching := make(Inventories, 0, len(items))
+	for _, item := range items {
+		if slices.ContainsFunc(filters, func(f customfields.Filter) bool { return !f.Matches(item.CustomFields) }) {
+			continue
+		}
+		matching = append(matching, item)
+	}
+	return matching, nil
+}
@@ -0,0 +1,37 @@
+package inventories
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NameSimilarity returns the trigram similarity of two item names, from 0 to 1,
+// computed like PostgreSQL's pg_trgm similarity(): lower-cased words padded
+// with two leading and one trailing space, shared trigrams over all trigrams.
+func NameSimilarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	shared := 0
+	for t := range ta {
+		if tb[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
+
+func trigrams(s string) map[string]bool {
+	set := map[string]bool{}
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range words {
+		padded := []rune("  " + w + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = true
+		}
+	}
+	return set
+}
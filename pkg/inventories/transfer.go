@@ -0,0 +1,334 @@
+package inventories
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Angak0k/pimpmypack/pkg/customfields"
+)
+
+// Inventory import and export: CSV files have a header row with the
+// transferColumns, JSON files are an array of items or {"items": [...]}.
+// Weights are in grams and prices in the minor unit of their currency.
+
+// Export formats
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+var (
+	// ErrInvalidExportFormat is returned for an unknown export format
+	ErrInvalidExportFormat = errors.New("format must be csv or json")
+	// ErrUnreadableInventoryFile is returned when an import file is neither CSV nor JSON
+	ErrUnreadableInventoryFile = errors.New("unreadable file, expected a CSV or JSON inventory")
+	// ErrNoItemNameColumn is returned when a CSV import has no item_name column
+	ErrNoItemNameColumn = errors.New("no item_name column found")
+	// ErrNoValidInventoryRows is returned when no row of an import can be imported
+	ErrNoValidInventoryRows = errors.New("no valid rows to import")
+	// ErrTooManyInventoryRows is returned when an import exceeds MaxImportItems
+	ErrTooManyInventoryRows = errors.New("too many items to import")
+)
+
+var transferColumns = []string{
+	"external_key", "item_name", "category", "description", "weight", "url", "price", "currency", "pack_count",
+}
+
+// updatableImportFields are the optional fields an import only updates on an
+// existing item when the row has them, so that a file without them keeps the
+// values of the inventory
+var updatableImportFields = []string{"description", "weight", "url", "price"}
+
+// inventoryImportRow is an item to import with its row number and the
+// updatableImportFields it has
+type inventoryImportRow struct {
+	row     int
+	item    InventoryTransferItem
+	present map[string]bool
+}
+
+// presentFields returns the updatableImportFields for which has is true
+func presentFields(has func(name string) bool) map[string]bool {
+	present := map[string]bool{}
+	for _, name := range updatableImportFields {
+		if has(name) {
+			present[name] = true
+		}
+	}
+	return present
+}
+
+// writeInventoryCSV writes items as CSV with a header of the transferColumns
+// followed by a field.<key> column per custom field key
+func writeInventoryCSV(w io.Writer, items []InventoryTransferItem, fieldKeys []string) error {
+	cw := csv.NewWriter(w)
+	header := slices.Clone(transferColumns)
+	for _, key := range fieldKeys {
+		header = append(header, customfields.FilterPrefix+key)
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, item := range items {
+		record := []string{
+			item.ExternalKey, item.ItemName, item.Category, item.Description, strconv.Itoa(item.Weight),
+			item.URL, strconv.Itoa(item.Price), item.Currency, strconv.Itoa(item.PackCount),
+		}
+		for _, key := range fieldKeys {
+			record = append(record, formatCustomValue(item.CustomFields[key]))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatCustomValue writes a custom field value as a CSV cell
+func formatCustomValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// parseInventoryFile reads an uploaded CSV or JSON inventory. Rows that cannot
+// be read are returned as row errors.
+func parseInventoryFile(data []byte, filename string) ([]inventoryImportRow, []InventoryImportRowError, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	trimmed := bytes.TrimSpace(data)
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext == ".json" || bytes.HasPrefix(trimmed, []byte("[")) || bytes.HasPrefix(trimmed, []byte("{")) {
+		rows, err := parseInventoryJSON(trimmed)
+		if err != nil {
+			return nil, nil, err
+		}
+		return validateImportRows(rows, nil)
+	}
+	return parseInventoryCSV(data)
+}
+
+// parseInventoryJSON numbers the items of a JSON import from 1, noting the
+// keys each item has; a null value counts as a missing key
+func parseInventoryJSON(data []byte) ([]inventoryImportRow, error) {
+	var objects []json.RawMessage
+	if bytes.HasPrefix(data, []byte("{")) {
+		var request struct {
+			Items []json.RawMessage `json:"items"`
+		}
+		if err := json.Unmarshal(data, &request); err != nil {
+			return nil, ErrUnreadableInventoryFile
+		}
+		objects = request.Items
+	} else if err := json.Unmarshal(data, &objects); err != nil {
+		return nil, ErrUnreadableInventoryFile
+	}
+
+	rows := make([]inventoryImportRow, 0, len(objects))
+	for i, object := range objects {
+		r := inventoryImportRow{row: i + 1}
+		var keys map[string]json.RawMessage
+		if err := json.Unmarshal(object, &r.item); err != nil {
+			return nil, ErrUnreadableInventoryFile
+		}
+		if err := json.Unmarshal(object, &keys); err != nil {
+			return nil, ErrUnreadableInventoryFile
+		}
+		r.present = presentFields(func(name string) bool {
+			value, ok := keys[name]
+			return ok && string(value) != "null"
+		})
+		rows = append(rows, r)
+	}
+	return rows, nil
+}
+
+func parseInventoryCSV(data []byte) ([]inventoryImportRow, []InventoryImportRowError, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, ErrUnreadableInventoryFile
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["item_name"]; !ok {
+		return nil, nil, ErrNoItemNameColumn
+	}
+
+	var rows []inventoryImportRow
+	var rowErrors []InventoryImportRowError
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, ErrUnreadableInventoryFile
+		}
+		// Rows are numbered by line, blank lines being skipped by the reader
+		row, _ := reader.FieldPos(0)
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		item, present, err := csvRecordToItem(record, columns)
+		if err != nil {
+			rowErrors = append(rowErrors, InventoryImportRowError{Row: row, Error: err.Error()})
+			continue
+		}
+		rows = append(rows, inventoryImportRow{row: row, item: item, present: present})
+	}
+	return validateImportRows(rows, rowErrors)
+}
+
+// csvRecordToItem reads a CSV row. The text columns of the header are
+// present, even empty; the number columns only when the cell is not empty.
+func csvRecordToItem(record []string, columns map[string]int) (InventoryTransferItem, map[string]bool, error) {
+	cell := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	item := InventoryTransferItem{
+		ExternalKey: cell("external_key"),
+		ItemName:    cell("item_name"),
+		Category:    cell("category"),
+		Description: cell("description"),
+		URL:         cell("url"),
+		Currency:    cell("currency"),
+	}
+	for name := range columns {
+		if key, ok := strings.CutPrefix(name, customfields.FilterPrefix); ok && cell(name) != "" {
+			if item.CustomFields == nil {
+				item.CustomFields = customfields.Values{}
+			}
+			item.CustomFields[key] = cell(name)
+		}
+	}
+	for name, target := range map[string]*int{"weight": &item.Weight, "price": &item.Price} {
+		value := cell(name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return item, nil, fmt.Errorf("invalid %s %q", name, value)
+		}
+		*target = n
+	}
+	present := presentFields(func(name string) bool {
+		_, ok := columns[name]
+		if name == "weight" || name == "price" {
+			return ok && cell(name) != ""
+		}
+		return ok
+	})
+	return item, present, nil
+}
+
+// validateImportRows checks the fields of each row, normalizes currencies and
+// rejects repeated external keys, adding the rejected rows to rowErrors
+func validateImportRows(
+	rows []inventoryImportRow, rowErrors []InventoryImportRowError,
+) ([]inventoryImportRow, []InventoryImportRowError, error) {
+	if len(rows) > MaxImportItems {
+		return nil, nil, fmt.Errorf("%w: %d items exceeds limit of %d", ErrTooManyInventoryRows, len(rows), MaxImportItems)
+	}
+
+	valid := make([]inventoryImportRow, 0, len(rows))
+	keys := map[string]int{}
+	for _, r := range rows {
+		if err := validateImportItem(&r.item); err != nil {
+			rowErrors = append(rowErrors, InventoryImportRowError{Row: r.row, Error: err.Error()})
+			continue
+		}
+		if r.item.ExternalKey != "" {
+			if first, ok := keys[r.item.ExternalKey]; ok {
+				rowErrors = append(rowErrors, InventoryImportRowError{
+					Row: r.row, Error: fmt.Sprintf("external_key already used on row %d", first),
+				})
+				continue
+			}
+			keys[r.item.ExternalKey] = r.row
+		}
+		valid = append(valid, r)
+	}
+
+	if rowErrors == nil {
+		rowErrors = []InventoryImportRowError{}
+	}
+	sort.SliceStable(rowErrors, func(i, j int) bool { return rowErrors[i].Row < rowErrors[j].Row })
+	if len(valid) == 0 {
+		return nil, rowErrors, ErrNoValidInventoryRows
+	}
+	return valid, rowErrors, nil
+}
+
+func validateImportItem(item *InventoryTransferItem) error {
+	item.ExternalKey = strings.TrimSpace(item.ExternalKey)
+	item.ItemName = strings.TrimSpace(item.ItemName)
+	item.Category = strings.TrimSpace(item.Category)
+	switch {
+	case item.ItemName == "":
+		return errors.New("missing item_name")
+	case item.Category == "":
+		return errors.New("missing category")
+	case item.Weight < 0:
+		return errors.New("weight must not be negative")
+	case item.Price < 0:
+		return errors.New("price must not be negative")
+	}
+	// An empty currency keeps the currency of an updated item
+	if item.Currency != "" {
+		currency, err := normalizeCurrency(item.Currency)
+		if err != nil {
+			return fmt.Errorf("invalid currency %q", item.Currency)
+		}
+		item.Currency = currency
+	}
+	return nil
+}
+
+// normalizeImportCustomFields checks the custom field values of the rows
+// against the fields of the user, adding the rows with invalid values to rowErrors
+func normalizeImportCustomFields(
+	rows []inventoryImportRow, rowErrors []InventoryImportRowError, fields customfields.Fields,
+) ([]inventoryImportRow, []InventoryImportRowError, error) {
+	valid := make([]inventoryImportRow, 0, len(rows))
+	for _, r := range rows {
+		values, err := fields.Normalize(r.item.CustomFields)
+		if err != nil {
+			rowErrors = append(rowErrors, InventoryImportRowError{Row: r.row, Error: err.Error()})
+			continue
+		}
+		r.item.CustomFields = values
+		valid = append(valid, r)
+	}
+
+	sort.SliceStable(rowErrors, func(i, j int) bool { return rowErrors[i].Row < rowErrors[j].Row })
+	if len(valid) == 0 {
+		return nil, rowErrors, ErrNoValidInventoryRows
+	}
+	return valid, rowErrors, nil
+}
@@ -1,26 +1,54 @@
 package inventories
 
-import "time"
+import (
+	"time"
+
+	"github.com/Angak0k/pimpmypack/pkg/customfields"
+	"github.com/Angak0k/pimpmypack/pkg/units"
+)
 
 // DefaultCurrency is the default currency used when none is specified
-// This matches the database default value in the Currency enum
+// This matches the database default value of inventory.currency
 const DefaultCurrency = "EUR"
 
 // Inventory represents an item in a user's inventory
 type Inventory struct {
-	ID          uint      `json:"id"`
-	UserID      uint      `json:"user_id"`
-	ItemName    string    `json:"item_name"`
-	Category    string    `json:"category"`
-	Description string    `json:"description"`
-	Weight      int       `json:"weight"`
-	URL         string    `json:"url"`
-	Price       int       `json:"price"`
-	Currency    string    `json:"currency"`
-	HasImage    bool      `json:"has_image"`
-	PackCount   int       `json:"pack_count"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uint   `json:"id"`
+	UserID      uint   `json:"user_id"`
+	ItemName    string `json:"item_name"`
+	Category    string `json:"category"`
+	Description string `json:"description"`
+	Weight      int    `json:"weight"`
+	URL         string `json:"url"`
+	Price       int    `json:"price"`
+	Currency    string `json:"currency"`
+	// PurchaseDate is formatted as YYYY-MM-DD
+	PurchaseDate *string `json:"purchase_date,omitempty"`
+	Condition    *string `json:"condition,omitempty"`
+	// Retired items stay in their packs but are hidden from GET /myinventory by default
+	Retired bool `json:"retired"`
+	// Wishlist items are planned purchases: they can be put in packs but are
+	// listed by GET /myinventory only with ?wishlist=true
+	Wishlist                bool `json:"wishlist"`
+	MaintenanceIntervalDays *int `json:"maintenance_interval_days,omitempty"`
+	// CustomFields holds the values of the custom fields of the owner by key
+	CustomFields customfields.Values `json:"custom_fields"`
+	// ProductID links the item to a product of the community catalog, whose
+	// median measured weight is ProductMedianWeight
+	ProductID           *uint `json:"product_id,omitempty"`
+	ProductMedianWeight *int  `json:"product_median_weight,omitempty"`
+	// WeightFromProduct is set when Weight was filled in from the product at
+	// creation; such weights are left out of the median
+	WeightFromProduct bool `json:"-"`
+	// PriceInPreferredCurrency is Price converted to the preferred currency of
+	// the owner; omitted when no exchange rate is known
+	PriceInPreferredCurrency *int `json:"price_in_preferred_currency,omitempty"`
+	// WeightDisplay is Weight in the unit system asked with ?units=
+	WeightDisplay *units.Weight `json:"weight_display,omitempty"`
+	HasImage      bool          `json:"has_image"`
+	PackCount     int           `json:"pack_count"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
 }
 
 // Inventories represents a collection of inventory items
@@ -35,6 +63,12 @@ type InventoryCreateRequest struct {
 	URL         string `json:"url"`
 	Price       int    `json:"price"`
 	Currency    string `json:"currency"`
+	InventoryLifecycleInput
+	// CustomFields replaces the custom field values of the item when set
+	CustomFields customfields.Values `json:"custom_fields"`
+	// ProductID links the item to a catalog product; a zero weight and an
+	// empty URL are taken from the product
+	ProductID *uint `json:"product_id"`
 }
 
 // InventoryCreateAdminRequest represents the input for creating an inventory item (admin endpoint)
@@ -47,6 +81,12 @@ type InventoryCreateAdminRequest struct {
 	URL         string `json:"url"`
 	Price       int    `json:"price"`
 	Currency    string `json:"currency"`
+	InventoryLifecycleInput
+	// CustomFields replaces the custom field values of the item when set
+	CustomFields customfields.Values `json:"custom_fields"`
+	// ProductID links the item to a catalog product; a zero weight and an
+	// empty URL are taken from the product
+	ProductID *uint `json:"product_id"`
 }
 
 // InventoryUpdateRequest represents the input for updating an inventory item
@@ -58,8 +98,20 @@ type InventoryUpdateRequest struct {
 	URL         string `json:"url"`
 	Price       int    `json:"price"`
 	Currency    string `json:"currency"`
+	InventoryLifecycleInput
+	// CustomFields replaces the custom field values of the item when set
+	CustomFields customfields.Values `json:"custom_fields"`
+	// ProductID links the item to a catalog product when set; 0 unlinks it
+	ProductID *uint `json:"product_id"`
 }
 
+// Image selections of a merge
+const (
+	ImageSourceSource = "source"
+	ImageSourceTarget = "target"
+	ImageSourceNone   = "none"
+)
+
 // MergeInventoryRequest represents the input for merging two inventory items
 type MergeInventoryRequest struct {
 	SourceItemID uint   `json:"source_item_id" binding:"required,gt=0"`
@@ -73,3 +125,142 @@ type MergeInventoryRequest struct {
 	Currency     string `json:"currency"`
 	ImageSource  string `json:"image_source" binding:"required,oneof=source target none"`
 }
+
+// BatchMergeInventoryRequest represents the input for merging several inventory items into a target.
+// With image_source "source", the image of image_source_item_id is kept.
+type BatchMergeInventoryRequest struct {
+	SourceItemIDs     []uint `json:"source_item_ids" binding:"required,min=1,dive,gt=0"`
+	TargetItemID      uint   `json:"target_item_id" binding:"required,gt=0"`
+	ItemName          string `json:"item_name" binding:"required"`
+	Category          string `json:"category" binding:"required"`
+	Description       string `json:"description"`
+	Weight            int    `json:"weight"`
+	URL               string `json:"url"`
+	Price             int    `json:"price"`
+	Currency          string `json:"currency"`
+	ImageSource       string `json:"image_source" binding:"required,oneof=source target none"`
+	ImageSourceItemID uint   `json:"image_source_item_id"`
+}
+
+// DuplicateGroup is a cluster of inventory items that are likely the same piece of gear.
+// Confidence is the mean score, from 0 to 1, of the duplicate pairs of the group.
+type DuplicateGroup struct {
+	Confidence        float64     `json:"confidence"`
+	Reasons           []string    `json:"reasons"`
+	SuggestedTargetID uint        `json:"suggested_target_id"`
+	Items             Inventories `json:"items"`
+}
+
+// Item conditions
+var itemConditions = []string{"new", "good", "fair", "poor", "broken"}
+
+// InventoryLifecycleInput holds the lifecycle fields of an item create or update request.
+// Omitted fields are left unchanged on update; an empty purchase_date or condition
+// and a 0 maintenance_interval_days clear them. Setting wishlist to false marks a
+// planned purchase as owned.
+type InventoryLifecycleInput struct {
+	PurchaseDate            *string `json:"purchase_date" example:"2024-05-01"`
+	Condition               *string `json:"condition" enums:"new,good,fair,poor,broken"`
+	Retired                 *bool   `json:"retired"`
+	Wishlist                *bool   `json:"wishlist"`
+	MaintenanceIntervalDays *int    `json:"maintenance_interval_days"`
+}
+
+// MaintenanceEntry is an entry of the maintenance log of an item.
+// Cost is in the minor unit of Currency.
+type MaintenanceEntry struct {
+	ID          uint      `json:"id"`
+	ItemID      uint      `json:"item_id"`
+	PerformedOn string    `json:"performed_on"`
+	Action      string    `json:"action"`
+	Notes       string    `json:"notes"`
+	Cost        int       `json:"cost"`
+	Currency    string    `json:"currency"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// MaintenanceEntryRequest represents the input for logging maintenance on an item.
+// The currency of the item is used when none is given.
+type MaintenanceEntryRequest struct {
+	PerformedOn string `json:"performed_on" binding:"required" example:"2024-05-01"`
+	Action      string `json:"action" binding:"required"`
+	Notes       string `json:"notes"`
+	Cost        int    `json:"cost" binding:"gte=0"`
+	Currency    string `json:"currency"`
+}
+
+// MaintenanceDue is an item whose maintenance interval has elapsed, counted from
+// its last maintenance, else its purchase date, else its creation
+type MaintenanceDue struct {
+	ItemID                  uint    `json:"item_id"`
+	ItemName                string  `json:"item_name"`
+	Category                string  `json:"category"`
+	MaintenanceIntervalDays int     `json:"maintenance_interval_days"`
+	LastMaintenance         *string `json:"last_maintenance,omitempty"`
+	DueOn                   string  `json:"due_on"`
+	DaysOverdue             int     `json:"days_overdue"`
+}
+
+// MaxImportItems caps the number of rows accepted by a single inventory import
+const MaxImportItems = 1000
+
+// InventoryTransferItem is an inventory item as exported and imported.
+// Imports match existing items by ExternalKey, then by name, category and
+// description; PackCount is ignored on import.
+type InventoryTransferItem struct {
+	ExternalKey string `json:"external_key"`
+	ItemName    string `json:"item_name"`
+	Category    string `json:"category"`
+	Description string `json:"description"`
+	Weight      int    `json:"weight"`
+	URL         string `json:"url"`
+	Price       int    `json:"price"`
+	Currency    string `json:"currency"`
+	PackCount   int    `json:"pack_count"`
+	// CustomFields are exported as field.<key> columns in CSV files
+	CustomFields customfields.Values `json:"custom_fields,omitempty"`
+}
+
+// InventoryImportRequest is the JSON body of an inventory import
+type InventoryImportRequest struct {
+	Items []InventoryTransferItem `json:"items"`
+}
+
+// InventoryImportRowError reports a row that was not imported; rows are numbered
+// from 1, CSV rows counting the header
+type InventoryImportRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// InventoryImportResponse is returned by an inventory import
+type InventoryImportResponse struct {
+	Message string                    `json:"message"`
+	Created int                       `json:"created"`
+	Updated int                       `json:"updated"`
+	Errors  []InventoryImportRowError `json:"errors"`
+}
+
+// InventoryImportErrorResponse is returned when no row of an import could be read
+type InventoryImportErrorResponse struct {
+	Error  string                    `json:"error"`
+	Errors []InventoryImportRowError `json:"errors"`
+}
+
+// ItemUsage is the usage of an item accumulated over the trips logged on the
+// packs that contained it. The costs per use divide Price (in the minor unit
+// of Currency) by the trips, nights and kilometres, and are omitted while
+// there are none.
+type ItemUsage struct {
+	ItemID       uint    `json:"item_id"`
+	Trips        int     `json:"trips"`
+	Nights       int     `json:"nights"`
+	DistanceKm   int     `json:"distance_km"`
+	FirstUsed    *string `json:"first_used,omitempty"`
+	LastUsed     *string `json:"last_used,omitempty"`
+	Price        int     `json:"price"`
+	Currency     string  `json:"currency"`
+	CostPerUse   *int    `json:"cost_per_use,omitempty"`
+	CostPerNight *int    `json:"cost_per_night,omitempty"`
+	CostPerKm    *int    `json:"cost_per_km,omitempty"`
+}
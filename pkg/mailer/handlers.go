@@ -0,0 +1,102 @@
+package mailer
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Angak0k/pimpmypack/pkg/helper"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultOutboxLimit = 100
+	maxOutboxLimit     = 500
+)
+
+// GetOutboxEmails handles GET /admin/emails
+// @Summary [ADMIN] List outbox emails
+// @Description List the emails of the outbox, most recent first, to follow failed sends - for admin use only.
+// @Description Bodies are not returned.
+// @Security Bearer
+// @Tags Internal
+// @Produce json
+// @Param status query string false "pending, sent or dead"
+// @Param recipient query string false "Recipient email address"
+// @Param limit query int false "Maximum number of emails (default 100, max 500)"
+// @Param offset query int false "Number of emails to skip"
+// @Success 200 {object} OutboxEmails
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid filter"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal server error"
+// @Router /admin/emails [get]
+func GetOutboxEmails(c *gin.Context) {
+	filter := OutboxEmailFilter{
+		Status:    c.Query("status"),
+		Recipient: c.Query("recipient"),
+		Limit:     defaultOutboxLimit,
+	}
+
+	switch filter.Status {
+	case "", StatusPending, StatusSent, StatusDead:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be pending, sent or dead"})
+		return
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxOutboxLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and " + strconv.Itoa(maxOutboxLimit)})
+			return
+		}
+		filter.Limit = limit
+	}
+	if raw := c.Query("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative integer"})
+			return
+		}
+		filter.Offset = offset
+	}
+
+	emails, err := returnOutboxEmails(c.Request.Context(), filter)
+	if err != nil {
+		helper.LogAndSanitize(err, "get outbox emails: return outbox emails failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, emails)
+}
+
+// RetryOutboxEmail handles POST /admin/emails/:id/retry
+// @Summary [ADMIN] Retry a dead email
+// @Description Requeue a dead-lettered email with a fresh set of attempts - for admin use only
+// @Security Bearer
+// @Tags Internal
+// @Produce json
+// @Param id path int true "Outbox email ID"
+// @Success 200 {object} apitypes.OkResponse
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
+// @Failure 404 {object} apitypes.ErrorResponse "No dead email with this ID"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal server error"
+// @Router /admin/emails/{id}/retry [post]
+func RetryOutboxEmail(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	requeued, err := requeueEmail(c.Request.Context(), id)
+	if err != nil {
+		helper.LogAndSanitize(err, "retry outbox email: requeue failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+	if !requeued {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no dead email with this ID"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "email requeued"})
+}
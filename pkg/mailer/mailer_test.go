@@ -0,0 +1,274 @@
+package mailer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Angak0k/pimpmypack/pkg/config"
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/gin-gonic/gin"
+	"github.com/gruntwork-io/terratest/modules/random"
+)
+
+func TestMain(m *testing.M) {
+	// init env
+	err := config.EnvInit("../../.env")
+	if err != nil {
+		log.Fatalf("Error loading .env file or environment variable : %v", err)
+	}
+
+	// init DB
+	err = database.Initialization()
+	if err != nil {
+		log.Fatalf("Error connecting database : %v", err)
+	}
+
+	// init DB migration
+	err = database.Migrate()
+	if err != nil {
+		log.Fatalf("Error migrating database : %v", err)
+	}
+
+	os.Exit(m.Run())
+}
+
+// failingSender is a transport that is always down
+type failingSender struct{}
+
+func (f *failingSender) SendEmail(_, _, _, _ string) error {
+	return errors.New("connection refused")
+}
+
+// enqueueForTest enqueues an email for a unique recipient and returns its outbox ID
+func enqueueForTest(t *testing.T) (int64, string) {
+	t.Helper()
+	to := "outbox-" + random.UniqueID() + "@exemple.com"
+	if err := Enqueue(context.Background(), to, "Hello", "text body", "<p>html body</p>"); err != nil {
+		t.Fatalf("failed to enqueue email: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = database.DB().ExecContext(context.Background(), "DELETE FROM email_outbox WHERE recipient = $1", to)
+	})
+
+	var id int64
+	if err := database.DB().QueryRow(`SELECT id FROM email_outbox WHERE recipient = $1`, to).Scan(&id); err != nil {
+		t.Fatalf("failed to find enqueued email: %v", err)
+	}
+	return id, to
+}
+
+func readOutboxRow(t *testing.T, id int64) (string, int, string) {
+	t.Helper()
+	var status, textBody string
+	var attempts int
+	err := database.DB().QueryRow(
+		`SELECT status, attempts, text_body FROM email_outbox WHERE id = $1`, id,
+	).Scan(&status, &attempts, &textBody)
+	if err != nil {
+		t.Fatalf("failed to read outbox email %d: %v", id, err)
+	}
+	return status, attempts, textBody
+}
+
+func TestProcessOutbox_Delivers(t *testing.T) {
+	sink := NewMemorySink()
+	SetTransport(sink)
+	t.Cleanup(func() { SetTransport(nil) })
+
+	id, to := enqueueForTest(t)
+	if _, err := ProcessOutbox(context.Background()); err != nil {
+		t.Fatalf("failed to process outbox: %v", err)
+	}
+
+	messages := sink.MessagesTo(to)
+	if len(messages) != 1 || messages[0].Subject != "Hello" || messages[0].HTMLBody != "<p>html body</p>" {
+		t.Errorf("Expected the email delivered to %s, got %+v", to, messages)
+	}
+	status, attempts, textBody := readOutboxRow(t, id)
+	if status != StatusSent || attempts != 1 {
+		t.Errorf("Expected status sent after 1 attempt but got %s after %d", status, attempts)
+	}
+	if textBody != "" {
+		t.Errorf("Expected the body to be cleared once sent")
+	}
+}
+
+func TestProcessOutbox_RetryAndDeadLetter(t *testing.T) {
+	SetTransport(&failingSender{})
+	t.Cleanup(func() { SetTransport(nil) })
+	prev := config.EmailMaxAttempts
+	config.EmailMaxAttempts = 2
+	t.Cleanup(func() { config.EmailMaxAttempts = prev })
+
+	id, _ := enqueueForTest(t)
+
+	if _, err := ProcessOutbox(context.Background()); err != nil {
+		t.Fatalf("failed to process outbox: %v", err)
+	}
+	status, attempts, _ := readOutboxRow(t, id)
+	if status != StatusPending || attempts != 1 {
+		t.Fatalf("Expected a pending retry after 1 attempt but got %s after %d", status, attempts)
+	}
+
+	// Not due yet: the next run leaves it alone
+	if _, err := ProcessOutbox(context.Background()); err != nil {
+		t.Fatalf("failed to process outbox: %v", err)
+	}
+	if _, attempts, _ = readOutboxRow(t, id); attempts != 1 {
+		t.Errorf("Expected no attempt before the retry delay but got %d attempts", attempts)
+	}
+
+	if _, err := database.DB().Exec(`UPDATE email_outbox SET next_attempt_at = NOW() WHERE id = $1`, id); err != nil {
+		t.Fatalf("failed to make email due: %v", err)
+	}
+	if _, err := ProcessOutbox(context.Background()); err != nil {
+		t.Fatalf("failed to process outbox: %v", err)
+	}
+	status, attempts, _ = readOutboxRow(t, id)
+	if status != StatusDead || attempts != 2 {
+		t.Fatalf("Expected a dead email after 2 attempts but got %s after %d", status, attempts)
+	}
+	var deadAt sql.NullTime
+	if err := database.DB().QueryRow(`SELECT dead_at FROM email_outbox WHERE id = $1`, id).Scan(&deadAt); err != nil {
+		t.Fatalf("failed to read dead_at: %v", err)
+	}
+	if !deadAt.Valid {
+		t.Errorf("Expected dead_at to be set on a dead email")
+	}
+
+	t.Run("admin lists and requeues the dead email", func(t *testing.T) {
+		testAdminRequeue(t, id)
+	})
+}
+
+func TestCleanupDeadEmails(t *testing.T) {
+	recent, _ := enqueueForTest(t)
+	expired, _ := enqueueForTest(t)
+	_, err := database.DB().Exec(
+		`UPDATE email_outbox SET status = 'dead', dead_at = NOW() - CASE WHEN id = $2 THEN INTERVAL '8 days'
+			ELSE INTERVAL '1 day' END
+		WHERE id IN ($1, $2)`, recent, expired)
+	if err != nil {
+		t.Fatalf("failed to dead-letter emails: %v", err)
+	}
+
+	if _, err := CleanupDeadEmails(context.Background()); err != nil {
+		t.Fatalf("failed to cleanup dead emails: %v", err)
+	}
+	var remaining []int64
+	rows, err := database.DB().Query(`SELECT id FROM email_outbox WHERE id IN ($1, $2)`, recent, expired)
+	if err != nil {
+		t.Fatalf("failed to query outbox: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			t.Fatalf("failed to scan outbox row: %v", err)
+		}
+		remaining = append(remaining, id)
+	}
+	if len(remaining) != 1 || remaining[0] != recent {
+		t.Errorf("Expected only dead email %d to remain but got %v", recent, remaining)
+	}
+}
+
+func testAdminRequeue(t *testing.T, id int64) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.GET("/admin/emails", GetOutboxEmails)
+	router.POST("/admin/emails/:id/retry", RetryOutboxEmail)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/emails?status=dead&limit=500", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d but got %d", http.StatusOK, w.Code)
+	}
+	var emails OutboxEmails
+	if err := json.Unmarshal(w.Body.Bytes(), &emails); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	found := false
+	for _, e := range emails {
+		if e.ID == id {
+			found = e.LastError != nil && strings.Contains(*e.LastError, "connection refused")
+		}
+	}
+	if !found {
+		t.Errorf("Expected dead email %d with its last error in the listing", id)
+	}
+
+	path := "/admin/emails/" + strconv.FormatInt(id, 10) + "/retry"
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d but got %d", http.StatusOK, w.Code)
+	}
+	if status, attempts, _ := readOutboxRow(t, id); status != StatusPending || attempts != 0 {
+		t.Errorf("Expected a pending email with no attempts but got %s after %d", status, attempts)
+	}
+
+	// Only dead emails can be requeued
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d but got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestEnqueue_InvalidRecipient(t *testing.T) {
+	err := Enqueue(context.Background(), "not-an-email", "Hello", "text", "html")
+	if !errors.Is(err, ErrInvalidRecipient) {
+		t.Errorf("Expected ErrInvalidRecipient but got %v", err)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	prevBase, prevMax := config.EmailRetryBaseSeconds, config.EmailRetryMaxSeconds
+	config.EmailRetryBaseSeconds, config.EmailRetryMaxSeconds = 30, 300
+	t.Cleanup(func() { config.EmailRetryBaseSeconds, config.EmailRetryMaxSeconds = prevBase, prevMax })
+
+	tests := map[int]time.Duration{
+		1:  30 * time.Second,
+		2:  60 * time.Second,
+		4:  240 * time.Second,
+		5:  300 * time.Second,
+		40: 300 * time.Second,
+	}
+	for attempts, want := range tests {
+		if got := retryDelay(attempts); got != want {
+			t.Errorf("retryDelay(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
+
+func TestDirSink(t *testing.T) {
+	dir := t.TempDir()
+	sink := &DirSink{Dir: dir, From: "noreply@exemple.com"}
+	if err := sink.SendEmail("bob@exemple.com", "Hello", "text body", "<p>html body</p>"); err != nil {
+		t.Fatalf("failed to send email: %v", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*-bob@exemple.com.eml"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("Expected one .eml file for bob@exemple.com, got %v (%v)", files, err)
+	}
+	content, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("failed to read email file: %v", err)
+	}
+	if !strings.Contains(string(content), "Subject: Hello") {
+		t.Errorf("Expected the MIME message with its subject, got %s", content)
+	}
+}
@@ -0,0 +1,274 @@
+// Package mailer delivers the application emails through a durable outbox:
+// senders enqueue, a background worker sends with retries and dead-letters
+// what keeps failing. Delivery goes to SMTP or, in development and tests, to
+// a directory or in-memory sink.
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Angak0k/pimpmypack/pkg/config"
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/Angak0k/pimpmypack/pkg/helper"
+)
+
+const (
+	// sentRetention is how long sent emails stay listed before the cleanup job drops them
+	sentRetention = 30 * 24 * time.Hour
+	// deadRetention is how long dead emails, bodies included, stay requeueable
+	// before the cleanup job drops them
+	deadRetention = 7 * 24 * time.Hour
+	// maxLastErrorLength bounds the delivery error kept on a row
+	maxLastErrorLength = 500
+)
+
+// ErrInvalidRecipient is returned when enqueuing an email for an invalid address
+var ErrInvalidRecipient = errors.New("invalid recipient email address")
+
+var (
+	transport  helper.EmailSender
+	memorySink = NewMemorySink()
+)
+
+// SetTransport replaces the delivery backend of the worker (tests inject a MemorySink)
+func SetTransport(t helper.EmailSender) {
+	transport = t
+}
+
+// getTransport returns the delivery backend, selected by MAIL_TRANSPORT by default
+func getTransport() helper.EmailSender {
+	if transport != nil {
+		return transport
+	}
+	switch config.MailTransport {
+	case "dir":
+		return &DirSink{Dir: config.MailSinkDir, From: config.MailServerConfig.MailIdentity}
+	case "memory":
+		return memorySink
+	default:
+		return &helper.SMTPClient{Server: config.MailServerConfig}
+	}
+}
+
+// Sink returns the in-memory sink used with MAIL_TRANSPORT=memory
+func Sink() *MemorySink {
+	return memorySink
+}
+
+// Outbox is the EmailSender of the application: SendEmail only stores the
+// email, so an SMTP outage delays it instead of failing the request
+type Outbox struct{}
+
+// NewOutbox returns the outbox email sender
+func NewOutbox() *Outbox {
+	return &Outbox{}
+}
+
+// SendEmail enqueues the email for the worker
+func (o *Outbox) SendEmail(to, subject, textBody, htmlBody string) error {
+	return Enqueue(context.Background(), to, subject, textBody, htmlBody)
+}
+
+// Enqueue stores an email in the outbox, due immediately
+func Enqueue(ctx context.Context, to, subject, textBody, htmlBody string) error {
+	if !helper.IsValidEmail(to) {
+		return ErrInvalidRecipient
+	}
+
+	_, err := database.DB().ExecContext(ctx,
+		`INSERT INTO email_outbox (recipient, subject, text_body, html_body) VALUES ($1, $2, $3, $4);`,
+		to, subject, textBody, htmlBody)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue email: %w", err)
+	}
+	return nil
+}
+
+// claimDueEmails takes up to limit due emails and pushes their next attempt
+// back by lease, so that another worker skips them while they are being sent
+func claimDueEmails(ctx context.Context, limit int, lease time.Duration) ([]queuedEmail, error) {
+	now := time.Now()
+	rows, err := database.DB().QueryContext(ctx,
+		`UPDATE email_outbox SET next_attempt_at = $1
+		WHERE id IN (
+			SELECT id FROM email_outbox
+			WHERE status = 'pending' AND next_attempt_at <= $2
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED)
+		RETURNING id, recipient, subject, text_body, html_body, attempts;`,
+		now.Add(lease), now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due emails: %w", err)
+	}
+	defer rows.Close()
+
+	var emails []queuedEmail
+	for rows.Next() {
+		var e queuedEmail
+		if err := rows.Scan(&e.id, &e.to, &e.subject, &e.textBody, &e.htmlBody, &e.attempts); err != nil {
+			return nil, fmt.Errorf("failed to scan queued email: %w", err)
+		}
+		emails = append(emails, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate queued emails: %w", err)
+	}
+	return emails, nil
+}
+
+// markSent records a delivery and clears the bodies, which may hold secrets
+func markSent(ctx context.Context, id int64) error {
+	_, err := database.DB().ExecContext(ctx,
+		`UPDATE email_outbox
+		SET status = 'sent', sent_at = $2, attempts = attempts + 1, last_error = NULL,
+			text_body = '', html_body = ''
+		WHERE id = $1;`,
+		id, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to mark email %d as sent: %w", id, err)
+	}
+	return nil
+}
+
+// markFailed schedules the next attempt of a failed delivery, or
+// dead-letters the email once EMAIL_MAX_ATTEMPTS is reached; its bodies are
+// kept for a requeue until deadRetention has passed
+func markFailed(ctx context.Context, e queuedEmail, sendErr error) error {
+	attempts := e.attempts + 1
+	status := StatusPending
+	if attempts >= config.EmailMaxAttempts {
+		status = StatusDead
+	}
+
+	lastError := sendErr.Error()
+	if len(lastError) > maxLastErrorLength {
+		lastError = lastError[:maxLastErrorLength]
+	}
+
+	now := time.Now()
+	_, err := database.DB().ExecContext(ctx,
+		`UPDATE email_outbox SET status = $2, attempts = $3, last_error = $4, next_attempt_at = $5,
+			dead_at = CASE WHEN $2 = 'dead' THEN $6::timestamptz END
+		WHERE id = $1;`,
+		e.id, status, attempts, lastError, now.Add(retryDelay(attempts)), now)
+	if err != nil {
+		return fmt.Errorf("failed to record failure of email %d: %w", e.id, err)
+	}
+	return nil
+}
+
+// retryDelay is the wait after the given number of failed attempts:
+// EMAIL_RETRY_BASE_SECONDS doubled on each further failure, capped at
+// EMAIL_RETRY_MAX_SECONDS
+func retryDelay(attempts int) time.Duration {
+	delay := time.Duration(config.EmailRetryBaseSeconds) * time.Second
+	maxDelay := time.Duration(config.EmailRetryMaxSeconds) * time.Second
+	for i := 1; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDelay)
+}
+
+func returnOutboxEmails(ctx context.Context, filter OutboxEmailFilter) (OutboxEmails, error) {
+	var conditions []string
+	var args []any
+
+	addCondition := func(clause string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf("%s $%d", clause, len(args)))
+	}
+
+	if filter.Status != "" {
+		addCondition("status =", filter.Status)
+	}
+	if filter.Recipient != "" {
+		addCondition("recipient =", filter.Recipient)
+	}
+
+	query := `SELECT id, recipient, subject, status, attempts, last_error, next_attempt_at, created_at, sent_at
+        FROM email_outbox`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := database.DB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query outbox emails: %w", err)
+	}
+	defer rows.Close()
+
+	emails := OutboxEmails{}
+	for rows.Next() {
+		var e OutboxEmail
+		err := rows.Scan(&e.ID, &e.Recipient, &e.Subject, &e.Status, &e.Attempts, &e.LastError,
+			&e.NextAttemptAt, &e.CreatedAt, &e.SentAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan outbox email: %w", err)
+		}
+		emails = append(emails, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate outbox emails: %w", err)
+	}
+
+	return emails, nil
+}
+
+// requeueEmail gives a dead-lettered email a fresh set of attempts; it
+// returns false when id is not a dead email
+func requeueEmail(ctx context.Context, id int64) (bool, error) {
+	result, err := database.DB().ExecContext(ctx,
+		`UPDATE email_outbox SET status = 'pending', attempts = 0, next_attempt_at = $2, dead_at = NULL
+		WHERE id = $1 AND status = 'dead';`,
+		id, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("failed to requeue email: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// CleanupSentEmails deletes the sent emails older than the retention period
+func CleanupSentEmails(ctx context.Context) (int64, error) {
+	result, err := database.DB().ExecContext(ctx,
+		`DELETE FROM email_outbox WHERE status = 'sent' AND sent_at < $1;`,
+		time.Now().Add(-sentRetention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to cleanup sent emails: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// CleanupDeadEmails deletes the dead emails older than the retention period,
+// so that their bodies do not keep reset links or codes around
+func CleanupDeadEmails(ctx context.Context) (int64, error) {
+	result, err := database.DB().ExecContext(ctx,
+		`DELETE FROM email_outbox WHERE status = 'dead' AND dead_at < $1;`,
+		time.Now().Add(-deadRetention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to cleanup dead emails: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
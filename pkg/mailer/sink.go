@@ -0,0 +1,85 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Angak0k/pimpmypack/pkg/helper"
+)
+
+// MemorySink keeps sent emails in memory so that tests can assert on them
+type MemorySink struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewMemorySink returns an empty in-memory sink
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+// SendEmail records the email
+func (s *MemorySink) SendEmail(to, subject, textBody, htmlBody string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, Message{
+		To: to, Subject: subject, TextBody: textBody, HTMLBody: htmlBody, SentAt: time.Now(),
+	})
+	return nil
+}
+
+// Messages returns the emails received so far, oldest first
+func (s *MemorySink) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.messages...)
+}
+
+// MessagesTo returns the emails received for one recipient, oldest first
+func (s *MemorySink) MessagesTo(to string) []Message {
+	var found []Message
+	for _, m := range s.Messages() {
+		if m.To == to {
+			found = append(found, m)
+		}
+	}
+	return found
+}
+
+// Reset forgets the received emails
+func (s *MemorySink) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = nil
+}
+
+// DirSink writes each email as a .eml file in a local directory, for
+// development without an SMTP server
+type DirSink struct {
+	Dir  string
+	From string
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9@._-]`)
+
+// SendEmail writes the MIME message to <dir>/<timestamp>-<recipient>.eml
+func (s *DirSink) SendEmail(to, subject, textBody, htmlBody string) error {
+	msg, err := helper.BuildMIMEMessage("PimpMyPack", s.From, to, subject, textBody, htmlBody)
+	if err != nil {
+		return fmt.Errorf("failed to build email message: %w", err)
+	}
+	if err := os.MkdirAll(s.Dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create mail sink directory: %w", err)
+	}
+
+	name := strconv.FormatInt(time.Now().UnixNano(), 10) + "-" + unsafeFileChars.ReplaceAllString(to, "_") + ".eml"
+	if err := os.WriteFile(filepath.Join(s.Dir, name), msg, 0o600); err != nil {
+		return fmt.Errorf("failed to write email to mail sink: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package mailer
+
+import "time"
+
+// Outbox email statuses
+const (
+	StatusPending = "pending"
+	StatusSent    = "sent"
+	StatusDead    = "dead"
+)
+
+// OutboxEmail is an email of the outbox as shown to admins; bodies are left out
+type OutboxEmail struct {
+	ID            int64      `json:"id"`
+	Recipient     string     `json:"recipient"`
+	Subject       string     `json:"subject"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     *string    `json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+}
+
+// OutboxEmails represents a list of outbox emails
+type OutboxEmails []OutboxEmail
+
+// OutboxEmailFilter narrows the outbox listing; zero values mean no filtering
+type OutboxEmailFilter struct {
+	Status    string
+	Recipient string
+	Limit     int
+	Offset    int
+}
+
+// Message is an email handed to a sink
+type Message struct {
+	To       string    `json:"to"`
+	Subject  string    `json:"subject"`
+	TextBody string    `json:"text_body"`
+	HTMLBody string    `json:"html_body"`
+	SentAt   time.Time `json:"sent_at"`
+}
+
+// queuedEmail is an outbox row claimed by the worker
+type queuedEmail struct {
+	id       int64
+	to       string
+	subject  string
+	textBody string
+	htmlBody string
+	attempts int
+}
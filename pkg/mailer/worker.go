@@ -0,0 +1,64 @@
+package mailer
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Angak0k/pimpmypack/pkg/helper"
+)
+
+const (
+	// pollInterval is how often the worker looks for due emails
+	pollInterval = 5 * time.Second
+	// batchSize is the number of emails claimed at once
+	batchSize = 20
+	// claimLease is how long a claimed email is hidden from other workers
+	claimLease = 5 * time.Minute
+)
+
+// RunWorker sends the due outbox emails every pollInterval until ctx is done.
+// Several replicas can run it: claiming uses row locks with SKIP LOCKED.
+func RunWorker(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := ProcessOutbox(ctx); err != nil {
+				helper.LogAndSanitize(err, "email outbox: process failed")
+			}
+		}
+	}
+}
+
+// ProcessOutbox sends one batch of due emails and returns how many were
+// delivered. Failures are rescheduled or dead-lettered, not returned.
+func ProcessOutbox(ctx context.Context) (int, error) {
+	emails, err := claimDueEmails(ctx, batchSize, claimLease)
+	if err != nil {
+		return 0, err
+	}
+
+	sender := getTransport()
+	sent := 0
+	for _, e := range emails {
+		if sendErr := sender.SendEmail(e.to, e.subject, e.textBody, e.htmlBody); sendErr != nil {
+			//nolint:gosec // the email ID is generated by the database
+			log.Printf("email outbox: delivery of email %d failed (attempt %d)", e.id, e.attempts+1)
+			if err := markFailed(ctx, e, sendErr); err != nil {
+				return sent, err
+			}
+			continue
+		}
+		if err := markSent(ctx, e.id); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+
+	return sent, nil
+}
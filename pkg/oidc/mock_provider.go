@@ -0,0 +1,227 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// MockUser is the identity the MockProvider authenticates
+type MockUser struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	PreferredUsername string
+	GivenName         string
+	FamilyName        string
+}
+
+// mockGrant is an issued authorization code awaiting exchange
+type mockGrant struct {
+	user          MockUser
+	clientID      string
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+}
+
+// MockProvider is an in-process OpenID provider for tests and local
+// development. It serves discovery, JWKS and token endpoints and signs ID
+// tokens with an RS256 key; Authorize stands in for the browser login.
+type MockProvider struct {
+	ClientID     string
+	ClientSecret string
+
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	kid    string
+
+	mu     sync.Mutex
+	grants map[string]mockGrant
+}
+
+// NewMockProvider starts a mock provider accepting the given client credentials
+func NewMockProvider(clientID, clientSecret string) (*MockProvider, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate mock provider key: %w", err)
+	}
+
+	m := &MockProvider{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		key:          key,
+		kid:          "mock-1",
+		grants:       map[string]mockGrant{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", m.handleDiscovery)
+	mux.HandleFunc("GET /jwks", m.handleJWKS)
+	mux.HandleFunc("POST /token", m.handleToken)
+	m.server = httptest.NewServer(mux)
+
+	return m, nil
+}
+
+// Issuer returns the provider issuer identifier
+func (m *MockProvider) Issuer() string {
+	return m.server.URL
+}
+
+// DiscoveryURL returns the provider discovery document URL
+func (m *MockProvider) DiscoveryURL() string {
+	return m.server.URL + "/.well-known/openid-configuration"
+}
+
+// Close stops the provider
+func (m *MockProvider) Close() {
+	m.server.Close()
+}
+
+// Authorize simulates the user logging in at the authorization URL built by
+// Provider.AuthCodeURL and returns the code and state the provider would
+// send to the redirect URI
+func (m *MockProvider) Authorize(authURL string, user MockUser) (string, string, error) {
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid authorization URL: %w", err)
+	}
+	query := parsed.Query()
+
+	switch {
+	case query.Get("response_type") != "code":
+		return "", "", errors.New("unsupported response_type")
+	case query.Get("client_id") != m.ClientID:
+		return "", "", errors.New("unknown client_id")
+	case query.Get("redirect_uri") == "":
+		return "", "", errors.New("missing redirect_uri")
+	case query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "":
+		return "", "", errors.New("PKCE S256 code challenge required")
+	}
+
+	code, err := RandomToken(16)
+	if err != nil {
+		return "", "", err
+	}
+
+	m.mu.Lock()
+	m.grants[code] = mockGrant{
+		user:          user,
+		clientID:      query.Get("client_id"),
+		redirectURI:   query.Get("redirect_uri"),
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+	}
+	m.mu.Unlock()
+
+	return code, query.Get("state"), nil
+}
+
+func (m *MockProvider) handleDiscovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, discoveryDocument{
+		Issuer:                        m.server.URL,
+		AuthorizationEndpoint:         m.server.URL + "/authorize",
+		TokenEndpoint:                 m.server.URL + "/token",
+		JWKSURI:                       m.server.URL + "/jwks",
+		CodeChallengeMethodsSupported: []string{"S256"},
+	})
+}
+
+func (m *MockProvider) handleJWKS(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, jsonWebKeySet{Keys: []jsonWebKey{{
+		Kty: "RSA",
+		Kid: m.kid,
+		Use: "sig",
+		N:   base64.RawURLEncoding.EncodeToString(m.key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.key.E)).Bytes()),
+	}}})
+}
+
+func (m *MockProvider) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeTokenError(w, "invalid_request")
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	m.mu.Lock()
+	grant, ok := m.grants[code]
+	// Codes are single use, even when the exchange fails
+	delete(m.grants, code)
+	m.mu.Unlock()
+
+	switch {
+	case r.PostForm.Get("grant_type") != "authorization_code":
+		writeTokenError(w, "unsupported_grant_type")
+		return
+	case r.PostForm.Get("client_id") != m.ClientID || r.PostForm.Get("client_secret") != m.ClientSecret:
+		writeJSON(w, http.StatusUnauthorized, tokenResponse{Error: "invalid_client"})
+		return
+	case !ok || grant.clientID != m.ClientID || grant.redirectURI != r.PostForm.Get("redirect_uri"):
+		writeTokenError(w, "invalid_grant")
+		return
+	case CodeChallengeS256(r.PostForm.Get("code_verifier")) != grant.codeChallenge:
+		writeTokenError(w, "invalid_grant")
+		return
+	}
+
+	idToken, err := m.SignIDToken(grant.user, grant.nonce, time.Hour)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, tokenResponse{Error: "server_error"})
+		return
+	}
+
+	accessToken, err := RandomToken(16)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, tokenResponse{Error: "server_error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, tokenResponse{AccessToken: accessToken, TokenType: "Bearer", IDToken: idToken})
+}
+
+// SignIDToken issues an ID token for user, valid for ttl (negative for an
+// already expired token)
+func (m *MockProvider) SignIDToken(user MockUser, nonce string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    m.server.URL,
+			Subject:   user.Subject,
+			Audience:  jwt.ClaimStrings{m.ClientID},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+		Nonce:             nonce,
+		Email:             user.Email,
+		EmailVerified:     user.EmailVerified,
+		PreferredUsername: user.PreferredUsername,
+		GivenName:         user.GivenName,
+		FamilyName:        user.FamilyName,
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = m.kid
+	return token.SignedString(m.key)
+}
+
+func writeTokenError(w http.ResponseWriter, code string) {
+	writeJSON(w, http.StatusBadRequest, tokenResponse{Error: code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,144 @@
+package oidc
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testClientID     = "pimpmypack"
+	testClientSecret = "s3cret"
+	testRedirectURL  = "http://localhost:8080/oidc/callback"
+)
+
+func newTestProvider(t *testing.T) (*MockProvider, *Provider) {
+	t.Helper()
+	mock, err := NewMockProvider(testClientID, testClientSecret)
+	require.NoError(t, err)
+	t.Cleanup(mock.Close)
+
+	provider, err := Discover(context.Background(), mock.DiscoveryURL(), ClientConfig{
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  testRedirectURL,
+		Scopes:       []string{"openid", "email", "profile"},
+	})
+	require.NoError(t, err)
+	return mock, provider
+}
+
+var testUser = MockUser{
+	Subject:           "sub-123",
+	Email:             "jane@example.com",
+	EmailVerified:     true,
+	PreferredUsername: "jane",
+}
+
+func TestCodeChallengeS256(t *testing.T) {
+	// RFC 7636 appendix B
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+		CodeChallengeS256("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+
+	verifier, err := NewCodeVerifier()
+	require.NoError(t, err)
+	assert.Len(t, verifier, 43)
+}
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	mock, provider := newTestProvider(t)
+	assert.Equal(t, mock.Issuer(), provider.Issuer())
+
+	verifier, err := NewCodeVerifier()
+	require.NoError(t, err)
+	authURL := provider.AuthCodeURL("state-1", "nonce-1", CodeChallengeS256(verifier))
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "openid email profile", parsed.Query().Get("scope"))
+	assert.Equal(t, testRedirectURL, parsed.Query().Get("redirect_uri"))
+
+	t.Run("valid exchange returns verified claims", func(t *testing.T) {
+		code, state, err := mock.Authorize(authURL, testUser)
+		require.NoError(t, err)
+		assert.Equal(t, "state-1", state)
+
+		claims, err := provider.Exchange(context.Background(), code, verifier, "nonce-1")
+		require.NoError(t, err)
+		assert.Equal(t, "sub-123", claims.Subject)
+		assert.Equal(t, "jane@example.com", claims.Email)
+		assert.True(t, claims.EmailVerified)
+		assert.Equal(t, "jane", claims.PreferredUsername)
+	})
+
+	t.Run("code is single use", func(t *testing.T) {
+		code, _, err := mock.Authorize(authURL, testUser)
+		require.NoError(t, err)
+		_, err = provider.Exchange(context.Background(), code, verifier, "nonce-1")
+		require.NoError(t, err)
+		_, err = provider.Exchange(context.Background(), code, verifier, "nonce-1")
+		require.ErrorIs(t, err, ErrTokenExchange)
+	})
+
+	t.Run("wrong code verifier is rejected", func(t *testing.T) {
+		code, _, err := mock.Authorize(authURL, testUser)
+		require.NoError(t, err)
+		other, err := NewCodeVerifier()
+		require.NoError(t, err)
+		_, err = provider.Exchange(context.Background(), code, other, "nonce-1")
+		require.ErrorIs(t, err, ErrTokenExchange)
+	})
+
+	t.Run("nonce mismatch is rejected", func(t *testing.T) {
+		code, _, err := mock.Authorize(authURL, testUser)
+		require.NoError(t, err)
+		_, err = provider.Exchange(context.Background(), code, verifier, "other-nonce")
+		require.ErrorIs(t, err, ErrInvalidIDToken)
+	})
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	mock, provider := newTestProvider(t)
+
+	t.Run("expired token", func(t *testing.T) {
+		raw, err := mock.SignIDToken(testUser, "n", -time.Minute)
+		require.NoError(t, err)
+		_, err = provider.VerifyIDToken(context.Background(), raw, "n")
+		require.ErrorIs(t, err, ErrInvalidIDToken)
+	})
+
+	t.Run("token of another provider", func(t *testing.T) {
+		other, err := NewMockProvider(testClientID, testClientSecret)
+		require.NoError(t, err)
+		defer other.Close()
+
+		raw, err := other.SignIDToken(testUser, "n", time.Hour)
+		require.NoError(t, err)
+		_, err = provider.VerifyIDToken(context.Background(), raw, "n")
+		require.ErrorIs(t, err, ErrInvalidIDToken)
+	})
+
+	t.Run("token for another client", func(t *testing.T) {
+		other, err := Discover(context.Background(), mock.DiscoveryURL(), ClientConfig{ClientID: "someone-else"})
+		require.NoError(t, err)
+
+		raw, err := mock.SignIDToken(testUser, "n", time.Hour)
+		require.NoError(t, err)
+		_, err = other.VerifyIDToken(context.Background(), raw, "n")
+		require.ErrorIs(t, err, ErrInvalidIDToken)
+	})
+}
+
+func TestDiscover_Unreachable(t *testing.T) {
+	mock, err := NewMockProvider(testClientID, testClientSecret)
+	require.NoError(t, err)
+	discoveryURL := mock.DiscoveryURL()
+	mock.Close()
+
+	_, err = Discover(context.Background(), discoveryURL, ClientConfig{ClientID: testClientID})
+	require.Error(t, err)
+}
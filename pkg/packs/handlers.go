@@ -1,17 +1,25 @@
 package packs
 
 import (
+	"cmp"
 	"context"
 	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
 
+	"github.com/Angak0k/pimpmypack/pkg/currencies"
+	"github.com/Angak0k/pimpmypack/pkg/database"
 	"github.com/Angak0k/pimpmypack/pkg/helper"
 	"github.com/Angak0k/pimpmypack/pkg/inventories"
 	"github.com/Angak0k/pimpmypack/pkg/security"
 	"github.com/Angak0k/pimpmypack/pkg/trails"
+	"github.com/Angak0k/pimpmypack/pkg/units"
 	"github.com/gin-gonic/gin"
 )
 
@@ -121,7 +129,7 @@ func PostPack(c *gin.Context) {
 		Adventure:       input.Adventure,
 	}
 
-	err = insertPack(c.Request.Context(), &newPack)
+	err = insertPack(c.Request.Context(), database.DB(), &newPack)
 	if err != nil {
 		helper.LogAndSanitize(err, "post pack: insert pack failed")
 		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
@@ -193,8 +201,10 @@ func PutPackByID(c *gin.Context) {
 		UpdatedAt:       existingPack.UpdatedAt,      // Preserve existing UpdatedAt
 		SharingCode:     existingPack.SharingCode,    // Preserve existing SharingCode
 		IsFavorite:      existingPack.IsFavorite,     // Preserve existing IsFavorite
+		Template:        existingPack.Template,       // Preserve existing Template
 		PackWeight:      existingPack.PackWeight,     // Preserve computed field
 		PackItemsCount:  existingPack.PackItemsCount, // Preserve computed field
+		WishlistWeight:  existingPack.WishlistWeight, // Preserve computed field
 		HasImage:        existingPack.HasImage,       // Preserve computed field
 	}
 
@@ -243,7 +253,9 @@ func DeletePackByID(c *gin.Context) {
 // @Security Bearer
 // @Tags Packs
 // @Produce  json
+// @Param units query string false "Add weight displays in this unit system" Enums(preferred, metric, imperial)
 // @Success 200 {object} Packs "Packs"
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid units"
 // @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
 // @Failure 404 {object} apitypes.ErrorResponse "No pack found"
 // @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
@@ -257,6 +269,12 @@ func GetMyPacks(c *gin.Context) {
 		return
 	}
 
+	system, err := units.FromRequest(c, userID)
+	if err != nil {
+		units.RespondError(c, err, "get my packs: resolve units failed")
+		return
+	}
+
 	packs, err := findPacksByUserID(c.Request.Context(), userID)
 
 	if err != nil {
@@ -270,6 +288,7 @@ func GetMyPacks(c *gin.Context) {
 		return
 	}
 
+	setPackWeightDisplays(*packs, system)
 	c.IndentedJSON(http.StatusOK, *packs)
 }
 
@@ -280,8 +299,9 @@ func GetMyPacks(c *gin.Context) {
 // @Tags Packs
 // @Produce  json
 // @Param id path int true "Pack ID"
+// @Param units query string false "Add weight displays in this unit system" Enums(preferred, metric, imperial)
 // @Success 200 {object} Pack
-// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format or units"
 // @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
 // @Failure 403 {object} apitypes.ErrorResponse "This pack does not belong to you"
 // @Failure 404 {object} apitypes.ErrorResponse "Pack not found"
@@ -301,6 +321,12 @@ func GetMyPackByID(c *gin.Context) {
 		return
 	}
 
+	system, err := units.FromRequest(c, userID)
+	if err != nil {
+		units.RespondError(c, err, "get my pack by ID: resolve units failed")
+		return
+	}
+
 	// Check existence first
 	pack, err := findPackByID(c.Request.Context(), id)
 	if err != nil {
@@ -319,6 +345,7 @@ func GetMyPackByID(c *gin.Context) {
 		return
 	}
 
+	pack.PackWeightDisplay = units.NewWeight(pack.PackWeight, system)
 	c.IndentedJSON(http.StatusOK, *pack)
 }
 
@@ -372,7 +399,7 @@ func PostMyPack(c *gin.Context) {
 		Adventure:       input.Adventure,
 	}
 
-	err = insertPack(c.Request.Context(), &newPack)
+	err = insertPack(c.Request.Context(), database.DB(), &newPack)
 	if err != nil {
 		helper.LogAndSanitize(err, "post my pack: insert pack failed")
 		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
@@ -609,9 +636,12 @@ func PostPackContent(c *gin.Context) {
 		Quantity:   input.Quantity,
 		Worn:       input.Worn,
 		Consumable: input.Consumable,
+		Notes:      input.Notes,
+		Position:   input.Position,
+		Optional:   input.Optional,
 	}
 
-	err := insertPackContent(c.Request.Context(), &newPackContent)
+	err := insertPackContent(c.Request.Context(), database.DB(), &newPackContent)
 	if err != nil {
 		helper.LogAndSanitize(err, "post pack content: insert pack content failed")
 		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
@@ -703,8 +733,11 @@ func PostMyPackContent(c *gin.Context) {
 	newPackContent.Quantity = requestData.Quantity
 	newPackContent.Worn = requestData.Worn
 	newPackContent.Consumable = requestData.Consumable
+	newPackContent.Notes = valueOr(requestData.Notes, "")
+	newPackContent.Position = requestData.Position
+	newPackContent.Optional = valueOr(requestData.Optional, false)
 
-	err = insertPackContent(c.Request.Context(), &newPackContent)
+	err = insertPackContent(c.Request.Context(), database.DB(), &newPackContent)
 	if err != nil {
 		helper.LogAndSanitize(err, "post my pack content: insert pack content failed")
 		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
@@ -756,6 +789,9 @@ func PutPackContentByID(c *gin.Context) {
 		Quantity:   input.Quantity,
 		Worn:       input.Worn,
 		Consumable: input.Consumable,
+		Notes:      valueOr(input.Notes, existingPackContent.Notes),
+		Position:   cmp.Or(input.Position, existingPackContent.Position),
+		Optional:   valueOr(input.Optional, existingPackContent.Optional),
 		CreatedAt:  existingPackContent.CreatedAt, // Preserve existing CreatedAt
 		UpdatedAt:  existingPackContent.UpdatedAt, // Preserve existing UpdatedAt
 	}
@@ -858,6 +894,9 @@ func PutMyPackContentByID(c *gin.Context) {
 		Quantity:   input.Quantity,
 		Worn:       input.Worn,
 		Consumable: input.Consumable,
+		Notes:      valueOr(input.Notes, existingPackContent.Notes), // Keep the omitted fields
+		Position:   cmp.Or(input.Position, existingPackContent.Position),
+		Optional:   valueOr(input.Optional, existingPackContent.Optional),
 		CreatedAt:  existingPackContent.CreatedAt, // Preserve existing CreatedAt
 		UpdatedAt:  existingPackContent.UpdatedAt, // Preserve existing UpdatedAt
 	}
@@ -1023,8 +1062,9 @@ func GetPackContentsByPackID(c *gin.Context) {
 // @Tags Packs
 // @Produce  json
 // @Param id path int true "Pack Content ID"
+// @Param units query string false "Add weight displays in this unit system" Enums(preferred, metric, imperial)
 // @Success 200 {object} PackContent "Pack Item"
-// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format or units"
 // @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
 // @Failure 403 {object} apitypes.ErrorResponse "This pack does not belong to you"
 // @Failure 404 {object} apitypes.ErrorResponse "Pack not found"
@@ -1046,6 +1086,12 @@ func GetMyPackContentsByPackID(c *gin.Context) {
 		return
 	}
 
+	system, err := units.FromRequest(c, userID)
+	if err != nil {
+		units.RespondError(c, err, "get my pack contents by pack ID: resolve units failed")
+		return
+	}
+
 	myPack, err := CheckPackOwnership(c.Request.Context(), id, userID)
 	if err != nil {
 		helper.LogAndSanitize(err, "get my pack contents by pack ID: check ownership failed")
@@ -1053,40 +1099,100 @@ func GetMyPackContentsByPackID(c *gin.Context) {
 		return
 	}
 
-	if myPack {
-		packContents, err = returnPackContentsByPackID(c.Request.Context(), id)
-		if err != nil {
-			if errors.Is(err, ErrPackNotFound) {
-				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Pack not found"})
-				return
-			}
-			helper.LogAndSanitize(err, "get my pack contents by pack ID: return pack contents failed")
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+	if !myPack {
+		c.IndentedJSON(http.StatusForbidden, gin.H{"error": "This pack does not belong to you"})
+		return
+	}
+
+	packContents, err = returnPackContentsByPackID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrPackNotFound) {
+			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Pack not found"})
 			return
 		}
+		helper.LogAndSanitize(err, "get my pack contents by pack ID: return pack contents failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
 
-		c.IndentedJSON(http.StatusOK, packContents)
-	} else {
-		c.IndentedJSON(http.StatusForbidden, gin.H{"error": "This pack does not belong to you"})
+	if err := setPreferredPrices(c.Request.Context(), userID, *packContents); err != nil {
+		helper.LogAndSanitize(err, "get my pack contents by pack ID: convert prices failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
 		return
 	}
+	setContentWeightDisplays(*packContents, system)
+
+	c.IndentedJSON(http.StatusOK, packContents)
 }
 
-// Share a pack by ID
-// @Summary Share a pack by ID
-// @Description Generate a sharing code for a pack to make it publicly accessible (idempotent)
+// Reorder the items of my pack
+// @Summary Reorder the items of a pack
+// @Description Sets the position of the listed pack contents to their index in the list. Items are
+// @Description ordered by position within their category; the contents left out keep their position.
 // @Security Bearer
 // @Tags Packs
+// @Accept  json
 // @Produce  json
 // @Param id path int true "Pack ID"
-// @Success 200 {object} map[string]string "Pack shared successfully"
+// @Param order body PackContentOrderRequest true "Pack content IDs in their new order"
+// @Success 200 {object} PackContentWithItems
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid payload, duplicate or unknown pack content"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 403 {object} apitypes.ErrorResponse "This pack does not belong to you"
+// @Failure 404 {object} apitypes.ErrorResponse "Pack not found"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/mypack/{id}/packcontents/order [put]
+func PutMyPackContentsOrder(c *gin.Context) {
+	pack, ok := findMyPack(c, "put my pack contents order")
+	if !ok {
+		return
+	}
+
+	var input PackContentOrderRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		helper.LogAndSanitize(err, "put my pack contents order: bind JSON failed")
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": helper.ErrMsgBadRequest})
+		return
+	}
+
+	err := reorderPackContents(c.Request.Context(), pack.ID, input.PackContentIDs)
+	if err != nil {
+		if errors.Is(err, ErrDuplicatePackContent) || errors.Is(err, ErrUnknownPackContent) {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		helper.LogAndSanitize(err, "put my pack contents order: reorder pack contents failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	contents, err := returnPackContentsByPackID(c.Request.Context(), pack.ID)
+	if err != nil {
+		helper.LogAndSanitize(err, "put my pack contents order: return pack contents failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, contents)
+}
+
+// Get the cost of my pack
+// @Summary Get the cost of a pack
+// @Description Sum the price x quantity of the items of a pack in the preferred currency of the user,
+// @Description with a breakdown by original currency. Currencies without an exchange rate are listed
+// @Description in missing_rates and left out of the total.
+// @Security Bearer
+// @Tags Packs
+// @Produce  json
+// @Param id path int true "Pack ID"
+// @Success 200 {object} PackCost
 // @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
 // @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
 // @Failure 403 {object} apitypes.ErrorResponse "This pack does not belong to you"
 // @Failure 404 {object} apitypes.ErrorResponse "Pack not found"
 // @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
-// @Router /v1/mypack/{id}/share [post]
-func ShareMyPack(c *gin.Context) {
+// @Router /v1/mypack/{id}/cost [get]
+func GetMyPackCost(c *gin.Context) {
 	id, err := helper.StringToUint(c.Param("id"))
 	if err != nil {
 		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
@@ -1095,244 +1201,936 @@ func ShareMyPack(c *gin.Context) {
 
 	userID, err := security.ExtractTokenID(c)
 	if err != nil {
-		helper.LogAndSanitize(err, "share my pack: extract token ID failed")
+		helper.LogAndSanitize(err, "get my pack cost: extract token ID failed")
 		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
 		return
 	}
 
-	// Check if pack exists
-	_, err = FindPackByID(c.Request.Context(), id)
+	myPack, err := CheckPackOwnership(c.Request.Context(), id, userID)
+	if err != nil {
+		helper.LogAndSanitize(err, "get my pack cost: check ownership failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+	if !myPack {
+		c.IndentedJSON(http.StatusForbidden, gin.H{"error": "This pack does not belong to you"})
+		return
+	}
+
+	packContents, err := returnPackContentsByPackID(c.Request.Context(), id)
 	if err != nil {
 		if errors.Is(err, ErrPackNotFound) {
 			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Pack not found"})
 			return
 		}
-		helper.LogAndSanitize(err, "share my pack: find pack failed")
+		helper.LogAndSanitize(err, "get my pack cost: return pack contents failed")
 		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
 		return
 	}
 
-	// Share the pack (idempotent)
-	sharingCode, err := sharePackByID(c.Request.Context(), id, userID)
+	preferred, cv, err := currencies.ConverterFor(c.Request.Context(), userID)
 	if err != nil {
-		if errors.Is(err, ErrPackNotOwned) {
-			c.IndentedJSON(http.StatusForbidden, gin.H{"error": "This pack does not belong to you"})
-			return
-		}
-		helper.LogAndSanitize(err, "share my pack: share pack failed")
+		helper.LogAndSanitize(err, "get my pack cost: load exchange rates failed")
 		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, gin.H{
-		"message":      "Pack shared successfully",
-		"sharing_code": sharingCode,
-	})
+	c.IndentedJSON(http.StatusOK, computePackCost(id, *packContents, cv, preferred))
 }
 
-// Unshare a pack by ID
-// @Summary Unshare a pack by ID
-// @Description Remove the sharing code from a pack to make it private (idempotent)
+// Suggest lighter alternatives for the items of a pack
+// @Summary Suggest lighter items for a pack
+// @Description Suggests lighter items of the same category for each item of a pack of the user: unused
+// @Description items of the user's inventory (in no pack and not retired) first, then items of other
+// @Description users' shared packs, lightest first. Each suggestion gives the weight saved and the price
+// @Description difference in the preferred currency of the user.
 // @Security Bearer
 // @Tags Packs
 // @Produce  json
 // @Param id path int true "Pack ID"
-// @Success 200 {object} apitypes.OkResponse "Pack unshared successfully"
-// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
+// @Param limit query int false "Suggestions per item, 3 by default and 10 at most"
+// @Success 200 {object} PackSuggestions
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format or limit"
 // @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
 // @Failure 403 {object} apitypes.ErrorResponse "This pack does not belong to you"
 // @Failure 404 {object} apitypes.ErrorResponse "Pack not found"
 // @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
-// @Router /v1/mypack/{id}/share [delete]
-func UnshareMyPack(c *gin.Context) {
-	packAction(c, "unshare", unsharePackByID, "Pack unshared successfully")
+// @Router /v1/mypack/{id}/suggestions [get]
+func GetMyPackSuggestions(c *gin.Context) {
+	pack, ok := findMyPack(c, "get my pack suggestions")
+	if !ok {
+		return
+	}
+
+	limit := defaultSuggestionLimit
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxSuggestionLimit {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": ErrInvalidSuggestionLimit.Error()})
+			return
+		}
+	}
+
+	suggestions, err := suggestLighterItems(c.Request.Context(), pack, limit)
+	if err != nil {
+		helper.LogAndSanitize(err, "get my pack suggestions: suggest lighter items failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, suggestions)
 }
 
-// Favorite a pack by ID
-// @Summary Favorite a pack by ID
-// @Description Mark a pack as favorite. Only one pack per user can be favorite at a time (idempotent)
+// Plan the purchases of the wishlist for a pack
+// @Summary Plan wishlist purchases for a pack
+// @Description Matches each wishlist item of the user with the heaviest owned item of the same category in
+// @Description the pack that it would replace, and ranks them by the grams saved per unit of the preferred
+// @Description currency of the user.
 // @Security Bearer
 // @Tags Packs
 // @Produce  json
 // @Param id path int true "Pack ID"
-// @Success 200 {object} apitypes.OkResponse "Pack favorited successfully"
+// @Success 200 {object} PurchasePlan
 // @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
 // @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
 // @Failure 403 {object} apitypes.ErrorResponse "This pack does not belong to you"
 // @Failure 404 {object} apitypes.ErrorResponse "Pack not found"
 // @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
-// @Router /v1/mypack/{id}/favorite [post]
-func FavoriteMyPack(c *gin.Context) {
-	packAction(c, "favorite", favoritePackByID, "Pack favorited successfully")
+// @Router /v1/mypack/{id}/planner [get]
+func GetMyPackPlanner(c *gin.Context) {
+	pack, ok := findMyPack(c, "get my pack planner")
+	if !ok {
+		return
+	}
+
+	plan, err := planPurchases(c.Request.Context(), pack)
+	if err != nil {
+		helper.LogAndSanitize(err, "get my pack planner: plan purchases failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, plan)
 }
 
-// Unfavorite a pack by ID
-// @Summary Unfavorite a pack by ID
-// @Description Remove favorite status from a pack (idempotent)
+// Get the trips of a pack
+// @Summary Get the trips of a pack
+// @Description Lists the trips logged on a pack of the user, most recent first
 // @Security Bearer
 // @Tags Packs
 // @Produce  json
 // @Param id path int true "Pack ID"
-// @Success 200 {object} apitypes.OkResponse "Pack unfavorited successfully"
+// @Success 200 {array} Trip
 // @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
 // @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
 // @Failure 403 {object} apitypes.ErrorResponse "This pack does not belong to you"
 // @Failure 404 {object} apitypes.ErrorResponse "Pack not found"
 // @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
-// @Router /v1/mypack/{id}/favorite [delete]
-func UnfavoriteMyPack(c *gin.Context) {
-	packAction(c, "unfavorite", unfavoritePackByID, "Pack unfavorited successfully")
+// @Router /v1/mypack/{id}/trips [get]
+func GetMyPackTrips(c *gin.Context) {
+	pack, ok := findMyPack(c, "get my pack trips")
+	if !ok {
+		return
+	}
+
+	trips, err := returnTripsByPackID(c.Request.Context(), pack.ID)
+	if err != nil {
+		helper.LogAndSanitize(err, "get my pack trips: return trips failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, trips)
 }
 
-// Duplicate a pack by ID
-// @Summary Duplicate a pack by ID
-// @Description Clone an existing pack (metadata + all pack contents) into a new pack
-// @Description owned by the same user. The new pack's name is prefixed with "COPY - ",
-// @Description is_favorite is reset to false and sharing_code is not copied. The pack
-// @Description image is intentionally not copied.
+// Log a trip on a pack
+// @Summary Log a trip on a pack
+// @Description Logs a trip made with a pack of the user. The current pack contents are recorded as used,
+// @Description which adds up in the usage of each inventory item. end_date defaults to start_date,
+// @Description nights to the days between them and distance_km to the distance of the pack trail.
 // @Security Bearer
 // @Tags Packs
+// @Accept  json
 // @Produce  json
 // @Param id path int true "Pack ID"
-// @Success 201 {object} Pack "Pack duplicated successfully"
-// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
+// @Param trip body TripRequest true "Trip"
+// @Success 201 {object} Trip
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid payload"
 // @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
 // @Failure 403 {object} apitypes.ErrorResponse "This pack does not belong to you"
 // @Failure 404 {object} apitypes.ErrorResponse "Pack not found"
 // @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
-// @Router /v1/mypack/{id}/duplicate [post]
-func DuplicateMyPack(c *gin.Context) {
-	id, err := helper.StringToUint(c.Param("id"))
-	if err != nil {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
-		return
-	}
-
-	userID, err := security.ExtractTokenID(c)
-	if err != nil {
-		helper.LogAndSanitize(err, "duplicate my pack: extract token ID failed")
-		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+// @Router /v1/mypack/{id}/trips [post]
+func PostMyPackTrip(c *gin.Context) {
+	pack, ok := findMyPack(c, "post my pack trip")
+	if !ok {
 		return
 	}
 
-	_, err = FindPackByID(c.Request.Context(), id)
-	if err != nil {
-		if errors.Is(err, ErrPackNotFound) {
-			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Pack not found"})
-			return
-		}
-		helper.LogAndSanitize(err, "duplicate my pack: find pack failed")
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+	var input TripRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		helper.LogAndSanitize(err, "post my pack trip: bind JSON failed")
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": helper.ErrMsgBadRequest})
 		return
 	}
 
-	newPackID, err := duplicatePackByID(c.Request.Context(), id, userID)
+	trip, err := newTrip(pack.ID, input)
 	if err != nil {
-		if errors.Is(err, ErrPackNotOwned) {
-			c.IndentedJSON(http.StatusForbidden, gin.H{"error": "This pack does not belong to you"})
-			return
-		}
-		if errors.Is(err, ErrPackNotFound) {
-			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Pack not found"})
-			return
-		}
-		helper.LogAndSanitize(err, "duplicate my pack: duplicate pack failed")
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	newPack, err := FindPackByID(c.Request.Context(), newPackID)
-	if err != nil {
-		helper.LogAndSanitize(err, "duplicate my pack: re-read new pack failed")
+	if err := insertTrip(c.Request.Context(), pack.UserID, &trip); err != nil {
+		helper.LogAndSanitize(err, "post my pack trip: insert trip failed")
 		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
 		return
 	}
 
-	c.IndentedJSON(http.StatusCreated, newPack)
+	c.IndentedJSON(http.StatusCreated, trip)
 }
 
-// packAction is a shared helper for pack operations that follow the same pattern:
-// parse ID, extract token, check pack exists, call action, handle ownership error.
-func packAction(c *gin.Context, actionName string,
-	action func(ctx context.Context, packID, userID uint) error, successMsg string) {
-	id, err := helper.StringToUint(c.Param("id"))
-	if err != nil {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
-		return
-	}
-
-	userID, err := security.ExtractTokenID(c)
-	if err != nil {
-		helper.LogAndSanitize(err, actionName+" my pack: extract token ID failed")
-		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+// Delete a trip of a pack
+// @Summary Delete a trip of a pack
+// @Description Deletes a trip logged on a pack of the user, and the item usage it recorded
+// @Security Bearer
+// @Tags Packs
+// @Produce  json
+// @Param id path int true "Pack ID"
+// @Param trip_id path int true "Trip ID"
+// @Success 200 {object} apitypes.OkResponse "Trip deleted"
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 403 {object} apitypes.ErrorResponse "This pack does not belong to you"
+// @Failure 404 {object} apitypes.ErrorResponse "Pack or trip not found"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/mypack/{id}/trips/{trip_id} [delete]
+func DeleteMyPackTrip(c *gin.Context) {
+	pack, ok := findMyPack(c, "delete my pack trip")
+	if !ok {
 		return
 	}
 
-	_, err = FindPackByID(c.Request.Context(), id)
+	tripID, err := helper.StringToUint(c.Param("trip_id"))
 	if err != nil {
-		if errors.Is(err, ErrPackNotFound) {
-			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Pack not found"})
-			return
-		}
-		helper.LogAndSanitize(err, actionName+" my pack: find pack failed")
-		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
 		return
 	}
 
-	err = action(c.Request.Context(), id, userID)
+	err = deleteTrip(c.Request.Context(), pack.ID, tripID)
 	if err != nil {
-		if errors.Is(err, ErrPackNotOwned) {
-			c.IndentedJSON(http.StatusForbidden, gin.H{"error": "This pack does not belong to you"})
+		if errors.Is(err, ErrTripNotFound) {
+			c.IndentedJSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
-		helper.LogAndSanitize(err, actionName+" my pack: "+actionName+" pack failed")
+		helper.LogAndSanitize(err, "delete my pack trip: delete trip failed")
 		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, gin.H{"message": successMsg})
-}
-
-// respondInsertExternalPackError maps insertExternalPack errors to an HTTP response
-func respondInsertExternalPackError(c *gin.Context, err error, logMsg string) {
-	if errors.Is(err, ErrTooManyItems) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": ErrTooManyItems.Error()})
-		return
-	}
-	helper.LogAndSanitize(err, logMsg)
-	c.JSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+	c.IndentedJSON(http.StatusOK, gin.H{"message": "Trip deleted"})
 }
 
-// Import from lighterpack
-// @Summary Import from lighterpack csv pack file
-// @Description Import from lighterpack csv pack file
+// List the variants of a pack
+// @Summary List the variants of a pack
+// @Description Lists the named variants of a pack of the user, with the totals of the pack contents they
+// @Description change and their weight delta from the pack.
 // @Security Bearer
 // @Tags Packs
-// @Accept  multipart/form-data
 // @Produce  json
-// @Param file formData file true "CSV file"
-// @Success 200 {object} ImportExternalPackResponse "CSV data imported successfully with pack ID"
-// @Failure 400 {object} apitypes.ErrorResponse "Invalid CSV format"
+// @Param id path int true "Pack ID"
+// @Success 200 {object} PackVariants
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
 // @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 403 {object} apitypes.ErrorResponse "This pack does not belong to you"
+// @Failure 404 {object} apitypes.ErrorResponse "Pack not found"
 // @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
-// @Router /v1/importfromlighterpack [post]
-func ImportFromLighterPack(c *gin.Context) {
-	var externalPack ExternalPack
-
-	userID, err := security.ExtractTokenID(c)
-	if err != nil {
-		helper.LogAndSanitize(err, "import from lighterpack: extract token ID failed")
-		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+// @Router /v1/mypack/{id}/variants [get]
+func GetMyPackVariants(c *gin.Context) {
+	pack, ok := findMyPack(c, "get my pack variants")
+	if !ok {
 		return
 	}
 
-	file, _, err := c.Request.FormFile("file")
+	variants, err := returnPackVariants(c.Request.Context(), pack)
 	if err != nil {
-		helper.LogAndSanitize(err, "import from lighterpack: form file failed")
-		c.JSON(http.StatusBadRequest, gin.H{"error": helper.ErrMsgBadRequest})
+		helper.LogAndSanitize(err, "get my pack variants: return variants failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
 		return
 	}
-	defer file.Close()
+
+	c.IndentedJSON(http.StatusOK, variants)
+}
+
+// Get a variant of a pack
+// @Summary Get a variant of a pack
+// @Description Returns a variant of a pack of the user with the pack contents it changes and their totals
+// @Security Bearer
+// @Tags Packs
+// @Produce  json
+// @Param id path int true "Pack ID"
+// @Param variant_id path int true "Variant ID"
+// @Success 200 {object} PackVariant
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 403 {object} apitypes.ErrorResponse "This pack does not belong to you"
+// @Failure 404 {object} apitypes.ErrorResponse "Pack or variant not found"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/mypack/{id}/variants/{variant_id} [get]
+func GetMyPackVariant(c *gin.Context) {
+	pack, ok := findMyPack(c, "get my pack variant")
+	if !ok {
+		return
+	}
+
+	variantID, err := helper.StringToUint(c.Param("variant_id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	variant, err := returnPackVariant(c.Request.Context(), pack, variantID)
+	if err != nil {
+		respondVariantError(c, err, "get my pack variant: return variant failed")
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, variant)
+}
+
+// Create a variant of a pack
+// @Summary Create a variant of a pack
+// @Description Creates a named variant of a pack of the user, such as "rain forecast", from changes to
+// @Description the pack contents: add an item, remove an item, or update its quantity, worn or consumable
+// @Description fields. The changes apply to the current pack contents whenever the variant is read.
+// @Security Bearer
+// @Tags Packs
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Pack ID"
+// @Param variant body PackVariantRequest true "Variant"
+// @Success 201 {object} PackVariant
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid payload or changes"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 403 {object} apitypes.ErrorResponse "This pack does not belong to you"
+// @Failure 404 {object} apitypes.ErrorResponse "Pack not found"
+// @Failure 409 {object} apitypes.ErrorResponse "A variant with this name already exists"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/mypack/{id}/variants [post]
+func PostMyPackVariant(c *gin.Context) {
+	saveMyPackVariant(c, "post my pack variant", false)
+}
+
+// Replace a variant of a pack
+// @Summary Replace a variant of a pack
+// @Description Replaces the name and the changes of a variant of a pack of the user
+// @Security Bearer
+// @Tags Packs
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Pack ID"
+// @Param variant_id path int true "Variant ID"
+// @Param variant body PackVariantRequest true "Variant"
+// @Success 200 {object} PackVariant
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid payload or changes"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 403 {object} apitypes.ErrorResponse "This pack does not belong to you"
+// @Failure 404 {object} apitypes.ErrorResponse "Pack or variant not found"
+// @Failure 409 {object} apitypes.ErrorResponse "A variant with this name already exists"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/mypack/{id}/variants/{variant_id} [put]
+func PutMyPackVariant(c *gin.Context) {
+	saveMyPackVariant(c, "put my pack variant", true)
+}
+
+// saveMyPackVariant creates a variant, or replaces the variant of the
+// variant_id path parameter when replace is set
+func saveMyPackVariant(c *gin.Context, logCtx string, replace bool) {
+	pack, ok := findMyPack(c, logCtx)
+	if !ok {
+		return
+	}
+
+	var variantID uint
+	if replace {
+		var err error
+		variantID, err = helper.StringToUint(c.Param("variant_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+			return
+		}
+	}
+
+	var input PackVariantRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		helper.LogAndSanitize(err, logCtx+": bind JSON failed")
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": helper.ErrMsgBadRequest})
+		return
+	}
+	if err := validateVariant(&input); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	variantID, err := saveVariant(c.Request.Context(), pack.UserID, pack.ID, variantID, input)
+	if err != nil {
+		respondVariantError(c, err, logCtx+": save variant failed")
+		return
+	}
+	variant, err := returnPackVariant(c.Request.Context(), pack, variantID)
+	if err != nil {
+		respondVariantError(c, err, logCtx+": return variant failed")
+		return
+	}
+
+	status := http.StatusCreated
+	if replace {
+		status = http.StatusOK
+	}
+	c.IndentedJSON(status, variant)
+}
+
+// Delete a variant of a pack
+// @Summary Delete a variant of a pack
+// @Description Deletes a variant of a pack of the user; the pack contents are left unchanged
+// @Security Bearer
+// @Tags Packs
+// @Produce  json
+// @Param id path int true "Pack ID"
+// @Param variant_id path int true "Variant ID"
+// @Success 200 {object} apitypes.OkResponse "Variant deleted"
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 403 {object} apitypes.ErrorResponse "This pack does not belong to you"
+// @Failure 404 {object} apitypes.ErrorResponse "Pack or variant not found"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/mypack/{id}/variants/{variant_id} [delete]
+func DeleteMyPackVariant(c *gin.Context) {
+	pack, ok := findMyPack(c, "delete my pack variant")
+	if !ok {
+		return
+	}
+
+	variantID, err := helper.StringToUint(c.Param("variant_id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	if err := deleteVariant(c.Request.Context(), pack.ID, variantID); err != nil {
+		respondVariantError(c, err, "delete my pack variant: delete variant failed")
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, gin.H{"message": "Variant deleted"})
+}
+
+// respondVariantError maps variant errors to an HTTP response
+func respondVariantError(c *gin.Context, err error, logMsg string) {
+	switch {
+	case errors.Is(err, ErrVariantNotFound):
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrDuplicateVariantName):
+		c.IndentedJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrUnknownVariantItem):
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		helper.LogAndSanitize(err, logMsg)
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+	}
+}
+
+// Mark a pack as a template
+// @Summary Mark a pack as a template
+// @Description Lists a pack of the user as a template other users can start from; its season,
+// @Description adventure and trail describe the template. Official templates stay official.
+// @Security Bearer
+// @Tags Packs
+// @Produce  json
+// @Param id path int true "Pack ID"
+// @Success 200 {object} apitypes.OkResponse "Pack marked as template"
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 403 {object} apitypes.ErrorResponse "This pack does not belong to you"
+// @Failure 404 {object} apitypes.ErrorResponse "Pack not found"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/mypack/{id}/template [post]
+func TemplateMyPack(c *gin.Context) {
+	packAction(c, "template", templatePackByID, "Pack marked as template")
+}
+
+// Unmark a pack as a template
+// @Summary Unmark a pack as a template
+// @Description Removes a pack of the user from the templates (idempotent)
+// @Security Bearer
+// @Tags Packs
+// @Produce  json
+// @Param id path int true "Pack ID"
+// @Success 200 {object} apitypes.OkResponse "Pack removed from templates"
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 403 {object} apitypes.ErrorResponse "This pack does not belong to you"
+// @Failure 404 {object} apitypes.ErrorResponse "Pack not found"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/mypack/{id}/template [delete]
+func UntemplateMyPack(c *gin.Context) {
+	packAction(c, "untemplate", untemplatePackByID, "Pack removed from templates")
+}
+
+// Get the pack templates
+// @Summary Get the pack templates
+// @Description Lists the pack templates, official ones first, optionally filtered by season,
+// @Description adventure and trail, ignoring case
+// @Security Bearer
+// @Tags Packs
+// @Produce  json
+// @Param season query string false "Season"
+// @Param adventure query string false "Adventure"
+// @Param trail query string false "Trail"
+// @Success 200 {array} PackTemplate
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/templates [get]
+func GetTemplates(c *gin.Context) {
+	templates, err := returnTemplates(c.Request.Context(),
+		strings.TrimSpace(c.Query("season")),
+		strings.TrimSpace(c.Query("adventure")),
+		strings.TrimSpace(c.Query("trail")))
+	if err != nil {
+		helper.LogAndSanitize(err, "get templates: return templates failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, templates)
+}
+
+// Create a pack from a template
+// @Summary Create a pack from a template
+// @Description Creates a pack of the user from a template. Each template item is mapped onto the
+// @Description inventory of the user by name and category, ignoring case; missing gear is added to the
+// @Description wishlist as a placeholder. The season, adventure and trail of the template are copied.
+// @Description The pack name defaults to the template name.
+// @Security Bearer
+// @Tags Packs
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Template pack ID"
+// @Param pack body FromTemplateRequest false "Pack name and description"
+// @Success 201 {object} FromTemplateResponse
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format, payload or empty template"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 404 {object} apitypes.ErrorResponse "Template not found"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/mypack/from-template/{id} [post]
+func PostMyPackFromTemplate(c *gin.Context) {
+	id, err := helper.StringToUint(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	userID, err := security.ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, "post my pack from template: extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return
+	}
+
+	var input FromTemplateRequest
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		helper.LogAndSanitize(err, "post my pack from template: bind JSON failed")
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": helper.ErrMsgBadRequest})
+		return
+	}
+
+	template, err := findTemplateByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrTemplateNotFound) {
+			c.IndentedJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		helper.LogAndSanitize(err, "post my pack from template: find template failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	result, err := createPackFromTemplate(c.Request.Context(), template, userID, input)
+	if err != nil {
+		if errors.Is(err, ErrEmptyTemplate) {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		respondInsertExternalPackError(c, err, "post my pack from template: create pack failed")
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, result)
+}
+
+// Mark a pack as an official template
+// @Summary [ADMIN] Mark a pack as an official template
+// @Description Marks any pack as an official template, listed before the templates of users
+// @Security Bearer
+// @Tags Internal
+// @Produce  json
+// @Param id path int true "Pack ID"
+// @Success 200 {object} apitypes.OkResponse "Pack marked as official template"
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
+// @Failure 404 {object} apitypes.ErrorResponse "Pack not found"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /admin/packs/{id}/template [post]
+func PostOfficialTemplate(c *gin.Context) {
+	officialTemplateAction(c, true, "Pack marked as official template")
+}
+
+// Remove a pack from the templates
+// @Summary [ADMIN] Remove a pack from the templates
+// @Description Removes any pack from the templates, official or not
+// @Security Bearer
+// @Tags Internal
+// @Produce  json
+// @Param id path int true "Pack ID"
+// @Success 200 {object} apitypes.OkResponse "Pack removed from templates"
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
+// @Failure 404 {object} apitypes.ErrorResponse "Pack not found"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /admin/packs/{id}/template [delete]
+func DeleteOfficialTemplate(c *gin.Context) {
+	officialTemplateAction(c, false, "Pack removed from templates")
+}
+
+func officialTemplateAction(c *gin.Context, official bool, successMsg string) {
+	id, err := helper.StringToUint(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	if err := setOfficialTemplate(c.Request.Context(), id, official); err != nil {
+		if errors.Is(err, ErrPackNotFound) {
+			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Pack not found"})
+			return
+		}
+		helper.LogAndSanitize(err, "official template: set template failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, gin.H{"message": successMsg})
+}
+
+// findMyPack returns the pack of the id path parameter, or responds with an
+// error and returns false unless it exists and belongs to the user
+func findMyPack(c *gin.Context, logCtx string) (*Pack, bool) {
+	id, err := helper.StringToUint(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return nil, false
+	}
+
+	userID, err := security.ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, logCtx+": extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return nil, false
+	}
+
+	pack, err := findPackByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrPackNotFound) {
+			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Pack not found"})
+			return nil, false
+		}
+		helper.LogAndSanitize(err, logCtx+": find pack failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return nil, false
+	}
+	if pack.UserID != userID {
+		c.IndentedJSON(http.StatusForbidden, gin.H{"error": "This pack does not belong to you"})
+		return nil, false
+	}
+	return pack, true
+}
+
+// Share a pack by ID
+// @Summary Share a pack by ID
+// @Description Generate a sharing code for a pack to make it publicly accessible (idempotent)
+// @Security Bearer
+// @Tags Packs
+// @Produce  json
+// @Param id path int true "Pack ID"
+// @Success 200 {object} map[string]string "Pack shared successfully"
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 403 {object} apitypes.ErrorResponse "This pack does not belong to you"
+// @Failure 404 {object} apitypes.ErrorResponse "Pack not found"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/mypack/{id}/share [post]
+func ShareMyPack(c *gin.Context) {
+	id, err := helper.StringToUint(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	userID, err := security.ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, "share my pack: extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return
+	}
+
+	// Check if pack exists
+	_, err = FindPackByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrPackNotFound) {
+			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Pack not found"})
+			return
+		}
+		helper.LogAndSanitize(err, "share my pack: find pack failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	// Share the pack (idempotent)
+	sharingCode, err := sharePackByID(c.Request.Context(), id, userID)
+	if err != nil {
+		if errors.Is(err, ErrPackNotOwned) {
+			c.IndentedJSON(http.StatusForbidden, gin.H{"error": "This pack does not belong to you"})
+			return
+		}
+		helper.LogAndSanitize(err, "share my pack: share pack failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, gin.H{
+		"message":      "Pack shared successfully",
+		"sharing_code": sharingCode,
+	})
+}
+
+// Unshare a pack by ID
+// @Summary Unshare a pack by ID
+// @Description Remove the sharing code from a pack to make it private (idempotent)
+// @Security Bearer
+// @Tags Packs
+// @Produce  json
+// @Param id path int true "Pack ID"
+// @Success 200 {object} apitypes.OkResponse "Pack unshared successfully"
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 403 {object} apitypes.ErrorResponse "This pack does not belong to you"
+// @Failure 404 {object} apitypes.ErrorResponse "Pack not found"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/mypack/{id}/share [delete]
+func UnshareMyPack(c *gin.Context) {
+	packAction(c, "unshare", unsharePackByID, "Pack unshared successfully")
+}
+
+// Favorite a pack by ID
+// @Summary Favorite a pack by ID
+// @Description Mark a pack as favorite. Only one pack per user can be favorite at a time (idempotent)
+// @Security Bearer
+// @Tags Packs
+// @Produce  json
+// @Param id path int true "Pack ID"
+// @Success 200 {object} apitypes.OkResponse "Pack favorited successfully"
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 403 {object} apitypes.ErrorResponse "This pack does not belong to you"
+// @Failure 404 {object} apitypes.ErrorResponse "Pack not found"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/mypack/{id}/favorite [post]
+func FavoriteMyPack(c *gin.Context) {
+	packAction(c, "favorite", favoritePackByID, "Pack favorited successfully")
+}
+
+// Unfavorite a pack by ID
+// @Summary Unfavorite a pack by ID
+// @Description Remove favorite status from a pack (idempotent)
+// @Security Bearer
+// @Tags Packs
+// @Produce  json
+// @Param id path int true "Pack ID"
+// @Success 200 {object} apitypes.OkResponse "Pack unfavorited successfully"
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 403 {object} apitypes.ErrorResponse "This pack does not belong to you"
+// @Failure 404 {object} apitypes.ErrorResponse "Pack not found"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/mypack/{id}/favorite [delete]
+func UnfavoriteMyPack(c *gin.Context) {
+	packAction(c, "unfavorite", unfavoritePackByID, "Pack unfavorited successfully")
+}
+
+// Duplicate a pack by ID
+// @Summary Duplicate a pack by ID
+// @Description Clone an existing pack (metadata + all pack contents) into a new pack
+// @Description owned by the same user. The new pack's name is prefixed with "COPY - ",
+// @Description is_favorite is reset to false and sharing_code is not copied. The pack
+// @Description image is intentionally not copied.
+// @Security Bearer
+// @Tags Packs
+// @Produce  json
+// @Param id path int true "Pack ID"
+// @Success 201 {object} Pack "Pack duplicated successfully"
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 403 {object} apitypes.ErrorResponse "This pack does not belong to you"
+// @Failure 404 {object} apitypes.ErrorResponse "Pack not found"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/mypack/{id}/duplicate [post]
+func DuplicateMyPack(c *gin.Context) {
+	id, err := helper.StringToUint(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	userID, err := security.ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, "duplicate my pack: extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return
+	}
+
+	_, err = FindPackByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrPackNotFound) {
+			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Pack not found"})
+			return
+		}
+		helper.LogAndSanitize(err, "duplicate my pack: find pack failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	newPackID, err := duplicatePackByID(c.Request.Context(), id, userID)
+	if err != nil {
+		if errors.Is(err, ErrPackNotOwned) {
+			c.IndentedJSON(http.StatusForbidden, gin.H{"error": "This pack does not belong to you"})
+			return
+		}
+		if errors.Is(err, ErrPackNotFound) {
+			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Pack not found"})
+			return
+		}
+		helper.LogAndSanitize(err, "duplicate my pack: duplicate pack failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	newPack, err := FindPackByID(c.Request.Context(), newPackID)
+	if err != nil {
+		helper.LogAndSanitize(err, "duplicate my pack: re-read new pack failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, newPack)
+}
+
+// packAction is a shared helper for pack operations that follow the same pattern:
+// parse ID, extract token, check pack exists, call action, handle ownership error.
+func packAction(c *gin.Context, actionName string,
+	action func(ctx context.Context, packID, userID uint) error, successMsg string) {
+	id, err := helper.StringToUint(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	userID, err := security.ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, actionName+" my pack: extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return
+	}
+
+	_, err = FindPackByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrPackNotFound) {
+			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Pack not found"})
+			return
+		}
+		helper.LogAndSanitize(err, actionName+" my pack: find pack failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	err = action(c.Request.Context(), id, userID)
+	if err != nil {
+		if errors.Is(err, ErrPackNotOwned) {
+			c.IndentedJSON(http.StatusForbidden, gin.H{"error": "This pack does not belong to you"})
+			return
+		}
+		helper.LogAndSanitize(err, actionName+" my pack: "+actionName+" pack failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, gin.H{"message": successMsg})
+}
+
+// valueOr returns the value of v, or fallback when v is nil
+func valueOr[T any](v *T, fallback T) T {
+	if v == nil {
+		return fallback
+	}
+	return *v
+}
+
+// respondInsertExternalPackError maps insertExternalPack errors to an HTTP response
+func respondInsertExternalPackError(c *gin.Context, err error, logMsg string) {
+	if errors.Is(err, ErrTooManyItems) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": ErrTooManyItems.Error()})
+		return
+	}
+	for _, known := range []error{
+		currencies.ErrInvalidCurrency, ErrUnresolvedImportItem, ErrImportItemNotFound, ErrImportNotesTooLong,
+		ErrDuplicateImportItem,
+	} {
+		if errors.Is(err, known) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": known.Error()})
+			return
+		}
+	}
+	helper.LogAndSanitize(err, logMsg)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+}
+
+// Import from lighterpack
+// @Summary Import from lighterpack csv pack file
+// @Description Import from lighterpack csv pack file
+// @Security Bearer
+// @Tags Packs
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param file formData file true "CSV file"
+// @Success 200 {object} ImportExternalPackResponse "CSV data imported successfully with pack ID"
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid CSV format"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/importfromlighterpack [post]
+func ImportFromLighterPack(c *gin.Context) {
+	var externalPack ExternalPack
+
+	userID, err := security.ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, "import from lighterpack: extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return
+	}
+
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		helper.LogAndSanitize(err, "import from lighterpack: form file failed")
+		c.JSON(http.StatusBadRequest, gin.H{"error": helper.ErrMsgBadRequest})
+		return
+	}
+	defer file.Close()
 
 	// Parse the CSV file
 	reader := csv.NewReader(file)
@@ -1395,6 +2193,164 @@ func ImportFromLighterPack(c *gin.Context) {
 	})
 }
 
+// Import a spreadsheet
+// @Summary Import a pack from a CSV or XLSX spreadsheet
+// @Description Import a gear list exported by LighterPack, Packstack, GearGrams, Hikerhub or written by hand.
+// @Description CSV files may use comma, semicolon, tab or pipe separators and UTF-8, UTF-16 or Windows-1252.
+// @Description Columns are detected from the header row; mapping overrides them with a JSON object from field
+// @Description (item_name, category, desc, qty, weight, unit, url, price, currency, worn, consumable) to header.
+// @Description Rows that cannot be read are skipped and reported in errors.
+// @Security Bearer
+// @Tags Packs
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param file formData file true "CSV or XLSX file"
+// @Param pack_name formData string false "Pack name, the file name by default"
+// @Param pack_description formData string false "Pack description"
+// @Param mapping formData string false "Column mapping, e.g. {\"item_name\":\"Gear\",\"weight\":\"Grams\"}"
+// @Param weight_unit formData string false "Unit of weights without a unit column (g, kg, oz, lb), g by default"
+// @Param currency formData string false "Currency of prices without one, the preferred currency by default"
+// @Param dry_run formData bool false "Return the import preview instead of creating the pack"
+// @Success 200 {object} ImportSpreadsheetResponse "Spreadsheet imported with pack ID and skipped rows"
+// @Failure 400 {object} ImportSpreadsheetErrorResponse "Unreadable file, invalid options or no valid row"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/importspreadsheet [post]
+func ImportSpreadsheet(c *gin.Context) {
+	userID, err := security.ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, "import spreadsheet: extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return
+	}
+
+	file, fileHeader, err := c.Request.FormFile("file")
+	if err != nil {
+		helper.LogAndSanitize(err, "import spreadsheet: form file failed")
+		c.JSON(http.StatusBadRequest, gin.H{"error": helper.ErrMsgBadRequest})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		helper.LogAndSanitize(err, "import spreadsheet: read file failed")
+		c.JSON(http.StatusBadRequest, gin.H{"error": helper.ErrMsgBadRequest})
+		return
+	}
+
+	opts, err := spreadsheetFormOptions(c, userID)
+	if err != nil {
+		respondSpreadsheetError(c, err, nil, "import spreadsheet: read options failed")
+		return
+	}
+
+	parsed, err := parseSpreadsheet(data, opts)
+	if err != nil {
+		var rowErrors []SpreadsheetRowError
+		if parsed != nil {
+			rowErrors = parsed.Errors
+		}
+		respondSpreadsheetError(c, err, rowErrors, "import spreadsheet: parse failed")
+		return
+	}
+
+	packName := c.PostForm("pack_name")
+	if packName == "" {
+		packName = strings.TrimSuffix(fileHeader.Filename, filepath.Ext(fileHeader.Filename))
+	}
+	if packName == "" {
+		packName = "Spreadsheet Import"
+	}
+
+	if dryRun, _ := strconv.ParseBool(c.PostForm("dry_run")); dryRun {
+		preview, err := previewImport(c.Request.Context(), parsed.Items, userID, true)
+		if err != nil {
+			respondInsertExternalPackError(c, err, "import spreadsheet: preview failed")
+			return
+		}
+		c.JSON(http.StatusOK, ImportSpreadsheetResponse{
+			Message:  "Spreadsheet preview",
+			Imported: len(parsed.Items),
+			Columns:  parsed.Columns,
+			Errors:   parsed.Errors,
+			Preview: &ImportPreviewResponse{
+				PackName:        packName,
+				PackDescription: c.PostForm("pack_description"),
+				Items:           preview,
+			},
+		})
+		return
+	}
+
+	packID, err := insertExternalPack(
+		c.Request.Context(), &parsed.Items, userID, packName, c.PostForm("pack_description"))
+	if err != nil {
+		respondInsertExternalPackError(c, err, "import spreadsheet: insert external pack failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, ImportSpreadsheetResponse{
+		Message:  "Spreadsheet imported successfully",
+		PackID:   packID,
+		Imported: len(parsed.Items),
+		Columns:  parsed.Columns,
+		Errors:   parsed.Errors,
+	})
+}
+
+// spreadsheetFormOptions reads the mapping, weight_unit and currency form fields
+func spreadsheetFormOptions(c *gin.Context, userID uint) (spreadsheetOptions, error) {
+	var opts spreadsheetOptions
+
+	if mapping := c.PostForm("mapping"); mapping != "" {
+		if err := json.Unmarshal([]byte(mapping), &opts.Mapping); err != nil {
+			return opts, ErrInvalidColumnMapping
+		}
+	}
+
+	if unit := c.PostForm("weight_unit"); unit != "" {
+		opts.WeightUnit = normalizeWeightUnit(unit)
+		if opts.WeightUnit == "" {
+			return opts, ErrInvalidWeightUnit
+		}
+	}
+
+	if currency := c.PostForm("currency"); currency != "" {
+		normalized, err := currencies.Normalize(currency)
+		if err != nil {
+			return opts, err
+		}
+		opts.Currency = normalized
+		return opts, nil
+	}
+
+	preferred, err := currencies.PreferredCurrency(c.Request.Context(), userID)
+	if err != nil {
+		return opts, err
+	}
+	opts.Currency = preferred
+	return opts, nil
+}
+
+// respondSpreadsheetError maps spreadsheet option and parsing errors to an HTTP response
+func respondSpreadsheetError(c *gin.Context, err error, rowErrors []SpreadsheetRowError, logMsg string) {
+	for _, known := range []error{
+		ErrNoValidRows, ErrUnreadableSpreadsheet, ErrNoSpreadsheetHeader, ErrInvalidColumnMapping,
+		ErrInvalidWeightUnit, ErrTooManyItems, currencies.ErrInvalidCurrency,
+	} {
+		if errors.Is(err, known) {
+			if rowErrors == nil {
+				rowErrors = []SpreadsheetRowError{}
+			}
+			c.JSON(http.StatusBadRequest, ImportSpreadsheetErrorResponse{Error: known.Error(), Errors: rowErrors})
+			return
+		}
+	}
+	helper.LogAndSanitize(err, logMsg)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+}
+
 // Import from LighterPack URL
 // @Summary Import a pack from a LighterPack sharing URL
 // @Description Import items from a LighterPack sharing URL into a new pack
@@ -1505,6 +2461,107 @@ func ImportPack(c *gin.Context) {
 	})
 }
 
+// Preview a pack import
+// @Summary Preview a pack import
+// @Description Propose an action for each item without persisting anything: reuse (an identical inventory item
+// @Description exists), create, or review (similar inventory items are listed as candidates). Submit the reconciled
+// @Description items to /v1/importpack/commit.
+// @Tags Packs
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param input body ImportPackRequest true "Pack name, description and items"
+// @Success 200 {object} ImportPreviewResponse
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid request"
+// @Failure 422 {object} apitypes.ErrorResponse "Empty payload"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/importpack/preview [post]
+func PreviewImportPack(c *gin.Context) {
+	var input ImportPackRequest
+
+	userID, err := security.ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, "preview import pack: extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		helper.LogAndSanitize(err, "preview import pack: bind json failed")
+		c.JSON(http.StatusBadRequest, gin.H{"error": helper.ErrMsgBadRequest})
+		return
+	}
+
+	if len(input.Items) == 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "payload is empty"})
+		return
+	}
+	if len(input.Items) > MaxImportItems {
+		c.JSON(http.StatusBadRequest, gin.H{"error": ErrTooManyItems.Error()})
+		return
+	}
+
+	preview, err := previewImport(c.Request.Context(), input.Items, userID, true)
+	if err != nil {
+		respondInsertExternalPackError(c, err, "preview import pack: preview failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, ImportPreviewResponse{
+		PackName:        input.PackName,
+		PackDescription: input.PackDescription,
+		Items:           preview,
+	})
+}
+
+// Commit a reconciled pack import
+// @Summary Commit a reconciled pack import
+// @Description Create a pack from a reconciled import plan. Each item must either reuse an inventory item of the
+// @Description user (action reuse with inventory_id) or create a new one (action create). An inventory item
+// @Description can only be reused, or identical items created, once per plan.
+// @Tags Packs
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param input body ImportPlanRequest true "Pack name, description and reconciled items"
+// @Success 200 {object} ImportExternalPackResponse
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid request, unresolved or duplicate item"
+// @Failure 422 {object} apitypes.ErrorResponse "Empty payload"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/importpack/commit [post]
+func CommitImportPack(c *gin.Context) {
+	var input ImportPlanRequest
+
+	userID, err := security.ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, "commit import pack: extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		helper.LogAndSanitize(err, "commit import pack: bind json failed")
+		c.JSON(http.StatusBadRequest, gin.H{"error": helper.ErrMsgBadRequest})
+		return
+	}
+
+	if len(input.Items) == 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "payload is empty"})
+		return
+	}
+
+	packID, err := commitImportPlan(c.Request.Context(), input.Items, userID, input.PackName, input.PackDescription)
+	if err != nil {
+		respondInsertExternalPackError(c, err, "commit import pack: commit plan failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, ImportExternalPackResponse{
+		Message: "Pack imported successfully",
+		PackID:  packID,
+	})
+}
+
 // Parse a LighterPack URL without persisting (public, no auth).
 // @Summary Parse a LighterPack sharing URL
 // @Description Fetch and parse a LighterPack sharing URL and return its items without saving
@@ -1709,13 +2766,21 @@ func GetPackOptionsV2(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param sharing_code path string true "Pack sharing code"
+// @Param units query string false "Add weight displays in this unit system" Enums(preferred, metric, imperial)
 // @Success 200 {object} SharedPackResponse "Shared pack with metadata and contents"
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid units"
 // @Failure 404 {object} apitypes.ErrorResponse "Pack not found or not shared"
 // @Failure 500 {object} apitypes.ErrorResponse "Internal server error"
 // @Router /sharedlist/{sharing_code} [get]
 func SharedList(c *gin.Context) {
 	sharingCode := c.Param("sharing_code")
 
+	system, err := units.FromRequest(c, 0)
+	if err != nil {
+		units.RespondError(c, err, "shared list: resolve units failed")
+		return
+	}
+
 	// Get shared pack with metadata and contents
 	sharedPack, err := returnSharedPack(c.Request.Context(), sharingCode)
 	if err != nil {
@@ -1728,5 +2793,7 @@ func SharedList(c *gin.Context) {
 		return
 	}
 
+	setContentWeightDisplays(sharedPack.Contents, system)
+
 	c.IndentedJSON(http.StatusOK, sharedPack)
 }
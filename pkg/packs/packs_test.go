@@ -12,15 +12,19 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/Angak0k/pimpmypack/pkg/config"
 	"github.com/Angak0k/pimpmypack/pkg/database"
 	"github.com/Angak0k/pimpmypack/pkg/helper"
+	"github.com/Angak0k/pimpmypack/pkg/inventories"
 	"github.com/Angak0k/pimpmypack/pkg/security"
 	"github.com/gin-gonic/gin"
 	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMain(m *testing.M) {
@@ -2229,3 +2233,169 @@ func TestPostPackWithMetadata(t *testing.T) {
 		testPostPackInvalidMetadata(t, router, "adventure", "Sailing")
 	})
 }
+
+func TestPackTotalsLeaveOutWishlist(t *testing.T) {
+	ctx := t.Context()
+	pack := Pack{UserID: users[0].ID, PackName: "Wishlist Totals Pack"}
+	require.NoError(t, insertPack(ctx, database.DB(), &pack))
+	owned := inventories.Inventory{UserID: users[0].ID, ItemName: "Old Tent", Category: "Shelter", Weight: 1500,
+		Currency: "EUR"}
+	planned := inventories.Inventory{UserID: users[0].ID, ItemName: "Dream Tent", Category: "Shelter", Weight: 700,
+		Currency: "EUR", Wishlist: true}
+	require.NoError(t, inventories.InsertInventory(ctx, &owned))
+	require.NoError(t, inventories.InsertInventory(ctx, &planned))
+	defer func() {
+		_, err := database.DB().ExecContext(ctx, "DELETE FROM pack WHERE id = $1", pack.ID)
+		require.NoError(t, err)
+		_, err = database.DB().ExecContext(ctx, "DELETE FROM inventory WHERE id IN ($1, $2)", owned.ID, planned.ID)
+		require.NoError(t, err)
+	}()
+	for _, item := range []inventories.Inventory{owned, planned} {
+		content := PackContent{PackID: pack.ID, ItemID: item.ID, Quantity: 1}
+		require.NoError(t, insertPackContent(ctx, database.DB(), &content))
+	}
+
+	found, err := findPackByID(ctx, pack.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, found.PackItemsCount)
+	assert.Equal(t, 1500, found.PackWeight)
+	assert.Equal(t, 700, found.WishlistWeight)
+
+	mine, err := findPacksByUserID(ctx, users[0].ID)
+	require.NoError(t, err)
+	for _, p := range *mine {
+		if p.ID == pack.ID {
+			assert.Equal(t, 1500, p.PackWeight)
+			assert.Equal(t, 700, p.WishlistWeight)
+		}
+	}
+}
+
+func TestMyPackContentDetails(t *testing.T) {
+	token, err := security.GenerateToken(users[0].ID)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/mypack/:id/packcontents", GetMyPackContentsByPackID)
+	router.POST("/mypack/:id/packcontent", PostMyPackContent)
+	router.PUT("/mypack/:id/packcontent/:item_id", PutMyPackContentByID)
+	router.PUT("/mypack/:id/packcontents/order", PutMyPackContentsOrder)
+
+	ctx := t.Context()
+	pack := Pack{UserID: users[0].ID, PackName: "Item Details Pack"}
+	require.NoError(t, insertPack(ctx, database.DB(), &pack))
+	stove := inventories.Inventory{UserID: users[0].ID, ItemName: "Stove", Category: "Kitchen", Weight: 90,
+		Currency: "EUR"}
+	pot := inventories.Inventory{UserID: users[0].ID, ItemName: "Pot", Category: "Kitchen", Weight: 150,
+		Currency: "EUR"}
+	require.NoError(t, inventories.InsertInventory(ctx, &stove))
+	require.NoError(t, inventories.InsertInventory(ctx, &pot))
+	var trip Trip
+	defer func() {
+		_, err := database.DB().ExecContext(ctx, "DELETE FROM pack_trip WHERE id = $1", trip.ID)
+		require.NoError(t, err)
+		_, err = database.DB().ExecContext(ctx, "DELETE FROM pack WHERE id = $1", pack.ID)
+		require.NoError(t, err)
+		_, err = database.DB().ExecContext(ctx, "DELETE FROM inventory WHERE id IN ($1, $2)", stove.ID, pot.ID)
+		require.NoError(t, err)
+	}()
+
+	path := fmt.Sprintf("/mypack/%d/packcontent", pack.ID)
+	stoveNotes, optional := "rent on site", true
+	w := serveTripRequest(t, router, token, http.MethodPost, path, PackContentRequest{
+		InventoryID: stove.ID, Quantity: 1, Notes: &stoveNotes, Optional: &optional,
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var stoveContent PackContent
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stoveContent))
+	assert.Equal(t, "rent on site", stoveContent.Notes)
+	assert.True(t, stoveContent.Optional)
+
+	w = serveTripRequest(t, router, token, http.MethodPost, path, PackContentRequest{InventoryID: pot.ID, Quantity: 2})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var potContent PackContent
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &potContent))
+
+	longNotes := strings.Repeat("a", 501)
+	w = serveTripRequest(t, router, token, http.MethodPost, path, PackContentRequest{
+		InventoryID: pot.ID, Quantity: 1, Notes: &longNotes,
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// The optional stove is left out of the totals
+	found, err := findPackByID(ctx, pack.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, found.PackItemsCount)
+	assert.Equal(t, 300, found.PackWeight)
+
+	contentsPath := fmt.Sprintf("/mypack/%d/packcontents", pack.ID)
+	orderPath := contentsPath + "/order"
+	w = serveTripRequest(t, router, token, http.MethodPut, orderPath, PackContentOrderRequest{
+		PackContentIDs: []uint{stoveContent.ID, potContent.ID},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var contents PackContentWithItems
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &contents))
+	require.Len(t, contents, 2)
+	assert.Equal(t, "Stove", contents[0].ItemName, "positions come before names")
+	assert.Equal(t, "rent on site", contents[0].Notes)
+	require.NotNil(t, contents[1].Position)
+	assert.Equal(t, 1, *contents[1].Position)
+
+	w = serveTripRequest(t, router, token, http.MethodPut, orderPath, PackContentOrderRequest{
+		PackContentIDs: []uint{potContent.ID, potContent.ID},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	firstPack, err := returnPackContentsByPackID(ctx, FindPackIDByPackName(packs, "First Pack"))
+	require.NoError(t, err)
+	require.NotEmpty(t, *firstPack)
+	w = serveTripRequest(t, router, token, http.MethodPut, orderPath, PackContentOrderRequest{
+		PackContentIDs: []uint{potContent.ID, (*firstPack)[0].PackContentID},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// The position is kept when an update omits it
+	potNotes := "shared with Sam"
+	w = serveTripRequest(t, router, token, http.MethodPut, fmt.Sprintf("%s/%d", path, potContent.ID),
+		PackContentRequest{InventoryID: pot.ID, Quantity: 1, Notes: &potNotes})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	updated, err := findPackContentByID(ctx, potContent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "shared with Sam", updated.Notes)
+	require.NotNil(t, updated.Position)
+	assert.Equal(t, 1, *updated.Position)
+
+	// So are the notes and the optional flag
+	w = serveTripRequest(t, router, token, http.MethodPut, fmt.Sprintf("%s/%d", path, stoveContent.ID),
+		PackContentRequest{InventoryID: stove.ID, Quantity: 2})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	updated, err = findPackContentByID(ctx, stoveContent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, updated.Quantity)
+	assert.Equal(t, "rent on site", updated.Notes)
+	assert.True(t, updated.Optional)
+
+	// The optional stove is not recorded as used on a trip
+	trip = Trip{PackID: pack.ID, StartDate: "2024-07-01", EndDate: "2024-07-02", Nights: 1}
+	require.NoError(t, insertTrip(ctx, users[0].ID, &trip))
+	rows, err := database.DB().QueryContext(ctx, "SELECT item_id FROM pack_trip_item WHERE trip_id = $1", trip.ID)
+	require.NoError(t, err)
+	defer rows.Close()
+	var usedIDs []uint
+	for rows.Next() {
+		var id uint
+		require.NoError(t, rows.Scan(&id))
+		usedIDs = append(usedIDs, id)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []uint{pot.ID}, usedIDs)
+	assert.Equal(t, 1, trip.ItemsCount)
+
+	otherToken, err := security.GenerateToken(users[1].ID)
+	require.NoError(t, err)
+	w = serveTripRequest(t, router, otherToken, http.MethodPut, orderPath, PackContentOrderRequest{
+		PackContentIDs: []uint{potContent.ID},
+	})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -62,6 +62,8 @@ func convertSharedPackToExternalPack(resp *SharedPackResponse) *ExternalPack {
 			Currency:   c.Currency,
 			Worn:       c.Worn,
 			Consumable: c.Consumable,
+			Notes:      c.Notes,
+			Optional:   c.Optional,
 		})
 	}
 	return &items
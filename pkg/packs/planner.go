@@ -0,0 +1,110 @@
+package packs
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/Angak0k/pimpmypack/pkg/currencies"
+	"github.com/Angak0k/pimpmypack/pkg/inventories"
+)
+
+// buildPurchasePlan matches each wishlist item with the heaviest owned pack
+// item of the same category, ignoring case, that is heavier than it. Items
+// are ranked by grams saved per currency unit, free savings first, then the
+// ones without a ratio by weight saved.
+func buildPurchasePlan(
+	packID uint, contents PackContentWithItems, wishlist inventories.Inventories,
+	cv *currencies.Converter, preferred string,
+) PurchasePlan {
+	plan := PurchasePlan{PackID: packID, Currency: preferred, Items: []PlannedPurchase{}}
+	for _, wish := range wishlist {
+		planned := PlannedPurchase{
+			InventoryID:              wish.ID,
+			ItemName:                 wish.ItemName,
+			Category:                 wish.Category,
+			Weight:                   wish.Weight,
+			Price:                    wish.Price,
+			Currency:                 wish.Currency,
+			PriceInPreferredCurrency: cv.ConvertPtr(wish.Price, wish.Currency, preferred),
+		}
+		for _, item := range contents {
+			if item.Wishlist || item.Weight <= wish.Weight || !strings.EqualFold(item.Category, wish.Category) {
+				continue
+			}
+			if planned.Replaces == nil || item.Weight > planned.Replaces.Weight {
+				planned.Replaces = &ReplacedItem{
+					PackContentID: item.PackContentID,
+					InventoryID:   item.InventoryID,
+					ItemName:      item.ItemName,
+					Weight:        item.Weight,
+					Quantity:      item.Quantity,
+				}
+			}
+		}
+		if planned.Replaces != nil {
+			planned.WeightSaved = planned.Replaces.Weight - wish.Weight
+			planned.GramsPerUnit = gramsPerUnit(planned.WeightSaved, planned.PriceInPreferredCurrency, preferred)
+		}
+		plan.Items = append(plan.Items, planned)
+	}
+
+	sort.SliceStable(plan.Items, func(i, j int) bool {
+		a, b := plan.Items[i], plan.Items[j]
+		if ra, rb := purchaseRank(a), purchaseRank(b); ra != rb {
+			return ra > rb
+		}
+		return a.WeightSaved > b.WeightSaved
+	})
+	return plan
+}
+
+// gramsPerUnit divides a weight saving by a price in the minor unit of the
+// currency, rounded to 2 decimals; nil for a missing or zero price
+func gramsPerUnit(weightSaved int, price *int, currency string) *float64 {
+	if price == nil || *price <= 0 {
+		return nil
+	}
+	major := float64(*price) / math.Pow10(currencies.MinorUnits(currency))
+	ratio := math.Round(float64(weightSaved)/major*100) / 100
+	return &ratio
+}
+
+// purchaseRank orders planned purchases: free savings, then by grams per
+// unit, then the ones without a ratio
+func purchaseRank(p PlannedPurchase) float64 {
+	switch {
+	case p.GramsPerUnit != nil:
+		return *p.GramsPerUnit
+	case p.WeightSaved > 0 && p.PriceInPreferredCurrency != nil && *p.PriceInPreferredCurrency == 0:
+		return math.Inf(1)
+	default:
+		return -1
+	}
+}
+
+// planPurchases builds the purchase plan of the wishlist of the owner of a pack
+func planPurchases(ctx context.Context, pack *Pack) (*PurchasePlan, error) {
+	contents, err := returnPackContentsByPackID(ctx, pack.ID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := inventories.ReturnInventoriesByUserID(ctx, pack.UserID)
+	if err != nil {
+		return nil, err
+	}
+	var wishlist inventories.Inventories
+	for _, item := range *items {
+		if item.Wishlist {
+			wishlist = append(wishlist, item)
+		}
+	}
+
+	preferred, cv, err := currencies.ConverterFor(ctx, pack.UserID)
+	if err != nil {
+		return nil, err
+	}
+	plan := buildPurchasePlan(pack.ID, *contents, wishlist, cv, preferred)
+	return &plan, nil
+}
@@ -0,0 +1,58 @@
+package packs
+
+import (
+	"testing"
+
+	"github.com/Angak0k/pimpmypack/pkg/currencies"
+	"github.com/Angak0k/pimpmypack/pkg/inventories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildPurchasePlan(t *testing.T) {
+	contents := PackContentWithItems{
+		{PackContentID: 1, InventoryID: 10, ItemName: "Tent", Category: "Shelter", Weight: 1200, Quantity: 1},
+		{PackContentID: 2, InventoryID: 11, ItemName: "Bivy", Category: "Shelter", Weight: 400, Quantity: 1},
+		{PackContentID: 3, InventoryID: 12, ItemName: "Dream Pot", Category: "Kitchen", Weight: 90, Wishlist: true},
+		{PackContentID: 4, InventoryID: 13, ItemName: "Pot", Category: "Kitchen", Weight: 150, Quantity: 1},
+	}
+	wishlist := inventories.Inventories{
+		{ID: 20, ItemName: "Duplex", Category: "shelter", Weight: 540, Price: 60000, Currency: "EUR"},
+		{ID: 21, ItemName: "Tarp", Category: "Shelter", Weight: 300, Price: 11000, Currency: "USD"},
+		{ID: 12, ItemName: "Dream Pot", Category: "Kitchen", Weight: 90, Price: 0, Currency: "EUR"},
+		{ID: 22, ItemName: "Quilt", Category: "Sleeping", Weight: 500, Price: 30000, Currency: "EUR"},
+		{ID: 23, ItemName: "Stove", Category: "Kitchen", Weight: 80, Price: 5000, Currency: "XXX"},
+	}
+	cv := currencies.NewConverter(map[string]float64{"EUR": 1, "USD": 1.1})
+
+	plan := buildPurchasePlan(5, contents, wishlist, cv, "EUR")
+	require.Len(t, plan.Items, 5)
+	assert.Equal(t, "EUR", plan.Currency)
+
+	// The free pot first, then 900 g for 100 EUR, then 660 g for 600 EUR
+	assert.Equal(t, uint(12), plan.Items[0].InventoryID)
+	require.NotNil(t, plan.Items[0].Replaces)
+	assert.Equal(t, uint(13), plan.Items[0].Replaces.InventoryID)
+	assert.Nil(t, plan.Items[0].GramsPerUnit)
+
+	tarp := plan.Items[1]
+	assert.Equal(t, uint(21), tarp.InventoryID)
+	require.NotNil(t, tarp.Replaces)
+	assert.Equal(t, uint(10), tarp.Replaces.InventoryID)
+	assert.Equal(t, 900, tarp.WeightSaved)
+	require.NotNil(t, tarp.GramsPerUnit)
+	assert.InDelta(t, 9.0, *tarp.GramsPerUnit, 0.001)
+
+	duplex := plan.Items[2]
+	assert.Equal(t, uint(20), duplex.InventoryID)
+	assert.Equal(t, 660, duplex.WeightSaved)
+	assert.InDelta(t, 1.1, *duplex.GramsPerUnit, 0.001)
+
+	// No exchange rate, then nothing to replace
+	assert.Equal(t, uint(23), plan.Items[3].InventoryID)
+	assert.Equal(t, 70, plan.Items[3].WeightSaved)
+	assert.Nil(t, plan.Items[3].GramsPerUnit)
+	assert.Equal(t, uint(22), plan.Items[4].InventoryID)
+	assert.Nil(t, plan.Items[4].Replaces)
+	assert.Zero(t, plan.Items[4].WeightSaved)
+}
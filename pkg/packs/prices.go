@@ -0,0 +1,54 @@
+package packs
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Angak0k/pimpmypack/pkg/currencies"
+)
+
+// setPreferredPrices fills PriceInPreferredCurrency for the contents of a pack;
+// it stays nil for the currencies without an exchange rate
+func setPreferredPrices(ctx context.Context, userID uint, contents PackContentWithItems) error {
+	preferred, cv, err := currencies.ConverterFor(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for i := range contents {
+		contents[i].PriceInPreferredCurrency = cv.ConvertPtr(contents[i].Price, contents[i].Currency, preferred)
+	}
+	return nil
+}
+
+// computePackCost sums price x quantity of the contents per currency, leaving
+// out the optional items, then converts each subtotal to the currency to
+func computePackCost(packID uint, contents PackContentWithItems, cv *currencies.Converter, to string) PackCost {
+	cost := PackCost{
+		PackID:       packID,
+		Currency:     to,
+		ByCurrency:   []CurrencyAmount{},
+		MissingRates: []string{},
+	}
+
+	byCurrency := map[string]int{}
+	for _, item := range contents {
+		if item.Price == 0 || item.Optional {
+			continue
+		}
+		byCurrency[item.Currency] += item.Price * item.Quantity
+	}
+
+	for currency, amount := range byCurrency {
+		cost.ByCurrency = append(cost.ByCurrency, CurrencyAmount{Currency: currency, Amount: amount})
+		converted, ok := cv.Convert(amount, currency, to)
+		if !ok {
+			cost.MissingRates = append(cost.MissingRates, currency)
+			continue
+		}
+		cost.Total += converted
+	}
+
+	sort.Slice(cost.ByCurrency, func(i, j int) bool { return cost.ByCurrency[i].Currency < cost.ByCurrency[j].Currency })
+	sort.Strings(cost.MissingRates)
+	return cost
+}
@@ -0,0 +1,35 @@
+package packs
+
+import (
+	"testing"
+
+	"github.com/Angak0k/pimpmypack/pkg/currencies"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputePackCost(t *testing.T) {
+	cv := currencies.NewConverter(map[string]float64{"USD": 1.25, "JPY": 160})
+	contents := PackContentWithItems{
+		{ItemName: "Tent", Price: 25000, Currency: "USD", Quantity: 1},
+		{ItemName: "Stakes", Price: 250, Currency: "USD", Quantity: 4},
+		{ItemName: "Stove", Price: 4800, Currency: "JPY", Quantity: 1},
+		{ItemName: "Pot", Price: 3000, Currency: "EUR", Quantity: 1},
+		{ItemName: "Knife", Price: 8900, Currency: "CHF", Quantity: 1},
+		{ItemName: "Map", Price: 0, Currency: "GBP", Quantity: 2},
+		{ItemName: "Spare tent", Price: 20000, Currency: "USD", Quantity: 1, Optional: true},
+	}
+
+	cost := computePackCost(7, contents, cv, "EUR")
+
+	assert.Equal(t, uint(7), cost.PackID)
+	assert.Equal(t, "EUR", cost.Currency)
+	// 260 USD = 208 EUR, 4800 JPY = 30 EUR, 30 EUR
+	assert.Equal(t, 26800, cost.Total)
+	assert.Equal(t, []CurrencyAmount{
+		{Currency: "CHF", Amount: 8900},
+		{Currency: "EUR", Amount: 3000},
+		{Currency: "JPY", Amount: 4800},
+		{Currency: "USD", Amount: 26000},
+	}, cost.ByCurrency)
+	assert.Equal(t, []string{"CHF"}, cost.MissingRates)
+}
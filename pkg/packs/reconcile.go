@@ -0,0 +1,304 @@
+package packs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/Angak0k/pimpmypack/pkg/currencies"
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/Angak0k/pimpmypack/pkg/inventories"
+)
+
+// Import reconciliation: a preview proposes, for each incoming item, to reuse
+// the identical inventory item, to create a new one, or lists similar items
+// for the user to review; the reconciled plan is then committed.
+
+// Actions of an import plan item
+const (
+	ImportActionReuse  = "reuse"
+	ImportActionCreate = "create"
+	// ImportActionReview is only proposed by previews: similar items exist
+	// and the user picks one of them or creates a new item
+	ImportActionReview = "review"
+)
+
+const (
+	// importMatchThreshold is the minimum name similarity of a candidate
+	importMatchThreshold = 0.4
+	// maxImportCandidates caps the candidates listed for an item
+	maxImportCandidates = 5
+	// maxPackContentNotes caps the notes of a pack item, in characters, like
+	// the binding of PackContentRequest
+	maxPackContentNotes = 500
+)
+
+var (
+	// ErrUnresolvedImportItem is returned when a committed plan item is neither reused nor created
+	ErrUnresolvedImportItem = errors.New("every import item needs a reuse or create action")
+	// ErrImportItemNotFound is returned when a plan reuses an item that is not in the user's inventory
+	ErrImportItemNotFound = errors.New("inventory item to reuse not found")
+	// ErrImportNotesTooLong is returned when the notes of an import item are too long
+	ErrImportNotesTooLong = errors.New("item notes must be 500 characters or less")
+	// ErrDuplicateImportItem is returned when a plan reuses or creates the same inventory item twice
+	ErrDuplicateImportItem = errors.New("an inventory item can only be imported once per pack")
+)
+
+// previewImport proposes an action for each item. With fuzzy, items without
+// an identical inventory item list the similar ones as candidates.
+func previewImport(ctx context.Context, lp ExternalPack, userID uint, fuzzy bool) ([]ImportPreviewItem, error) {
+	if err := normalizeImportCurrencies(ctx, lp, userID); err != nil {
+		return nil, err
+	}
+
+	var inventory inventories.Inventories
+	if fuzzy {
+		userInventory, err := inventories.ReturnInventoriesByUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		inventory = *userInventory
+	}
+
+	preview := make([]ImportPreviewItem, 0, len(lp))
+	for _, item := range lp {
+		p := ImportPreviewItem{
+			ImportPlanItem: ImportPlanItem{ExternalPackItem: item, Action: ImportActionCreate},
+			Candidates:     []ImportCandidate{},
+		}
+
+		existing, err := inventories.FindInventoryItemByAttributes(ctx, userID, item.ItemName, item.Category, item.Desc)
+		if err != nil && !errors.Is(err, inventories.ErrNoItemFound) {
+			return nil, fmt.Errorf("failed to check for existing item: %w", err)
+		}
+		if existing != nil {
+			p.Action = ImportActionReuse
+			p.InventoryID = existing.ID
+		} else if p.Candidates = importCandidates(item, inventory); len(p.Candidates) > 0 {
+			p.Action = ImportActionReview
+		}
+
+		preview = append(preview, p)
+	}
+	return preview, nil
+}
+
+// importCandidates returns the inventory items whose name is similar to the
+// incoming item, most similar first
+func importCandidates(item ExternalPackItem, inventory inventories.Inventories) []ImportCandidate {
+	candidates := []ImportCandidate{}
+	for _, inv := range inventory {
+		similarity := inventories.NameSimilarity(item.ItemName, inv.ItemName)
+		if similarity < importMatchThreshold {
+			continue
+		}
+		candidates = append(candidates, ImportCandidate{
+			InventoryID: inv.ID,
+			ItemName:    inv.ItemName,
+			Category:    inv.Category,
+			Description: inv.Description,
+			Weight:      inv.Weight,
+			Similarity:  similarity,
+		})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Similarity > candidates[j].Similarity })
+	if len(candidates) > maxImportCandidates {
+		candidates = candidates[:maxImportCandidates]
+	}
+	return candidates
+}
+
+// commitImportPlan creates a pack from a reconciled plan within a
+// transaction. Reused items must belong to the user, and no inventory item
+// may be reused or created twice.
+func commitImportPlan(
+	ctx context.Context, plan []ImportPlanItem, userID uint, packName, packDescription string,
+) (uint, error) {
+	if err := checkImportPlan(ctx, plan, userID); err != nil {
+		return 0, err
+	}
+
+	tx, err := database.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	packID, err := writeImportPlan(ctx, tx, plan, userID, packName, packDescription)
+	if err != nil {
+		return 0, err
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return packID, nil
+}
+
+// mergeImportPlan merges the items of a plan with the same target, as a pack
+// holds an inventory item once: reused items with the same inventory ID, and
+// items to create with the same createKey. The first item is kept, with the
+// summed quantity and the first notes; it is optional only if all are.
+func mergeImportPlan(plan []ImportPlanItem, createKey func(ExternalPackItem) string) []ImportPlanItem {
+	merged := make([]ImportPlanItem, 0, len(plan))
+	index := map[string]int{}
+	for _, item := range plan {
+		key := "create\x00" + createKey(item.ExternalPackItem)
+		if item.Action == ImportActionReuse {
+			key = fmt.Sprintf("reuse\x00%d", item.InventoryID)
+		}
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(merged)
+			merged = append(merged, item)
+			continue
+		}
+		merged[i].Qty += item.Qty
+		merged[i].Optional = merged[i].Optional && item.Optional
+		if merged[i].Notes == "" {
+			merged[i].Notes = item.Notes
+		}
+	}
+	return merged
+}
+
+// exactItemKey identifies the items to create by name, category and
+// description, like the reuse of existing items
+func exactItemKey(item ExternalPackItem) string {
+	return item.ItemName + "\x00" + item.Category + "\x00" + item.Desc
+}
+
+// checkImportPlan validates the size and items of a plan before anything is written
+func checkImportPlan(ctx context.Context, plan []ImportPlanItem, userID uint) error {
+	if len(plan) == 0 {
+		return errors.New("payload is empty")
+	}
+	if len(plan) > MaxImportItems {
+		return fmt.Errorf("%w: %d items exceeds limit of %d", ErrTooManyItems, len(plan), MaxImportItems)
+	}
+	return validateImportPlan(ctx, plan, userID)
+}
+
+// writeImportPlan creates the pack, the inventory items and the pack
+// contents of a checked plan with tx
+func writeImportPlan(
+	ctx context.Context, tx *sql.Tx, plan []ImportPlanItem, userID uint, packName, packDescription string,
+) (uint, error) {
+	newPack := Pack{UserID: userID, PackName: packName, PackDescription: packDescription}
+	if err := insertPack(ctx, tx, &newPack); err != nil {
+		return 0, err
+	}
+
+	for _, item := range plan {
+		itemID := item.InventoryID
+		if item.Action == ImportActionCreate {
+			i := inventories.Inventory{
+				UserID:      userID,
+				ItemName:    item.ItemName,
+				Category:    item.Category,
+				Description: item.Desc,
+				Weight:      item.Weight,
+				URL:         item.URL,
+				Price:       item.Price,
+				Currency:    item.Currency,
+				Wishlist:    item.placeholder,
+			}
+			if err := inventories.InsertInventoryTx(ctx, tx, &i); err != nil {
+				return 0, err
+			}
+			itemID = i.ID
+		}
+
+		pc := PackContent{
+			PackID:     newPack.ID,
+			ItemID:     itemID,
+			Quantity:   item.Qty,
+			Worn:       item.Worn,
+			Consumable: item.Consumable,
+			Notes:      item.Notes,
+			Optional:   item.Optional,
+		}
+		if err := insertPackContent(ctx, tx, &pc); err != nil {
+			return 0, err
+		}
+	}
+	return newPack.ID, nil
+}
+
+// validateImportPlan checks the actions and reused items of a plan and
+// normalizes the currencies of the items to create. An item reused twice,
+// or two identical items to create, would be the same pack content twice.
+func validateImportPlan(ctx context.Context, plan []ImportPlanItem, userID uint) error {
+	preferred, err := currencies.PreferredCurrency(ctx, userID)
+	if err != nil {
+		return err
+	}
+	reused := map[uint]bool{}
+	created := map[[3]string]bool{}
+	for i := range plan {
+		item := &plan[i]
+		if utf8.RuneCountInString(item.Notes) > maxPackContentNotes {
+			return fmt.Errorf("%w: item %d", ErrImportNotesTooLong, i)
+		}
+		switch item.Action {
+		case ImportActionReuse:
+			owned, err := inventories.CheckInventoryOwnership(ctx, item.InventoryID, userID)
+			if err != nil {
+				return err
+			}
+			if !owned {
+				return fmt.Errorf("%w: item %d", ErrImportItemNotFound, i)
+			}
+			if reused[item.InventoryID] {
+				return fmt.Errorf("%w: item %d", ErrDuplicateImportItem, i)
+			}
+			reused[item.InventoryID] = true
+		case ImportActionCreate:
+			key := [3]string{item.ItemName, item.Category, item.Desc}
+			if created[key] {
+				return fmt.Errorf("%w: item %d", ErrDuplicateImportItem, i)
+			}
+			created[key] = true
+			if err := normalizeImportCurrency(&item.ExternalPackItem, preferred); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%w: item %d", ErrUnresolvedImportItem, i)
+		}
+	}
+	return nil
+}
+
+// normalizeImportCurrencies upper-cases the currency of every imported item;
+// items without one are priced in the preferred currency of the user
+func normalizeImportCurrencies(ctx context.Context, lp ExternalPack, userID uint) error {
+	preferred, err := currencies.PreferredCurrency(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for i := range lp {
+		if err := normalizeImportCurrency(&lp[i], preferred); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func normalizeImportCurrency(item *ExternalPackItem, preferred string) error {
+	if item.Currency == "" {
+		item.Currency = preferred
+		return nil
+	}
+	currency, err := currencies.Normalize(item.Currency)
+	if err != nil {
+		return fmt.Errorf("%w: %q for item %q", err, item.Currency, item.ItemName)
+	}
+	item.Currency = currency
+	return nil
+}
@@ -0,0 +1,138 @@
+package packs
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Angak0k/pimpmypack/pkg/inventories"
+	"github.com/Angak0k/pimpmypack/pkg/security"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func postImportJSON(t *testing.T, router *gin.Engine, token, path string, payload any) *httptest.ResponseRecorder {
+	t.Helper()
+	body, err := json.Marshal(payload)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestImportPackPreviewAndCommit(t *testing.T) {
+	userID := users[0].ID
+	token, err := security.GenerateToken(userID)
+	require.NoError(t, err)
+
+	filter := inventories.Inventory{
+		UserID: userID, ItemName: "Sawyer Squeeze", Category: "Water", Weight: 85, Currency: "EUR",
+	}
+	require.NoError(t, inventories.InsertInventory(t.Context(), &filter))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/importpack/preview", PreviewImportPack)
+	router.POST("/importpack/commit", CommitImportPack)
+
+	items := ExternalPack{
+		{ItemName: "Sawyer Squeeze", Category: "Water", Qty: 1, Weight: 85},
+		{ItemName: "Sawyer Squeeze Filter", Category: "Hydration", Qty: 1, Weight: 90},
+		{ItemName: "Quokka Spork", Category: "Kitchen", Qty: 1, Weight: 12},
+	}
+	w := postImportJSON(t, router, token, "/importpack/preview", ImportPackRequest{PackName: "Reconciled", Items: items})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var preview ImportPreviewResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &preview))
+	require.Len(t, preview.Items, 3)
+	assert.Equal(t, ImportActionReuse, preview.Items[0].Action)
+	assert.Equal(t, filter.ID, preview.Items[0].InventoryID)
+	assert.Equal(t, ImportActionReview, preview.Items[1].Action)
+	require.NotEmpty(t, preview.Items[1].Candidates)
+	assert.Equal(t, filter.ID, preview.Items[1].Candidates[0].InventoryID)
+	assert.Equal(t, ImportActionCreate, preview.Items[2].Action)
+	assert.Empty(t, preview.Items[2].Candidates)
+
+	// The candidate is already reused by the first item: it cannot be a pack content twice
+	plan := []ImportPlanItem{
+		preview.Items[0].ImportPlanItem,
+		{ExternalPackItem: items[1], Action: ImportActionReuse, InventoryID: filter.ID},
+		preview.Items[2].ImportPlanItem,
+	}
+	w = postImportJSON(t, router, token, "/importpack/commit", ImportPlanRequest{PackName: "Reconciled", Items: plan})
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+	plan[1] = ImportPlanItem{ExternalPackItem: items[1], Action: ImportActionCreate}
+	w = postImportJSON(t, router, token, "/importpack/commit", ImportPlanRequest{PackName: "Reconciled", Items: plan})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response ImportExternalPackResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	contents, err := returnPackContentsByPackID(t.Context(), response.PackID)
+	require.NoError(t, err)
+	require.Len(t, *contents, 3)
+	names := map[string]bool{}
+	for _, content := range *contents {
+		names[content.ItemName] = content.InventoryID == filter.ID
+	}
+	assert.Equal(t, map[string]bool{
+		"Sawyer Squeeze": true, "Sawyer Squeeze Filter": false, "Quokka Spork": false,
+	}, names)
+}
+
+func TestCommitImportPackRejectsInvalidPlans(t *testing.T) {
+	token, err := security.GenerateToken(users[0].ID)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/importpack/commit", CommitImportPack)
+
+	owned := inventories.Inventory{UserID: users[0].ID, ItemName: "Tarp", Category: "Shelter", Currency: "EUR"}
+	require.NoError(t, inventories.InsertInventory(t.Context(), &owned))
+
+	item := ExternalPackItem{ItemName: "Tarp", Category: "Shelter", Qty: 1, Weight: 300}
+	reuse := ImportPlanItem{ExternalPackItem: item, Action: ImportActionReuse, InventoryID: owned.ID}
+	create := ImportPlanItem{ExternalPackItem: item, Action: ImportActionCreate}
+	tests := map[string][]ImportPlanItem{
+		"review is not a final action": {{ExternalPackItem: item, Action: ImportActionReview}},
+		"missing action":               {{ExternalPackItem: item}},
+		"item of another user":         {{ExternalPackItem: item, Action: ImportActionReuse, InventoryID: 999999}},
+		"item reused twice":            {reuse, reuse},
+		"item created twice":           {create, create},
+	}
+	for name, plan := range tests {
+		w := postImportJSON(t, router, token, "/importpack/commit",
+			ImportPlanRequest{PackName: "Invalid", Items: plan})
+		assert.Equal(t, http.StatusBadRequest, w.Code, name)
+	}
+
+	w := postImportJSON(t, router, token, "/importpack/commit", ImportPlanRequest{PackName: "Empty"})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestMergeImportPlan(t *testing.T) {
+	stove := ExternalPackItem{ItemName: "Stove", Category: "Kitchen", Qty: 1}
+	gas := ExternalPackItem{ItemName: "Gas", Category: "Kitchen", Qty: 2, Optional: true}
+	plan := []ImportPlanItem{
+		{ExternalPackItem: stove, Action: ImportActionReuse, InventoryID: 7},
+		{ExternalPackItem: gas, Action: ImportActionCreate},
+		{ExternalPackItem: stove, Action: ImportActionReuse, InventoryID: 7},
+		{ExternalPackItem: gas, Action: ImportActionCreate},
+		{ExternalPackItem: stove, Action: ImportActionCreate},
+	}
+
+	merged := mergeImportPlan(plan, exactItemKey)
+	require.Len(t, merged, 3)
+	assert.Equal(t, 2, merged[0].Qty)
+	assert.Equal(t, 4, merged[1].Qty)
+	assert.True(t, merged[1].Optional)
+	assert.Equal(t, ImportActionCreate, merged[2].Action, "creating differs from reusing")
+}
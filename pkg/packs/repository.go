@@ -11,7 +11,7 @@ import (
 
 	"github.com/Angak0k/pimpmypack/pkg/database"
 	"github.com/Angak0k/pimpmypack/pkg/helper"
-	"github.com/Angak0k/pimpmypack/pkg/inventories"
+	"github.com/lib/pq"
 )
 
 // Pack queries
@@ -22,17 +22,18 @@ func returnPacks(ctx context.Context) (Packs, error) {
 	rows, err := database.DB().QueryContext(ctx,
 		`SELECT p.id, p.user_id, p.pack_name, p.pack_description, p.sharing_code, p.is_favorite,
 		p.created_at, p.updated_at,
-		COALESCE(SUM(pc.quantity), 0) as items_count,
-		COALESCE(SUM(i.weight * pc.quantity), 0) as total_weight,
+		COALESCE(SUM(pc.quantity) FILTER (WHERE NOT pc.optional AND NOT i.wishlist), 0) as items_count,
+		COALESCE(SUM(i.weight * pc.quantity) FILTER (WHERE NOT pc.optional AND NOT i.wishlist), 0) as total_weight,
+		COALESCE(SUM(i.weight * pc.quantity) FILTER (WHERE NOT pc.optional AND i.wishlist), 0) as wishlist_weight,
 		CASE WHEN pi.pack_id IS NOT NULL THEN true ELSE false END as has_image,
-		p.season, COALESCE(t.name, p.trail) as trail, p.trail_id, p.adventure
+		p.season, COALESCE(t.name, p.trail) as trail, p.trail_id, p.adventure, p.template
 		FROM pack p
 		LEFT JOIN pack_content pc ON p.id = pc.pack_id
 		LEFT JOIN inventory i ON pc.item_id = i.id
 		LEFT JOIN pack_images pi ON p.id = pi.pack_id
 		LEFT JOIN trail t ON p.trail_id = t.id
 		GROUP BY p.id, p.user_id, p.pack_name, p.pack_description, p.sharing_code, p.is_favorite,
-		p.created_at, p.updated_at, pi.pack_id, p.season, t.name, p.trail, p.trail_id, p.adventure
+		p.created_at, p.updated_at, pi.pack_id, p.season, t.name, p.trail, p.trail_id, p.adventure, p.template
 		ORDER BY p.id;`)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -55,11 +56,13 @@ func returnPacks(ctx context.Context) (Packs, error) {
 			&pack.UpdatedAt,
 			&pack.PackItemsCount,
 			&pack.PackWeight,
+			&pack.WishlistWeight,
 			&pack.HasImage,
 			&pack.Season,
 			&pack.Trail,
 			&pack.TrailID,
 			&pack.Adventure,
+			&pack.Template,
 		)
 		if err != nil {
 			return nil, err
@@ -81,10 +84,11 @@ func findPackByID(ctx context.Context, id uint) (*Pack, error) {
 	row := database.DB().QueryRowContext(ctx,
 		`SELECT p.id, p.user_id, p.pack_name, p.pack_description, p.sharing_code, p.is_favorite,
 		p.created_at, p.updated_at,
-		COALESCE(SUM(pc.quantity), 0) as items_count,
-		COALESCE(SUM(i.weight * pc.quantity), 0) as total_weight,
+		COALESCE(SUM(pc.quantity) FILTER (WHERE NOT pc.optional AND NOT i.wishlist), 0) as items_count,
+		COALESCE(SUM(i.weight * pc.quantity) FILTER (WHERE NOT pc.optional AND NOT i.wishlist), 0) as total_weight,
+		COALESCE(SUM(i.weight * pc.quantity) FILTER (WHERE NOT pc.optional AND i.wishlist), 0) as wishlist_weight,
 		CASE WHEN pi.pack_id IS NOT NULL THEN true ELSE false END as has_image,
-		p.season, COALESCE(t.name, p.trail) as trail, p.trail_id, p.adventure
+		p.season, COALESCE(t.name, p.trail) as trail, p.trail_id, p.adventure, p.template
 		FROM pack p
 		LEFT JOIN pack_content pc ON p.id = pc.pack_id
 		LEFT JOIN inventory i ON pc.item_id = i.id
@@ -92,7 +96,7 @@ func findPackByID(ctx context.Context, id uint) (*Pack, error) {
 		LEFT JOIN trail t ON p.trail_id = t.id
 		WHERE p.id = $1
 		GROUP BY p.id, p.user_id, p.pack_name, p.pack_description, p.sharing_code, p.is_favorite,
-		p.created_at, p.updated_at, pi.pack_id, p.season, t.name, p.trail, p.trail_id, p.adventure;`,
+		p.created_at, p.updated_at, pi.pack_id, p.season, t.name, p.trail, p.trail_id, p.adventure, p.template;`,
 		id)
 	err := row.Scan(
 		&pack.ID,
@@ -105,11 +109,13 @@ func findPackByID(ctx context.Context, id uint) (*Pack, error) {
 		&pack.UpdatedAt,
 		&pack.PackItemsCount,
 		&pack.PackWeight,
+		&pack.WishlistWeight,
 		&pack.HasImage,
 		&pack.Season,
 		&pack.Trail,
 		&pack.TrailID,
-		&pack.Adventure)
+		&pack.Adventure,
+		&pack.Template)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -127,10 +133,11 @@ func findPacksByUserID(ctx context.Context, id uint) (*Packs, error) {
 	rows, err := database.DB().QueryContext(ctx, `
 		SELECT p.id, p.user_id, p.pack_name, p.pack_description, p.sharing_code, p.is_favorite,
 		p.created_at, p.updated_at,
-		COALESCE(SUM(pc.quantity), 0) as items_count,
-		COALESCE(SUM(i.weight * pc.quantity), 0) as total_weight,
+		COALESCE(SUM(pc.quantity) FILTER (WHERE NOT pc.optional AND NOT i.wishlist), 0) as items_count,
+		COALESCE(SUM(i.weight * pc.quantity) FILTER (WHERE NOT pc.optional AND NOT i.wishlist), 0) as total_weight,
+		COALESCE(SUM(i.weight * pc.quantity) FILTER (WHERE NOT pc.optional AND i.wishlist), 0) as wishlist_weight,
 		CASE WHEN pi.pack_id IS NOT NULL THEN true ELSE false END as has_image,
-		p.season, COALESCE(t.name, p.trail) as trail, p.trail_id, p.adventure
+		p.season, COALESCE(t.name, p.trail) as trail, p.trail_id, p.adventure, p.template
 		FROM pack p
 		LEFT JOIN pack_content pc ON p.id = pc.pack_id
 		LEFT JOIN inventory i ON pc.item_id = i.id
@@ -138,7 +145,7 @@ func findPacksByUserID(ctx context.Context, id uint) (*Packs, error) {
 		LEFT JOIN trail t ON p.trail_id = t.id
 		WHERE p.user_id = $1
 		GROUP BY p.id, p.user_id, p.pack_name, p.pack_description, p.sharing_code, p.is_favorite,
-		         p.created_at, p.updated_at, pi.pack_id, p.season, t.name, p.trail, p.trail_id, p.adventure;`, id)
+		         p.created_at, p.updated_at, pi.pack_id, p.season, t.name, p.trail, p.trail_id, p.adventure, p.template;`, id)
 	if err != nil {
 		return nil, err
 	}
@@ -157,11 +164,13 @@ func findPacksByUserID(ctx context.Context, id uint) (*Packs, error) {
 			&pack.UpdatedAt,
 			&pack.PackItemsCount,
 			&pack.PackWeight,
+			&pack.WishlistWeight,
 			&pack.HasImage,
 			&pack.Season,
 			&pack.Trail,
 			&pack.TrailID,
-			&pack.Adventure)
+			&pack.Adventure,
+			&pack.Template)
 		if err != nil {
 			return nil, err
 		}
@@ -201,7 +210,7 @@ func checkPackExists(ctx context.Context, id uint) (bool, error) {
 
 // Pack writes
 
-func insertPack(ctx context.Context, p *Pack) error {
+func insertPack(ctx context.Context, q database.Queryer, p *Pack) error {
 	if p == nil {
 		return errors.New("payload is empty")
 	}
@@ -209,7 +218,7 @@ func insertPack(ctx context.Context, p *Pack) error {
 	p.UpdatedAt = time.Now().Truncate(time.Second)
 	// SharingCode is now NULL by default (pack is private)
 
-	err := database.DB().QueryRowContext(ctx,
+	err := q.QueryRowContext(ctx,
 		`INSERT INTO pack
 		(user_id, pack_name, pack_description, sharing_code, season, trail, trail_id, adventure, created_at, updated_at)
 		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
@@ -492,8 +501,8 @@ func duplicatePackByID(ctx context.Context, sourceID uint, userID uint) (uint, e
 
 	_, err = tx.ExecContext(ctx,
 		`INSERT INTO pack_content
-		(pack_id, item_id, quantity, worn, consumable, created_at, updated_at)
-		SELECT $1, item_id, quantity, worn, consumable, $2, $2
+		(pack_id, item_id, quantity, worn, consumable, notes, position, optional, created_at, updated_at)
+		SELECT $1, item_id, quantity, worn, consumable, notes, position, optional, $2, $2
 		FROM pack_content
 		WHERE pack_id = $3;`,
 		newPackID, now, sourceID)
@@ -575,7 +584,7 @@ func returnPackContents(ctx context.Context) (*PackContents, error) {
 	var packContents PackContents
 
 	rows, err := database.DB().QueryContext(ctx,
-		`SELECT id, pack_id, item_id, quantity, worn, consumable, created_at, updated_at
+		`SELECT id, pack_id, item_id, quantity, worn, consumable, notes, position, optional, created_at, updated_at
 		FROM pack_content;`)
 	if err != nil {
 		return nil, err
@@ -591,6 +600,9 @@ func returnPackContents(ctx context.Context) (*PackContents, error) {
 			&packContent.Quantity,
 			&packContent.Worn,
 			&packContent.Consumable,
+			&packContent.Notes,
+			&packContent.Position,
+			&packContent.Optional,
 			&packContent.CreatedAt,
 			&packContent.UpdatedAt)
 		if err != nil {
@@ -610,7 +622,7 @@ func findPackContentByID(ctx context.Context, id uint) (*PackContent, error) {
 	var packcontent PackContent
 
 	row := database.DB().QueryRowContext(ctx,
-		`SELECT id, pack_id, item_id, quantity, worn, consumable, created_at, updated_at
+		`SELECT id, pack_id, item_id, quantity, worn, consumable, notes, position, optional, created_at, updated_at
 		FROM pack_content
 		WHERE id = $1;`,
 		id)
@@ -621,6 +633,9 @@ func findPackContentByID(ctx context.Context, id uint) (*PackContent, error) {
 		&packcontent.Quantity,
 		&packcontent.Worn,
 		&packcontent.Consumable,
+		&packcontent.Notes,
+		&packcontent.Position,
+		&packcontent.Optional,
 		&packcontent.CreatedAt,
 		&packcontent.UpdatedAt)
 
@@ -661,11 +676,17 @@ func returnPackContentsByPackID(ctx context.Context, id uint) (*PackContentWithI
 			pc.quantity,
 			pc.worn,
 			pc.consumable,
+			pc.notes,
+			pc.position,
+			pc.optional,
+			i.wishlist,
 			CASE WHEN ii.item_id IS NOT NULL THEN true ELSE false END as has_image
 			FROM pack_content pc
 			JOIN inventory i ON pc.item_id = i.id
 			LEFT JOIN inventory_images ii ON i.id = ii.item_id
-			WHERE pc.pack_id = $1;`,
+			LEFT JOIN category c ON c.user_id = i.user_id AND c.name = i.category
+			WHERE pc.pack_id = $1
+			ORDER BY c.position NULLS LAST, i.category, pc.position NULLS LAST, i.item_name, pc.id;`,
 		id)
 
 	if err != nil {
@@ -689,6 +710,10 @@ func returnPackContentsByPackID(ctx context.Context, id uint) (*PackContentWithI
 			&item.Quantity,
 			&item.Worn,
 			&item.Consumable,
+			&item.Notes,
+			&item.Position,
+			&item.Optional,
+			&item.Wishlist,
 			&item.HasImage)
 		if err != nil {
 			return nil, err
@@ -717,23 +742,26 @@ func checkItemInPack(ctx context.Context, packID uint, itemID uint) (bool, error
 
 // Pack content writes
 
-func insertPackContent(ctx context.Context, pc *PackContent) error {
+func insertPackContent(ctx context.Context, q database.Queryer, pc *PackContent) error {
 	if pc == nil {
 		return errors.New("payload is empty")
 	}
 	pc.CreatedAt = time.Now().Truncate(time.Second)
 	pc.UpdatedAt = time.Now().Truncate(time.Second)
 
-	err := database.DB().QueryRowContext(ctx, `
+	err := q.QueryRowContext(ctx, `
 		INSERT INTO pack_content
-		(pack_id, item_id, quantity, worn, consumable, created_at, updated_at)
-		VALUES ($1,$2,$3,$4,$5,$6,$7)
+		(pack_id, item_id, quantity, worn, consumable, notes, position, optional, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
 		RETURNING id;`,
 		pc.PackID,
 		pc.ItemID,
 		pc.Quantity,
 		pc.Worn,
 		pc.Consumable,
+		pc.Notes,
+		pc.Position,
+		pc.Optional,
 		pc.CreatedAt,
 		pc.UpdatedAt).Scan(&pc.ID)
 
@@ -751,8 +779,9 @@ func updatePackContentByID(ctx context.Context, id uint, pc *PackContent) error
 
 	statement, err := database.DB().PrepareContext(ctx, `
 		UPDATE pack_content
-		SET pack_id=$1, item_id=$2, quantity=$3, worn=$4, consumable=$5, updated_at=$6
-		WHERE id=$7;`)
+		SET pack_id=$1, item_id=$2, quantity=$3, worn=$4, consumable=$5,
+			notes=$6, position=$7, optional=$8, updated_at=$9
+		WHERE id=$10;`)
 	if err != nil {
 		return err
 	}
@@ -760,7 +789,8 @@ func updatePackContentByID(ctx context.Context, id uint, pc *PackContent) error
 	defer statement.Close()
 
 	_, err = statement.ExecContext(ctx,
-		pc.PackID, pc.ItemID, pc.Quantity, pc.Worn, pc.Consumable, pc.UpdatedAt, id)
+		pc.PackID, pc.ItemID, pc.Quantity, pc.Worn, pc.Consumable,
+		pc.Notes, pc.Position, pc.Optional, pc.UpdatedAt, id)
 	if err != nil {
 		return err
 	}
@@ -782,6 +812,52 @@ func deletePackContentByID(ctx context.Context, id uint) error {
 	return nil
 }
 
+// reorderPackContents sets the position of each pack content of a pack to
+// its index in ids; the contents left out keep their position
+func reorderPackContents(ctx context.Context, packID uint, ids []uint) error {
+	seen := map[uint]bool{}
+	order := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return ErrDuplicatePackContent
+		}
+		seen[id] = true
+		order = append(order, int64(id))
+	}
+
+	tx, err := database.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE pack_content pc SET position = o.position - 1, updated_at = NOW()
+		FROM unnest($2::bigint[]) WITH ORDINALITY AS o(id, position)
+		WHERE pc.id = o.id AND pc.pack_id = $1;`,
+		packID, pq.Array(order))
+	if err != nil {
+		return fmt.Errorf("failed to reorder pack contents: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected != int64(len(ids)) {
+		err = ErrUnknownPackContent
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
 // Import/CSV
 
 // readLineFromCSV takes a record from csv.NewReader and returns an ExternalPackItem
@@ -836,67 +912,15 @@ func insertExternalPack(
 		return 0, fmt.Errorf("%w: %d items exceeds limit of %d", ErrTooManyItems, len(*lp), MaxImportItems)
 	}
 
-	// Create new pack
-	var newPack Pack
-	newPack.UserID = userID
-	newPack.PackName = packName
-	newPack.PackDescription = packDescription
-	err := insertPack(ctx, &newPack)
+	// Reuse identical inventory items, create the others
+	preview, err := previewImport(ctx, *lp, userID, false)
 	if err != nil {
 		return 0, err
 	}
-
-	// Insert content in new pack with insertPackContent
-	for _, item := range *lp {
-		var itemID uint
-
-		// Check if item already exists in inventory
-		existingItem, err := inventories.FindInventoryItemByAttributes(
-			ctx,
-			userID,
-			item.ItemName,
-			item.Category,
-			item.Desc,
-		)
-		if err != nil && !errors.Is(err, inventories.ErrNoItemFound) {
-			return 0, fmt.Errorf("failed to check for existing item: %w", err)
-		}
-
-		if existingItem != nil {
-			// Item exists, reuse it
-			itemID = existingItem.ID
-		} else {
-			// Item doesn't exist, create it
-			var i inventories.Inventory
-			i.UserID = userID
-			i.ItemName = item.ItemName
-			i.Category = item.Category
-			i.Description = item.Desc
-			i.Weight = item.Weight
-			i.URL = item.URL
-			i.Price = item.Price
-			i.Currency = item.Currency
-			if i.Currency == "" {
-				i.Currency = "USD"
-			}
-			err := inventories.InsertInventory(ctx, &i)
-			if err != nil {
-				return 0, err
-			}
-			itemID = i.ID
-		}
-
-		// Create PackContent with the item (existing or new)
-		var pc PackContent
-		pc.PackID = newPack.ID
-		pc.ItemID = itemID
-		pc.Quantity = item.Qty
-		pc.Worn = item.Worn
-		pc.Consumable = item.Consumable
-		err = insertPackContent(ctx, &pc)
-		if err != nil {
-			return 0, err
-		}
+	plan := make([]ImportPlanItem, len(preview))
+	for i, p := range preview {
+		plan[i] = p.ImportPlanItem
 	}
-	return newPack.ID, nil
+	// Repeated lines become one pack content
+	return commitImportPlan(ctx, mergeImportPlan(plan, exactItemKey), userID, packName, packDescription)
 }
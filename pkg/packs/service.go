@@ -14,6 +14,16 @@ func CheckPackOwnership(ctx context.Context, packID uint, userID uint) (bool, er
 	return checkPackOwnership(ctx, packID, userID)
 }
 
+// FindPacksByUserID returns all packs of a user
+func FindPacksByUserID(ctx context.Context, userID uint) (*Packs, error) {
+	return findPacksByUserID(ctx, userID)
+}
+
+// ReturnPackContentsByPackID returns the items of a pack with their inventory details
+func ReturnPackContentsByPackID(ctx context.Context, packID uint) (*PackContentWithItems, error) {
+	return returnPackContentsByPackID(ctx, packID)
+}
+
 // CheckItemInPack verifies if an inventory item is part of a pack's contents
 func CheckItemInPack(ctx context.Context, packID uint, itemID uint) (bool, error) {
 	return checkItemInPack(ctx, packID, itemID)
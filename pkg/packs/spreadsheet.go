@@ -0,0 +1,700 @@
+package packs
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Angak0k/pimpmypack/pkg/currencies"
+	"github.com/Angak0k/pimpmypack/pkg/helper"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// Spreadsheet import: CSV (comma, semicolon, tab or pipe separated; UTF-8,
+// UTF-16 or Windows-1252) and XLSX exports of LighterPack, Packstack,
+// GearGrams, Hikerhub or hand-made sheets. Columns are matched by header
+// name, or by an explicit mapping from field to header.
+
+var (
+	// ErrUnreadableSpreadsheet is returned when a file is neither a CSV nor an XLSX workbook
+	ErrUnreadableSpreadsheet = errors.New("unreadable spreadsheet, expected a CSV or XLSX file")
+	// ErrNoSpreadsheetHeader is returned when no header row names the item column
+	ErrNoSpreadsheetHeader = errors.New("no header row with an item name column found")
+	// ErrInvalidColumnMapping is returned for a mapping on an unknown field or a missing header
+	ErrInvalidColumnMapping = errors.New("invalid column mapping")
+	// ErrNoValidRows is returned when every row of a spreadsheet has an error
+	ErrNoValidRows = errors.New("no valid rows in spreadsheet")
+	// ErrInvalidWeightUnit is returned for a default weight unit other than g, kg, oz or lb
+	ErrInvalidWeightUnit = errors.New("weight_unit must be g, kg, oz or lb")
+)
+
+// Fields a spreadsheet column can be mapped to, named like ExternalPackItem JSON
+const (
+	colItemName   = "item_name"
+	colCategory   = "category"
+	colDesc       = "desc"
+	colQty        = "qty"
+	colWeight     = "weight"
+	colUnit       = "unit"
+	colURL        = "url"
+	colPrice      = "price"
+	colCurrency   = "currency"
+	colWorn       = "worn"
+	colConsumable = "consumable"
+)
+
+// columnAliases lists, for each field, the normalized headers used by the known tools
+var columnAliases = map[string][]string{
+	colItemName: {"item name", "item", "name", "gear", "gear name", "product"},
+	colCategory: {"category", "categories", "group", "type"},
+	colDesc:     {"desc", "description", "notes", "note", "details"},
+	colQty:      {"qty", "quantity", "count", "number"},
+	colWeight: {"weight", "weight (g)", "weight (kg)", "weight (oz)", "weight (lb)", "item weight",
+		"unit weight", "grams", "ounces"},
+	colUnit:       {"unit", "units", "weight unit", "unit of measure"},
+	colURL:        {"url", "link", "website", "product url"},
+	colPrice:      {"price", "cost", "price paid"},
+	colCurrency:   {"currency"},
+	colWorn:       {"worn", "worn?", "wearing", "worn item"},
+	colConsumable: {"consumable", "consumable?", "consumables", "consumed"},
+}
+
+const (
+	// headerSearchRows is how many leading rows may hold titles before the header
+	headerSearchRows = 10
+	// maxXLSXEntryBytes caps the decompressed size of a workbook part
+	maxXLSXEntryBytes = 16 << 20
+	// maxXLSXColumns ignores cells far right of any sensible gear list
+	maxXLSXColumns = 100
+	// defaultMinorUnits is used for prices whose currency is not known yet
+	defaultMinorUnits = 2
+	// maxSpreadsheetNumber caps quantities, weights and prices, so that grams
+	// and minor units still fit the integer columns
+	maxSpreadsheetNumber = 1e6
+)
+
+// spreadsheetOptions tunes the parsing of a spreadsheet
+type spreadsheetOptions struct {
+	// Mapping maps fields to header names, overriding the detected columns
+	Mapping map[string]string
+	// WeightUnit is the unit of weights without a unit column or header hint
+	WeightUnit string
+	// Currency is the currency of prices without a currency column or symbol
+	Currency string
+}
+
+// spreadsheetImport is the result of parsing a spreadsheet
+type spreadsheetImport struct {
+	Items   ExternalPack
+	Columns map[string]string
+	Errors  []SpreadsheetRowError
+}
+
+// spreadsheetRow is a row of cells with its number in the file, for error reports
+type spreadsheetRow struct {
+	number int
+	cells  []string
+}
+
+// spreadsheetColumn is a column matched to a field
+type spreadsheetColumn struct {
+	index  int
+	header string
+}
+
+// parseSpreadsheet reads a CSV or XLSX file into pack items. Rows that cannot
+// be read are reported in Errors and skipped.
+func parseSpreadsheet(data []byte, opts spreadsheetOptions) (*spreadsheetImport, error) {
+	rows, err := readSpreadsheetRows(data)
+	if err != nil {
+		return nil, err
+	}
+
+	headerRow, columns, err := findSpreadsheetColumns(rows, opts.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &spreadsheetImport{
+		Items:   ExternalPack{},
+		Columns: map[string]string{},
+		Errors:  []SpreadsheetRowError{},
+	}
+	for field, col := range columns {
+		result.Columns[field] = col.header
+	}
+
+	for _, row := range rows[headerRow+1:] {
+		if isBlankRow(row.cells) {
+			continue
+		}
+		item, err := spreadsheetRowToItem(row.cells, columns, opts)
+		if err != nil {
+			result.Errors = append(result.Errors, SpreadsheetRowError{Row: row.number, Error: err.Error()})
+			continue
+		}
+		result.Items = append(result.Items, item)
+		if len(result.Items) > MaxImportItems {
+			return nil, fmt.Errorf("%w: more than %d items", ErrTooManyItems, MaxImportItems)
+		}
+	}
+
+	if len(result.Items) == 0 {
+		return result, ErrNoValidRows
+	}
+	return result, nil
+}
+
+// Reading
+
+// readSpreadsheetRows returns the cells of an XLSX workbook's first sheet, or of a CSV file
+func readSpreadsheetRows(data []byte) ([]spreadsheetRow, error) {
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		return readXLSXRows(data)
+	}
+
+	text, err := decodeSpreadsheetText(data)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma = detectDelimiter(text)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	rows := []spreadsheetRow{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUnreadableSpreadsheet, err)
+		}
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, spreadsheetRow{number: line, cells: record})
+	}
+}
+
+// decodeSpreadsheetText converts UTF-8 (with or without BOM), UTF-16 with BOM
+// or Windows-1252 text to a Go string
+func decodeSpreadsheetText(data []byte) (string, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		data = data[3:]
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}), bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		decoded, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewDecoder().Bytes(data)
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrUnreadableSpreadsheet, err)
+		}
+		return string(decoded), nil
+	}
+
+	if utf8.Valid(data) {
+		if bytes.IndexByte(data, 0) >= 0 {
+			return "", ErrUnreadableSpreadsheet
+		}
+		return string(data), nil
+	}
+
+	decoded, err := charmap.Windows1252.NewDecoder().Bytes(data)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrUnreadableSpreadsheet, err)
+	}
+	return string(decoded), nil
+}
+
+// detectDelimiter picks the candidate delimiter found the most times on a
+// single line among the first lines, which may hold a title before the header
+func detectDelimiter(text string) rune {
+	lines := strings.SplitN(text, "\n", headerSearchRows+1)
+	if len(lines) > headerSearchRows {
+		lines = lines[:headerSearchRows]
+	}
+	best, bestCount := ',', 0
+	for _, candidate := range []rune{',', ';', '\t', '|'} {
+		for _, line := range lines {
+			if count := strings.Count(line, string(candidate)); count > bestCount {
+				best, bestCount = candidate, count
+			}
+		}
+	}
+	return best
+}
+
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (x xlsxText) String() string {
+	if len(x.Runs) == 0 {
+		return x.T
+	}
+	var b strings.Builder
+	for _, r := range x.Runs {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		Index int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string   `xml:"r,attr"`
+			Type   string   `xml:"t,attr"`
+			Value  string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXLSXRows returns the cells of the first worksheet of a workbook
+func readXLSXRows(data []byte) ([]spreadsheetRow, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnreadableSpreadsheet, err)
+	}
+
+	var shared xlsxSharedStrings
+	if f := findZipFile(zr, "xl/sharedStrings.xml"); f != nil {
+		if err := decodeZipXML(f, &shared); err != nil {
+			return nil, err
+		}
+	}
+
+	sheetFile := firstXLSXWorksheet(zr)
+	if sheetFile == nil {
+		return nil, fmt.Errorf("%w: no worksheet in workbook", ErrUnreadableSpreadsheet)
+	}
+	var sheet xlsxWorksheet
+	if err := decodeZipXML(sheetFile, &sheet); err != nil {
+		return nil, err
+	}
+
+	rows := make([]spreadsheetRow, 0, len(sheet.Rows))
+	for i, r := range sheet.Rows {
+		number := r.Index
+		if number == 0 {
+			number = i + 1
+		}
+		cells := []string{}
+		for j, c := range r.Cells {
+			col := xlsxColumnIndex(c.Ref, j)
+			if col >= maxXLSXColumns {
+				continue
+			}
+			for len(cells) <= col {
+				cells = append(cells, "")
+			}
+			cells[col] = xlsxCellValue(c.Type, c.Value, c.Inline, shared)
+		}
+		rows = append(rows, spreadsheetRow{number: number, cells: cells})
+	}
+	return rows, nil
+}
+
+func xlsxCellValue(cellType, value string, inline xlsxText, shared xlsxSharedStrings) string {
+	switch cellType {
+	case "s":
+		idx, err := strconv.Atoi(value)
+		if err != nil || idx < 0 || idx >= len(shared.Items) {
+			return ""
+		}
+		return shared.Items[idx].String()
+	case "inlineStr":
+		return inline.String()
+	case "b":
+		if value == "1" {
+			return "true"
+		}
+		return "false"
+	default:
+		return value
+	}
+}
+
+// xlsxColumnIndex converts the letters of a cell reference ("C7") to a
+// zero-based column, the position in the row when there is no reference
+func xlsxColumnIndex(ref string, fallback int) int {
+	col := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A') + 1
+	}
+	if col == 0 || col > 16384 {
+		return fallback
+	}
+	return col - 1
+}
+
+func findZipFile(zr *zip.Reader, name string) *zip.File {
+	for _, f := range zr.File {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// firstXLSXWorksheet returns sheet1, or the first worksheet by name
+func firstXLSXWorksheet(zr *zip.Reader) *zip.File {
+	if f := findZipFile(zr, "xl/worksheets/sheet1.xml"); f != nil {
+		return f
+	}
+	var sheets []*zip.File
+	for _, f := range zr.File {
+		if path.Dir(f.Name) == "xl/worksheets" && path.Ext(f.Name) == ".xml" {
+			sheets = append(sheets, f)
+		}
+	}
+	if len(sheets) == 0 {
+		return nil
+	}
+	sort.Slice(sheets, func(i, j int) bool { return sheets[i].Name < sheets[j].Name })
+	return sheets[0]
+}
+
+func decodeZipXML(f *zip.File, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnreadableSpreadsheet, err)
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(io.LimitReader(rc, maxXLSXEntryBytes+1))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnreadableSpreadsheet, err)
+	}
+	if len(content) > maxXLSXEntryBytes {
+		return fmt.Errorf("%w: %s is too large", ErrUnreadableSpreadsheet, f.Name)
+	}
+	if err := xml.Unmarshal(content, v); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnreadableSpreadsheet, err)
+	}
+	return nil
+}
+
+// Columns
+
+// normalizeHeader lower-cases a header and collapses its whitespace
+func normalizeHeader(header string) string {
+	header = strings.TrimPrefix(header, "\ufeff")
+	return strings.ToLower(strings.Join(strings.Fields(header), " "))
+}
+
+// findSpreadsheetColumns returns the index of the header row and its columns.
+// Without a mapping the header is the first row naming an item column.
+func findSpreadsheetColumns(
+	rows []spreadsheetRow, mapping map[string]string,
+) (int, map[string]spreadsheetColumn, error) {
+	for field := range mapping {
+		if _, ok := columnAliases[field]; !ok {
+			return 0, nil, fmt.Errorf("%w: unknown field %q", ErrInvalidColumnMapping, field)
+		}
+	}
+
+	for i := 0; i < len(rows) && i < headerSearchRows; i++ {
+		columns := matchSpreadsheetColumns(rows[i].cells, mapping)
+		if _, ok := columns[colItemName]; !ok {
+			continue
+		}
+		for field, header := range mapping {
+			if _, ok := columns[field]; !ok {
+				return 0, nil, fmt.Errorf("%w: no column %q for %s", ErrInvalidColumnMapping, header, field)
+			}
+		}
+		return i, columns, nil
+	}
+
+	if _, ok := mapping[colItemName]; ok {
+		return 0, nil, fmt.Errorf("%w: no column %q for %s", ErrInvalidColumnMapping, mapping[colItemName], colItemName)
+	}
+	return 0, nil, ErrNoSpreadsheetHeader
+}
+
+// matchSpreadsheetColumns maps fields to the cells of a candidate header row;
+// mapped headers win over aliases, and the first matching cell wins
+func matchSpreadsheetColumns(row []string, mapping map[string]string) map[string]spreadsheetColumn {
+	columns := map[string]spreadsheetColumn{}
+	taken := map[int]bool{}
+
+	for field, header := range mapping {
+		for i, cell := range row {
+			if normalizeHeader(cell) == normalizeHeader(header) {
+				columns[field] = spreadsheetColumn{index: i, header: cell}
+				taken[i] = true
+				break
+			}
+		}
+	}
+
+	for field, aliases := range columnAliases {
+		if _, ok := columns[field]; ok {
+			continue
+		}
+		if _, ok := mapping[field]; ok {
+			continue
+		}
+		for i, cell := range row {
+			if !taken[i] && containsString(aliases, normalizeHeader(cell)) {
+				columns[field] = spreadsheetColumn{index: i, header: cell}
+				taken[i] = true
+				break
+			}
+		}
+	}
+	return columns
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func isBlankRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Rows
+
+// spreadsheetRowToItem converts a row to a pack item
+func spreadsheetRowToItem(
+	row []string, columns map[string]spreadsheetColumn, opts spreadsheetOptions,
+) (ExternalPackItem, error) {
+	cell := func(field string) string {
+		col, ok := columns[field]
+		if !ok || col.index >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[col.index])
+	}
+
+	item := ExternalPackItem{
+		ItemName:   cell(colItemName),
+		Category:   cell(colCategory),
+		Desc:       cell(colDesc),
+		URL:        cell(colURL),
+		Worn:       parseSpreadsheetBool(cell(colWorn), colWorn),
+		Consumable: parseSpreadsheetBool(cell(colConsumable), colConsumable),
+		Qty:        1,
+	}
+	if item.ItemName == "" {
+		return item, errors.New("missing item name")
+	}
+
+	if qty := cell(colQty); qty != "" {
+		q, err := parseSpreadsheetNumber(qty)
+		if err != nil || q < 1 || q != math.Trunc(q) {
+			return item, fmt.Errorf("invalid quantity %q", qty)
+		}
+		item.Qty = int(q)
+	}
+
+	unit := spreadsheetWeightUnit(cell(colUnit), columns, opts.WeightUnit)
+	if unit == "" {
+		return item, fmt.Errorf("unknown weight unit %q", cell(colUnit))
+	}
+	item.Unit = unit
+	if weight := cell(colWeight); weight != "" {
+		w, err := parseSpreadsheetNumber(weight)
+		if err != nil || w < 0 {
+			return item, fmt.Errorf("invalid weight %q", weight)
+		}
+		item.Weight = int(math.Round(helper.ConvertToGrams(w, unit)))
+	}
+
+	price, currency, err := parseSpreadsheetPrice(cell(colPrice), cell(colCurrency), opts.Currency)
+	if err != nil {
+		return item, err
+	}
+	item.Price = price
+	item.Currency = currency
+
+	return item, nil
+}
+
+// spreadsheetWeightUnit returns the unit of a row's weight: its unit cell,
+// a unit in the weight header ("Weight (oz)"), or the default unit
+func spreadsheetWeightUnit(unitCell string, columns map[string]spreadsheetColumn, defaultUnit string) string {
+	if unitCell != "" {
+		return normalizeWeightUnit(unitCell)
+	}
+	if col, ok := columns[colWeight]; ok {
+		header := normalizeHeader(col.header)
+		for _, hint := range []string{"(g)", "(kg)", "(oz)", "(lb)", "grams", "ounces"} {
+			if strings.Contains(header, hint) {
+				return normalizeWeightUnit(strings.Trim(hint, "()"))
+			}
+		}
+	}
+	if defaultUnit == "" {
+		return "g"
+	}
+	return normalizeWeightUnit(defaultUnit)
+}
+
+// normalizeWeightUnit returns g, kg, oz or lb, "" for an unknown unit
+func normalizeWeightUnit(unit string) string {
+	switch strings.ToLower(strings.TrimSpace(unit)) {
+	case "g", "gr", "gram", "grams", "gramme", "grammes":
+		return "g"
+	case "kg", "kilo", "kilos", "kilogram", "kilograms":
+		return "kg"
+	case "oz", "ounce", "ounces":
+		return "oz"
+	case "lb", "lbs", "pound", "pounds":
+		return "lb"
+	default:
+		return ""
+	}
+}
+
+// errSpreadsheetNumberRange is returned for NaN, infinite or out of range numbers
+var errSpreadsheetNumberRange = errors.New("number out of range")
+
+// commaThousandsPattern matches numbers grouped by thousands with commas only,
+// such as "1,234" or "12,345,678", which are not decimal commas
+var commaThousandsPattern = regexp.MustCompile(`^[-+]?[1-9][0-9]{0,2}(,[0-9]{3})+$`)
+
+// parseSpreadsheetNumber parses "1234.5", "1 234,5", "1,234" or "1,234.5", up
+// to maxSpreadsheetNumber in absolute value. A comma followed by groups of
+// three digits separates thousands, any other single comma is decimal.
+func parseSpreadsheetNumber(s string) (float64, error) {
+	s = strings.NewReplacer(" ", "", "\u00a0", "", "\u202f", "", "'", "").Replace(s)
+	lastComma, lastDot := strings.LastIndex(s, ","), strings.LastIndex(s, ".")
+	switch {
+	case lastComma >= 0 && lastDot >= 0 && lastComma > lastDot:
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.Replace(s, ",", ".", 1)
+	case lastComma >= 0 && lastDot >= 0, commaThousandsPattern.MatchString(s):
+		s = strings.ReplaceAll(s, ",", "")
+	case lastComma >= 0:
+		s = strings.Replace(s, ",", ".", 1)
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(n) || math.IsInf(n, 0) || math.Abs(n) > maxSpreadsheetNumber {
+		return 0, errSpreadsheetNumberRange
+	}
+	return n, nil
+}
+
+// currencySymbols maps the price symbols found in exports to currency codes
+var currencySymbols = []struct {
+	symbol   string
+	currency string
+}{
+	{"US$", "USD"}, {"CA$", "CAD"}, {"A$", "AUD"}, {"NZ$", "NZD"},
+	{"$", "USD"}, {"€", "EUR"}, {"£", "GBP"}, {"¥", "JPY"}, {"₩", "KRW"}, {"₹", "INR"},
+	{"CHF", "CHF"}, {"kr", "SEK"},
+}
+
+// parseSpreadsheetPrice converts a price to minor units. The currency is
+// the currency cell, a symbol or code in the price, or the default currency.
+func parseSpreadsheetPrice(price, currencyCell, defaultCurrency string) (int, string, error) {
+	currency := ""
+	if currencyCell != "" {
+		c, err := currencies.Normalize(currencyCell)
+		if err != nil {
+			return 0, "", fmt.Errorf("invalid currency %q", currencyCell)
+		}
+		currency = c
+	}
+
+	code, amount := extractPriceCurrency(price)
+	if currency == "" {
+		currency = code
+	}
+	if currency == "" {
+		currency = defaultCurrency
+	}
+
+	amount = strings.TrimSpace(amount)
+	if amount == "" {
+		return 0, currency, nil
+	}
+	value, err := parseSpreadsheetNumber(amount)
+	if err != nil || value < 0 {
+		return 0, currency, fmt.Errorf("invalid price %q", price)
+	}
+
+	decimals := defaultMinorUnits
+	if currencies.IsValid(currency) {
+		decimals = currencies.MinorUnits(currency)
+	}
+	return int(math.Round(value * math.Pow10(decimals))), currency, nil
+}
+
+// extractPriceCurrency returns the currency code or symbol of a price, if
+// any, and the price without it
+func extractPriceCurrency(price string) (string, string) {
+	if code, rest, ok := splitCurrencyCode(price); ok {
+		return code, rest
+	}
+	for _, s := range currencySymbols {
+		if strings.Contains(price, s.symbol) {
+			return s.currency, strings.Replace(price, s.symbol, "", 1)
+		}
+	}
+	return "", price
+}
+
+// splitCurrencyCode extracts an ISO 4217 code at the start or end of a price ("EUR 12.50", "12,50 CHF")
+func splitCurrencyCode(price string) (string, string, bool) {
+	fields := strings.Fields(price)
+	if len(fields) != 2 {
+		return "", "", false
+	}
+	if code, err := currencies.Normalize(fields[0]); err == nil {
+		return code, fields[1], true
+	}
+	if code, err := currencies.Normalize(fields[1]); err == nil {
+		return code, fields[0], true
+	}
+	return "", "", false
+}
+
+// parseSpreadsheetBool reads the worn and consumable flags: yes/true/x/1 or
+// the field name itself, as in LighterPack's "Worn" and "Consumable" cells
+func parseSpreadsheetBool(value, field string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "true", "yes", "y", "x", "✓", "✔", field:
+		return true
+	default:
+		return false
+	}
+}
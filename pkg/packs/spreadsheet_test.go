@@ -0,0 +1,268 @@
+package packs
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Angak0k/pimpmypack/pkg/security"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func TestParseSpreadsheetLighterPackCSV(t *testing.T) {
+	data := []byte("Item Name,Category,desc,qty,weight,unit,url,price,worn,consumable\n" +
+		"Tent,Shelter,Ultralight tent,1,1.2,kg,https://example.com/tent,349.99,,\n" +
+		"Hat,Clothing,,1,2,oz,,25,Worn,\n" +
+		"Bars,Food,,4,60,g,,3,,Consumable\n")
+
+	parsed, err := parseSpreadsheet(data, spreadsheetOptions{Currency: "EUR"})
+	require.NoError(t, err)
+	require.Len(t, parsed.Items, 3)
+	assert.Empty(t, parsed.Errors)
+
+	assert.Equal(t, ExternalPackItem{
+		ItemName: "Tent", Category: "Shelter", Desc: "Ultralight tent", Qty: 1, Weight: 1200, Unit: "kg",
+		URL: "https://example.com/tent", Price: 34999, Currency: "EUR",
+	}, parsed.Items[0])
+	assert.Equal(t, 57, parsed.Items[1].Weight)
+	assert.True(t, parsed.Items[1].Worn)
+	assert.Equal(t, 4, parsed.Items[2].Qty)
+	assert.True(t, parsed.Items[2].Consumable)
+	assert.Equal(t, "Item Name", parsed.Columns[colItemName])
+}
+
+func TestParseSpreadsheetSemicolonWindows1252(t *testing.T) {
+	text := "Mon sac à dos\n" +
+		"Nom;Catégorie;Poids (g);Quantité;Prix\n" +
+		"Réchaud;Cuisine;85,5;1;39,90 €\n"
+	data, err := charmap.Windows1252.NewEncoder().Bytes([]byte(text))
+	require.NoError(t, err)
+
+	parsed, err := parseSpreadsheet(data, spreadsheetOptions{
+		Mapping: map[string]string{
+			colItemName: "nom", colCategory: "Catégorie", colWeight: "Poids (g)", colQty: "Quantité", colPrice: "Prix",
+		},
+		Currency: "USD",
+	})
+	require.NoError(t, err)
+	require.Len(t, parsed.Items, 1)
+
+	item := parsed.Items[0]
+	assert.Equal(t, "Réchaud", item.ItemName)
+	assert.Equal(t, "Cuisine", item.Category)
+	assert.Equal(t, 86, item.Weight)
+	assert.Equal(t, 3990, item.Price)
+	assert.Equal(t, "EUR", item.Currency)
+}
+
+func TestParseSpreadsheetUTF16Tabs(t *testing.T) {
+	text := "Gear\tWeight (oz)\tPrice\tCurrency\nQuilt\t20\t300\tjpy\n"
+	data, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().Bytes([]byte(text))
+	require.NoError(t, err)
+
+	parsed, err := parseSpreadsheet(data, spreadsheetOptions{Currency: "EUR"})
+	require.NoError(t, err)
+	require.Len(t, parsed.Items, 1)
+	assert.Equal(t, 567, parsed.Items[0].Weight)
+	assert.Equal(t, "oz", parsed.Items[0].Unit)
+	assert.Equal(t, 300, parsed.Items[0].Price)
+	assert.Equal(t, "JPY", parsed.Items[0].Currency)
+}
+
+func TestParseSpreadsheetRowErrors(t *testing.T) {
+	data := []byte("name,qty,weight,unit,price\n" +
+		"Stove,1,85,g,30\n" +
+		",1,10,g,\n" +
+		"\n" +
+		"Pot,two,120,g,\n" +
+		"Cup,1,heavy,g,\n" +
+		"Spoon,1,12,stone,\n" +
+		"Knife,1,40,g,12 XYZ\n" +
+		"Fork,1,NaN,g,\n" +
+		"Plate,Inf,10,g,\n" +
+		"Bowl,1,10,g,1e400\n")
+
+	parsed, err := parseSpreadsheet(data, spreadsheetOptions{Currency: "EUR"})
+	require.NoError(t, err)
+	require.Len(t, parsed.Items, 1)
+	assert.Equal(t, []SpreadsheetRowError{
+		{Row: 3, Error: "missing item name"},
+		{Row: 5, Error: `invalid quantity "two"`},
+		{Row: 6, Error: `invalid weight "heavy"`},
+		{Row: 7, Error: `unknown weight unit "stone"`},
+		{Row: 8, Error: `invalid price "12 XYZ"`},
+		{Row: 9, Error: `invalid weight "NaN"`},
+		{Row: 10, Error: `invalid quantity "Inf"`},
+		{Row: 11, Error: `invalid price "1e400"`},
+	}, parsed.Errors)
+}
+
+func TestParseSpreadsheetFileErrors(t *testing.T) {
+	_, err := parseSpreadsheet([]byte("foo,bar\n1,2\n"), spreadsheetOptions{})
+	require.ErrorIs(t, err, ErrNoSpreadsheetHeader)
+
+	_, err = parseSpreadsheet([]byte("name\nTent\n"), spreadsheetOptions{Mapping: map[string]string{"colour": "Color"}})
+	require.ErrorIs(t, err, ErrInvalidColumnMapping)
+
+	_, err = parseSpreadsheet([]byte("name,weight\nTent,1\n"), spreadsheetOptions{
+		Mapping: map[string]string{colPrice: "Cost"},
+	})
+	require.ErrorIs(t, err, ErrInvalidColumnMapping)
+
+	parsed, err := parseSpreadsheet([]byte("name,qty\n,1\n"), spreadsheetOptions{})
+	require.ErrorIs(t, err, ErrNoValidRows)
+	assert.Len(t, parsed.Errors, 1)
+
+	_, err = parseSpreadsheet([]byte("PK\x03\x04garbage"), spreadsheetOptions{})
+	require.ErrorIs(t, err, ErrUnreadableSpreadsheet)
+}
+
+// buildXLSX writes a minimal workbook with shared and inline strings
+func buildXLSX(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	files := map[string]string{
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<si><t>Item</t></si><si><t>Weight</t></si><si><r><t>Sleeping </t></r><r><t>Pad</t></r></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<sheetData>` +
+			`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c>` +
+			`<c r="D1" t="inlineStr"><is><t>Worn</t></is></c></row>` +
+			`<row r="3"><c r="A3" t="s"><v>2</v></c><c r="B3"><v>410</v></c><c r="D3" t="b"><v>0</v></c></row>` +
+			`<row r="4"><c r="A4" t="inlineStr"><is><t>Jacket</t></is></c><c r="B4"><v>198.6</v></c>` +
+			`<c r="D4" t="b"><v>1</v></c></row>` +
+			`<row r="5"><c r="B5"><v>12</v></c></row>` +
+			`</sheetData></worksheet>`,
+	}
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestParseSpreadsheetXLSX(t *testing.T) {
+	parsed, err := parseSpreadsheet(buildXLSX(t), spreadsheetOptions{Currency: "EUR"})
+	require.NoError(t, err)
+	require.Len(t, parsed.Items, 2)
+
+	assert.Equal(t, "Sleeping Pad", parsed.Items[0].ItemName)
+	assert.Equal(t, 410, parsed.Items[0].Weight)
+	assert.False(t, parsed.Items[0].Worn)
+	assert.Equal(t, "Jacket", parsed.Items[1].ItemName)
+	assert.Equal(t, 199, parsed.Items[1].Weight)
+	assert.True(t, parsed.Items[1].Worn)
+	assert.Equal(t, []SpreadsheetRowError{{Row: 5, Error: "missing item name"}}, parsed.Errors)
+}
+
+func TestParseSpreadsheetNumber(t *testing.T) {
+	tests := map[string]float64{
+		"1234.5":    1234.5,
+		"1234,5":    1234.5,
+		"1,234.5":   1234.5,
+		"1.234,5":   1234.5,
+		"1 234,5":   1234.5,
+		"1 234":     1234,
+		"1,234":     1234,
+		"1,000,000": 1000000,
+		"1,5":       1.5,
+		"0,500":     0.5,
+		"-12,5":     -12.5,
+	}
+	for input, want := range tests {
+		got, err := parseSpreadsheetNumber(input)
+		require.NoError(t, err, input)
+		assert.InDelta(t, want, got, 1e-9, input)
+	}
+	for _, input := range []string{"NaN", "-Inf", "+Infinity", "1e400", "2000000", "1,2,3"} {
+		_, err := parseSpreadsheetNumber(input)
+		require.Error(t, err, input)
+	}
+}
+
+func postSpreadsheet(t *testing.T, router *gin.Engine, token, filename string, data []byte,
+	fields map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	fileWriter, err := writer.CreateFormFile("file", filename)
+	require.NoError(t, err)
+	_, err = fileWriter.Write(data)
+	require.NoError(t, err)
+	for k, v := range fields {
+		require.NoError(t, writer.WriteField(k, v))
+	}
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/importspreadsheet", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestImportSpreadsheet(t *testing.T) {
+	token, err := security.GenerateToken(users[0].ID)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/importspreadsheet", ImportSpreadsheet)
+
+	t.Run("imports valid rows and reports the others", func(t *testing.T) {
+		csvData := []byte("Gear;Group;Grams;Qty\nBivy Sack;Shelter;230;1\n;Shelter;10;1\n")
+		w := postSpreadsheet(t, router, token, "Summer Kit.csv", csvData, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response ImportSpreadsheetResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.NotZero(t, response.PackID)
+		assert.Equal(t, 1, response.Imported)
+		assert.Equal(t, []SpreadsheetRowError{{Row: 3, Error: "missing item name"}}, response.Errors)
+
+		contents, err := returnPackContentsByPackID(t.Context(), response.PackID)
+		require.NoError(t, err)
+		require.Len(t, *contents, 1)
+		assert.Equal(t, "Bivy Sack", (*contents)[0].ItemName)
+		assert.Equal(t, 230, (*contents)[0].Weight)
+
+		pack, err := findPackByID(t.Context(), response.PackID)
+		require.NoError(t, err)
+		assert.Equal(t, "Summer Kit", pack.PackName)
+	})
+
+	t.Run("rejects a file without valid rows", func(t *testing.T) {
+		w := postSpreadsheet(t, router, token, "empty.csv", []byte("name,qty\n,1\n"), nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response ImportSpreadsheetErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, ErrNoValidRows.Error(), response.Error)
+		assert.Len(t, response.Errors, 1)
+	})
+
+	t.Run("rejects invalid options", func(t *testing.T) {
+		data := []byte("name\nTent\n")
+		for _, fields := range []map[string]string{
+			{"mapping": "not json"},
+			{"weight_unit": "stone"},
+			{"currency": "ZZZ"},
+		} {
+			w := postSpreadsheet(t, router, token, "kit.csv", data, fields)
+			assert.Equal(t, http.StatusBadRequest, w.Code, fields)
+		}
+	})
+}
@@ -0,0 +1,236 @@
+package packs
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Angak0k/pimpmypack/pkg/currencies"
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/lib/pq"
+)
+
+const (
+	// defaultSuggestionLimit and maxSuggestionLimit bound the suggestions per item
+	defaultSuggestionLimit = 3
+	maxSuggestionLimit     = 10
+	// maxCommunityCandidates bounds the shared-pack items read for a pack
+	maxCommunityCandidates = 2000
+)
+
+// ErrInvalidSuggestionLimit is returned for a limit out of 1..maxSuggestionLimit
+var ErrInvalidSuggestionLimit = errors.New("limit must be between 1 and 10")
+
+// suggestionCandidate is an item that may replace pack items of its category
+type suggestionCandidate struct {
+	Suggestion
+	category string
+}
+
+// buildPackSuggestions suggests, for each pack item, up to limit lighter
+// candidates of the same category ignoring case: the unused items of the user
+// first, then the community items, each group lightest first. Candidates with
+// the name of the pack item are skipped.
+func buildPackSuggestions(
+	packID uint, contents PackContentWithItems, own, community []suggestionCandidate,
+	limit int, cv *currencies.Converter, preferred string,
+) PackSuggestions {
+	result := PackSuggestions{PackID: packID, Currency: preferred, Items: []ItemSuggestions{}}
+	for _, group := range [][]suggestionCandidate{own, community} {
+		sort.SliceStable(group, func(i, j int) bool { return group[i].Weight < group[j].Weight })
+	}
+
+	for _, item := range contents {
+		suggestions := ItemSuggestions{
+			PackContentID: item.PackContentID,
+			InventoryID:   item.InventoryID,
+			ItemName:      item.ItemName,
+			Category:      item.Category,
+			Weight:        item.Weight,
+			Quantity:      item.Quantity,
+			Suggestions:   []Suggestion{},
+		}
+		for _, group := range [][]suggestionCandidate{own, community} {
+			for _, candidate := range group {
+				if len(suggestions.Suggestions) == limit {
+					break
+				}
+				if candidate.Weight >= item.Weight || !strings.EqualFold(candidate.category, item.Category) ||
+					strings.EqualFold(candidate.ItemName, item.ItemName) {
+					continue
+				}
+				s := candidate.Suggestion
+				s.WeightSaved = item.Weight - candidate.Weight
+				s.PriceDifference = priceDifference(item, candidate.Suggestion, cv, preferred)
+				suggestions.Suggestions = append(suggestions.Suggestions, s)
+			}
+		}
+
+		best := 0
+		for _, s := range suggestions.Suggestions {
+			best = max(best, s.WeightSaved)
+		}
+		result.WeightSaved += best * item.Quantity
+		result.Items = append(result.Items, suggestions)
+	}
+	return result
+}
+
+// priceDifference returns the price of the suggestion minus the price of the
+// item in the preferred currency, or nil when a rate is missing
+func priceDifference(item PackContentWithItem, s Suggestion, cv *currencies.Converter, preferred string) *int {
+	itemPrice, ok := cv.Convert(item.Price, item.Currency, preferred)
+	if !ok {
+		return nil
+	}
+	price, ok := cv.Convert(s.Price, s.Currency, preferred)
+	if !ok {
+		return nil
+	}
+	difference := price - itemPrice
+	return &difference
+}
+
+// groupCommunityCandidates merges the shared-pack items with the same name
+// and category, ignoring case, into the candidate of median weight. PackCount
+// sums the shared packs using them.
+func groupCommunityCandidates(items []suggestionCandidate) []suggestionCandidate {
+	groups := map[string][]suggestionCandidate{}
+	var keys []string
+	for _, item := range items {
+		key := strings.ToLower(strings.Join(strings.Fields(item.ItemName), " ")) + "\x00" +
+			strings.ToLower(item.category)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], item)
+	}
+
+	grouped := make([]suggestionCandidate, 0, len(keys))
+	for _, key := range keys {
+		group := groups[key]
+		sort.SliceStable(group, func(i, j int) bool { return group[i].Weight < group[j].Weight })
+		candidate := group[(len(group)-1)/2]
+		candidate.PackCount = 0
+		for _, item := range group {
+			candidate.PackCount += item.PackCount
+		}
+		grouped = append(grouped, candidate)
+	}
+	return grouped
+}
+
+// packCategories returns the lower-cased categories of the contents and the
+// heaviest item weight, which bound the candidates worth reading
+func packCategories(contents PackContentWithItems) ([]string, int) {
+	var categories []string
+	maxWeight := 0
+	for _, item := range contents {
+		category := strings.ToLower(item.Category)
+		if !slices.Contains(categories, category) {
+			categories = append(categories, category)
+		}
+		maxWeight = max(maxWeight, item.Weight)
+	}
+	return categories, maxWeight
+}
+
+// returnUnusedItemCandidates returns the owned items of the user in no pack and
+// not retired, in the given categories and lighter than maxWeight
+func returnUnusedItemCandidates(
+	ctx context.Context, userID uint, categories []string, maxWeight int,
+) ([]suggestionCandidate, error) {
+	rows, err := database.DB().QueryContext(ctx,
+		`SELECT i.id, i.item_name, i.category, i.weight, i.url, i.price, i.currency
+		FROM inventory i
+		WHERE i.user_id = $1 AND NOT i.retired AND NOT i.wishlist AND i.weight > 0 AND i.weight < $3
+			AND LOWER(i.category) = ANY($2)
+			AND NOT EXISTS (SELECT 1 FROM pack_content pc WHERE pc.item_id = i.id)
+		ORDER BY i.weight, i.id;`,
+		userID, pq.Array(categories), maxWeight)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []suggestionCandidate
+	for rows.Next() {
+		var c suggestionCandidate
+		var id uint
+		if err := rows.Scan(&id, &c.ItemName, &c.category, &c.Weight, &c.URL, &c.Price, &c.Currency); err != nil {
+			return nil, err
+		}
+		c.Source = SuggestionSourceInventory
+		c.InventoryID = &id
+		candidates = append(candidates, c)
+	}
+	return candidates, rows.Err()
+}
+
+// returnCommunityCandidates returns the owned items of other users in shared
+// packs, in the given categories and lighter than maxWeight, grouped by name
+func returnCommunityCandidates(
+	ctx context.Context, userID uint, categories []string, maxWeight int,
+) ([]suggestionCandidate, error) {
+	rows, err := database.DB().QueryContext(ctx,
+		`SELECT i.item_name, i.category, i.weight, i.url, i.price, i.currency, COUNT(DISTINCT p.id)
+		FROM inventory i
+		JOIN pack_content pc ON pc.item_id = i.id
+		JOIN pack p ON p.id = pc.pack_id
+		WHERE p.sharing_code IS NOT NULL AND i.user_id <> $1 AND NOT i.wishlist AND i.weight > 0 AND i.weight < $3
+			AND LOWER(i.category) = ANY($2)
+		GROUP BY i.id
+		ORDER BY i.weight, i.id
+		LIMIT $4;`,
+		userID, pq.Array(categories), maxWeight, maxCommunityCandidates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []suggestionCandidate
+	for rows.Next() {
+		var c suggestionCandidate
+		err := rows.Scan(&c.ItemName, &c.category, &c.Weight, &c.URL, &c.Price, &c.Currency, &c.PackCount)
+		if err != nil {
+			return nil, err
+		}
+		c.Source = SuggestionSourceCommunity
+		candidates = append(candidates, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return groupCommunityCandidates(candidates), nil
+}
+
+// suggestLighterItems builds the lighter-alternative suggestions for the
+// contents of a pack of the user
+func suggestLighterItems(ctx context.Context, pack *Pack, limit int) (*PackSuggestions, error) {
+	contents, err := returnPackContentsByPackID(ctx, pack.ID)
+	if err != nil {
+		return nil, err
+	}
+	categories, maxWeight := packCategories(*contents)
+
+	var own, community []suggestionCandidate
+	if len(categories) > 0 {
+		own, err = returnUnusedItemCandidates(ctx, pack.UserID, categories, maxWeight)
+		if err != nil {
+			return nil, err
+		}
+		community, err = returnCommunityCandidates(ctx, pack.UserID, categories, maxWeight)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	preferred, cv, err := currencies.ConverterFor(ctx, pack.UserID)
+	if err != nil {
+		return nil, err
+	}
+	suggestions := buildPackSuggestions(pack.ID, *contents, own, community, limit, cv, preferred)
+	return &suggestions, nil
+}
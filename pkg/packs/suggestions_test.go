@@ -0,0 +1,113 @@
+package packs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Angak0k/pimpmypack/pkg/currencies"
+	"github.com/Angak0k/pimpmypack/pkg/security"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildPackSuggestions(t *testing.T) {
+	id := uint(7)
+	contents := PackContentWithItems{
+		{PackContentID: 1, ItemName: "Tent", Category: "Shelter", Weight: 1200, Price: 150, Currency: "EUR", Quantity: 1},
+		{PackContentID: 2, ItemName: "Stake", Category: "Stakes", Weight: 20, Price: 2, Currency: "EUR", Quantity: 6},
+		{PackContentID: 3, ItemName: "Pot", Category: "Kitchen", Weight: 150, Price: 30, Currency: "EUR", Quantity: 1},
+	}
+	own := []suggestionCandidate{
+		{Suggestion{Source: SuggestionSourceInventory, InventoryID: &id, ItemName: "Tarp", Weight: 600,
+			Price: 110, Currency: "USD"}, "shelter"},
+		{Suggestion{Source: SuggestionSourceInventory, ItemName: "Ti stake", Weight: 10, Price: 3,
+			Currency: "EUR"}, "STAKES"},
+	}
+	community := []suggestionCandidate{
+		{Suggestion{Source: SuggestionSourceCommunity, ItemName: "Duplex", Weight: 540, Price: 600,
+			Currency: "XXX", PackCount: 3}, "Shelter"},
+		{Suggestion{Source: SuggestionSourceCommunity, ItemName: "tent", Weight: 900, Price: 90,
+			Currency: "EUR"}, "Shelter"},
+		{Suggestion{Source: SuggestionSourceCommunity, ItemName: "Toaks", Weight: 100, Price: 35,
+			Currency: "EUR"}, "Kitchen"},
+	}
+	cv := currencies.NewConverter(map[string]float64{"EUR": 1, "USD": 1.1})
+
+	result := buildPackSuggestions(5, contents, own, community, 2, cv, "EUR")
+	require.Len(t, result.Items, 3)
+	assert.Equal(t, "EUR", result.Currency)
+
+	tent := result.Items[0].Suggestions
+	require.Len(t, tent, 2)
+	assert.Equal(t, "Tarp", tent[0].ItemName)
+	assert.Equal(t, 600, tent[0].WeightSaved)
+	require.NotNil(t, tent[0].PriceDifference)
+	assert.Equal(t, -50, *tent[0].PriceDifference)
+	assert.Equal(t, "Duplex", tent[1].ItemName)
+	assert.Equal(t, 660, tent[1].WeightSaved)
+	assert.Nil(t, tent[1].PriceDifference)
+
+	stakes := result.Items[1].Suggestions
+	require.Len(t, stakes, 1)
+	assert.Equal(t, "Ti stake", stakes[0].ItemName)
+	assert.Equal(t, 1, *stakes[0].PriceDifference)
+
+	pot := result.Items[2].Suggestions
+	require.Len(t, pot, 1)
+	assert.Equal(t, 50, pot[0].WeightSaved)
+
+	assert.Equal(t, 660+10*6+50, result.WeightSaved)
+}
+
+func TestGroupCommunityCandidates(t *testing.T) {
+	grouped := groupCommunityCandidates([]suggestionCandidate{
+		{Suggestion{ItemName: "Toaks  Pot", Weight: 110, PackCount: 1}, "Kitchen"},
+		{Suggestion{ItemName: "toaks pot", Weight: 100, PackCount: 2}, "kitchen"},
+		{Suggestion{ItemName: "Toaks Pot", Weight: 130, PackCount: 1}, "Kitchen"},
+		{Suggestion{ItemName: "Toaks Pot", Weight: 90, PackCount: 1}, "Cooking"},
+	})
+	require.Len(t, grouped, 2)
+	assert.Equal(t, 110, grouped[0].Weight)
+	assert.Equal(t, 4, grouped[0].PackCount)
+	assert.Equal(t, 90, grouped[1].Weight)
+	assert.Equal(t, 1, grouped[1].PackCount)
+}
+
+func TestGetMyPackSuggestions(t *testing.T) {
+	token, err := security.GenerateToken(users[0].ID)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/mypack/:id/suggestions", GetMyPackSuggestions)
+
+	packID := FindPackIDByPackName(packs, "First Pack")
+	path := fmt.Sprintf("/mypack/%d/suggestions", packID)
+
+	w := serveTripRequest(t, router, token, http.MethodGet, path+"?limit=11", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serveTripRequest(t, router, token, http.MethodGet, path, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result PackSuggestions
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	contents, err := returnPackContentsByPackID(t.Context(), packID)
+	require.NoError(t, err)
+	require.Len(t, result.Items, len(*contents))
+	for _, item := range result.Items {
+		assert.LessOrEqual(t, len(item.Suggestions), defaultSuggestionLimit)
+		for _, s := range item.Suggestions {
+			assert.Less(t, s.Weight, item.Weight)
+			assert.False(t, strings.EqualFold(s.ItemName, item.ItemName))
+		}
+	}
+
+	otherToken, err := security.GenerateToken(users[1].ID)
+	require.NoError(t, err)
+	w = serveTripRequest(t, router, otherToken, http.MethodGet, path, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -0,0 +1,264 @@
+package packs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/Angak0k/pimpmypack/pkg/inventories"
+)
+
+var (
+	// ErrTemplateNotFound is returned when a pack does not exist or is not a template
+	ErrTemplateNotFound = errors.New("template not found")
+	// ErrEmptyTemplate is returned when a pack is created from a template without items
+	ErrEmptyTemplate = errors.New("template has no items")
+)
+
+// templateItemKey matches template items with inventory items by name and
+// category, ignoring case and spacing
+func templateItemKey(name, category string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " ")) + "\x00" +
+		strings.ToLower(strings.TrimSpace(category))
+}
+
+// buildTemplatePlan maps the template contents onto the inventory of the
+// user: an item of the same name and category is reused, preferring owned
+// items in service, then wishlist items, then retired ones. The other items
+// are created as wishlist placeholders. Template items matching the same
+// item, or the same placeholder, are merged.
+func buildTemplatePlan(contents PackContentWithItems, inventory inventories.Inventories) []ImportPlanItem {
+	rank := func(item inventories.Inventory) int {
+		switch {
+		case item.Retired:
+			return 2
+		case item.Wishlist:
+			return 1
+		default:
+			return 0
+		}
+	}
+	matches := map[string]inventories.Inventory{}
+	for _, item := range inventory {
+		key := templateItemKey(item.ItemName, item.Category)
+		if best, ok := matches[key]; !ok || rank(item) < rank(best) {
+			matches[key] = item
+		}
+	}
+
+	plan := make([]ImportPlanItem, 0, len(contents))
+	for _, item := range contents {
+		planned := ImportPlanItem{
+			ExternalPackItem: ExternalPackItem{
+				ItemName:   item.ItemName,
+				Category:   item.Category,
+				Desc:       item.ItemDescription,
+				Qty:        item.Quantity,
+				Weight:     item.Weight,
+				URL:        item.ItemURL,
+				Price:      item.Price,
+				Currency:   item.Currency,
+				Worn:       item.Worn,
+				Consumable: item.Consumable,
+				Notes:      item.Notes,
+				Optional:   item.Optional,
+			},
+			Action:      ImportActionCreate,
+			placeholder: true,
+		}
+		if match, ok := matches[templateItemKey(item.ItemName, item.Category)]; ok {
+			planned.Action = ImportActionReuse
+			planned.InventoryID = match.ID
+			planned.placeholder = false
+		}
+		plan = append(plan, planned)
+	}
+	return mergeImportPlan(plan, func(item ExternalPackItem) string {
+		return templateItemKey(item.ItemName, item.Category)
+	})
+}
+
+// createPackFromTemplate creates a pack of the user with the contents,
+// season, adventure and trail of a template, within a transaction
+func createPackFromTemplate(
+	ctx context.Context, template *Pack, userID uint, input FromTemplateRequest,
+) (*FromTemplateResponse, error) {
+	contents, err := returnPackContentsByPackID(ctx, template.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(*contents) == 0 {
+		return nil, ErrEmptyTemplate
+	}
+	inventory, err := inventories.ReturnInventoriesByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	packName, packDescription := template.PackName, template.PackDescription
+	if name := strings.TrimSpace(input.PackName); name != "" {
+		packName, packDescription = name, input.PackDescription
+	}
+	plan := buildTemplatePlan(*contents, *inventory)
+	if err := checkImportPlan(ctx, plan, userID); err != nil {
+		return nil, err
+	}
+
+	tx, err := database.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	packID, err := writeImportPlan(ctx, tx, plan, userID, packName, packDescription)
+	if err != nil {
+		return nil, err
+	}
+	if err = copyPackDescriptors(ctx, tx, template.ID, packID); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	reused := map[uint]bool{}
+	for _, item := range plan {
+		if item.Action == ImportActionReuse {
+			reused[item.InventoryID] = true
+		}
+	}
+	created, err := returnPackContentsByPackID(ctx, packID)
+	if err != nil {
+		return nil, err
+	}
+	result := &FromTemplateResponse{PackID: packID, Items: make([]TemplateItemMatch, 0, len(*created))}
+	for _, item := range *created {
+		match := TemplateItemMatch{
+			ItemName:    item.ItemName,
+			Category:    item.Category,
+			InventoryID: item.InventoryID,
+			Placeholder: !reused[item.InventoryID],
+		}
+		if match.Placeholder {
+			result.Placeholders++
+		} else {
+			result.Reused++
+		}
+		result.Items = append(result.Items, match)
+	}
+	return result, nil
+}
+
+// findTemplateByID returns a pack when it is a template
+func findTemplateByID(ctx context.Context, id uint) (*Pack, error) {
+	pack, err := findPackByID(ctx, id)
+	if errors.Is(err, ErrPackNotFound) || (err == nil && pack.Template == nil) {
+		return nil, ErrTemplateNotFound
+	}
+	return pack, err
+}
+
+// copyPackDescriptors copies the season, adventure and trail of a pack to another
+func copyPackDescriptors(ctx context.Context, tx *sql.Tx, fromID, toID uint) error {
+	_, err := tx.ExecContext(ctx,
+		`UPDATE pack p SET season = t.season, trail = t.trail, trail_id = t.trail_id, adventure = t.adventure
+		FROM pack t
+		WHERE p.id = $1 AND t.id = $2;`,
+		toID, fromID)
+	if err != nil {
+		return fmt.Errorf("failed to copy pack descriptors: %w", err)
+	}
+	return nil
+}
+
+// returnTemplates lists the templates, official ones first, matching the
+// season, adventure and trail filters that are not empty, ignoring case
+func returnTemplates(ctx context.Context, season, adventure, trail string) ([]PackTemplate, error) {
+	rows, err := database.DB().QueryContext(ctx,
+		`SELECT p.id, p.pack_name, p.pack_description, p.template,
+			p.season, COALESCE(t.name, p.trail) as trail, p.adventure,
+			COALESCE(SUM(pc.quantity) FILTER (WHERE NOT pc.optional AND NOT i.wishlist), 0) as items_count,
+			COALESCE(SUM(i.weight * pc.quantity) FILTER (WHERE NOT pc.optional AND NOT i.wishlist), 0) as total_weight
+		FROM pack p
+		LEFT JOIN pack_content pc ON p.id = pc.pack_id
+		LEFT JOIN inventory i ON pc.item_id = i.id
+		LEFT JOIN trail t ON p.trail_id = t.id
+		WHERE p.template IS NOT NULL
+			AND ($1 = '' OR LOWER(p.season) = LOWER($1))
+			AND ($2 = '' OR LOWER(p.adventure) = LOWER($2))
+			AND ($3 = '' OR LOWER(COALESCE(t.name, p.trail)) = LOWER($3))
+		GROUP BY p.id, t.name
+		ORDER BY p.template = 'official' DESC, LOWER(p.pack_name), p.id;`,
+		season, adventure, trail)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []PackTemplate{}
+	for rows.Next() {
+		var template PackTemplate
+		err := rows.Scan(&template.ID, &template.PackName, &template.PackDescription, &template.Template,
+			&template.Season, &template.Trail, &template.Adventure, &template.PackItemsCount, &template.PackWeight)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// templatePackByID lists a pack of the user as a template; official
+// templates stay official
+func templatePackByID(ctx context.Context, packID uint, userID uint) error {
+	return setPackTemplate(ctx, packID, userID,
+		`UPDATE pack SET template = COALESCE(template, 'user') WHERE id = $1;`)
+}
+
+// untemplatePackByID removes a pack of the user from the templates
+func untemplatePackByID(ctx context.Context, packID uint, userID uint) error {
+	return setPackTemplate(ctx, packID, userID, `UPDATE pack SET template = NULL WHERE id = $1;`)
+}
+
+func setPackTemplate(ctx context.Context, packID, userID uint, query string) error {
+	owns, err := checkPackOwnership(ctx, packID, userID)
+	if err != nil {
+		return err
+	}
+	if !owns {
+		return ErrPackNotOwned
+	}
+	if _, err := database.DB().ExecContext(ctx, query, packID); err != nil {
+		return fmt.Errorf("failed to update pack template: %w", err)
+	}
+	return nil
+}
+
+// setOfficialTemplate marks any pack as an official template, or removes
+// it from the templates
+func setOfficialTemplate(ctx context.Context, packID uint, official bool) error {
+	result, err := database.DB().ExecContext(ctx,
+		`UPDATE pack SET template = CASE WHEN $2 THEN 'official' END WHERE id = $1;`,
+		packID, official)
+	if err != nil {
+		return fmt.Errorf("failed to update pack template: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrPackNotFound
+	}
+	return nil
+}
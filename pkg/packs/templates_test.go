@@ -0,0 +1,123 @@
+package packs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Angak0k/pimpmypack/pkg/inventories"
+	"github.com/Angak0k/pimpmypack/pkg/security"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTemplatePlan(t *testing.T) {
+	contents := PackContentWithItems{
+		{ItemName: "Tent", Category: "Shelter", Weight: 1200, Quantity: 1},
+		{ItemName: "Rain  jacket", Category: "Clothing", Weight: 300, Quantity: 1, Worn: true},
+		{ItemName: "Stove", Category: "Kitchen", Weight: 90, Quantity: 1},
+		{ItemName: "Gas", Category: "Kitchen", Weight: 200, Quantity: 2, Consumable: true},
+		{ItemName: "tent", Category: "shelter", Weight: 1200, Quantity: 1, Notes: "spare"},
+		{ItemName: "Stake", Category: "Shelter", Weight: 10, Quantity: 4},
+		{ItemName: "stake", Category: "Shelter", Weight: 10, Quantity: 2, Optional: true},
+	}
+	inventory := inventories.Inventories{
+		{ID: 1, ItemName: "tent", Category: "shelter", Retired: true},
+		{ID: 2, ItemName: "Tent", Category: "Shelter", Wishlist: true},
+		{ID: 3, ItemName: "rain jacket", Category: "CLOTHING"},
+		{ID: 4, ItemName: "Stove", Category: "Cooking"},
+	}
+
+	plan := buildTemplatePlan(contents, inventory)
+	require.Len(t, plan, 5, "items matching the same item or placeholder are merged")
+	assert.Equal(t, ImportActionReuse, plan[0].Action)
+	assert.Equal(t, uint(2), plan[0].InventoryID, "wishlist items come before retired ones")
+	assert.Equal(t, 2, plan[0].Qty)
+	assert.Equal(t, "spare", plan[0].Notes)
+	assert.Equal(t, ImportActionCreate, plan[4].Action)
+	assert.Equal(t, 6, plan[4].Qty)
+	assert.False(t, plan[4].Optional)
+	assert.Equal(t, uint(3), plan[1].InventoryID)
+	assert.Equal(t, ImportActionCreate, plan[2].Action, "the category must match")
+	assert.True(t, plan[2].placeholder)
+	assert.Equal(t, ImportActionCreate, plan[3].Action)
+	assert.Equal(t, 2, plan[3].Qty)
+	assert.True(t, plan[3].Consumable)
+	assert.False(t, plan[0].placeholder)
+}
+
+func TestMyPackFromTemplate(t *testing.T) {
+	token, err := security.GenerateToken(users[0].ID)
+	require.NoError(t, err)
+	otherToken, err := security.GenerateToken(users[1].ID)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/templates", GetTemplates)
+	router.POST("/mypack/:id/template", TemplateMyPack)
+	router.DELETE("/mypack/:id/template", UntemplateMyPack)
+	router.POST("/mypack/from-template/:id", PostMyPackFromTemplate)
+
+	// First Pack holds the backpack and the tent of the first user
+	packID := FindPackIDByPackName(packs, "First Pack")
+	templatePath := fmt.Sprintf("/mypack/%d/template", packID)
+	fromPath := fmt.Sprintf("/mypack/from-template/%d", packID)
+
+	w := serveTripRequest(t, router, otherToken, http.MethodPost, fromPath, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = serveTripRequest(t, router, otherToken, http.MethodPost, templatePath, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = serveTripRequest(t, router, token, http.MethodPost, templatePath, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	defer func() {
+		require.NoError(t, untemplatePackByID(t.Context(), packID, users[0].ID))
+	}()
+
+	w = serveTripRequest(t, router, otherToken, http.MethodGet, "/templates?season=winter&trail=gr20", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var templates []PackTemplate
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &templates))
+	require.Len(t, templates, 1)
+	assert.Equal(t, packID, templates[0].ID)
+	assert.Equal(t, TemplateUser, templates[0].Template)
+	assert.Equal(t, 5, templates[0].PackItemsCount)
+
+	// The second user owns none of the items: all of them become placeholders
+	w = serveTripRequest(t, router, otherToken, http.MethodPost, fromPath,
+		FromTemplateRequest{PackName: "My first pack"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created FromTemplateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	defer func() { require.NoError(t, deletePackByID(t.Context(), created.PackID)) }()
+	assert.Equal(t, 0, created.Reused)
+	assert.Equal(t, 2, created.Placeholders)
+	pack, err := findPackByID(t.Context(), created.PackID)
+	require.NoError(t, err)
+	assert.Equal(t, "My first pack", pack.PackName)
+	assert.Equal(t, users[1].ID, pack.UserID)
+	require.NotNil(t, pack.Season)
+	assert.Equal(t, seasonWinter, *pack.Season)
+	assert.Nil(t, pack.Template)
+	contents, err := returnPackContentsByPackID(t.Context(), created.PackID)
+	require.NoError(t, err)
+	for _, item := range *contents {
+		assert.True(t, item.Wishlist, item.ItemName)
+	}
+
+	// The owner of the template owns all of them
+	w = serveTripRequest(t, router, token, http.MethodPost, fromPath, nil)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var reused FromTemplateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &reused))
+	defer func() { require.NoError(t, deletePackByID(t.Context(), reused.PackID)) }()
+	assert.Equal(t, 2, reused.Reused)
+	assert.Equal(t, 0, reused.Placeholders)
+
+	w = serveTripRequest(t, router, token, http.MethodDelete, templatePath, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = serveTripRequest(t, router, otherToken, http.MethodPost, fromPath, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
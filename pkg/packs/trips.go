@@ -0,0 +1,150 @@
+package packs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Angak0k/pimpmypack/pkg/database"
+)
+
+// tripDateLayout is the format of trip dates
+const tripDateLayout = "2006-01-02"
+
+var (
+	// ErrInvalidTripDates is returned for unparsable trip dates or an end before the start
+	ErrInvalidTripDates = errors.New("trip dates must be formatted as YYYY-MM-DD, end_date not before start_date")
+	// ErrInvalidTripValue is returned for negative nights or distance
+	ErrInvalidTripValue = errors.New("nights and distance_km must not be negative")
+	// ErrTripNotFound is returned when a trip does not exist on the pack
+	ErrTripNotFound = errors.New("trip not found")
+)
+
+// newTrip validates a trip request and fills its defaults, except the
+// distance that defaults to the pack trail when the trip is inserted
+func newTrip(packID uint, input TripRequest) (Trip, error) {
+	start, err := time.Parse(tripDateLayout, input.StartDate)
+	if err != nil {
+		return Trip{}, ErrInvalidTripDates
+	}
+	end := start
+	if input.EndDate != "" {
+		end, err = time.Parse(tripDateLayout, input.EndDate)
+		if err != nil || end.Before(start) {
+			return Trip{}, ErrInvalidTripDates
+		}
+	}
+
+	nights := int(end.Sub(start).Hours() / 24)
+	if input.Nights != nil {
+		nights = *input.Nights
+	}
+	if nights < 0 || (input.DistanceKm != nil && *input.DistanceKm < 0) {
+		return Trip{}, ErrInvalidTripValue
+	}
+
+	return Trip{
+		PackID:     packID,
+		StartDate:  start.Format(tripDateLayout),
+		EndDate:    end.Format(tripDateLayout),
+		Nights:     nights,
+		DistanceKm: input.DistanceKm,
+		Notes:      input.Notes,
+	}, nil
+}
+
+// insertTrip logs a trip on a pack of the user and records the current
+// pack contents, but the wishlist and optional items, as used on it
+func insertTrip(ctx context.Context, userID uint, trip *Trip) error {
+	tx, err := database.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO pack_trip (user_id, pack_id, start_date, end_date, nights, distance_km, notes)
+		VALUES ($1, $2, $3::date, $4::date, $5,
+			COALESCE($6, (SELECT t.distance_km FROM pack p JOIN trail t ON t.id = p.trail_id WHERE p.id = $2)),
+			$7)
+		RETURNING id, distance_km, created_at;`,
+		userID, trip.PackID, trip.StartDate, trip.EndDate, trip.Nights, trip.DistanceKm, trip.Notes,
+	).Scan(&trip.ID, &trip.DistanceKm, &trip.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert trip: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO pack_trip_item (trip_id, item_id, quantity)
+		SELECT $1, pc.item_id, COALESCE(pc.quantity, 1)
+		FROM pack_content pc
+		JOIN inventory i ON i.id = pc.item_id
+		WHERE pc.pack_id = $2 AND NOT i.wishlist AND NOT pc.optional;`,
+		trip.ID, trip.PackID)
+	if err != nil {
+		return fmt.Errorf("failed to record trip items: %w", err)
+	}
+	itemsCount, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	trip.ItemsCount = int(itemsCount)
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// returnTripsByPackID retrieves the trips logged on a pack, most recent first
+func returnTripsByPackID(ctx context.Context, packID uint) ([]Trip, error) {
+	rows, err := database.DB().QueryContext(ctx,
+		`SELECT t.id, t.pack_id, TO_CHAR(t.start_date, 'YYYY-MM-DD'), TO_CHAR(t.end_date, 'YYYY-MM-DD'),
+			t.nights, t.distance_km, t.notes,
+			(SELECT COUNT(*) FROM pack_trip_item ti WHERE ti.trip_id = t.id), t.created_at
+		FROM pack_trip t
+		WHERE t.pack_id = $1
+		ORDER BY t.start_date DESC, t.id DESC;`,
+		packID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	trips := []Trip{}
+	for rows.Next() {
+		var trip Trip
+		err := rows.Scan(&trip.ID, &trip.PackID, &trip.StartDate, &trip.EndDate, &trip.Nights,
+			&trip.DistanceKm, &trip.Notes, &trip.ItemsCount, &trip.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		trips = append(trips, trip)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return trips, nil
+}
+
+// deleteTrip removes a trip of a pack and the usage it recorded
+func deleteTrip(ctx context.Context, packID, tripID uint) error {
+	result, err := database.DB().ExecContext(ctx,
+		`DELETE FROM pack_trip WHERE id = $1 AND pack_id = $2;`, tripID, packID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrTripNotFound
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package packs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/Angak0k/pimpmypack/pkg/security"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTrip(t *testing.T) {
+	nights, distance, negative := 2, 42, -1
+
+	trip, err := newTrip(5, TripRequest{StartDate: "2024-07-01", EndDate: "2024-07-04", Notes: "GR20 north"})
+	require.NoError(t, err)
+	assert.Equal(t, Trip{
+		PackID: 5, StartDate: "2024-07-01", EndDate: "2024-07-04", Nights: 3, Notes: "GR20 north",
+	}, trip)
+
+	trip, err = newTrip(5, TripRequest{StartDate: "2024-07-01", Nights: &nights, DistanceKm: &distance})
+	require.NoError(t, err)
+	assert.Equal(t, "2024-07-01", trip.EndDate)
+	assert.Equal(t, 2, trip.Nights)
+	assert.Equal(t, &distance, trip.DistanceKm)
+
+	for _, input := range []TripRequest{
+		{StartDate: "01/07/2024"},
+		{StartDate: "2024-07-04", EndDate: "2024-07-01"},
+		{StartDate: "2024-07-01", EndDate: "soon"},
+	} {
+		_, err = newTrip(5, input)
+		require.ErrorIs(t, err, ErrInvalidTripDates, input)
+	}
+	_, err = newTrip(5, TripRequest{StartDate: "2024-07-01", Nights: &negative})
+	require.ErrorIs(t, err, ErrInvalidTripValue)
+	_, err = newTrip(5, TripRequest{StartDate: "2024-07-01", DistanceKm: &negative})
+	require.ErrorIs(t, err, ErrInvalidTripValue)
+}
+
+func serveTripRequest(t *testing.T, router *gin.Engine, token, method, path string,
+	payload any) *httptest.ResponseRecorder {
+	t.Helper()
+	body, err := json.Marshal(payload)
+	require.NoError(t, err)
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMyPackTrips(t *testing.T) {
+	token, err := security.GenerateToken(users[0].ID)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/mypack/:id/trips", GetMyPackTrips)
+	router.POST("/mypack/:id/trips", PostMyPackTrip)
+	router.DELETE("/mypack/:id/trips/:trip_id", DeleteMyPackTrip)
+
+	// First Pack is on the GR20 (180 km)
+	packID := FindPackIDByPackName(packs, "First Pack")
+	path := fmt.Sprintf("/mypack/%d/trips", packID)
+	contents, err := returnPackContentsByPackID(t.Context(), packID)
+	require.NoError(t, err)
+	quantity := 0
+	for _, item := range *contents {
+		quantity += item.Quantity
+	}
+
+	w := serveTripRequest(t, router, token, http.MethodPost, path,
+		TripRequest{StartDate: "2024-07-01", EndDate: "2024-07-04"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var trip Trip
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &trip))
+	assert.Equal(t, 3, trip.Nights)
+	require.NotNil(t, trip.DistanceKm)
+	assert.Equal(t, 180, *trip.DistanceKm)
+	assert.Len(t, *contents, trip.ItemsCount)
+
+	var recorded int
+	require.NoError(t, database.DB().QueryRowContext(t.Context(),
+		`SELECT COALESCE(SUM(quantity), 0) FROM pack_trip_item WHERE trip_id = $1`, trip.ID).Scan(&recorded))
+	assert.Equal(t, quantity, recorded)
+
+	w = serveTripRequest(t, router, token, http.MethodPost, path, TripRequest{StartDate: "someday"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serveTripRequest(t, router, token, http.MethodGet, path, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var trips []Trip
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &trips))
+	require.Len(t, trips, 1)
+	assert.Equal(t, trip.ID, trips[0].ID)
+	assert.Equal(t, trip.ItemsCount, trips[0].ItemsCount)
+
+	otherPath := fmt.Sprintf("/mypack/%d/trips", FindPackIDByPackName(packs, "Third Pack"))
+	w = serveTripRequest(t, router, token, http.MethodGet, otherPath, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	tripPath := fmt.Sprintf("%s/%d", path, trip.ID)
+	w = serveTripRequest(t, router, token, http.MethodDelete, tripPath, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = serveTripRequest(t, router, token, http.MethodDelete, tripPath, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/Angak0k/pimpmypack/pkg/trails"
+	"github.com/Angak0k/pimpmypack/pkg/units"
 )
 
 // Domain errors
@@ -21,6 +22,12 @@ var (
 
 	// ErrTooManyItems is returned when an import exceeds MaxImportItems
 	ErrTooManyItems = errors.New("too many items in import")
+
+	// ErrDuplicatePackContent is returned when an order lists a pack content twice
+	ErrDuplicatePackContent = errors.New("the order lists the same pack content twice")
+
+	// ErrUnknownPackContent is returned when an order lists a pack content of another pack
+	ErrUnknownPackContent = errors.New("the order lists a pack content that is not in this pack")
 )
 
 // MaxImportItems caps the number of items accepted by a single pack import
@@ -36,21 +43,29 @@ var allowedAdventures = []string{"Bikepacking", "Backpacking", "Thru-hike", "Bac
 
 // Pack represents a pack with its metadata
 type Pack struct {
-	ID              uint      `json:"id"`
-	UserID          uint      `json:"user_id"`
-	PackName        string    `json:"pack_name"`
-	PackDescription string    `json:"pack_description"`
-	PackWeight      int       `json:"pack_weight"`
-	PackItemsCount  int       `json:"pack_items_count"`
-	SharingCode     *string   `json:"sharing_code,omitempty"`
-	IsFavorite      bool      `json:"is_favorite"`
-	HasImage        bool      `json:"has_image"`
-	Season          *string   `json:"season,omitempty"`
-	Trail           *string   `json:"trail,omitempty"`
-	TrailID         *uint     `json:"trail_id,omitempty"`
-	Adventure       *string   `json:"adventure,omitempty"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID              uint   `json:"id"`
+	UserID          uint   `json:"user_id"`
+	PackName        string `json:"pack_name"`
+	PackDescription string `json:"pack_description"`
+	PackWeight      int    `json:"pack_weight"`
+	// PackWeightDisplay is PackWeight in the unit system asked with ?units=
+	PackWeightDisplay *units.Weight `json:"pack_weight_display,omitempty"`
+	PackItemsCount    int           `json:"pack_items_count"`
+	// WishlistWeight is the weight of the planned purchases put in the pack,
+	// left out of PackWeight and PackItemsCount
+	WishlistWeight int     `json:"wishlist_weight"`
+	SharingCode    *string `json:"sharing_code,omitempty"`
+	IsFavorite     bool    `json:"is_favorite"`
+	HasImage       bool    `json:"has_image"`
+	Season         *string `json:"season,omitempty"`
+	Trail          *string `json:"trail,omitempty"`
+	TrailID        *uint   `json:"trail_id,omitempty"`
+	Adventure      *string `json:"adventure,omitempty"`
+	// Template is set for the packs listed as templates, official ones being
+	// marked by admins
+	Template  *string   `json:"template,omitempty" enums:"user,official"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // Packs represents a collection of packs
@@ -86,14 +101,20 @@ type PackUpdateRequest struct {
 
 // PackContent represents an item in a pack
 type PackContent struct {
-	ID         uint      `json:"id"`
-	PackID     uint      `json:"pack_id"`
-	ItemID     uint      `json:"item_id"`
-	Quantity   int       `json:"quantity"`
-	Worn       bool      `json:"worn"`
-	Consumable bool      `json:"consumable"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID         uint   `json:"id"`
+	PackID     uint   `json:"pack_id"`
+	ItemID     uint   `json:"item_id"`
+	Quantity   int    `json:"quantity"`
+	Worn       bool   `json:"worn"`
+	Consumable bool   `json:"consumable"`
+	Notes      string `json:"notes"`
+	// Position orders the item within its category; items without one come
+	// after the others, by name
+	Position *int `json:"position"`
+	// Optional items are listed but left out of the totals
+	Optional  bool      `json:"optional"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // PackContents represents a collection of pack contents
@@ -108,34 +129,286 @@ type PackContentWithItem struct {
 	Category        string `json:"category"`
 	ItemDescription string `json:"item_description"`
 	Weight          int    `json:"weight"`
-	ItemURL         string `json:"item_url"`
-	Price           int    `json:"price"`
-	Currency        string `json:"currency"`
-	Quantity        int    `json:"quantity"`
-	Worn            bool   `json:"worn"`
-	Consumable      bool   `json:"consumable"`
-	HasImage        bool   `json:"has_image"`
+	// WeightDisplay is Weight in the unit system asked with ?units=
+	WeightDisplay *units.Weight `json:"weight_display,omitempty"`
+	ItemURL       string        `json:"item_url"`
+	Price         int           `json:"price"`
+	Currency      string        `json:"currency"`
+	// PriceInPreferredCurrency is Price converted to the preferred currency of
+	// the requester; omitted when no exchange rate is known
+	PriceInPreferredCurrency *int   `json:"price_in_preferred_currency,omitempty"`
+	Quantity                 int    `json:"quantity"`
+	Worn                     bool   `json:"worn"`
+	Consumable               bool   `json:"consumable"`
+	Notes                    string `json:"notes"`
+	Position                 *int   `json:"position"`
+	// Optional items are listed but left out of the totals
+	Optional bool `json:"optional"`
+	// Wishlist is set for the planned purchases put in the pack
+	Wishlist bool `json:"wishlist"`
+	HasImage bool `json:"has_image"`
 }
 
 // PackContentWithItems represents a collection of pack contents with item details
 type PackContentWithItems []PackContentWithItem
 
+// CurrencyAmount is an amount in minor units of a currency
+type CurrencyAmount struct {
+	Currency string `json:"currency"`
+	Amount   int    `json:"amount"`
+}
+
+// PackCost is the cost of a pack (price x quantity of its items) in the
+// preferred currency of the user. Items priced in a currency without an
+// exchange rate are left out of Total and their currency listed in MissingRates.
+type PackCost struct {
+	PackID       uint             `json:"pack_id"`
+	Currency     string           `json:"currency"`
+	Total        int              `json:"total"`
+	ByCurrency   []CurrencyAmount `json:"by_currency"`
+	MissingRates []string         `json:"missing_rates"`
+}
+
+// Trip is a trip logged on a pack. Items counts the pack contents
+// recorded as used on the trip.
+type Trip struct {
+	ID         uint      `json:"id"`
+	PackID     uint      `json:"pack_id"`
+	StartDate  string    `json:"start_date"`
+	EndDate    string    `json:"end_date"`
+	Nights     int       `json:"nights"`
+	DistanceKm *int      `json:"distance_km,omitempty"`
+	Notes      string    `json:"notes"`
+	ItemsCount int       `json:"items_count"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TripRequest represents the input for logging a trip on a pack. EndDate
+// defaults to StartDate, Nights to the days between them and DistanceKm to
+// the distance of the pack trail.
+type TripRequest struct {
+	StartDate  string `json:"start_date" binding:"required" example:"2024-07-01"`
+	EndDate    string `json:"end_date" example:"2024-07-04"`
+	Nights     *int   `json:"nights"`
+	DistanceKm *int   `json:"distance_km"`
+	Notes      string `json:"notes"`
+}
+
+// Template kinds
+const (
+	TemplateUser     = "user"
+	TemplateOfficial = "official"
+)
+
+// PackTemplate is a pack listed as a template, described by its season,
+// adventure and trail
+type PackTemplate struct {
+	ID              uint    `json:"id"`
+	PackName        string  `json:"pack_name"`
+	PackDescription string  `json:"pack_description"`
+	Template        string  `json:"template" enums:"user,official"`
+	Season          *string `json:"season,omitempty"`
+	Trail           *string `json:"trail,omitempty"`
+	Adventure       *string `json:"adventure,omitempty"`
+	PackItemsCount  int     `json:"pack_items_count"`
+	PackWeight      int     `json:"pack_weight"`
+}
+
+// PackContentOrderRequest lists pack contents in their new order: each one
+// takes its index in the list as position
+type PackContentOrderRequest struct {
+	PackContentIDs []uint `json:"pack_content_ids" binding:"required,min=1"`
+}
+
+// FromTemplateRequest represents the optional input for creating a pack from
+// a template; the name and description of the template are used by default
+type FromTemplateRequest struct {
+	PackName        string `json:"pack_name"`
+	PackDescription string `json:"pack_description"`
+}
+
+// FromTemplateResponse is returned when a pack is created from a template.
+// Reused items were found in the inventory by name and category; the
+// others were created as wishlist placeholders.
+type FromTemplateResponse struct {
+	PackID       uint                `json:"pack_id"`
+	Reused       int                 `json:"reused"`
+	Placeholders int                 `json:"placeholders"`
+	Items        []TemplateItemMatch `json:"items"`
+}
+
+// TemplateItemMatch tells how a template item was mapped onto the inventory
+type TemplateItemMatch struct {
+	ItemName    string `json:"item_name"`
+	Category    string `json:"category"`
+	InventoryID uint   `json:"inventory_id"`
+	Placeholder bool   `json:"placeholder"`
+}
+
+// Variant change actions
+const (
+	VariantActionAdd    = "add"
+	VariantActionRemove = "remove"
+	VariantActionUpdate = "update"
+)
+
+// VariantChange is a change of a variant to the pack contents, by inventory
+// item. add puts the item in the pack (quantity 1 by default), remove takes it
+// out and update sets the quantity, worn or consumable fields it gives. An
+// update of an item no longer in the pack is ignored.
+type VariantChange struct {
+	ItemID     uint   `json:"item_id" binding:"required"`
+	Action     string `json:"action" binding:"required" enums:"add,remove,update"`
+	Quantity   *int   `json:"quantity,omitempty"`
+	Worn       *bool  `json:"worn,omitempty"`
+	Consumable *bool  `json:"consumable,omitempty"`
+}
+
+// PackTotals are the weights of pack contents in grams. BaseWeight leaves out
+// the worn and consumable items.
+type PackTotals struct {
+	ItemsCount       int `json:"items_count"`
+	TotalWeight      int `json:"total_weight"`
+	BaseWeight       int `json:"base_weight"`
+	WornWeight       int `json:"worn_weight"`
+	ConsumableWeight int `json:"consumable_weight"`
+	// WishlistWeight is the weight of the wishlist items, which are not owned
+	// gear and are left out of the other totals
+	WishlistWeight int `json:"wishlist_weight"`
+}
+
+// PackVariant is a named set of changes to the contents of a pack, with the
+// totals of the changed contents. WeightDelta is the change of the total and
+// wishlist weights from the pack; Contents are returned for a single variant
+// only.
+type PackVariant struct {
+	ID          uint                 `json:"id"`
+	PackID      uint                 `json:"pack_id"`
+	Name        string               `json:"name"`
+	Changes     []VariantChange      `json:"changes"`
+	Totals      PackTotals           `json:"totals"`
+	WeightDelta int                  `json:"weight_delta"`
+	Contents    PackContentWithItems `json:"contents,omitempty"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
+
+// PackVariants lists the variants of a pack next to the totals of the pack
+type PackVariants struct {
+	PackID   uint          `json:"pack_id"`
+	Totals   PackTotals    `json:"totals"`
+	Variants []PackVariant `json:"variants"`
+}
+
+// PackVariantRequest represents the input for creating or replacing a variant
+type PackVariantRequest struct {
+	Name    string          `json:"name" binding:"required" example:"rain forecast"`
+	Changes []VariantChange `json:"changes"`
+}
+
+// Suggestion sources
+const (
+	SuggestionSourceInventory = "inventory"
+	SuggestionSourceCommunity = "community"
+)
+
+// PackSuggestions lists lighter alternatives for the items of a pack.
+// WeightSaved sums the savings of the best suggestion of each item, times
+// its quantity.
+type PackSuggestions struct {
+	PackID      uint              `json:"pack_id"`
+	Currency    string            `json:"currency"`
+	WeightSaved int               `json:"weight_saved"`
+	Items       []ItemSuggestions `json:"items"`
+}
+
+// ItemSuggestions lists the lighter alternatives for one pack item, from the
+// unused items of the user first, then from shared packs
+type ItemSuggestions struct {
+	PackContentID uint         `json:"pack_content_id"`
+	InventoryID   uint         `json:"inventory_id"`
+	ItemName      string       `json:"item_name"`
+	Category      string       `json:"category"`
+	Weight        int          `json:"weight"`
+	Quantity      int          `json:"quantity"`
+	Suggestions   []Suggestion `json:"suggestions"`
+}
+
+// Suggestion is a lighter item of the same category. InventoryID is set for
+// the items of the user, and PackCount counts the shared packs using a
+// community item. PriceDifference is the price of the suggestion minus the
+// price of the pack item, in the preferred currency of the user; omitted when
+// an exchange rate is missing.
+type Suggestion struct {
+	Source          string `json:"source" enums:"inventory,community"`
+	InventoryID     *uint  `json:"inventory_id,omitempty"`
+	ItemName        string `json:"item_name"`
+	Weight          int    `json:"weight"`
+	URL             string `json:"url"`
+	Price           int    `json:"price"`
+	Currency        string `json:"currency"`
+	PackCount       int    `json:"pack_count,omitempty"`
+	WeightSaved     int    `json:"weight_saved"`
+	PriceDifference *int   `json:"price_difference,omitempty"`
+}
+
+// PurchasePlan ranks the wishlist items of the user for a pack by the weight
+// they would save per unit of the preferred currency spent
+type PurchasePlan struct {
+	PackID   uint              `json:"pack_id"`
+	Currency string            `json:"currency"`
+	Items    []PlannedPurchase `json:"items"`
+}
+
+// PlannedPurchase is a wishlist item and the heaviest owned pack item of its
+// category it would replace. WeightSaved is per unit; GramsPerUnit divides it
+// by the price in the preferred currency, and is omitted when there is no
+// replaced item, no price or no exchange rate.
+type PlannedPurchase struct {
+	InventoryID              uint          `json:"inventory_id"`
+	ItemName                 string        `json:"item_name"`
+	Category                 string        `json:"category"`
+	Weight                   int           `json:"weight"`
+	Price                    int           `json:"price"`
+	Currency                 string        `json:"currency"`
+	PriceInPreferredCurrency *int          `json:"price_in_preferred_currency,omitempty"`
+	Replaces                 *ReplacedItem `json:"replaces,omitempty"`
+	WeightSaved              int           `json:"weight_saved"`
+	GramsPerUnit             *float64      `json:"grams_per_unit,omitempty"`
+}
+
+// ReplacedItem is the pack item a planned purchase would replace
+type ReplacedItem struct {
+	PackContentID uint   `json:"pack_content_id"`
+	InventoryID   uint   `json:"inventory_id"`
+	ItemName      string `json:"item_name"`
+	Weight        int    `json:"weight"`
+	Quantity      int    `json:"quantity"`
+}
+
 // PackContentRequest represents the data required to add an item to a pack
 type PackContentRequest struct {
 	InventoryID uint `json:"inventory_id" binding:"required"`
 	Quantity    int  `json:"quantity" binding:"required,min=1"`
 	Worn        bool `json:"worn"`
 	Consumable  bool `json:"consumable"`
+	// Notes, Position and Optional are kept on update when omitted
+	Notes    *string `json:"notes" binding:"omitempty,max=500" example:"rent on site"`
+	Position *int    `json:"position" binding:"omitempty,min=0"`
+	Optional *bool   `json:"optional"`
 }
 
 // PackContentCreateRequest represents the input for creating pack content (admin)
 // Note: User endpoint uses PackContentRequest which doesn't include PackID/ItemID
 type PackContentCreateRequest struct {
-	PackID     uint `json:"pack_id" binding:"required"`
-	ItemID     uint `json:"item_id" binding:"required"`
-	Quantity   int  `json:"quantity" binding:"required,min=1"`
-	Worn       bool `json:"worn"`
-	Consumable bool `json:"consumable"`
+	PackID     uint   `json:"pack_id" binding:"required"`
+	ItemID     uint   `json:"item_id" binding:"required"`
+	Quantity   int    `json:"quantity" binding:"required,min=1"`
+	Worn       bool   `json:"worn"`
+	Consumable bool   `json:"consumable"`
+	Notes      string `json:"notes" binding:"max=500"`
+	Position   *int   `json:"position" binding:"omitempty,min=0"`
+	Optional   bool   `json:"optional"`
 }
 
 // PackContentUpdateRequest represents the input for updating pack content
@@ -145,6 +418,10 @@ type PackContentUpdateRequest struct {
 	Quantity   int  `json:"quantity" binding:"required,min=1"`
 	Worn       bool `json:"worn"`
 	Consumable bool `json:"consumable"`
+	// Notes, Position and Optional are kept when omitted
+	Notes    *string `json:"notes" binding:"omitempty,max=500"`
+	Position *int    `json:"position" binding:"omitempty,min=0"`
+	Optional *bool   `json:"optional"`
 }
 
 // ExternalPackItem represents an item imported from an external source (LighterPack, PimpMyPack, etc.)
@@ -160,6 +437,8 @@ type ExternalPackItem struct {
 	Currency   string `json:"currency"`
 	Worn       bool   `json:"worn"`
 	Consumable bool   `json:"consumable"`
+	Notes      string `json:"notes,omitempty"`
+	Optional   bool   `json:"optional,omitempty"`
 }
 
 // ImportFromURLRequest represents the request body for importing from a LighterPack URL
@@ -176,6 +455,30 @@ type ImportExternalPackResponse struct {
 	PackID  uint   `json:"pack_id"`
 }
 
+// SpreadsheetRowError reports a spreadsheet row that was skipped
+type SpreadsheetRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// ImportSpreadsheetResponse represents the response when importing a spreadsheet.
+// Columns maps each field to the header it was read from.
+type ImportSpreadsheetResponse struct {
+	Message  string                `json:"message"`
+	PackID   uint                  `json:"pack_id"`
+	Imported int                   `json:"imported"`
+	Columns  map[string]string     `json:"columns"`
+	Errors   []SpreadsheetRowError `json:"errors"`
+	// Preview is set instead of PackID on dry runs
+	Preview *ImportPreviewResponse `json:"preview,omitempty"`
+}
+
+// ImportSpreadsheetErrorResponse is returned when no row of a spreadsheet could be imported
+type ImportSpreadsheetErrorResponse struct {
+	Error  string                `json:"error"`
+	Errors []SpreadsheetRowError `json:"errors"`
+}
+
 // ParseExternalPackResponse is returned by the public parse endpoint.
 // It contains parsed pack data WITHOUT persisting anything.
 type ParseExternalPackResponse struct {
@@ -194,6 +497,47 @@ type ImportPackRequest struct {
 	Items           ExternalPack `json:"items"`
 }
 
+// ImportPlanItem is an import item with the action reconciling it with the
+// inventory: reuse the inventory item InventoryID, or create a new item.
+type ImportPlanItem struct {
+	ExternalPackItem
+	Action      string `json:"action"`
+	InventoryID uint   `json:"inventory_id"`
+	// placeholder items are created on the wishlist
+	placeholder bool
+}
+
+// ImportCandidate is an inventory item similar to an imported item
+type ImportCandidate struct {
+	InventoryID uint    `json:"inventory_id"`
+	ItemName    string  `json:"item_name"`
+	Category    string  `json:"category"`
+	Description string  `json:"description"`
+	Weight      int     `json:"weight"`
+	Similarity  float64 `json:"similarity"`
+}
+
+// ImportPreviewItem is the proposed action for an imported item. Items with
+// the review action list the similar inventory items as candidates.
+type ImportPreviewItem struct {
+	ImportPlanItem
+	Candidates []ImportCandidate `json:"candidates"`
+}
+
+// ImportPreviewResponse is returned by import dry runs; nothing is persisted
+type ImportPreviewResponse struct {
+	PackName        string              `json:"pack_name"`
+	PackDescription string              `json:"pack_description"`
+	Items           []ImportPreviewItem `json:"items"`
+}
+
+// ImportPlanRequest is a reconciled import plan to commit
+type ImportPlanRequest struct {
+	PackName        string           `json:"pack_name"`
+	PackDescription string           `json:"pack_description"`
+	Items           []ImportPlanItem `json:"items"`
+}
+
 // SharedPackResponse represents the response structure for shared pack endpoint
 type SharedPackResponse struct {
 	Pack     SharedPackInfo       `json:"pack"`
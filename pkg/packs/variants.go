@@ -0,0 +1,373 @@
+package packs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/lib/pq"
+)
+
+// maxVariantChanges bounds the changes of a variant
+const maxVariantChanges = 500
+
+// variantNameConstraint is the unique index of variant names within a pack
+const variantNameConstraint = "idx_pack_variant_name"
+
+var variantActions = []string{VariantActionAdd, VariantActionRemove, VariantActionUpdate}
+
+var (
+	// ErrVariantNotFound is returned when a variant does not exist on the pack
+	ErrVariantNotFound = errors.New("variant not found")
+	// ErrEmptyVariantName is returned for a blank variant name
+	ErrEmptyVariantName = errors.New("variant name must not be empty")
+	// ErrDuplicateVariantName is returned when the pack has a variant of the same name
+	ErrDuplicateVariantName = errors.New("a variant with this name already exists")
+	// ErrInvalidVariantChange is returned for an unknown action or a quantity below 1
+	ErrInvalidVariantChange = errors.New("changes need an add, remove or update action and a quantity of 1 or more")
+	// ErrDuplicateVariantItem is returned when a variant changes an item twice
+	ErrDuplicateVariantItem = errors.New("a variant changes each item once")
+	// ErrTooManyVariantChanges is returned for more than maxVariantChanges changes
+	ErrTooManyVariantChanges = errors.New("too many variant changes")
+	// ErrUnknownVariantItem is returned when a changed item is not in the inventory of the user
+	ErrUnknownVariantItem = errors.New("variant changes an item that is not in your inventory")
+)
+
+// validateVariant trims the name and checks the changes of a variant request
+func validateVariant(input *PackVariantRequest) error {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		return ErrEmptyVariantName
+	}
+	if len(input.Changes) > maxVariantChanges {
+		return ErrTooManyVariantChanges
+	}
+
+	seen := map[uint]bool{}
+	for i := range input.Changes {
+		change := &input.Changes[i]
+		if !slices.Contains(variantActions, change.Action) || (change.Quantity != nil && *change.Quantity < 1) {
+			return ErrInvalidVariantChange
+		}
+		if seen[change.ItemID] {
+			return ErrDuplicateVariantItem
+		}
+		seen[change.ItemID] = true
+		if change.Action == VariantActionRemove {
+			change.Quantity, change.Worn, change.Consumable = nil, nil, nil
+		}
+	}
+	return nil
+}
+
+// applyVariant returns the pack contents changed by a variant. Added items
+// come from items, by inventory ID, and follow the contents of the pack.
+func applyVariant(
+	contents PackContentWithItems, changes []VariantChange, items map[uint]PackContentWithItem,
+) PackContentWithItems {
+	byItem := make(map[uint]VariantChange, len(changes))
+	for _, change := range changes {
+		byItem[change.ItemID] = change
+	}
+
+	changed := make(PackContentWithItems, 0, len(contents)+len(changes))
+	inPack := map[uint]bool{}
+	for _, item := range contents {
+		inPack[item.InventoryID] = true
+		change, ok := byItem[item.InventoryID]
+		if ok && change.Action == VariantActionRemove {
+			continue
+		}
+		if ok {
+			change.applyTo(&item)
+		}
+		changed = append(changed, item)
+	}
+
+	for _, change := range changes {
+		item, ok := items[change.ItemID]
+		if change.Action != VariantActionAdd || inPack[change.ItemID] || !ok {
+			continue
+		}
+		item.Quantity = 1
+		change.applyTo(&item)
+		changed = append(changed, item)
+	}
+	return changed
+}
+
+// applyTo sets the fields the change gives on a pack item
+func (change VariantChange) applyTo(item *PackContentWithItem) {
+	if change.Quantity != nil {
+		item.Quantity = *change.Quantity
+	}
+	if change.Worn != nil {
+		item.Worn = *change.Worn
+	}
+	if change.Consumable != nil {
+		item.Consumable = *change.Consumable
+	}
+}
+
+// computeTotals sums weight x quantity of the contents, leaving out the
+// optional items. Wishlist items are summed apart, as they are not owned.
+func computeTotals(contents PackContentWithItems) PackTotals {
+	var totals PackTotals
+	for _, item := range contents {
+		if item.Optional {
+			continue
+		}
+		weight := item.Weight * item.Quantity
+		if item.Wishlist {
+			totals.WishlistWeight += weight
+			continue
+		}
+		totals.ItemsCount += item.Quantity
+		totals.TotalWeight += weight
+		switch {
+		case item.Worn:
+			totals.WornWeight += weight
+		case item.Consumable:
+			totals.ConsumableWeight += weight
+		default:
+			totals.BaseWeight += weight
+		}
+	}
+	return totals
+}
+
+// returnPackVariants returns the variants of a pack with their totals
+func returnPackVariants(ctx context.Context, pack *Pack) (*PackVariants, error) {
+	contents, err := returnPackContentsByPackID(ctx, pack.ID)
+	if err != nil {
+		return nil, err
+	}
+	variants, err := returnVariantsByPackID(ctx, pack.ID, 0)
+	if err != nil {
+		return nil, err
+	}
+	var changes []VariantChange
+	for _, variant := range variants {
+		changes = append(changes, variant.Changes...)
+	}
+	items, err := returnAddedItems(ctx, pack.ID, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PackVariants{PackID: pack.ID, Totals: computeTotals(*contents), Variants: variants}
+	for i := range result.Variants {
+		variant := &result.Variants[i]
+		variant.Totals = computeTotals(applyVariant(*contents, variant.Changes, items))
+		variant.WeightDelta = weightDelta(variant.Totals, result.Totals)
+	}
+	return result, nil
+}
+
+// returnPackVariant returns a variant of a pack with its contents
+func returnPackVariant(ctx context.Context, pack *Pack, variantID uint) (*PackVariant, error) {
+	variants, err := returnVariantsByPackID(ctx, pack.ID, variantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(variants) == 0 {
+		return nil, ErrVariantNotFound
+	}
+	variant := variants[0]
+
+	contents, err := returnPackContentsByPackID(ctx, pack.ID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := returnAddedItems(ctx, pack.ID, variant.Changes)
+	if err != nil {
+		return nil, err
+	}
+	variant.Contents = applyVariant(*contents, variant.Changes, items)
+	variant.Totals = computeTotals(variant.Contents)
+	variant.WeightDelta = weightDelta(variant.Totals, computeTotals(*contents))
+	return &variant, nil
+}
+
+// weightDelta is the weight a variant adds to the pack, wishlist items
+// included so that trying out planned purchases changes it
+func weightDelta(variant, pack PackTotals) int {
+	return variant.TotalWeight + variant.WishlistWeight - pack.TotalWeight - pack.WishlistWeight
+}
+
+// returnVariantsByPackID retrieves the variants of a pack with their changes,
+// or the variant variantID only when it is not 0
+func returnVariantsByPackID(ctx context.Context, packID, variantID uint) ([]PackVariant, error) {
+	rows, err := database.DB().QueryContext(ctx,
+		`SELECT v.id, v.pack_id, v.name, v.created_at, v.updated_at,
+			c.item_id, c.action, c.quantity, c.worn, c.consumable
+		FROM pack_variant v
+		LEFT JOIN pack_variant_change c ON c.variant_id = v.id
+		WHERE v.pack_id = $1 AND ($2 = 0 OR v.id = $2)
+		ORDER BY LOWER(v.name), v.id, c.item_id;`,
+		packID, variantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	variants := []PackVariant{}
+	for rows.Next() {
+		var variant PackVariant
+		var itemID sql.NullInt64
+		var action sql.NullString
+		var change VariantChange
+		err := rows.Scan(&variant.ID, &variant.PackID, &variant.Name, &variant.CreatedAt, &variant.UpdatedAt,
+			&itemID, &action, &change.Quantity, &change.Worn, &change.Consumable)
+		if err != nil {
+			return nil, err
+		}
+		if n := len(variants); n == 0 || variants[n-1].ID != variant.ID {
+			variant.Changes = []VariantChange{}
+			variants = append(variants, variant)
+		}
+		if itemID.Valid {
+			change.ItemID = uint(itemID.Int64)
+			change.Action = action.String
+			last := &variants[len(variants)-1]
+			last.Changes = append(last.Changes, change)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return variants, nil
+}
+
+// returnAddedItems retrieves the inventory items added by changes, by ID, as
+// pack contents of the pack
+func returnAddedItems(ctx context.Context, packID uint, changes []VariantChange) (map[uint]PackContentWithItem, error) {
+	var ids []int64
+	for _, change := range changes {
+		if change.Action == VariantActionAdd {
+			ids = append(ids, int64(change.ItemID))
+		}
+	}
+	items := map[uint]PackContentWithItem{}
+	if len(ids) == 0 {
+		return items, nil
+	}
+
+	rows, err := database.DB().QueryContext(ctx,
+		`SELECT i.id, i.item_name, i.category, i.description, i.weight, i.url, i.price, i.currency, i.wishlist,
+			CASE WHEN ii.item_id IS NOT NULL THEN true ELSE false END as has_image
+		FROM inventory i
+		LEFT JOIN inventory_images ii ON i.id = ii.item_id
+		WHERE i.id = ANY($1);`,
+		pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := PackContentWithItem{PackID: packID}
+		err := rows.Scan(&item.InventoryID, &item.ItemName, &item.Category, &item.ItemDescription, &item.Weight,
+			&item.ItemURL, &item.Price, &item.Currency, &item.Wishlist, &item.HasImage)
+		if err != nil {
+			return nil, err
+		}
+		items[item.InventoryID] = item
+	}
+	return items, rows.Err()
+}
+
+// saveVariant inserts a variant of a pack of the user, or replaces the name
+// and changes of the variant variantID when it is not 0
+func saveVariant(ctx context.Context, userID, packID, variantID uint, input PackVariantRequest) (uint, error) {
+	tx, err := database.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := checkVariantItems(ctx, tx, userID, input.Changes); err != nil {
+		return 0, err
+	}
+
+	if variantID == 0 {
+		err = tx.QueryRowContext(ctx,
+			`INSERT INTO pack_variant (pack_id, name) VALUES ($1, $2) RETURNING id;`,
+			packID, input.Name).Scan(&variantID)
+	} else {
+		err = tx.QueryRowContext(ctx,
+			`UPDATE pack_variant SET name = $1, updated_at = NOW() WHERE id = $2 AND pack_id = $3 RETURNING id;`,
+			input.Name, variantID, packID).Scan(&variantID)
+	}
+	var pqErr *pq.Error
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return 0, ErrVariantNotFound
+	case errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == variantNameConstraint:
+		return 0, ErrDuplicateVariantName
+	case err != nil:
+		return 0, fmt.Errorf("failed to save variant: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM pack_variant_change WHERE variant_id = $1;`, variantID); err != nil {
+		return 0, fmt.Errorf("failed to delete variant changes: %w", err)
+	}
+	for _, change := range input.Changes {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO pack_variant_change (variant_id, item_id, action, quantity, worn, consumable)
+			VALUES ($1, $2, $3, $4, $5, $6);`,
+			variantID, change.ItemID, change.Action, change.Quantity, change.Worn, change.Consumable)
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert variant change: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit variant: %w", err)
+	}
+	return variantID, nil
+}
+
+// checkVariantItems returns ErrUnknownVariantItem unless the changed items
+// belong to the user
+func checkVariantItems(ctx context.Context, tx *sql.Tx, userID uint, changes []VariantChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(changes))
+	for _, change := range changes {
+		ids = append(ids, int64(change.ItemID))
+	}
+	var owned int
+	err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM inventory WHERE user_id = $1 AND id = ANY($2);`,
+		userID, pq.Array(ids)).Scan(&owned)
+	if err != nil {
+		return fmt.Errorf("failed to check variant items: %w", err)
+	}
+	if owned != len(ids) {
+		return ErrUnknownVariantItem
+	}
+	return nil
+}
+
+// deleteVariant removes a variant of a pack
+func deleteVariant(ctx context.Context, packID, variantID uint) error {
+	result, err := database.DB().ExecContext(ctx,
+		`DELETE FROM pack_variant WHERE id = $1 AND pack_id = $2;`, variantID, packID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrVariantNotFound
+	}
+	return nil
+}
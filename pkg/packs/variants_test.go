@@ -0,0 +1,157 @@
+package packs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Angak0k/pimpmypack/pkg/security"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateVariant(t *testing.T) {
+	one, zero, worn := 1, 0, true
+	input := PackVariantRequest{Name: " rain forecast ", Changes: []VariantChange{
+		{ItemID: 1, Action: VariantActionRemove, Quantity: &one, Worn: &worn},
+		{ItemID: 2, Action: VariantActionUpdate, Quantity: &one},
+	}}
+	require.NoError(t, validateVariant(&input))
+	assert.Equal(t, "rain forecast", input.Name)
+	assert.Equal(t, VariantChange{ItemID: 1, Action: VariantActionRemove}, input.Changes[0])
+
+	tests := []struct {
+		input   PackVariantRequest
+		wantErr error
+	}{
+		{PackVariantRequest{Name: " "}, ErrEmptyVariantName},
+		{PackVariantRequest{Name: "a", Changes: []VariantChange{{ItemID: 1, Action: "swap"}}}, ErrInvalidVariantChange},
+		{PackVariantRequest{Name: "a", Changes: []VariantChange{{ItemID: 1, Action: VariantActionAdd, Quantity: &zero}}},
+			ErrInvalidVariantChange},
+		{PackVariantRequest{Name: "a", Changes: []VariantChange{
+			{ItemID: 1, Action: VariantActionAdd}, {ItemID: 1, Action: VariantActionRemove},
+		}}, ErrDuplicateVariantItem},
+		{PackVariantRequest{Name: "a", Changes: make([]VariantChange, maxVariantChanges+1)}, ErrTooManyVariantChanges},
+	}
+	for _, tt := range tests {
+		assert.ErrorIs(t, validateVariant(&tt.input), tt.wantErr)
+	}
+}
+
+func TestApplyVariant(t *testing.T) {
+	contents := PackContentWithItems{
+		{InventoryID: 1, ItemName: "Backpack", Weight: 950, Quantity: 1},
+		{InventoryID: 2, ItemName: "Jacket", Weight: 300, Quantity: 1, Worn: true},
+		{InventoryID: 3, ItemName: "Gas", Weight: 200, Quantity: 2, Consumable: true},
+		{InventoryID: 4, ItemName: "Stove", Weight: 90, Quantity: 1},
+	}
+	three, worn := 3, true
+	changes := []VariantChange{
+		{ItemID: 4, Action: VariantActionRemove},
+		{ItemID: 3, Action: VariantActionRemove},
+		{ItemID: 5, Action: VariantActionAdd, Worn: &worn},
+		{ItemID: 1, Action: VariantActionUpdate, Quantity: &three},
+		{ItemID: 6, Action: VariantActionUpdate, Quantity: &three},
+		{ItemID: 7, Action: VariantActionAdd},
+	}
+	items := map[uint]PackContentWithItem{
+		5: {InventoryID: 5, ItemName: "Rain pants", Weight: 150, Wishlist: true},
+	}
+
+	changed := applyVariant(contents, changes, items)
+	require.Len(t, changed, 3)
+	assert.Equal(t, "Backpack", changed[0].ItemName)
+	assert.Equal(t, 3, changed[0].Quantity)
+	assert.Equal(t, "Jacket", changed[1].ItemName)
+	assert.Equal(t, "Rain pants", changed[2].ItemName)
+	assert.Equal(t, 1, changed[2].Quantity)
+	assert.True(t, changed[2].Worn)
+	assert.Equal(t, 1, contents[0].Quantity, "the pack contents are left unchanged")
+
+	assert.Equal(t, PackTotals{
+		ItemsCount: 5, TotalWeight: 1740, BaseWeight: 1040, WornWeight: 300, ConsumableWeight: 400,
+	}, computeTotals(contents))
+	assert.Equal(t, PackTotals{
+		ItemsCount: 4, TotalWeight: 3150, BaseWeight: 2850, WornWeight: 300, WishlistWeight: 150,
+	}, computeTotals(changed), "wishlist items are not owned gear")
+	assert.Equal(t, 1560, weightDelta(computeTotals(changed), computeTotals(contents)))
+
+	changed[0].Optional = true
+	assert.Equal(t, PackTotals{
+		ItemsCount: 1, TotalWeight: 300, BaseWeight: 0, WornWeight: 300, WishlistWeight: 150,
+	}, computeTotals(changed), "optional items are left out of the totals")
+}
+
+func TestMyPackVariants(t *testing.T) {
+	token, err := security.GenerateToken(users[0].ID)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/mypack/:id/variants", GetMyPackVariants)
+	router.POST("/mypack/:id/variants", PostMyPackVariant)
+	router.GET("/mypack/:id/variants/:variant_id", GetMyPackVariant)
+	router.PUT("/mypack/:id/variants/:variant_id", PutMyPackVariant)
+	router.DELETE("/mypack/:id/variants/:variant_id", DeleteMyPackVariant)
+
+	// First Pack holds the backpack and the tent, not the sleeping bag
+	packID := FindPackIDByPackName(packs, "First Pack")
+	path := fmt.Sprintf("/mypack/%d/variants", packID)
+	backpack, tent, sleepingBag := inventoriesUserPack1[0].ID, inventoriesUserPack1[1].ID, inventoriesUserPack1[2].ID
+	one := 1
+	input := PackVariantRequest{Name: "No tent", Changes: []VariantChange{
+		{ItemID: tent, Action: VariantActionRemove},
+		{ItemID: sleepingBag, Action: VariantActionAdd},
+		{ItemID: backpack, Action: VariantActionUpdate, Quantity: &one},
+	}}
+
+	w := serveTripRequest(t, router, token, http.MethodPost, path, input)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var variant PackVariant
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &variant))
+	assert.Equal(t, "No tent", variant.Name)
+	assert.Len(t, variant.Changes, 3)
+	names := map[string]int{}
+	for _, item := range variant.Contents {
+		names[item.ItemName] = item.Quantity
+	}
+	assert.Equal(t, map[string]int{"Backpack": 1, "Sleeping Bag": 1}, names)
+	variantPath := fmt.Sprintf("%s/%d", path, variant.ID)
+
+	w = serveTripRequest(t, router, token, http.MethodPost, path, PackVariantRequest{Name: "no TENT"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = serveTripRequest(t, router, token, http.MethodPost, path, PackVariantRequest{
+		Name: "Borrowed", Changes: []VariantChange{{ItemID: 999999999, Action: VariantActionAdd}},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serveTripRequest(t, router, token, http.MethodGet, path, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var variants PackVariants
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &variants))
+	require.Len(t, variants.Variants, 1)
+	listed := variants.Variants[0]
+	assert.Equal(t, variant.Totals, listed.Totals)
+	assert.Equal(t, listed.Totals.TotalWeight-variants.Totals.TotalWeight, listed.WeightDelta)
+	assert.Empty(t, listed.Contents)
+
+	input.Name = "Rain forecast"
+	input.Changes = input.Changes[:1]
+	w = serveTripRequest(t, router, token, http.MethodPut, variantPath, input)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &variant))
+	assert.Equal(t, "Rain forecast", variant.Name)
+	assert.Equal(t, []VariantChange{{ItemID: tent, Action: VariantActionRemove}}, variant.Changes)
+
+	otherToken, err := security.GenerateToken(users[1].ID)
+	require.NoError(t, err)
+	w = serveTripRequest(t, router, otherToken, http.MethodGet, variantPath, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serveTripRequest(t, router, token, http.MethodDelete, variantPath, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = serveTripRequest(t, router, token, http.MethodGet, variantPath, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
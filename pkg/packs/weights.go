@@ -0,0 +1,17 @@
+package packs
+
+import "github.com/Angak0k/pimpmypack/pkg/units"
+
+// setPackWeightDisplays fills PackWeightDisplay in the unit system, none when system is ""
+func setPackWeightDisplays(packs Packs, system string) {
+	for i := range packs {
+		packs[i].PackWeightDisplay = units.NewWeight(packs[i].PackWeight, system)
+	}
+}
+
+// setContentWeightDisplays fills WeightDisplay in the unit system, none when system is ""
+func setContentWeightDisplays(contents PackContentWithItems, system string) {
+	for i := range contents {
+		contents[i].WeightDisplay = units.NewWeight(contents[i].Weight, system)
+	}
+}
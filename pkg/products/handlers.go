@@ -0,0 +1,178 @@
+package products
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Angak0k/pimpmypack/pkg/helper"
+	"github.com/gin-gonic/gin"
+)
+
+// SearchProducts autocompletes products of the community catalog
+// @Summary Search products
+// @Description Autocompletes products of the community gear catalog for item creation: products whose
+// @Description brand, model and variant contain every word of q, the most used first. Pass the ID of
+// @Description the chosen product as product_id to POST /v1/myinventory.
+// @Security Bearer
+// @Tags Products
+// @Produce json
+// @Param q query string true "Search words, at least 2 characters"
+// @Param limit query int false "Maximum number of products, 10 by default and 50 at most"
+// @Success 200 {object} Products
+// @Failure 400 {object} apitypes.ErrorResponse "Query too short or invalid limit"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/products [get]
+func SearchProducts(c *gin.Context) {
+	terms, err := searchTerms(c.Query("q"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit := defaultSearchLimit
+	if raw := c.Query("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxSearchLimit {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+	}
+
+	products, err := searchProducts(c.Request.Context(), terms, limit)
+	if err != nil {
+		helper.LogAndSanitize(err, "search products: search failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, products)
+}
+
+// GetProductByID returns a product of the community catalog
+// @Summary Get a product
+// @Description Returns a product with the number of inventory items linked to it and their median weight
+// @Security Bearer
+// @Tags Products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} Product
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 404 {object} apitypes.ErrorResponse "Product not found"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal Server Error"
+// @Router /v1/products/{id} [get]
+func GetProductByID(c *gin.Context) {
+	id, err := helper.StringToUint(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	product, err := findProductByID(c.Request.Context(), id)
+	if err != nil {
+		respondProductError(c, err, "get product by ID: find product failed")
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, product)
+}
+
+// ImportProducts handles POST /admin/products/import
+// @Summary [ADMIN] Import products
+// @Description Create or update products of the community catalog - for admin use only. Products are
+// @Description matched by brand, model and variant ignoring case, and imported products replace
+// @Description community ones.
+// @Security Bearer
+// @Tags Internal
+// @Accept json
+// @Produce json
+// @Param products body ProductImportRequest true "Products"
+// @Success 200 {object} ProductImportResult
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid payload"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal server error"
+// @Router /admin/products/import [post]
+func ImportProducts(c *gin.Context) {
+	var input ProductImportRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		helper.LogAndSanitize(err, "import products: bind JSON failed")
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": helper.ErrMsgBadRequest})
+		return
+	}
+	if err := validateImport(input.Products); err != nil {
+		respondProductError(c, err, "import products: validate failed")
+		return
+	}
+
+	result, err := upsertProducts(c.Request.Context(), input.Products)
+	if err != nil {
+		respondProductError(c, err, "import products: upsert failed")
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// PostCommunityProducts handles POST /admin/products/community
+// @Summary [ADMIN] Build products from community inventory
+// @Description Create products from the inventory item names owned by several users, ignoring case and
+// @Description spacing, and link the unlinked items to them - for admin use only. The first word of a
+// @Description name is taken as the brand and the rest as the model.
+// @Security Bearer
+// @Tags Internal
+// @Produce json
+// @Success 200 {object} CommunityImportResult
+// @Failure 500 {object} apitypes.ErrorResponse "Internal server error"
+// @Router /admin/products/community [post]
+func PostCommunityProducts(c *gin.Context) {
+	result, err := importCommunityProducts(c.Request.Context())
+	if err != nil {
+		helper.LogAndSanitize(err, "post community products: import failed")
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// DeleteProductByID handles DELETE /admin/products/:id
+// @Summary [ADMIN] Delete a product
+// @Description Delete a product of the community catalog; its inventory items are unlinked - for admin use only
+// @Security Bearer
+// @Tags Internal
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} apitypes.OkResponse "Product deleted"
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid ID format"
+// @Failure 404 {object} apitypes.ErrorResponse "Product not found"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal server error"
+// @Router /admin/products/{id} [delete]
+func DeleteProductByID(c *gin.Context) {
+	id, err := helper.StringToUint(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	if err := deleteProductByID(c.Request.Context(), id); err != nil {
+		respondProductError(c, err, "delete product by ID: delete failed")
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, gin.H{"message": "Product deleted"})
+}
+
+// respondProductError maps product errors to an HTTP response
+func respondProductError(c *gin.Context, err error, logMsg string) {
+	switch {
+	case errors.Is(err, ErrProductNotFound):
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": ErrProductNotFound.Error()})
+	case errors.Is(err, ErrEmptyProductName), errors.Is(err, ErrInvalidWeight), errors.Is(err, ErrTooManyProducts),
+		errors.Is(err, ErrDuplicateProducts):
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		helper.LogAndSanitize(err, logMsg)
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+	}
+}
@@ -0,0 +1,212 @@
+package products
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Angak0k/pimpmypack/pkg/config"
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/Angak0k/pimpmypack/pkg/security"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	err := config.EnvInit("../../.env")
+	if err != nil {
+		log.Fatalf("Error loading .env file or environment variable : %v", err)
+	}
+
+	err = database.Initialization()
+	if err != nil {
+		log.Fatalf("Error connecting database : %v", err)
+	}
+
+	err = database.Migrate()
+	if err != nil {
+		log.Fatalf("Error migrating database : %v", err)
+	}
+
+	err = loadingProductDataset()
+	if err != nil {
+		log.Fatalf("Error loading dataset : %v", err)
+	}
+
+	ret := m.Run()
+
+	if err := cleanupProductDataset(); err != nil {
+		log.Printf("Warning: Error cleaning up dataset : %v", err)
+	}
+
+	os.Exit(ret)
+}
+
+func TestValidateImport(t *testing.T) {
+	weight, negative := 539, -1
+	inputs := []ProductInput{
+		{Brand: " Zpacks ", Model: "Duplex ", Weight: &weight},
+		{Brand: "Zpacks", Model: "Duplex", Variant: " Zip "},
+	}
+	require.NoError(t, validateImport(inputs))
+	assert.Equal(t, "Zpacks", inputs[0].Brand)
+	assert.Equal(t, "Zip", inputs[1].Variant)
+
+	tests := []struct {
+		inputs  []ProductInput
+		wantErr error
+	}{
+		{[]ProductInput{{Brand: " ", Model: "Duplex"}}, ErrEmptyProductName},
+		{[]ProductInput{{Brand: "Zpacks", Model: "Duplex", Weight: &negative}}, ErrInvalidWeight},
+		{[]ProductInput{{Brand: "Zpacks", Model: "Duplex"}, {Brand: "ZPACKS", Model: "duplex "}}, ErrDuplicateProducts},
+		{make([]ProductInput, maxImportProducts+1), ErrTooManyProducts},
+	}
+	for _, tt := range tests {
+		assert.ErrorIs(t, validateImport(tt.inputs), tt.wantErr)
+	}
+}
+
+func TestSearchTerms(t *testing.T) {
+	terms, err := searchTerms("  zpacks 100%_dyneema ")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"zpacks", `100\%\_dyneema`}, terms)
+
+	terms, err = searchTerms("a b c d e f g")
+	require.NoError(t, err)
+	assert.Len(t, terms, maxSearchTerms)
+
+	_, err = searchTerms(" z ")
+	assert.ErrorIs(t, err, ErrShortSearchQuery)
+}
+
+func TestFindCommunityProducts(t *testing.T) {
+	items := []communityItem{
+		{1, 1, "Zpacks Duplex"},
+		{2, 2, "zpacks  duplex"},
+		{3, 3, "Zpacks Duplex Tent"},
+		{4, 3, "ZPACKS DUPLEX"},
+		{5, 4, "Zpacks Duplex"},
+		{6, 1, "Toaks Pot"},
+		{7, 2, "Toaks Pot"},
+		{8, 1, "Spork"},
+		{9, 2, "Spork"},
+		{10, 3, "Spork"},
+	}
+	found := findCommunityProducts(items)
+	require.Len(t, found, 1)
+	assert.Equal(t, "Zpacks", found[0].brand)
+	assert.Equal(t, "Duplex", found[0].model)
+	assert.ElementsMatch(t, []uint{1, 2, 4, 5}, found[0].itemIDs)
+}
+
+func productRequest(t *testing.T, method, path string, payload any) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/products", SearchProducts)
+	router.GET("/products/:id", GetProductByID)
+	router.POST("/admin/products/import", ImportProducts)
+	router.POST("/admin/products/community", PostCommunityProducts)
+	router.DELETE("/admin/products/:id", DeleteProductByID)
+
+	token, err := security.GenerateToken(testUsers[0].ID)
+	require.NoError(t, err)
+	var body bytes.Buffer
+	if payload != nil {
+		require.NoError(t, json.NewEncoder(&body).Encode(payload))
+	}
+	req := httptest.NewRequest(method, path, &body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func searchTestProducts(t *testing.T, query string) Products {
+	t.Helper()
+	w := productRequest(t, http.MethodGet, "/products?q="+url.QueryEscape(query), nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var found Products
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &found))
+	return found
+}
+
+func TestProducts(t *testing.T) {
+	var duplex Product
+
+	t.Run("community items shared by enough users become products", func(t *testing.T) {
+		w := productRequest(t, http.MethodPost, "/admin/products/community", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var result CommunityImportResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.GreaterOrEqual(t, result.Linked, 4)
+
+		found := searchTestProducts(t, strings.ToLower(testBrand)+" dup")
+		require.Len(t, found, 1)
+		duplex = found[0]
+		assert.Equal(t, testBrand+" Duplex", duplex.Name)
+		assert.Equal(t, SourceCommunity, duplex.Source)
+		assert.Equal(t, 4, duplex.ItemCount)
+		require.NotNil(t, duplex.MedianWeight)
+		assert.Equal(t, 560, *duplex.MedianWeight)
+
+		assert.Empty(t, searchTestProducts(t, testBrand+" stove"))
+	})
+
+	t.Run("admin import updates and creates products", func(t *testing.T) {
+		weight := 539
+		w := productRequest(t, http.MethodPost, "/admin/products/import", ProductImportRequest{
+			Products: []ProductInput{
+				{Brand: strings.ToLower(testBrand), Model: "DUPLEX", Weight: &weight, URL: "https://example.com/duplex"},
+				{Brand: testBrand, Model: "Stove", Variant: "Ti"},
+			},
+		})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var result ProductImportResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, ProductImportResult{Created: 1, Updated: 1}, result)
+
+		w = productRequest(t, http.MethodGet, "/products/"+uintString(duplex.ID), nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var updated Product
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+		assert.Equal(t, SourceAdmin, updated.Source)
+		assert.Equal(t, "DUPLEX", updated.Model)
+		assert.Equal(t, &weight, updated.Weight)
+		assert.Equal(t, 4, updated.ItemCount)
+	})
+
+	t.Run("search rejects short queries and bad limits", func(t *testing.T) {
+		w := productRequest(t, http.MethodGet, "/products?q=z", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = productRequest(t, http.MethodGet, "/products?q=zpacks&limit=500", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("delete unlinks the items", func(t *testing.T) {
+		w := productRequest(t, http.MethodDelete, "/admin/products/"+uintString(duplex.ID), nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		w = productRequest(t, http.MethodGet, "/products/"+uintString(duplex.ID), nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		var linked int
+		require.NoError(t, database.DB().QueryRowContext(context.Background(),
+			`SELECT COUNT(*) FROM inventory WHERE item_name ILIKE $1 AND product_id IS NOT NULL`,
+			testBrand+"%duplex").Scan(&linked))
+		assert.Zero(t, linked)
+	})
+}
+
+func uintString(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
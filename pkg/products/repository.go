@@ -0,0 +1,238 @@
+package products
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/lib/pq"
+)
+
+// medianWeightQuery is the median weight of the measured items of product p,
+// leaving out the wishlist items and the weights filled in from the product
+const medianWeightQuery = `(SELECT PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY i.weight)
+		FROM inventory i
+		WHERE i.product_id = p.id AND i.weight > 0 AND NOT i.wishlist AND NOT i.weight_from_product)`
+
+const productColumns = `p.id, p.brand, p.model, p.variant, p.weight, p.url, p.source,
+	(SELECT COUNT(*) FROM inventory i WHERE i.product_id = p.id) AS item_count,
+	` + medianWeightQuery + ` AS median_weight,
+	p.created_at, p.updated_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanProduct(row rowScanner) (Product, error) {
+	var p Product
+	var median sql.NullFloat64
+	err := row.Scan(&p.ID, &p.Brand, &p.Model, &p.Variant, &p.Weight, &p.URL, &p.Source, &p.ItemCount,
+		&median, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return p, err
+	}
+	p.Name = productName(p.Brand, p.Model, p.Variant)
+	if median.Valid {
+		weight := int(math.Round(median.Float64))
+		p.MedianWeight = &weight
+	}
+	return p, nil
+}
+
+// searchProducts returns the products whose name contains every term, the
+// most used first
+func searchProducts(ctx context.Context, terms []string, limit int) (Products, error) {
+	rows, err := database.DB().QueryContext(ctx,
+		`SELECT `+productColumns+`
+		FROM product p
+		WHERE NOT EXISTS (
+			SELECT 1 FROM UNNEST($1::text[]) AS t(term)
+			WHERE (p.brand || ' ' || p.model || ' ' || p.variant) NOT ILIKE '%' || t.term || '%'
+		)
+		ORDER BY item_count DESC, LOWER(p.brand), LOWER(p.model), LOWER(p.variant)
+		LIMIT $2;`,
+		pq.Array(terms), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search products: %w", err)
+	}
+	defer rows.Close()
+
+	products := Products{}
+	for rows.Next() {
+		p, err := scanProduct(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product: %w", err)
+		}
+		products = append(products, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate products: %w", err)
+	}
+	return products, nil
+}
+
+func findProductByID(ctx context.Context, id uint) (*Product, error) {
+	p, err := scanProduct(database.DB().QueryRowContext(ctx,
+		`SELECT `+productColumns+` FROM product p WHERE p.id = $1;`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrProductNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query product: %w", err)
+	}
+	return &p, nil
+}
+
+func returnMedianWeights(ctx context.Context, ids []uint) (map[uint]int, error) {
+	productIDs := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		productIDs = append(productIDs, int64(id))
+	}
+	rows, err := database.DB().QueryContext(ctx,
+		`SELECT p.id, `+medianWeightQuery+`
+		FROM product p WHERE p.id = ANY($1);`,
+		pq.Array(productIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query median weights: %w", err)
+	}
+	defer rows.Close()
+
+	medians := map[uint]int{}
+	for rows.Next() {
+		var id uint
+		var median sql.NullFloat64
+		if err := rows.Scan(&id, &median); err != nil {
+			return nil, fmt.Errorf("failed to scan median weight: %w", err)
+		}
+		if median.Valid {
+			medians[id] = int(math.Round(median.Float64))
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate median weights: %w", err)
+	}
+	return medians, nil
+}
+
+// upsertProducts creates or updates the products of an admin import, matched
+// by brand, model and variant ignoring case
+func upsertProducts(ctx context.Context, inputs []ProductInput) (*ProductImportResult, error) {
+	tx, err := database.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result := &ProductImportResult{}
+	for _, p := range inputs {
+		var inserted bool
+		err := tx.QueryRowContext(ctx,
+			`INSERT INTO product (brand, model, variant, weight, url, source)
+			VALUES ($1, $2, $3, $4, $5, 'admin')
+			ON CONFLICT (LOWER(brand), LOWER(model), LOWER(variant)) DO UPDATE
+			SET brand = EXCLUDED.brand, model = EXCLUDED.model, variant = EXCLUDED.variant,
+				weight = EXCLUDED.weight, url = EXCLUDED.url, source = 'admin', updated_at = NOW()
+			RETURNING xmax = 0;`,
+			p.Brand, p.Model, p.Variant, p.Weight, p.URL).Scan(&inserted)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upsert product: %w", err)
+		}
+		if inserted {
+			result.Created++
+		} else {
+			result.Updated++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit product import: %w", err)
+	}
+	return result, nil
+}
+
+// importCommunityProducts creates the products found in the inventories of
+// several users and links their unlinked items to them. Names matching an
+// existing product link the items to it.
+func importCommunityProducts(ctx context.Context) (*CommunityImportResult, error) {
+	tx, err := database.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT id, user_id, item_name FROM inventory WHERE product_id IS NULL AND NOT wishlist;`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query unlinked items: %w", err)
+	}
+	var items []communityItem
+	for rows.Next() {
+		var item communityItem
+		if err := rows.Scan(&item.id, &item.userID, &item.itemName); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan unlinked item: %w", err)
+		}
+		items = append(items, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate unlinked items: %w", err)
+	}
+
+	result := &CommunityImportResult{}
+	for _, found := range findCommunityProducts(items) {
+		var id uint
+		var inserted bool
+		err := tx.QueryRowContext(ctx,
+			`INSERT INTO product (brand, model, source) VALUES ($1, $2, 'community')
+			ON CONFLICT (LOWER(brand), LOWER(model), LOWER(variant)) DO UPDATE SET updated_at = product.updated_at
+			RETURNING id, xmax = 0;`,
+			found.brand, found.model).Scan(&id, &inserted)
+		if err != nil {
+			return nil, fmt.Errorf("failed to insert community product: %w", err)
+		}
+		if inserted {
+			result.Created++
+		}
+
+		itemIDs := make([]int64, 0, len(found.itemIDs))
+		for _, itemID := range found.itemIDs {
+			itemIDs = append(itemIDs, int64(itemID))
+		}
+		res, err := tx.ExecContext(ctx,
+			`UPDATE inventory SET product_id = $1 WHERE id = ANY($2) AND product_id IS NULL;`,
+			id, pq.Array(itemIDs))
+		if err != nil {
+			return nil, fmt.Errorf("failed to link items: %w", err)
+		}
+		linked, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("failed to count linked items: %w", err)
+		}
+		result.Linked += int(linked)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit community products: %w", err)
+	}
+	return result, nil
+}
+
+// deleteProductByID deletes a product; its items are unlinked
+func deleteProductByID(ctx context.Context, id uint) error {
+	res, err := database.DB().ExecContext(ctx, `DELETE FROM product WHERE id = $1;`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete product: %w", err)
+	}
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to count deleted products: %w", err)
+	}
+	if deleted == 0 {
+		return ErrProductNotFound
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package products
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxSearchTerms caps the words of a search query
+const maxSearchTerms = 5
+
+// FindProductByID retrieves a product with its community statistics
+func FindProductByID(ctx context.Context, id uint) (*Product, error) {
+	return findProductByID(ctx, id)
+}
+
+// ReturnMedianWeights returns the community median weight of the products
+// that have measured items, by product ID
+func ReturnMedianWeights(ctx context.Context, ids []uint) (map[uint]int, error) {
+	if len(ids) == 0 {
+		return map[uint]int{}, nil
+	}
+	return returnMedianWeights(ctx, ids)
+}
+
+// productName joins the brand, model and variant of a product
+func productName(brand, model, variant string) string {
+	return strings.TrimSpace(brand + " " + model + " " + variant)
+}
+
+// identityKey matches products by brand, model and variant ignoring case
+func identityKey(brand, model, variant string) string {
+	return strings.ToLower(brand) + "\x00" + strings.ToLower(model) + "\x00" + strings.ToLower(variant)
+}
+
+// validateImport trims the products of an admin import and checks them
+func validateImport(inputs []ProductInput) error {
+	if len(inputs) > maxImportProducts {
+		return ErrTooManyProducts
+	}
+	seen := map[string]bool{}
+	for i := range inputs {
+		p := &inputs[i]
+		p.Brand = strings.TrimSpace(p.Brand)
+		p.Model = strings.TrimSpace(p.Model)
+		p.Variant = strings.TrimSpace(p.Variant)
+		p.URL = strings.TrimSpace(p.URL)
+		if p.Brand == "" || p.Model == "" {
+			return ErrEmptyProductName
+		}
+		if p.Weight != nil && *p.Weight < 0 {
+			return ErrInvalidWeight
+		}
+		key := identityKey(p.Brand, p.Model, p.Variant)
+		if seen[key] {
+			return ErrDuplicateProducts
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// searchTerms splits a search query into the words every result must contain
+func searchTerms(query string) ([]string, error) {
+	query = strings.TrimSpace(query)
+	if utf8.RuneCountInString(query) < 2 {
+		return nil, ErrShortSearchQuery
+	}
+	terms := strings.Fields(query)
+	if len(terms) > maxSearchTerms {
+		terms = terms[:maxSearchTerms]
+	}
+	for i, term := range terms {
+		terms[i] = escapeLike(term)
+	}
+	return terms, nil
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// communityItem is an inventory item not yet linked to a product
+type communityItem struct {
+	id       uint
+	userID   uint
+	itemName string
+}
+
+// communityProduct is a product found in the inventories of several users
+type communityProduct struct {
+	brand, model string
+	itemIDs      []uint
+}
+
+// findCommunityProducts groups items by name, ignoring case and spacing, and
+// returns the names owned by at least minCommunityUsers users. The first word
+// of the most common spelling is taken as the brand and the rest as the model,
+// so single-word names are left out.
+func findCommunityProducts(items []communityItem) []communityProduct {
+	type group struct {
+		users     map[uint]bool
+		spellings map[string]int
+		itemIDs   []uint
+	}
+	groups := map[string]*group{}
+	for _, item := range items {
+		words := strings.Fields(item.itemName)
+		if len(words) < 2 {
+			continue
+		}
+		spelling := strings.Join(words, " ")
+		key := strings.ToLower(spelling)
+		g, ok := groups[key]
+		if !ok {
+			g = &group{users: map[uint]bool{}, spellings: map[string]int{}}
+			groups[key] = g
+		}
+		g.users[item.userID] = true
+		g.spellings[spelling]++
+		g.itemIDs = append(g.itemIDs, item.id)
+	}
+
+	var found []communityProduct
+	for _, g := range groups {
+		if len(g.users) < minCommunityUsers {
+			continue
+		}
+		spelling := ""
+		for s, count := range g.spellings {
+			if count > g.spellings[spelling] || (count == g.spellings[spelling] && s < spelling) {
+				spelling = s
+			}
+		}
+		brand, model, _ := strings.Cut(spelling, " ")
+		found = append(found, communityProduct{brand: brand, model: model, itemIDs: g.itemIDs})
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return identityKey(found[i].brand, found[i].model, "") < identityKey(found[j].brand, found[j].model, "")
+	})
+	return found
+}
@@ -0,0 +1,104 @@
+package products
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Angak0k/pimpmypack/pkg/accounts"
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/gruntwork-io/terratest/modules/random"
+)
+
+// testBrand makes the product names of the tests unique in the shared database
+var testBrand = "Brand" + random.UniqueID()
+
+var testUsers = []accounts.User{
+	{
+		Username:  "productuser-" + random.UniqueID(),
+		Email:     "productuser-" + random.UniqueID() + "@example.com",
+		Firstname: "Product",
+		Lastname:  "Tester",
+		Role:      "standard",
+		Status:    "active",
+	},
+	{
+		Username:  "productuser2-" + random.UniqueID(),
+		Email:     "productuser2-" + random.UniqueID() + "@example.com",
+		Firstname: "Product",
+		Lastname:  "Tester",
+		Role:      "standard",
+		Status:    "active",
+	},
+	{
+		Username:  "productuser3-" + random.UniqueID(),
+		Email:     "productuser3-" + random.UniqueID() + "@example.com",
+		Firstname: "Product",
+		Lastname:  "Tester",
+		Role:      "standard",
+		Status:    "active",
+	},
+}
+
+// testItems are inventory items by owner index, name and measured weight: the
+// tent is owned by three users, the stove by two only
+var testItems = []struct {
+	user   int
+	name   string
+	weight int
+}{
+	{0, testBrand + " Duplex", 540},
+	{1, testBrand + "  duplex", 560},
+	{2, testBrand + " Duplex", 0},
+	{2, testBrand + " Duplex", 610},
+	{0, testBrand + " Stove", 80},
+	{1, testBrand + " Stove", 85},
+}
+
+func loadingProductDataset() error {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	for i := range testUsers {
+		err := database.DB().QueryRowContext(ctx,
+			`INSERT INTO account (username, email, firstname, lastname, role, status, created_at, updated_at)
+			VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+			RETURNING id;`,
+			testUsers[i].Username, testUsers[i].Email,
+			testUsers[i].Firstname, testUsers[i].Lastname,
+			testUsers[i].Role, testUsers[i].Status, now, now).Scan(&testUsers[i].ID)
+		if err != nil {
+			return fmt.Errorf("failed to insert user: %w", err)
+		}
+	}
+
+	for _, item := range testItems {
+		_, err := database.DB().ExecContext(ctx,
+			`INSERT INTO inventory (user_id, item_name, category, description, weight, url, price, currency,
+				created_at, updated_at)
+			VALUES ($1, $2, 'Gear', '', $3, '', 0, 'EUR', $4, $5);`,
+			testUsers[item.user].ID, item.name, item.weight, now, now)
+		if err != nil {
+			return fmt.Errorf("failed to insert inventory item %q: %w", item.name, err)
+		}
+	}
+	return nil
+}
+
+// cleanupProductDataset deletes the test users, their items cascading, and the
+// products of the test brand
+func cleanupProductDataset() error {
+	ctx := context.Background()
+	for _, user := range testUsers {
+		if user.ID == 0 {
+			continue
+		}
+		if _, err := database.DB().ExecContext(ctx, "DELETE FROM account WHERE id = $1", user.ID); err != nil {
+			return fmt.Errorf("failed to delete user %d: %w", user.ID, err)
+		}
+	}
+	if _, err := database.DB().ExecContext(ctx, "DELETE FROM product WHERE brand ILIKE $1", testBrand); err != nil {
+		return fmt.Errorf("failed to delete products: %w", err)
+	}
+	return nil
+}
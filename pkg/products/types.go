@@ -0,0 +1,82 @@
+package products
+
+import (
+	"errors"
+	"time"
+)
+
+// Product sources
+const (
+	SourceAdmin     = "admin"
+	SourceCommunity = "community"
+)
+
+const (
+	// defaultSearchLimit and maxSearchLimit bound the results of a search
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+	// minCommunityUsers is the number of users who must own an item before it
+	// becomes a community product
+	minCommunityUsers = 3
+	// maxImportProducts caps the products of an admin import
+	maxImportProducts = 5000
+)
+
+// Domain errors
+var (
+	ErrProductNotFound   = errors.New("product not found")
+	ErrEmptyProductName  = errors.New("brand and model must not be empty")
+	ErrInvalidWeight     = errors.New("weight must not be negative")
+	ErrTooManyProducts   = errors.New("too many products to import")
+	ErrShortSearchQuery  = errors.New("search query must have at least 2 characters")
+	ErrDuplicateProducts = errors.New("the import lists the same product twice")
+)
+
+// Product is an entry of the community gear catalog. Weight is the official
+// weight; MedianWeight is the median weight of the inventory items linked to
+// the product, as measured by their owners.
+type Product struct {
+	ID           uint      `json:"id"`
+	Brand        string    `json:"brand"`
+	Model        string    `json:"model"`
+	Variant      string    `json:"variant"`
+	Name         string    `json:"name"`
+	Weight       *int      `json:"weight,omitempty"`
+	URL          string    `json:"url"`
+	Source       string    `json:"source" enums:"admin,community"`
+	ItemCount    int       `json:"item_count"`
+	MedianWeight *int      `json:"median_weight,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Products represents a collection of products
+type Products []Product
+
+// ProductInput is one product of an admin import
+type ProductInput struct {
+	Brand   string `json:"brand" binding:"required" example:"Zpacks"`
+	Model   string `json:"model" binding:"required" example:"Duplex"`
+	Variant string `json:"variant" example:"Dyneema"`
+	Weight  *int   `json:"weight" example:"539"`
+	URL     string `json:"url"`
+}
+
+// ProductImportRequest creates or updates a set of products, matched by
+// brand, model and variant ignoring case
+type ProductImportRequest struct {
+	Products []ProductInput `json:"products" binding:"required,min=1,dive"`
+}
+
+// ProductImportResult counts the products of an admin import
+type ProductImportResult struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+}
+
+// CommunityImportResult counts the products created from community inventory
+// and the inventory items linked to products
+type CommunityImportResult struct {
+	Created int `json:"created"`
+	Linked  int `json:"linked"`
+}
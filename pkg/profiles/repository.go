@@ -59,10 +59,10 @@ func returnSharedPacksByUsername(ctx context.Context, username string) ([]Shared
 	rows, err := database.DB().QueryContext(ctx, `
 		SELECT p.id, p.pack_name, p.pack_description,
 		    CASE WHEN pi.pack_id IS NOT NULL THEN true ELSE false END AS has_image,
-		    COALESCE(SUM(i.weight * pc.quantity), 0) AS pack_weight,
-		    COALESCE(SUM(CASE WHEN pc.worn = false AND pc.consumable = false
-		        THEN i.weight * pc.quantity ELSE 0 END), 0) AS base_weight,
-		    COALESCE(SUM(pc.quantity), 0) AS pack_items_count,
+		    COALESCE(SUM(i.weight * pc.quantity) FILTER (WHERE NOT pc.optional AND NOT i.wishlist), 0) AS pack_weight,
+		    COALESCE(SUM(CASE WHEN pc.worn = false AND pc.consumable = false AND pc.optional = false
+		        AND i.wishlist = false THEN i.weight * pc.quantity ELSE 0 END), 0) AS base_weight,
+		    COALESCE(SUM(pc.quantity) FILTER (WHERE NOT pc.optional AND NOT i.wishlist), 0) AS pack_items_count,
 		    p.sharing_code, p.season, COALESCE(t.name, p.trail) as trail, p.adventure, p.created_at
 		FROM pack p
 		JOIN account a ON p.user_id = a.id
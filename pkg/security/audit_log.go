@@ -1,9 +1,11 @@
 package security
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -22,10 +24,56 @@ const (
 	EventLogoutAll         AuditEventType = "logout_all_success"
 	EventRefreshReuse      AuditEventType = "refresh_token_reuse_detected"
 	EventRateLimitExceeded AuditEventType = "rate_limit_exceeded"
+	EventLoginThrottled    AuditEventType = "login_throttled"
+	EventAccountLocked     AuditEventType = "account_locked"
+	EventAccountUnlocked   AuditEventType = "account_unlocked"
+	EventOIDCAccountLinked AuditEventType = "oidc_account_linked"
+	EventAccountDeletion   AuditEventType = "account_deletion_requested"
+	EventAccountRestored   AuditEventType = "account_restored"
+	EventEmailChanged      AuditEventType = "email_changed"
 )
 
+// auditPersistTimeout bounds the database write of a single audit event, so a
+// slow database cannot stall the request that triggered the event
+const auditPersistTimeout = 5 * time.Second
+
+// Rate limit events are sampled: under a flood every rejected request would
+// otherwise become a database write, amplifying the load the limiter sheds.
+const (
+	// rateLimitAuditWindow is the sampling window of rate limit events
+	rateLimitAuditWindow = time.Minute
+	// maxRateLimitAuditsPerWindow caps the rate limit events recorded per window
+	maxRateLimitAuditsPerWindow = 100
+)
+
+// rateLimitAuditSampler records the first rate limit event of each IP and
+// endpoint per window, up to maxRateLimitAuditsPerWindow events in all
+type rateLimitAuditSampler struct {
+	mu          sync.Mutex
+	windowStart time.Time
+	seen        map[string]bool
+}
+
+var rateLimitAudits = &rateLimitAuditSampler{}
+
+// allow reports whether the event of key is recorded at now
+func (s *rateLimitAuditSampler) allow(key string, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seen == nil || now.Sub(s.windowStart) >= rateLimitAuditWindow {
+		s.windowStart = now
+		s.seen = map[string]bool{}
+	}
+	if s.seen[key] || len(s.seen) >= maxRateLimitAuditsPerWindow {
+		return false
+	}
+	s.seen[key] = true
+	return true
+}
+
 // AuditEvent represents a security audit event
 type AuditEvent struct {
+	ID         int64          `json:"id,omitempty"`
 	Timestamp  time.Time      `json:"timestamp"`
 	EventType  AuditEventType `json:"event_type"`
 	UserID     *uint          `json:"user_id,omitempty"`
@@ -36,7 +84,9 @@ type AuditEvent struct {
 	RememberMe bool           `json:"remember_me,omitempty"`
 }
 
-// logAuditEvent logs an audit event as structured JSON
+// logAuditEvent logs an audit event as structured JSON and persists it to the
+// audit_event table. Persistence is best-effort: a database failure is logged
+// but never fails the request being audited, the log line remains the fallback.
 func logAuditEvent(event AuditEvent) {
 	event.Timestamp = time.Now().UTC()
 
@@ -47,6 +97,12 @@ func logAuditEvent(event AuditEvent) {
 	}
 
 	log.Printf("[AUDIT] %s", string(jsonData))
+
+	ctx, cancel := context.WithTimeout(context.Background(), auditPersistTimeout)
+	defer cancel()
+	if err := insertAuditEvent(ctx, &event); err != nil {
+		log.Printf("[ERROR] Failed to persist audit event: %v", err)
+	}
 }
 
 // AuditLoginSuccess logs a successful login attempt
@@ -61,10 +117,16 @@ func AuditLoginSuccess(c *gin.Context, userID uint, rememberMe bool) {
 	})
 }
 
-// AuditLoginFailed logs a failed login attempt
-func AuditLoginFailed(c *gin.Context, username, reason string) {
+// AuditLoginFailed logs a failed login attempt. userID is 0 when the
+// submitted username matched no account.
+func AuditLoginFailed(c *gin.Context, userID uint, username, reason string) {
+	var accountID *uint
+	if userID != 0 {
+		accountID = &userID
+	}
 	logAuditEvent(AuditEvent{
 		EventType: EventLoginFailed,
+		UserID:    accountID,
 		Username:  username,
 		IP:        c.ClientIP(),
 		UserAgent: c.Request.UserAgent(),
@@ -72,6 +134,96 @@ func AuditLoginFailed(c *gin.Context, username, reason string) {
 	})
 }
 
+// AuditLoginThrottled logs a login attempt rejected by the account lock or
+// the progressive delay, before the password was checked
+func AuditLoginThrottled(c *gin.Context, userID uint, username string, throttled *LoginThrottledError) {
+	var accountID *uint
+	if userID != 0 {
+		accountID = &userID
+	}
+	logAuditEvent(AuditEvent{
+		EventType: EventLoginThrottled,
+		UserID:    accountID,
+		Username:  username,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Message:   fmt.Sprintf("%s (retry after %ds)", throttled.Error(), throttled.RetryAfterSeconds()),
+	})
+}
+
+// auditAccountLocked logs an account lock triggered by repeated failed logins.
+// It is emitted from RecordLoginFailure, which has no request context.
+func auditAccountLocked(userID uint, ip string, lockedUntil time.Time) {
+	logAuditEvent(AuditEvent{
+		EventType: EventAccountLocked,
+		UserID:    &userID,
+		IP:        ip,
+		Message:   "Account locked after repeated failed logins until " + lockedUntil.UTC().Format(time.RFC3339),
+	})
+}
+
+// AuditAccountUnlocked logs the early lift of an account lock; via is
+// "admin" or "email"
+func AuditAccountUnlocked(c *gin.Context, userID uint, via string) {
+	logAuditEvent(AuditEvent{
+		EventType: EventAccountUnlocked,
+		UserID:    &userID,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Message:   "Account unlocked by " + via,
+	})
+}
+
+// AuditOIDCAccountLinked logs an external identity being linked to an
+// account, either an existing one matched by verified email or one
+// provisioned at first login
+func AuditOIDCAccountLinked(c *gin.Context, userID uint, issuer string, provisioned bool) {
+	message := "OIDC identity of " + issuer + " linked to existing account"
+	if provisioned {
+		message = "Account provisioned for OIDC identity of " + issuer
+	}
+	logAuditEvent(AuditEvent{
+		EventType: EventOIDCAccountLinked,
+		UserID:    &userID,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Message:   message,
+	})
+}
+
+// AuditAccountDeletionRequested logs a user scheduling the deletion of their account
+func AuditAccountDeletionRequested(c *gin.Context, userID uint, scheduledAt time.Time) {
+	logAuditEvent(AuditEvent{
+		EventType: EventAccountDeletion,
+		UserID:    &userID,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Message:   "Account deletion scheduled for " + scheduledAt.UTC().Format(time.RFC3339),
+	})
+}
+
+// AuditAccountRestored logs a user restoring their account during the deletion grace period
+func AuditAccountRestored(c *gin.Context, userID uint) {
+	logAuditEvent(AuditEvent{
+		EventType: EventAccountRestored,
+		UserID:    &userID,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Message:   "Account restored, deletion cancelled",
+	})
+}
+
+// AuditEmailChanged logs the confirmation of an email change
+func AuditEmailChanged(c *gin.Context, userID uint) {
+	logAuditEvent(AuditEvent{
+		EventType: EventEmailChanged,
+		UserID:    &userID,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Message:   "Email address changed after confirmation of the new address",
+	})
+}
+
 // AuditRefreshSuccess logs a successful token refresh
 func AuditRefreshSuccess(c *gin.Context, userID uint) {
 	logAuditEvent(AuditEvent{
@@ -128,8 +280,12 @@ func AuditRefreshReuseDetected(c *gin.Context, userID uint) {
 	})
 }
 
-// AuditRateLimitExceeded logs a rate limit exceeded event
+// AuditRateLimitExceeded logs a rate limit exceeded event, once per IP and
+// endpoint per rateLimitAuditWindow
 func AuditRateLimitExceeded(c *gin.Context, endpoint string) {
+	if !rateLimitAudits.allow(c.ClientIP()+" "+endpoint, time.Now()) {
+		return
+	}
 	logAuditEvent(AuditEvent{
 		EventType: EventRateLimitExceeded,
 		IP:        c.ClientIP(),
@@ -0,0 +1,202 @@
+package security
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Angak0k/pimpmypack/pkg/config"
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAuditContext returns a gin context carrying a request from the given IP
+func newAuditContext(ip string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/login", nil)
+	c.Request.RemoteAddr = ip + ":12345"
+	c.Request.Header.Set("User-Agent", "audit-test")
+	return c
+}
+
+func setupAuditTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	r.GET("/admin/audit", GetAuditEvents)
+	r.GET("/v1/myaccount/security-events", JwtAuthProcessor(), GetMySecurityEvents)
+	return r
+}
+
+func getWithToken(t *testing.T, router *gin.Engine, path, accessToken string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuditEventPersisted(t *testing.T) {
+	ctx := context.Background()
+	accountID := createTestAccount(t)
+
+	AuditLoginSuccess(newAuditContext("192.0.2.10"), accountID, true)
+
+	events, err := returnAuditEvents(ctx, AuditEventFilter{UserID: &accountID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, EventLoginSuccess, events[0].EventType)
+	assert.Equal(t, "192.0.2.10", events[0].IP)
+	assert.Equal(t, "audit-test", events[0].UserAgent)
+	assert.True(t, events[0].RememberMe)
+	require.NotNil(t, events[0].UserID)
+	assert.Equal(t, accountID, *events[0].UserID)
+}
+
+func TestAuditEventAppendOnly(t *testing.T) {
+	accountID := createTestAccount(t)
+	AuditLogout(newAuditContext("192.0.2.11"), accountID)
+
+	_, err := database.DB().Exec(`UPDATE audit_event SET message = 'tampered' WHERE account_id = $1`, accountID)
+	require.Error(t, err)
+}
+
+func TestReturnAuditEvents_Filters(t *testing.T) {
+	ctx := context.Background()
+	accountID := createTestAccount(t)
+	username := "audit_filter_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	AuditLoginFailed(newAuditContext("198.51.100.7"), accountID, username, "invalid credentials")
+	AuditLoginSuccess(newAuditContext("198.51.100.8"), accountID, false)
+
+	t.Run("by event type", func(t *testing.T) {
+		events, err := returnAuditEvents(ctx, AuditEventFilter{
+			UserID: &accountID, EventType: string(EventLoginFailed), Limit: 10,
+		})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, username, events[0].Username)
+	})
+
+	t.Run("by IP", func(t *testing.T) {
+		events, err := returnAuditEvents(ctx, AuditEventFilter{UserID: &accountID, IP: "198.51.100.8", Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, EventLoginSuccess, events[0].EventType)
+	})
+
+	t.Run("by time range", func(t *testing.T) {
+		future := time.Now().Add(time.Hour)
+		events, err := returnAuditEvents(ctx, AuditEventFilter{UserID: &accountID, From: &future, Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, events)
+	})
+
+	t.Run("most recent first with limit", func(t *testing.T) {
+		events, err := returnAuditEvents(ctx, AuditEventFilter{UserID: &accountID, Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, EventLoginSuccess, events[0].EventType)
+	})
+}
+
+func TestCleanupExpiredAuditEvents(t *testing.T) {
+	ctx := context.Background()
+	accountID := createTestAccount(t)
+
+	_, err := database.DB().Exec(
+		`INSERT INTO audit_event (created_at, event_type, account_id, ip, message)
+         VALUES ($1, $2, $3, $4, $5)`,
+		time.Now().Add(-time.Hour*24*time.Duration(config.AuditRetentionDays+1)),
+		EventLoginSuccess, accountID, "192.0.2.12", "old event",
+	)
+	require.NoError(t, err)
+	AuditLoginSuccess(newAuditContext("192.0.2.12"), accountID, false)
+
+	deleted, err := CleanupExpiredAuditEvents(ctx)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, deleted, int64(1))
+
+	events, err := returnAuditEvents(ctx, AuditEventFilter{UserID: &accountID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "User logged in successfully", events[0].Message)
+}
+
+func TestGetMySecurityEvents_OnlyOwnEvents(t *testing.T) {
+	router := setupAuditTestRouter()
+	accountID := createTestAccount(t)
+	otherID := createTestAccount(t)
+
+	AuditLoginSuccess(newAuditContext("203.0.113.1"), accountID, false)
+	AuditLoginSuccess(newAuditContext("203.0.113.2"), otherID, false)
+
+	accessToken, err := GenerateToken(accountID)
+	require.NoError(t, err)
+
+	w := getWithToken(t, router, "/v1/myaccount/security-events", accessToken)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var events AuditEvents
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &events))
+	require.Len(t, events, 1)
+	require.NotNil(t, events[0].UserID)
+	assert.Equal(t, accountID, *events[0].UserID)
+	assert.Equal(t, "203.0.113.1", events[0].IP)
+}
+
+func TestGetMySecurityEvents_RequiresAuth(t *testing.T) {
+	router := setupAuditTestRouter()
+
+	w := getWithToken(t, router, "/v1/myaccount/security-events", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestGetAuditEvents_FilterByUser(t *testing.T) {
+	router := setupAuditTestRouter()
+	accountID := createTestAccount(t)
+	AuditLogoutAll(newAuditContext("203.0.113.3"), accountID, 2)
+
+	w := getWithToken(t, router, "/admin/audit?user_id="+strconv.FormatUint(uint64(accountID), 10)+
+		"&event_type="+string(EventLogoutAll), "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var events AuditEvents
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &events))
+	require.Len(t, events, 1)
+	assert.Equal(t, EventLogoutAll, events[0].EventType)
+}
+
+func TestGetAuditEvents_InvalidFilters(t *testing.T) {
+	router := setupAuditTestRouter()
+
+	for _, query := range []string{"user_id=abc", "from=yesterday", "limit=0", "limit=10000", "offset=-1"} {
+		w := getWithToken(t, router, "/admin/audit?"+query, "")
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestRateLimitAuditSampler(t *testing.T) {
+	s := &rateLimitAuditSampler{}
+	now := time.Now()
+
+	assert.True(t, s.allow("192.0.2.1 /api/login", now))
+	assert.False(t, s.allow("192.0.2.1 /api/login", now.Add(time.Second)), "one event per IP and endpoint")
+	assert.True(t, s.allow("192.0.2.1 /api/register", now))
+	assert.True(t, s.allow("192.0.2.1 /api/login", now.Add(rateLimitAuditWindow)), "a new window records again")
+
+	for i := range maxRateLimitAuditsPerWindow + 10 {
+		s.allow(strconv.Itoa(i), now.Add(rateLimitAuditWindow))
+	}
+	assert.Len(t, s.seen, maxRateLimitAuditsPerWindow)
+	assert.False(t, s.allow("198.51.100.1 /api/login", now.Add(rateLimitAuditWindow)))
+}
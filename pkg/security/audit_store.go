@@ -0,0 +1,129 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Angak0k/pimpmypack/pkg/config"
+	"github.com/Angak0k/pimpmypack/pkg/database"
+)
+
+// insertAuditEvent appends an audit event to the audit_event table.
+// It is a no-op when no database is initialized (e.g. unit tests that
+// exercise middleware without a database).
+func insertAuditEvent(ctx context.Context, event *AuditEvent) error {
+	db := database.DB()
+	if db == nil {
+		return nil
+	}
+
+	err := db.QueryRowContext(ctx,
+		`INSERT INTO audit_event (created_at, event_type, account_id, username, ip, user_agent, message, remember_me)
+         VALUES ($1, $2, $3, NULLIF($4, ''), $5, NULLIF($6, ''), $7, $8)
+         RETURNING id`,
+		event.Timestamp, event.EventType, event.UserID, event.Username, event.IP,
+		event.UserAgent, event.Message, event.RememberMe,
+	).Scan(&event.ID)
+	if err != nil {
+		return fmt.Errorf("failed to insert audit event: %w", err)
+	}
+
+	return nil
+}
+
+// returnAuditEvents returns the audit events matching the filter, most recent first
+func returnAuditEvents(ctx context.Context, filter AuditEventFilter) (AuditEvents, error) {
+	var conditions []string
+	var args []any
+
+	addCondition := func(clause string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf("%s $%d", clause, len(args)))
+	}
+
+	if filter.UserID != nil {
+		addCondition("account_id =", *filter.UserID)
+	}
+	if filter.EventType != "" {
+		addCondition("event_type =", filter.EventType)
+	}
+	if filter.IP != "" {
+		addCondition("ip =", filter.IP)
+	}
+	if filter.From != nil {
+		addCondition("created_at >=", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("created_at <", *filter.To)
+	}
+
+	query := `SELECT id, created_at, event_type, account_id, COALESCE(username, ''), ip,
+            COALESCE(user_agent, ''), message, remember_me
+        FROM audit_event`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := database.DB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit events: %w", err)
+	}
+	defer rows.Close()
+
+	events := AuditEvents{}
+	for rows.Next() {
+		var event AuditEvent
+		var accountID *uint
+		err := rows.Scan(
+			&event.ID,
+			&event.Timestamp,
+			&event.EventType,
+			&accountID,
+			&event.Username,
+			&event.IP,
+			&event.UserAgent,
+			&event.Message,
+			&event.RememberMe,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit event: %w", err)
+		}
+		event.UserID = accountID
+		event.Timestamp = event.Timestamp.UTC()
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate audit events: %w", err)
+	}
+
+	return events, nil
+}
+
+// CleanupExpiredAuditEvents deletes audit events older than the configured
+// retention (AUDIT_RETENTION_DAYS). A retention of 0 or less keeps events forever.
+func CleanupExpiredAuditEvents(ctx context.Context) (int64, error) {
+	if config.AuditRetentionDays <= 0 {
+		return 0, nil
+	}
+
+	cutoff := time.Now().Add(-time.Hour * 24 * time.Duration(config.AuditRetentionDays))
+	result, err := database.DB().ExecContext(ctx,
+		`DELETE FROM audit_event WHERE created_at < $1`,
+		cutoff,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to cleanup expired audit events: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
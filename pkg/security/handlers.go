@@ -224,3 +224,12 @@ func LogoutAllHandler(c *gin.Context) {
 		RevokedSessions: revoked,
 	})
 }
+
+// JWKSHandler handles GET /.well-known/jwks.json. It publishes the public
+// keys that verify access tokens (EdDSA/RS256 signing), so other services can
+// check PimpMyPack tokens without sharing a secret. The set is empty while
+// tokens are signed with HS256. Served outside /api, hence not in the API docs.
+func JWKSHandler(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, currentKeyring().jwks())
+}
@@ -0,0 +1,151 @@
+package security
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Angak0k/pimpmypack/pkg/helper"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultAuditEventLimit = 100
+	maxAuditEventLimit     = 500
+)
+
+// parseAuditEventFilter reads the audit query parameters shared by the admin
+// and self-service endpoints: event_type, ip, from, to (RFC 3339), limit, offset
+func parseAuditEventFilter(c *gin.Context) (AuditEventFilter, error) {
+	filter := AuditEventFilter{
+		EventType: c.Query("event_type"),
+		IP:        c.Query("ip"),
+		Limit:     defaultAuditEventLimit,
+	}
+
+	if err := parseTimeParam(c, "from", &filter.From); err != nil {
+		return filter, err
+	}
+	if err := parseTimeParam(c, "to", &filter.To); err != nil {
+		return filter, err
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxAuditEventLimit {
+			return filter, errors.New("limit must be between 1 and " + strconv.Itoa(maxAuditEventLimit))
+		}
+		filter.Limit = limit
+	}
+
+	if raw := c.Query("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return filter, errors.New("offset must be a non-negative integer")
+		}
+		filter.Offset = offset
+	}
+
+	return filter, nil
+}
+
+// parseTimeParam parses an optional RFC 3339 query parameter into target,
+// leaving it nil when the parameter is absent
+func parseTimeParam(c *gin.Context, param string, target **time.Time) error {
+	raw := c.Query(param)
+	if raw == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return errors.New(param + " must be an RFC 3339 timestamp")
+	}
+	*target = &t
+	return nil
+}
+
+// GetAuditEvents handles GET /admin/audit
+// @Summary [ADMIN] Query security audit events
+// @Description Query the persisted security audit trail, most recent first - for admin use only
+// @Security Bearer
+// @Tags Internal
+// @Produce json
+// @Param user_id query int false "Account ID"
+// @Param event_type query string false "Event type (e.g. login_failed)"
+// @Param ip query string false "Client IP address"
+// @Param from query string false "Start of the time range (RFC 3339, inclusive)"
+// @Param to query string false "End of the time range (RFC 3339, exclusive)"
+// @Param limit query int false "Maximum number of events (default 100, max 500)"
+// @Param offset query int false "Number of events to skip"
+// @Success 200 {object} AuditEvents
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid filter"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal server error"
+// @Router /admin/audit [get]
+func GetAuditEvents(c *gin.Context) {
+	filter, err := parseAuditEventFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if raw := c.Query("user_id"); raw != "" {
+		userID, err := helper.StringToUint(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id format"})
+			return
+		}
+		filter.UserID = &userID
+	}
+
+	events, err := returnAuditEvents(c.Request.Context(), filter)
+	if err != nil {
+		helper.LogAndSanitize(err, "get audit events: return audit events failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, events)
+}
+
+// GetMySecurityEvents handles GET /v1/myaccount/security-events
+// @Summary Get my security events
+// @Description List the security events (logins, failed logins, logouts, refreshes) of the
+// @Description authenticated user, most recent first
+// @Security Bearer
+// @Tags Accounts
+// @Produce json
+// @Param event_type query string false "Event type (e.g. login_failed)"
+// @Param from query string false "Start of the time range (RFC 3339, inclusive)"
+// @Param to query string false "End of the time range (RFC 3339, exclusive)"
+// @Param limit query int false "Maximum number of events (default 100, max 500)"
+// @Param offset query int false "Number of events to skip"
+// @Success 200 {object} AuditEvents
+// @Failure 400 {object} apitypes.ErrorResponse "Invalid filter"
+// @Failure 401 {object} apitypes.ErrorResponse "Unauthorized"
+// @Failure 500 {object} apitypes.ErrorResponse "Internal server error"
+// @Router /v1/myaccount/security-events [get]
+func GetMySecurityEvents(c *gin.Context) {
+	userID, err := ExtractTokenID(c)
+	if err != nil {
+		helper.LogAndSanitize(err, "get my security events: extract token ID failed")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": helper.ErrMsgUnauthorized})
+		return
+	}
+
+	filter, err := parseAuditEventFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.UserID = &userID
+
+	events, err := returnAuditEvents(c.Request.Context(), filter)
+	if err != nil {
+		helper.LogAndSanitize(err, "get my security events: return audit events failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, events)
+}
@@ -0,0 +1,252 @@
+package security
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/Angak0k/pimpmypack/pkg/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwtKey is a JWT signing or verification key identified by its kid
+type jwtKey struct {
+	kid    string
+	method jwt.SigningMethod
+	// signKey is nil for verification-only (retired) keys
+	signKey   any
+	verifyKey any
+}
+
+// jwtKeyring holds the active signing key and every key accepted for verification
+type jwtKeyring struct {
+	active *jwtKey
+	keys   map[string]*jwtKey
+	// legacy verifies tokens issued without a kid (before key rotation
+	// support); only set while HS256 with API_SECRET is the active key
+	legacy *jwtKey
+}
+
+// loadedKeyring is set by LoadJWTKeys at startup
+var loadedKeyring atomic.Pointer[jwtKeyring]
+
+// currentKeyring returns the keyring loaded at startup or, when LoadJWTKeys
+// was not called (unit tests), an HS256 keyring built from API_SECRET
+func currentKeyring() *jwtKeyring {
+	if ring := loadedKeyring.Load(); ring != nil {
+		return ring
+	}
+	ring, err := hmacKeyring()
+	if err != nil {
+		return &jwtKeyring{keys: map[string]*jwtKey{}}
+	}
+	return ring
+}
+
+func hmacKeyring() (*jwtKeyring, error) {
+	if config.APISecret == "" {
+		return nil, errors.New("API_SECRET is not set")
+	}
+	key := &jwtKey{
+		kid:       config.JWTSigningKeyID,
+		method:    jwt.SigningMethodHS256,
+		signKey:   []byte(config.APISecret),
+		verifyKey: []byte(config.APISecret),
+	}
+	if key.kid == "" {
+		key.kid = "primary"
+	}
+	return &jwtKeyring{active: key, keys: map[string]*jwtKey{key.kid: key}, legacy: key}, nil
+}
+
+// LoadJWTKeys builds the keyring from the configuration: the active signing
+// key (API_SECRET for HS256, JWT_PRIVATE_KEY_FILE for EdDSA/RS256) and the
+// retired keys of JWT_VERIFICATION_KEYS_DIR, which stay accepted until the
+// tokens they signed have expired.
+func LoadJWTKeys() error {
+	var ring *jwtKeyring
+	var err error
+
+	switch config.JWTSigningAlg {
+	case "", jwt.SigningMethodHS256.Alg():
+		ring, err = hmacKeyring()
+	case jwt.SigningMethodEdDSA.Alg(), jwt.SigningMethodRS256.Alg():
+		ring, err = asymmetricKeyring()
+	default:
+		err = fmt.Errorf("unsupported JWT signing algorithm %q", config.JWTSigningAlg)
+	}
+	if err != nil {
+		return err
+	}
+
+	if config.JWTVerificationKeysDir != "" {
+		if err := loadVerificationKeys(ring, config.JWTVerificationKeysDir); err != nil {
+			return err
+		}
+	}
+
+	loadedKeyring.Store(ring)
+	return nil
+}
+
+func asymmetricKeyring() (*jwtKeyring, error) {
+	pemBytes, err := os.ReadFile(config.JWTPrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT private key: %w", err)
+	}
+
+	key := &jwtKey{kid: config.JWTSigningKeyID}
+	switch config.JWTSigningAlg {
+	case jwt.SigningMethodEdDSA.Alg():
+		privateKey, err := jwt.ParseEdPrivateKeyFromPEM(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse EdDSA private key: %w", err)
+		}
+		edKey, ok := privateKey.(ed25519.PrivateKey)
+		if !ok {
+			return nil, errors.New("EdDSA private key is not an Ed25519 key")
+		}
+		key.method, key.signKey, key.verifyKey = jwt.SigningMethodEdDSA, edKey, edKey.Public()
+	default:
+		rsaKey, err := jwt.ParseRSAPrivateKeyFromPEM(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse RS256 private key: %w", err)
+		}
+		key.method, key.signKey, key.verifyKey = jwt.SigningMethodRS256, rsaKey, &rsaKey.PublicKey
+	}
+
+	return &jwtKeyring{active: key, keys: map[string]*jwtKey{key.kid: key}}, nil
+}
+
+// loadVerificationKeys adds the retired keys of dir: <kid>.pem holds an
+// Ed25519 or RSA public key, <kid>.secret an HMAC secret
+func loadVerificationKeys(ring *jwtKeyring, dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read JWT verification keys: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := filepath.Ext(entry.Name())
+		kid := strings.TrimSuffix(entry.Name(), ext)
+		if ext != ".pem" && ext != ".secret" {
+			continue
+		}
+		if _, exists := ring.keys[kid]; exists {
+			return fmt.Errorf("duplicate JWT key id %q", kid)
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to read JWT verification key %s: %w", kid, err)
+		}
+
+		key, err := parseVerificationKey(kid, ext, content)
+		if err != nil {
+			return err
+		}
+		ring.keys[kid] = key
+	}
+
+	return nil
+}
+
+func parseVerificationKey(kid, ext string, content []byte) (*jwtKey, error) {
+	if ext == ".secret" {
+		secret := strings.TrimSpace(string(content))
+		if len(secret) < 32 {
+			return nil, fmt.Errorf("JWT verification secret %s must be at least 32 bytes", kid)
+		}
+		return &jwtKey{kid: kid, method: jwt.SigningMethodHS256, verifyKey: []byte(secret)}, nil
+	}
+
+	if edKey, err := jwt.ParseEdPublicKeyFromPEM(content); err == nil {
+		return &jwtKey{kid: kid, method: jwt.SigningMethodEdDSA, verifyKey: edKey}, nil
+	}
+	if rsaKey, err := jwt.ParseRSAPublicKeyFromPEM(content); err == nil {
+		return &jwtKey{kid: kid, method: jwt.SigningMethodRS256, verifyKey: rsaKey}, nil
+	}
+	return nil, fmt.Errorf("JWT verification key %s is neither an Ed25519 nor an RSA public key", kid)
+}
+
+// validMethods lists the algorithms of the accepted keys, for jwt.WithValidMethods
+func (ring *jwtKeyring) validMethods() []string {
+	seen := map[string]bool{}
+	var methods []string
+	for _, key := range ring.keys {
+		if alg := key.method.Alg(); !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// keyFunc picks the verification key by kid and checks that the token's
+// algorithm is the key's own, so a public key can never be used as an
+// HMAC secret (algorithm confusion)
+func (ring *jwtKeyring) keyFunc(token *jwt.Token) (any, error) {
+	key := ring.legacy
+	if kid, ok := token.Header["kid"].(string); ok {
+		key = ring.keys[kid]
+	}
+	if key == nil {
+		return nil, fmt.Errorf("unknown signing key: %v", token.Header["kid"])
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.verifyKey, nil
+}
+
+// sign signs claims with the active key
+func (ring *jwtKeyring) sign(claims jwt.Claims) (string, error) {
+	if ring.active == nil {
+		return "", errors.New("no JWT signing key configured")
+	}
+	token := jwt.NewWithClaims(ring.active.method, claims)
+	token.Header["kid"] = ring.active.kid
+	return token.SignedString(ring.active.signKey)
+}
+
+// jwks returns the public keys of the keyring in JWK format. HMAC keys
+// are secrets and never published.
+func (ring *jwtKeyring) jwks() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for _, key := range ring.keys {
+		switch publicKey := key.verifyKey.(type) {
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "OKP",
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(publicKey),
+				Kid: key.kid,
+				Alg: key.method.Alg(),
+				Use: "sig",
+			})
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "RSA",
+				N:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+				Kid: key.kid,
+				Alg: key.method.Alg(),
+				Use: "sig",
+			})
+		}
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
@@ -0,0 +1,221 @@
+package security
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Angak0k/pimpmypack/pkg/config"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withJWTConfig sets the JWT key configuration for one test and drops the
+// loaded keyring afterwards
+func withJWTConfig(t *testing.T, alg, kid, privateKeyFile, verificationDir string) {
+	t.Helper()
+	setupTestEnv(t)
+	prevAlg, prevKid := config.JWTSigningAlg, config.JWTSigningKeyID
+	prevFile, prevDir := config.JWTPrivateKeyFile, config.JWTVerificationKeysDir
+	config.JWTSigningAlg, config.JWTSigningKeyID = alg, kid
+	config.JWTPrivateKeyFile, config.JWTVerificationKeysDir = privateKeyFile, verificationDir
+	t.Cleanup(func() {
+		config.JWTSigningAlg, config.JWTSigningKeyID = prevAlg, prevKid
+		config.JWTPrivateKeyFile, config.JWTVerificationKeysDir = prevFile, prevDir
+		loadedKeyring.Store(nil)
+		teardownTestEnv(t)
+	})
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+}
+
+func contextWithToken(tokenString string) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request.Header.Set("Authorization", "Bearer "+tokenString)
+	return c
+}
+
+func hs256Token(t *testing.T, secret string, header map[string]any) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"authorized": true,
+		"user_id":    42,
+		"exp":        time.Now().Add(time.Hour).Unix(),
+	})
+	for name, value := range header {
+		token.Header[name] = value
+	}
+	tokenString, err := token.SignedString([]byte(secret))
+	require.NoError(t, err)
+	return tokenString
+}
+
+func TestGenerateToken_SetsKid(t *testing.T) {
+	withJWTConfig(t, "HS256", "hs-2026", "", "")
+	require.NoError(t, LoadJWTKeys())
+
+	tokenString, err := GenerateToken(42)
+	require.NoError(t, err)
+
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "hs-2026", token.Header["kid"])
+	assert.Equal(t, "HS256", token.Header["alg"])
+
+	userID, err := ExtractTokenID(contextWithToken(tokenString))
+	require.NoError(t, err)
+	assert.Equal(t, uint(42), userID)
+}
+
+func TestHS256_LegacyTokenWithoutKid(t *testing.T) {
+	withJWTConfig(t, "HS256", "primary", "", "")
+	require.NoError(t, LoadJWTKeys())
+
+	userID, err := ExtractTokenID(contextWithToken(hs256Token(t, testAPISecret, nil)))
+	require.NoError(t, err)
+	assert.Equal(t, uint(42), userID)
+}
+
+func TestHS256_RotationKeepsRetiredSecret(t *testing.T) {
+	dir := t.TempDir()
+	oldSecret := "anotherverylongsecretof32bytes!!"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "hs-old.secret"), []byte(oldSecret+"\n"), 0o600))
+	withJWTConfig(t, "HS256", "hs-new", "", dir)
+	require.NoError(t, LoadJWTKeys())
+
+	t.Run("token of the retired key is accepted", func(t *testing.T) {
+		_, err := ExtractTokenID(contextWithToken(hs256Token(t, oldSecret, map[string]any{"kid": "hs-old"})))
+		require.NoError(t, err)
+	})
+
+	t.Run("retired secret cannot pose as the active key", func(t *testing.T) {
+		_, err := ExtractTokenID(contextWithToken(hs256Token(t, oldSecret, map[string]any{"kid": "hs-new"})))
+		require.Error(t, err)
+	})
+
+	t.Run("unknown kid is rejected", func(t *testing.T) {
+		_, err := ExtractTokenID(contextWithToken(hs256Token(t, testAPISecret, map[string]any{"kid": "nope"})))
+		require.Error(t, err)
+	})
+
+	t.Run("HMAC keys are not published", func(t *testing.T) {
+		assert.Empty(t, currentKeyring().jwks().Keys)
+	})
+}
+
+func TestEdDSA_SignVerifyAndJWKS(t *testing.T) {
+	dir := t.TempDir()
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+	keyFile := filepath.Join(dir, "ed.pem")
+	writePEM(t, keyFile, "PRIVATE KEY", der)
+
+	withJWTConfig(t, "EdDSA", "ed-1", keyFile, "")
+	require.NoError(t, LoadJWTKeys())
+
+	tokenString, err := GenerateToken(42)
+	require.NoError(t, err)
+	userID, err := ExtractTokenID(contextWithToken(tokenString))
+	require.NoError(t, err)
+	assert.Equal(t, uint(42), userID)
+
+	t.Run("legacy HS256 tokens are no longer accepted", func(t *testing.T) {
+		_, err := ExtractTokenID(contextWithToken(hs256Token(t, testAPISecret, nil)))
+		require.Error(t, err)
+	})
+
+	t.Run("JWKS publishes the public key", func(t *testing.T) {
+		router := gin.New()
+		router.GET("/.well-known/jwks.json", JWKSHandler)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var set JWKS
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &set))
+		require.Len(t, set.Keys, 1)
+		assert.Equal(t, "OKP", set.Keys[0].Kty)
+		assert.Equal(t, "ed-1", set.Keys[0].Kid)
+		assert.Equal(t, "EdDSA", set.Keys[0].Alg)
+
+		x, err := base64.RawURLEncoding.DecodeString(set.Keys[0].X)
+		require.NoError(t, err)
+		assert.Equal(t, []byte(publicKey), x)
+
+		// A third party verifies the token with the published key only
+		_, err = jwt.Parse(tokenString, func(_ *jwt.Token) (any, error) {
+			return ed25519.PublicKey(x), nil
+		}, jwt.WithValidMethods([]string{"EdDSA"}))
+		require.NoError(t, err)
+	})
+}
+
+func TestRS256_AlgorithmConfusionRejected(t *testing.T) {
+	dir := t.TempDir()
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+	keyFile := filepath.Join(dir, "rsa.pem")
+	writePEM(t, keyFile, "PRIVATE KEY", der)
+	publicDER, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+
+	withJWTConfig(t, "RS256", "rsa-1", keyFile, "")
+	require.NoError(t, LoadJWTKeys())
+
+	tokenString, err := GenerateToken(42)
+	require.NoError(t, err)
+	_, err = ExtractTokenID(contextWithToken(tokenString))
+	require.NoError(t, err)
+
+	// HS256 token "signed" with the public key, which is public knowledge
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+	forged := hs256Token(t, string(publicPEM), map[string]any{"kid": "rsa-1"})
+	_, err = ExtractTokenID(contextWithToken(forged))
+	require.Error(t, err)
+
+	set := currentKeyring().jwks()
+	require.Len(t, set.Keys, 1)
+	assert.Equal(t, "RSA", set.Keys[0].Kty)
+	assert.Equal(t, "AQAB", set.Keys[0].E)
+}
+
+func TestLoadJWTKeys_Errors(t *testing.T) {
+	t.Run("missing private key file", func(t *testing.T) {
+		withJWTConfig(t, "EdDSA", "ed-1", filepath.Join(t.TempDir(), "missing.pem"), "")
+		require.Error(t, LoadJWTKeys())
+	})
+
+	t.Run("short retired secret", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "old.secret"), []byte("short"), 0o600))
+		withJWTConfig(t, "HS256", "primary", "", dir)
+		require.Error(t, LoadJWTKeys())
+	})
+
+	t.Run("retired key reusing the active kid", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "primary.secret"), []byte(testAPISecret), 0o600))
+		withJWTConfig(t, "HS256", "primary", "", dir)
+		require.Error(t, LoadJWTKeys())
+	})
+}
@@ -0,0 +1,278 @@
+package security
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Angak0k/pimpmypack/pkg/config"
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/Angak0k/pimpmypack/pkg/helper"
+)
+
+const (
+	loginScopeAccount = "account"
+	loginScopeIP      = "ip"
+
+	// maxBackoffDoublings caps the exponent so the shift below cannot overflow
+	// before the configured maximum delay is applied
+	maxBackoffDoublings = 20
+)
+
+// LoginThrottledError is returned when a login attempt is rejected before the
+// password is even checked: the account is locked or the account/IP is in its
+// progressive-delay backoff.
+type LoginThrottledError struct {
+	Locked     bool
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	if e.Locked {
+		return "account temporarily locked"
+	}
+	return "too many failed login attempts"
+}
+
+// RetryAfterSeconds returns the delay before the next attempt, rounded up
+func (e *LoginThrottledError) RetryAfterSeconds() int {
+	return int((e.RetryAfter + time.Second - 1) / time.Second)
+}
+
+// AccountLock describes a lock applied by RecordLoginFailure
+type AccountLock struct {
+	LockedUntil time.Time
+	UnlockCode  string
+}
+
+// loginFailureState is a login_failure row
+type loginFailureState struct {
+	failureCount  int
+	lastFailureAt time.Time
+	lockedUntil   *time.Time
+}
+
+func failureWindow() time.Duration {
+	return time.Minute * time.Duration(config.LoginFailureWindowMinutes)
+}
+
+// loginBackoffDelay returns the delay imposed after failures consecutive
+// failures: nothing below threshold, then base, 2×base, 4×base… capped at the
+// configured maximum. A threshold of 0 or less disables backoff.
+func loginBackoffDelay(failures, threshold int) time.Duration {
+	if threshold <= 0 || failures < threshold {
+		return 0
+	}
+	doublings := min(failures-threshold, maxBackoffDoublings)
+	delay := time.Second * time.Duration(config.LoginBackoffBaseSeconds) << doublings
+	return min(delay, time.Second*time.Duration(config.LoginBackoffMaxSeconds))
+}
+
+// retryAfter returns how long the subject must still wait, 0 when an attempt is allowed
+func (s *loginFailureState) retryAfter(now time.Time, backoffThreshold int) (time.Duration, bool) {
+	if s.lockedUntil != nil && s.lockedUntil.After(now) {
+		return s.lockedUntil.Sub(now), true
+	}
+	if now.Sub(s.lastFailureAt) > failureWindow() {
+		return 0, false
+	}
+	next := s.lastFailureAt.Add(loginBackoffDelay(s.failureCount, backoffThreshold))
+	if next.After(now) {
+		return next.Sub(now), false
+	}
+	return 0, false
+}
+
+func findLoginFailure(ctx context.Context, scope, subject string) (*loginFailureState, error) {
+	var state loginFailureState
+	err := database.DB().QueryRowContext(ctx,
+		`SELECT failure_count, last_failure_at, locked_until
+         FROM login_failure WHERE scope = $1 AND subject = $2`,
+		scope, subject,
+	).Scan(&state.failureCount, &state.lastFailureAt, &state.lockedUntil)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil //nolint:nilnil // no failure recorded is not an error
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get login failures: %w", err)
+	}
+	return &state, nil
+}
+
+// CheckLoginThrottle returns a *LoginThrottledError when a login attempt from
+// ip for accountID must be rejected without checking the password. accountID
+// is 0 when the submitted username matched no account: only the IP is checked.
+func CheckLoginThrottle(ctx context.Context, accountID uint, ip string) error {
+	now := time.Now()
+
+	ipState, err := findLoginFailure(ctx, loginScopeIP, ip)
+	if err != nil {
+		return err
+	}
+	if ipState != nil {
+		if wait, _ := ipState.retryAfter(now, config.LoginIPBackoffThreshold); wait > 0 {
+			return &LoginThrottledError{RetryAfter: wait}
+		}
+	}
+
+	if accountID == 0 {
+		return nil
+	}
+
+	accountState, err := findLoginFailure(ctx, loginScopeAccount, strconv.FormatUint(uint64(accountID), 10))
+	if err != nil {
+		return err
+	}
+	if accountState != nil {
+		if wait, locked := accountState.retryAfter(now, config.LoginBackoffThreshold); wait > 0 {
+			return &LoginThrottledError{Locked: locked, RetryAfter: wait}
+		}
+	}
+
+	return nil
+}
+
+// incrementLoginFailure records one failure for a subject, restarting the
+// count when the previous failure fell outside the failure window
+func incrementLoginFailure(ctx context.Context, scope, subject string, now time.Time) (int, error) {
+	var count int
+	err := database.DB().QueryRowContext(ctx,
+		`INSERT INTO login_failure (scope, subject, failure_count, last_failure_at)
+         VALUES ($1, $2, 1, $3)
+         ON CONFLICT (scope, subject) DO UPDATE SET
+             failure_count = CASE WHEN login_failure.last_failure_at < $4 THEN 1
+                                  ELSE login_failure.failure_count + 1 END,
+             last_failure_at = $3
+         RETURNING failure_count`,
+		scope, subject, now, now.Add(-failureWindow()),
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record login failure: %w", err)
+	}
+	return count, nil
+}
+
+// RecordLoginFailure counts a failed login against the IP and, when known,
+// the account. It returns the lock when this failure reached the lockout
+// threshold, nil otherwise. The failure count restarts once the account is
+// locked, so the owner gets a fresh set of attempts after the lock expires.
+func RecordLoginFailure(ctx context.Context, accountID uint, ip string) (*AccountLock, error) {
+	now := time.Now()
+
+	if _, err := incrementLoginFailure(ctx, loginScopeIP, ip, now); err != nil {
+		return nil, err
+	}
+
+	if accountID == 0 {
+		return nil, nil //nolint:nilnil // no account, nothing to lock
+	}
+
+	subject := strconv.FormatUint(uint64(accountID), 10)
+	count, err := incrementLoginFailure(ctx, loginScopeAccount, subject, now)
+	if err != nil {
+		return nil, err
+	}
+	if config.LoginLockoutThreshold <= 0 || count < config.LoginLockoutThreshold {
+		return nil, nil //nolint:nilnil // below threshold: no lock applied
+	}
+
+	code, err := helper.GenerateRandomCode(30)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate unlock code: %w", err)
+	}
+	lock := &AccountLock{
+		LockedUntil: now.Add(time.Minute * time.Duration(config.LoginLockoutMinutes)),
+		UnlockCode:  code,
+	}
+
+	_, err = database.DB().ExecContext(ctx,
+		`UPDATE login_failure SET failure_count = 0, locked_until = $1, unlock_code = $2
+         WHERE scope = $3 AND subject = $4`,
+		lock.LockedUntil, lock.UnlockCode, loginScopeAccount, subject,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock account: %w", err)
+	}
+
+	auditAccountLocked(accountID, ip, lock.LockedUntil)
+	return lock, nil
+}
+
+// ResetLoginFailures clears the failure count of an account after a successful login
+func ResetLoginFailures(ctx context.Context, accountID uint) error {
+	_, err := database.DB().ExecContext(ctx,
+		`DELETE FROM login_failure WHERE scope = $1 AND subject = $2`,
+		loginScopeAccount, strconv.FormatUint(uint64(accountID), 10),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reset login failures: %w", err)
+	}
+	return nil
+}
+
+// UnlockAccount lifts the lock and backoff of an account (admin action).
+// It reports whether the account was locked.
+func UnlockAccount(ctx context.Context, accountID uint) (bool, error) {
+	result, err := database.DB().ExecContext(ctx,
+		`DELETE FROM login_failure WHERE scope = $1 AND subject = $2 AND locked_until > $3`,
+		loginScopeAccount, strconv.FormatUint(uint64(accountID), 10), time.Now(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to unlock account: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		// Not locked: still clear any pending backoff
+		return false, ResetLoginFailures(ctx, accountID)
+	}
+
+	return true, nil
+}
+
+// UnlockAccountWithCode lifts an account lock using the code from the lock
+// notification email. It reports whether a live lock matched the code.
+func UnlockAccountWithCode(ctx context.Context, accountID uint, code string) (bool, error) {
+	result, err := database.DB().ExecContext(ctx,
+		`DELETE FROM login_failure
+         WHERE scope = $1 AND subject = $2 AND unlock_code = $3 AND locked_until > $4`,
+		loginScopeAccount, strconv.FormatUint(uint64(accountID), 10), code, time.Now(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to unlock account: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// CleanupStaleLoginFailures deletes failure rows that no longer throttle
+// anything: last failure outside the window and no live lock
+func CleanupStaleLoginFailures(ctx context.Context) (int64, error) {
+	now := time.Now()
+	result, err := database.DB().ExecContext(ctx,
+		`DELETE FROM login_failure
+         WHERE last_failure_at < $1 AND (locked_until IS NULL OR locked_until < $2)`,
+		now.Add(-failureWindow()), now,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to cleanup stale login failures: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
@@ -0,0 +1,153 @@
+package security
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Angak0k/pimpmypack/pkg/config"
+	"github.com/Angak0k/pimpmypack/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withLoginThrottleConfig overrides the login throttling settings for one test
+func withLoginThrottleConfig(t *testing.T, backoffThreshold, lockoutThreshold int) {
+	t.Helper()
+	prevBackoff, prevLockout := config.LoginBackoffThreshold, config.LoginLockoutThreshold
+	config.LoginBackoffThreshold, config.LoginLockoutThreshold = backoffThreshold, lockoutThreshold
+	t.Cleanup(func() {
+		config.LoginBackoffThreshold, config.LoginLockoutThreshold = prevBackoff, prevLockout
+	})
+}
+
+func TestLoginBackoffDelay(t *testing.T) {
+	prevBase, prevMax := config.LoginBackoffBaseSeconds, config.LoginBackoffMaxSeconds
+	config.LoginBackoffBaseSeconds, config.LoginBackoffMaxSeconds = 1, 60
+	t.Cleanup(func() { config.LoginBackoffBaseSeconds, config.LoginBackoffMaxSeconds = prevBase, prevMax })
+
+	tests := []struct {
+		failures  int
+		threshold int
+		expected  time.Duration
+	}{
+		{failures: 2, threshold: 3, expected: 0},
+		{failures: 3, threshold: 3, expected: time.Second},
+		{failures: 4, threshold: 3, expected: 2 * time.Second},
+		{failures: 6, threshold: 3, expected: 8 * time.Second},
+		{failures: 10, threshold: 3, expected: 60 * time.Second},
+		{failures: 1000, threshold: 3, expected: 60 * time.Second},
+		{failures: 50, threshold: 0, expected: 0},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, loginBackoffDelay(tt.failures, tt.threshold),
+			"failures=%d threshold=%d", tt.failures, tt.threshold)
+	}
+}
+
+func TestLoginThrottledError_RetryAfterSeconds(t *testing.T) {
+	assert.Equal(t, 1, (&LoginThrottledError{RetryAfter: 10 * time.Millisecond}).RetryAfterSeconds())
+	assert.Equal(t, 2, (&LoginThrottledError{RetryAfter: 1500 * time.Millisecond}).RetryAfterSeconds())
+	assert.Equal(t, 30, (&LoginThrottledError{RetryAfter: 30 * time.Second}).RetryAfterSeconds())
+}
+
+func TestRecordLoginFailure_Backoff(t *testing.T) {
+	ctx := context.Background()
+	withLoginThrottleConfig(t, 2, 0)
+	accountID := createTestAccount(t)
+	ip := "192.0.2.50"
+	t.Cleanup(func() { _, _ = database.DB().Exec(`DELETE FROM login_failure WHERE subject = $1`, ip) })
+
+	lock, err := RecordLoginFailure(ctx, accountID, ip)
+	require.NoError(t, err)
+	assert.Nil(t, lock)
+	require.NoError(t, CheckLoginThrottle(ctx, accountID, ip), "one failure is below the backoff threshold")
+
+	_, err = RecordLoginFailure(ctx, accountID, ip)
+	require.NoError(t, err)
+
+	var throttled *LoginThrottledError
+	require.ErrorAs(t, CheckLoginThrottle(ctx, accountID, ip), &throttled)
+	assert.False(t, throttled.Locked)
+	assert.Positive(t, throttled.RetryAfter)
+
+	require.NoError(t, ResetLoginFailures(ctx, accountID))
+	assert.NoError(t, CheckLoginThrottle(ctx, accountID, ip))
+}
+
+func TestRecordLoginFailure_LockAndUnlock(t *testing.T) {
+	ctx := context.Background()
+	withLoginThrottleConfig(t, 0, 3)
+	accountID := createTestAccount(t)
+	ip := "192.0.2.51"
+	t.Cleanup(func() { _, _ = database.DB().Exec(`DELETE FROM login_failure WHERE subject = $1`, ip) })
+
+	var lock *AccountLock
+	for range 3 {
+		var err error
+		lock, err = RecordLoginFailure(ctx, accountID, ip)
+		require.NoError(t, err)
+	}
+	require.NotNil(t, lock, "third failure must lock the account")
+	assert.NotEmpty(t, lock.UnlockCode)
+	assert.True(t, lock.LockedUntil.After(time.Now()))
+
+	var throttled *LoginThrottledError
+	require.ErrorAs(t, CheckLoginThrottle(ctx, accountID, "192.0.2.52"), &throttled)
+	assert.True(t, throttled.Locked, "lock applies whatever the client IP")
+
+	unlocked, err := UnlockAccountWithCode(ctx, accountID, "wrong-code")
+	require.NoError(t, err)
+	assert.False(t, unlocked)
+
+	unlocked, err = UnlockAccountWithCode(ctx, accountID, lock.UnlockCode)
+	require.NoError(t, err)
+	assert.True(t, unlocked)
+	require.NoError(t, CheckLoginThrottle(ctx, accountID, "192.0.2.52"))
+
+	events, err := returnAuditEvents(ctx, AuditEventFilter{
+		UserID: &accountID, EventType: string(EventAccountLocked), Limit: 10,
+	})
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+}
+
+func TestUnlockAccount_Admin(t *testing.T) {
+	ctx := context.Background()
+	withLoginThrottleConfig(t, 0, 1)
+	accountID := createTestAccount(t)
+	ip := "192.0.2.53"
+	t.Cleanup(func() { _, _ = database.DB().Exec(`DELETE FROM login_failure WHERE subject = $1`, ip) })
+
+	_, err := RecordLoginFailure(ctx, accountID, ip)
+	require.NoError(t, err)
+
+	unlocked, err := UnlockAccount(ctx, accountID)
+	require.NoError(t, err)
+	assert.True(t, unlocked)
+
+	unlocked, err = UnlockAccount(ctx, accountID)
+	require.NoError(t, err)
+	assert.False(t, unlocked, "second unlock finds no lock")
+	assert.NoError(t, CheckLoginThrottle(ctx, accountID, "192.0.2.54"))
+}
+
+func TestCleanupStaleLoginFailures(t *testing.T) {
+	ctx := context.Background()
+	ip := "192.0.2.55"
+
+	_, err := database.DB().Exec(
+		`INSERT INTO login_failure (scope, subject, failure_count, last_failure_at) VALUES ($1, $2, 5, $3)`,
+		loginScopeIP, ip, time.Now().Add(-failureWindow()-time.Minute),
+	)
+	require.NoError(t, err)
+
+	deleted, err := CleanupStaleLoginFailures(ctx)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, deleted, int64(1))
+
+	state, err := findLoginFailure(ctx, loginScopeIP, ip)
+	require.NoError(t, err)
+	assert.Nil(t, state)
+}
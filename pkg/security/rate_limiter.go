@@ -1,23 +1,67 @@
 package security
 
 import (
+	"context"
 	"net/http"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/Angak0k/pimpmypack/pkg/config"
+	"github.com/Angak0k/pimpmypack/pkg/helper"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/time/rate"
 )
 
-// IPRateLimiter manages rate limiters per IP address
+// rateLimitBackendPostgres selects the shared Postgres backend (RATE_LIMIT_BACKEND)
+const rateLimitBackendPostgres = "postgres"
+
+// RateLimitBackend counts requests per key (client IP or user) and decides
+// whether one more request is allowed
+type RateLimitBackend interface {
+	Allow(ctx context.Context, key string) (bool, error)
+	RetryAfterSeconds() int
+}
+
+// RateLimitKeyFunc returns the key a request is counted against
+type RateLimitKeyFunc func(c *gin.Context) string
+
+// KeyByIP counts requests per client IP
+func KeyByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// KeyByUser counts requests per authenticated user. It falls back to the
+// client IP when the request carries no valid token, so it must be used
+// behind JwtAuthProcessor to be effective.
+func KeyByUser(c *gin.Context) string {
+	userID, err := ExtractTokenID(c)
+	if err != nil {
+		return KeyByIP(c)
+	}
+	return "user:" + strconv.FormatUint(uint64(userID), 10)
+}
+
+// limiterEntry is a token bucket and the last time it was used
+type limiterEntry struct {
+	limiter  *rate.Limiter
+	lastSeen atomic.Int64 // unix nanoseconds
+}
+
+// IPRateLimiter is the in-memory backend: a token bucket per key, local to
+// the process. Entries idle long enough to have refilled completely are
+// evicted, since a fresh bucket behaves the same.
 type IPRateLimiter struct {
-	limiters      sync.Map // map[string]*rate.Limiter
+	limiters      sync.Map // map[string]*limiterEntry
 	rate          rate.Limit
 	burst         int
 	windowMinutes int
+	idleTTL       time.Duration
+	lastSweep     atomic.Int64 // unix nanoseconds
 }
 
-// NewIPRateLimiter creates a new IP-based rate limiter
+// NewIPRateLimiter creates a new in-memory rate limiter
 func NewIPRateLimiter(requestsPerWindow, burstSize, windowMinutes int) *IPRateLimiter {
 	if windowMinutes <= 0 {
 		windowMinutes = 1
@@ -31,26 +75,38 @@ func NewIPRateLimiter(requestsPerWindow, burstSize, windowMinutes int) *IPRateLi
 
 	window := time.Duration(windowMinutes) * time.Minute
 	r := rate.Limit(float64(requestsPerWindow) / window.Seconds())
-	return &IPRateLimiter{
+	refill := time.Duration(float64(burstSize) / float64(r) * float64(time.Second))
+	rl := &IPRateLimiter{
 		rate:          r,
 		burst:         burstSize,
 		windowMinutes: windowMinutes,
+		idleTTL:       max(refill, window),
 	}
+	rl.lastSweep.Store(time.Now().UnixNano())
+	return rl
 }
 
-// GetLimiter returns the rate limiter for an IP, creating if needed
-func (rl *IPRateLimiter) GetLimiter(ip string) *rate.Limiter {
-	limiter, exists := rl.limiters.Load(ip)
+// GetLimiter returns the rate limiter for a key, creating if needed
+func (rl *IPRateLimiter) GetLimiter(key string) *rate.Limiter {
+	now := time.Now()
+	rl.maybeEvictIdle(now)
+
+	value, exists := rl.limiters.Load(key)
 	if !exists {
-		limiter = rate.NewLimiter(rl.rate, rl.burst)
-		rl.limiters.Store(ip, limiter)
+		value, _ = rl.limiters.LoadOrStore(key, &limiterEntry{limiter: rate.NewLimiter(rl.rate, rl.burst)})
 	}
-	rateLimiter, ok := limiter.(*rate.Limiter)
+	entry, ok := value.(*limiterEntry)
 	if !ok {
-		// This should never happen as we always store *rate.Limiter
-		rateLimiter = rate.NewLimiter(rl.rate, rl.burst)
+		// This should never happen as we always store *limiterEntry
+		return rate.NewLimiter(rl.rate, rl.burst)
 	}
-	return rateLimiter
+	entry.lastSeen.Store(now.UnixNano())
+	return entry.limiter
+}
+
+// Allow implements RateLimitBackend
+func (rl *IPRateLimiter) Allow(_ context.Context, key string) (bool, error) {
+	return rl.GetLimiter(key).Allow(), nil
 }
 
 // RetryAfterSeconds returns the retry_after value in seconds based on the configured window
@@ -58,18 +114,82 @@ func (rl *IPRateLimiter) RetryAfterSeconds() int {
 	return rl.windowMinutes * 60
 }
 
-// NewEndpointRateLimiter creates a rate limiting middleware for any endpoint
+// maybeEvictIdle sweeps idle entries at most once per idle TTL
+func (rl *IPRateLimiter) maybeEvictIdle(now time.Time) {
+	last := rl.lastSweep.Load()
+	if now.UnixNano()-last < int64(rl.idleTTL) {
+		return
+	}
+	if rl.lastSweep.CompareAndSwap(last, now.UnixNano()) {
+		rl.evictIdle(now)
+	}
+}
+
+// evictIdle removes the entries unused since idleTTL and returns how many were removed
+func (rl *IPRateLimiter) evictIdle(now time.Time) int {
+	cutoff := now.Add(-rl.idleTTL).UnixNano()
+	evicted := 0
+	rl.limiters.Range(func(key, value any) bool {
+		if entry, ok := value.(*limiterEntry); ok && entry.lastSeen.Load() < cutoff {
+			rl.limiters.Delete(key)
+			evicted++
+		}
+		return true
+	})
+	return evicted
+}
+
+// size returns the number of tracked keys
+func (rl *IPRateLimiter) size() int {
+	n := 0
+	rl.limiters.Range(func(_, _ any) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// newRateLimitBackend returns the backend selected by RATE_LIMIT_BACKEND.
+// bucket names the limiter in the shared backend, so that limiters of
+// different endpoints do not share counters.
+func newRateLimitBackend(bucket string, requestsPerWindow, burstSize, windowMinutes int) RateLimitBackend {
+	if config.RateLimitBackend == rateLimitBackendPostgres {
+		return NewPostgresRateLimiter(bucket, requestsPerWindow, windowMinutes)
+	}
+	return NewIPRateLimiter(requestsPerWindow, burstSize, windowMinutes)
+}
+
+// NewEndpointRateLimiter creates a rate limiting middleware for any endpoint, keyed by client IP
 func NewEndpointRateLimiter(endpoint string, requestsPerWindow, burstSize, windowMinutes int) gin.HandlerFunc {
-	limiter := NewIPRateLimiter(requestsPerWindow, burstSize, windowMinutes)
+	backend := newRateLimitBackend(endpoint, requestsPerWindow, burstSize, windowMinutes)
+	return RateLimitMiddleware(endpoint, backend, KeyByIP)
+}
 
+// NewUserRateLimiter creates a rate limiting middleware keyed by authenticated
+// user, for routes behind JwtAuthProcessor. Reusing the returned handler on
+// several routes makes them share one budget per user.
+func NewUserRateLimiter(endpoint string, requestsPerWindow, burstSize, windowMinutes int) gin.HandlerFunc {
+	backend := newRateLimitBackend(endpoint, requestsPerWindow, burstSize, windowMinutes)
+	return RateLimitMiddleware(endpoint, backend, KeyByUser)
+}
+
+// RateLimitMiddleware rejects requests over the backend limit with 429.
+// A backend error lets the request through: an unavailable shared store
+// must not take the API down with it.
+func RateLimitMiddleware(endpoint string, backend RateLimitBackend, keyFunc RateLimitKeyFunc) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		clientIP := c.ClientIP()
+		allowed, err := backend.Allow(c.Request.Context(), keyFunc(c))
+		if err != nil {
+			helper.LogAndSanitize(err, "rate limiter: check limit failed for "+endpoint)
+			c.Next()
+			return
+		}
 
-		if !limiter.GetLimiter(clientIP).Allow() {
+		if !allowed {
 			AuditRateLimitExceeded(c, endpoint)
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error":       "Rate limit exceeded",
-				"retry_after": limiter.RetryAfterSeconds(),
+				"retry_after": backend.RetryAfterSeconds(),
 			})
 			c.Abort()
 			return
@@ -0,0 +1,77 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Angak0k/pimpmypack/pkg/database"
+)
+
+// PostgresRateLimiter is the shared backend: fixed-window counters in the
+// rate_limit_counter table, so the limit holds across replicas. Unlike the
+// token bucket there is no burst: up to requestsPerWindow requests are
+// allowed per window, aligned on multiples of the window length.
+type PostgresRateLimiter struct {
+	bucket string
+	limit  int
+	window time.Duration
+}
+
+// NewPostgresRateLimiter creates a Postgres-backed rate limiter for bucket
+func NewPostgresRateLimiter(bucket string, requestsPerWindow, windowMinutes int) *PostgresRateLimiter {
+	if windowMinutes <= 0 {
+		windowMinutes = 1
+	}
+	if requestsPerWindow <= 0 {
+		requestsPerWindow = 1
+	}
+
+	return &PostgresRateLimiter{
+		bucket: bucket,
+		limit:  requestsPerWindow,
+		window: time.Duration(windowMinutes) * time.Minute,
+	}
+}
+
+// Allow counts one request for key in the current window
+func (rl *PostgresRateLimiter) Allow(ctx context.Context, key string) (bool, error) {
+	windowStart := time.Now().Truncate(rl.window)
+
+	var count int
+	err := database.DB().QueryRowContext(ctx,
+		`INSERT INTO rate_limit_counter (bucket, key, window_start, expires_at, count)
+         VALUES ($1, $2, $3, $4, 1)
+         ON CONFLICT (bucket, key, window_start) DO UPDATE SET count = rate_limit_counter.count + 1
+         RETURNING count`,
+		rl.bucket, key, windowStart, windowStart.Add(rl.window),
+	).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to increment rate limit counter: %w", err)
+	}
+
+	return count <= rl.limit, nil
+}
+
+// RetryAfterSeconds returns the retry_after value in seconds based on the configured window
+func (rl *PostgresRateLimiter) RetryAfterSeconds() int {
+	return int(rl.window / time.Second)
+}
+
+// CleanupExpiredRateLimitCounters deletes the counters of elapsed windows
+func CleanupExpiredRateLimitCounters(ctx context.Context) (int64, error) {
+	result, err := database.DB().ExecContext(ctx,
+		`DELETE FROM rate_limit_counter WHERE expires_at < $1`,
+		time.Now(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to cleanup expired rate limit counters: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
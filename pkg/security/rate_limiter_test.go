@@ -1,12 +1,16 @@
 package security
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
+	"github.com/Angak0k/pimpmypack/pkg/database"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -259,3 +263,130 @@ func TestIPRateLimiter_RetryAfterSeconds(t *testing.T) {
 	limiter2 := NewIPRateLimiter(10, 10, 1)
 	assert.Equal(t, 60, limiter2.RetryAfterSeconds())
 }
+
+func TestIPRateLimiter_EvictsIdleEntries(t *testing.T) {
+	limiter := NewIPRateLimiter(10, 10, 1)
+	limiter.GetLimiter("192.168.1.1")
+	limiter.GetLimiter("192.168.1.2")
+	require.Equal(t, 2, limiter.size())
+
+	// Nothing is idle yet
+	assert.Equal(t, 0, limiter.evictIdle(time.Now()))
+
+	// Past the idle TTL every bucket has refilled and can be dropped
+	assert.Equal(t, 2, limiter.evictIdle(time.Now().Add(2*time.Minute)))
+	assert.Equal(t, 0, limiter.size())
+}
+
+func TestIPRateLimiter_SweepKeepsActiveEntries(t *testing.T) {
+	limiter := NewIPRateLimiter(10, 10, 1)
+	limiter.GetLimiter("192.168.1.1")
+
+	// Backdate the idle entry and the last sweep so the next access sweeps
+	value, ok := limiter.limiters.Load("192.168.1.1")
+	require.True(t, ok)
+	entry, ok := value.(*limiterEntry)
+	require.True(t, ok)
+	entry.lastSeen.Store(time.Now().Add(-2 * time.Minute).UnixNano())
+	limiter.lastSweep.Store(time.Now().Add(-2 * time.Minute).UnixNano())
+
+	limiter.GetLimiter("192.168.1.2")
+
+	_, idleKept := limiter.limiters.Load("192.168.1.1")
+	assert.False(t, idleKept, "idle entry should be evicted")
+	_, activeKept := limiter.limiters.Load("192.168.1.2")
+	assert.True(t, activeKept, "entry in use should be kept")
+}
+
+func TestNewUserRateLimiter_PerUser(t *testing.T) {
+	setupTestEnv(t)
+	defer teardownTestEnv(t)
+	router := gin.New()
+	router.POST("/test", NewUserRateLimiter("/test", 2, 2, 1), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	post := func(accessToken string) int {
+		req := httptest.NewRequest(http.MethodPost, "/test", nil)
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	token1, err := GenerateToken(1)
+	require.NoError(t, err)
+	token2, err := GenerateToken(2)
+	require.NoError(t, err)
+
+	// Both users share the client IP but not the budget
+	assert.Equal(t, http.StatusOK, post(token1))
+	assert.Equal(t, http.StatusOK, post(token1))
+	assert.Equal(t, http.StatusTooManyRequests, post(token1))
+	assert.Equal(t, http.StatusOK, post(token2))
+}
+
+// failingRateLimitBackend always fails, as an unreachable shared store would
+type failingRateLimitBackend struct{}
+
+func (failingRateLimitBackend) Allow(_ context.Context, _ string) (bool, error) {
+	return false, errors.New("store unavailable")
+}
+
+func (failingRateLimitBackend) RetryAfterSeconds() int { return 60 }
+
+func TestRateLimitMiddleware_FailsOpen(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/test", RateLimitMiddleware("/test", failingRateLimitBackend{}, KeyByIP), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/test", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestPostgresRateLimiter(t *testing.T) {
+	ctx := context.Background()
+	bucket := "test-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	limiter := NewPostgresRateLimiter(bucket, 2, 1)
+	t.Cleanup(func() { _, _ = database.DB().Exec(`DELETE FROM rate_limit_counter WHERE bucket = $1`, bucket) })
+
+	for i := range 2 {
+		allowed, err := limiter.Allow(ctx, "ip:192.0.2.1")
+		require.NoError(t, err)
+		assert.True(t, allowed, "request %d should be allowed", i+1)
+	}
+
+	allowed, err := limiter.Allow(ctx, "ip:192.0.2.1")
+	require.NoError(t, err)
+	assert.False(t, allowed, "third request in the window should be blocked")
+
+	allowed, err = limiter.Allow(ctx, "ip:192.0.2.2")
+	require.NoError(t, err)
+	assert.True(t, allowed, "other keys have their own counter")
+	assert.Equal(t, 60, limiter.RetryAfterSeconds())
+}
+
+func TestCleanupExpiredRateLimitCounters(t *testing.T) {
+	ctx := context.Background()
+	bucket := "test-cleanup-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	past := time.Now().Add(-time.Hour)
+
+	_, err := database.DB().Exec(
+		`INSERT INTO rate_limit_counter (bucket, key, window_start, expires_at, count) VALUES ($1, $2, $3, $4, 5)`,
+		bucket, "ip:192.0.2.1", past, past.Add(time.Minute),
+	)
+	require.NoError(t, err)
+
+	deleted, err := CleanupExpiredRateLimitCounters(ctx)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, deleted, int64(1))
+
+	var remaining int
+	err = database.DB().QueryRow(`SELECT COUNT(*) FROM rate_limit_counter WHERE bucket = $1`, bucket).Scan(&remaining)
+	require.NoError(t, err)
+	assert.Zero(t, remaining)
+}
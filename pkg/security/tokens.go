@@ -13,33 +13,40 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-// jwtKeyFunc is the single source of truth for the HS256 signing key.
-// Call sites also pass jwt.WithValidMethods([]string{"HS256"}), which rejects
-// non-HS256 tokens before this function runs. The explicit method check here
-// is a secondary guard that protects any future call site that omits WithValidMethods.
+// jwtKeyFunc is the single source of truth for the verification key: it
+// selects the key by the token's kid header and rejects a token whose
+// algorithm differs from the key's. Call sites also pass
+// jwt.WithValidMethods(validJWTMethods()), which rejects unknown algorithms
+// (e.g. "none") before this function runs.
 func jwtKeyFunc(token *jwt.Token) (any, error) {
-	if token.Method != jwt.SigningMethodHS256 {
-		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-	}
-	return []byte(config.APISecret), nil
+	return currentKeyring().keyFunc(token)
+}
+
+// validJWTMethods lists the algorithms of the accepted keys
+func validJWTMethods() []string {
+	return currentKeyring().validMethods()
+}
+
+// parseJWT parses and verifies a token against the accepted keys
+func parseJWT(tokenString string) (*jwt.Token, error) {
+	return jwt.Parse(tokenString, jwtKeyFunc, jwt.WithValidMethods(validJWTMethods()))
 }
 
-// GenerateToken generates a JWT access token (existing function, moved here)
+// GenerateToken generates a JWT access token signed with the active key
 func GenerateToken(userID uint) (string, error) {
 	claims := jwt.MapClaims{
 		"authorized": true,
 		"user_id":    userID,
 		"exp":        time.Now().Add(time.Minute * time.Duration(config.AccessTokenMinutes)).Unix(),
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
-	return token.SignedString([]byte(config.APISecret))
+	return currentKeyring().sign(claims)
 }
 
 // TokenValid validates a JWT token (existing function, moved here)
 func TokenValid(c *gin.Context) error {
 	tokenString := ExtractToken(c)
-	_, err := jwt.Parse(tokenString, jwtKeyFunc, jwt.WithValidMethods([]string{"HS256"}))
+	_, err := parseJWT(tokenString)
 	return err
 }
 
@@ -57,7 +64,7 @@ func ExtractToken(c *gin.Context) string {
 // ExtractTokenID extracts user ID from token (existing function, moved here)
 func ExtractTokenID(c *gin.Context) (uint, error) {
 	tokenString := ExtractToken(c)
-	token, err := jwt.Parse(tokenString, jwtKeyFunc, jwt.WithValidMethods([]string{"HS256"}))
+	token, err := parseJWT(tokenString)
 	if err != nil {
 		return 0, err
 	}
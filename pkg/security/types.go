@@ -44,8 +44,41 @@ type RefreshResponse struct {
 	RefreshExpiresIn int64  `json:"refresh_expires_in,omitempty"`
 }
 
+// JWK is a public key in JSON Web Key format (RFC 7517); only the members
+// of the key type are set: crv and x for OKP (Ed25519), n and e for RSA
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set, served at /.well-known/jwks.json
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
 // LogoutAllResponse is the response of POST /v1/auth/logout-all
 type LogoutAllResponse struct {
 	Message         string `json:"message"`
 	RevokedSessions int64  `json:"revoked_sessions"`
 }
+
+// AuditEvents represents a collection of audit events
+type AuditEvents []AuditEvent
+
+// AuditEventFilter narrows an audit event query. Zero-valued fields are ignored;
+// From is inclusive and To exclusive.
+type AuditEventFilter struct {
+	UserID    *uint
+	EventType string
+	IP        string
+	From      *time.Time
+	To        *time.Time
+	Limit     int
+	Offset    int
+}
@@ -0,0 +1,18 @@
+package units
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Angak0k/pimpmypack/pkg/database"
+)
+
+func findPreferredUnitSystem(ctx context.Context, userID uint) (string, error) {
+	var system string
+	err := database.DB().QueryRowContext(ctx,
+		`SELECT preferred_unit_system FROM account WHERE id = $1;`, userID).Scan(&system)
+	if err != nil {
+		return "", fmt.Errorf("failed to query preferred unit system: %w", err)
+	}
+	return system, nil
+}
@@ -0,0 +1,118 @@
+// Package units formats weights in the unit system asked by a client, so
+// responses carry display values next to the raw grams.
+//
+// The conversion is opt-in: without a ?units= query parameter responses are
+// unchanged. "preferred" resolves to the account's preferred_unit_system, and
+// to metric for anonymous requests.
+package units
+
+import (
+	"errors"
+	"math"
+	"net/http"
+
+	"github.com/Angak0k/pimpmypack/pkg/helper"
+	"github.com/gin-gonic/gin"
+)
+
+// Unit systems, as stored in account.preferred_unit_system
+const (
+	Metric   = "METRIC"
+	Imperial = "IMPERIAL"
+)
+
+// Values of the units query parameter
+const (
+	paramPreferred = "preferred"
+	paramMetric    = "metric"
+	paramImperial  = "imperial"
+)
+
+// ErrInvalidUnits is returned for an unknown units query parameter
+var ErrInvalidUnits = errors.New("units must be preferred, metric or imperial")
+
+// Weight is a weight converted to the unit of a unit system
+type Weight struct {
+	Value     float64 `json:"value"`
+	Unit      string  `json:"unit"`
+	Formatted string  `json:"formatted"`
+}
+
+// FromRequest returns the unit system asked with the units query parameter,
+// or "" when the request does not ask for one. userID is 0 for anonymous requests.
+func FromRequest(c *gin.Context, userID uint) (string, error) {
+	switch c.Query("units") {
+	case "":
+		return "", nil
+	case paramMetric:
+		return Metric, nil
+	case paramImperial:
+		return Imperial, nil
+	case paramPreferred:
+		if userID == 0 {
+			return Metric, nil
+		}
+		system, err := findPreferredUnitSystem(c.Request.Context(), userID)
+		if err != nil {
+			return "", err
+		}
+		if system != Imperial {
+			return Metric, nil
+		}
+		return Imperial, nil
+	default:
+		return "", ErrInvalidUnits
+	}
+}
+
+// RespondError maps FromRequest errors to an HTTP response
+func RespondError(c *gin.Context, err error, logMsg string) {
+	if errors.Is(err, ErrInvalidUnits) {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": ErrInvalidUnits.Error()})
+		return
+	}
+	helper.LogAndSanitize(err, logMsg)
+	c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": helper.ErrMsgInternalServer})
+}
+
+// NewWeight converts grams to the unit system: grams for metric, ounces for
+// imperial below a pound and pounds above. It returns nil when system is "".
+func NewWeight(grams int, system string) *Weight {
+	if system == "" {
+		return nil
+	}
+	unit := displayUnit(grams, system)
+	value := helper.ConvertFromGrams(float64(grams), unit)
+	return &Weight{
+		Value:     roundTo(value, decimals(unit)),
+		Unit:      unit,
+		Formatted: helper.FormatWeight(float64(grams), unit),
+	}
+}
+
+func displayUnit(grams int, system string) string {
+	if system != Imperial {
+		return "g"
+	}
+	if float64(grams) >= helper.GramsPerPound {
+		return "lb"
+	}
+	return "oz"
+}
+
+// decimals matches the precision of helper.FormatWeight
+func decimals(unit string) int {
+	switch unit {
+	case "oz":
+		return 1
+	case "lb":
+		return 2
+	default:
+		return 0
+	}
+}
+
+func roundTo(value float64, decimals int) float64 {
+	p := math.Pow10(decimals)
+	return math.Round(value*p) / p
+}
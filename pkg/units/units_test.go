@@ -0,0 +1,64 @@
+package units
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWeight(t *testing.T) {
+	tests := []struct {
+		name   string
+		grams  int
+		system string
+		want   *Weight
+	}{
+		{"not asked", 500, "", nil},
+		{"metric", 1234, Metric, &Weight{Value: 1234, Unit: "g", Formatted: "1234 g"}},
+		{"imperial below a pound", 400, Imperial, &Weight{Value: 14.1, Unit: "oz", Formatted: "14.1 oz"}},
+		{"imperial above a pound", 5000, Imperial, &Weight{Value: 11.02, Unit: "lb", Formatted: "11.02 lb"}},
+		{"imperial zero", 0, Imperial, &Weight{Value: 0, Unit: "oz", Formatted: "0.0 oz"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewWeight(tt.grams, tt.system))
+		})
+	}
+}
+
+func TestFromRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		query   string
+		want    string
+		wantErr error
+	}{
+		{"", "", nil},
+		{"?units=metric", Metric, nil},
+		{"?units=imperial", Imperial, nil},
+		{"?units=preferred", Metric, nil},
+		{"?units=furlongs", "", ErrInvalidUnits},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/sharedlist/abc"+tt.query, nil)
+
+			// Anonymous requests never hit the database
+			got, err := FromRequest(c, 0)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}